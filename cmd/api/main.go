@@ -3,8 +3,8 @@ package main
 import (
 	"context"
 	"database/sql"
-	"fmt"
-	"log"
+	"flag"
+	"log/slog"
 	"os"
 	"os/signal"
 	"social-media-go-ddd/internal/application/config"
@@ -13,12 +13,18 @@ import (
 	"social-media-go-ddd/internal/domain/repository"
 	"social-media-go-ddd/internal/infrastructure/cache"
 	"social-media-go-ddd/internal/infrastructure/cache/redis"
+	"social-media-go-ddd/internal/infrastructure/logging"
+	"social-media-go-ddd/internal/infrastructure/metrics"
+	"social-media-go-ddd/internal/infrastructure/persistence/instrumented"
+	"social-media-go-ddd/internal/infrastructure/persistence/migration"
 	"social-media-go-ddd/internal/infrastructure/persistence/mysql"
 	"social-media-go-ddd/internal/infrastructure/persistence/postgres"
+	"social-media-go-ddd/internal/infrastructure/persistence/sqlite"
+	"social-media-go-ddd/internal/infrastructure/tracing"
 	"syscall"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/gofiber/fiber/v2/middleware/logger"
 	"github.com/gofiber/fiber/v2/middleware/recover"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
@@ -27,14 +33,36 @@ func main() {
 	cfg := config.LoadConfig()
 	ctx := context.Background()
 
+	logger, err := logging.New(os.Stdout, cfg.Log.Level, cfg.Log.Format)
+	if err != nil {
+		fatal("Invalid log config", err)
+	}
+	// The std log package writes through the logger as well
+	slog.SetDefault(logger)
+
 	if err := cfg.DB.DriverValid(); err != nil {
-		log.Fatalf("Database driver invalid error: %v", err)
+		fatal("Database driver invalid", err)
 	}
 
+	autoMigrate := flag.Bool("auto-migrate", cfg.DB.AutoMigrate, "apply pending migrations before starting, defaults to DB_AUTO_MIGRATE")
+	flag.Parse()
+
+	if *autoMigrate {
+		if err := runMigrations(cfg); err != nil {
+			fatal("Failed to apply migrations", err)
+		}
+	}
+
+	shutdownTracing, err := tracing.Setup(ctx, cfg.Trace.Exporter, cfg.Trace.OTLPEndpoint, cfg.Trace.ServiceName, os.Stdout)
+	if err != nil {
+		fatal("Invalid trace config", err)
+	}
+
+	m := metrics.NewMetrics()
+
 	var pool *pgxpool.Pool
 	var mysqlDB *sql.DB
-
-	var err error
+	var sqliteDB *sql.DB
 
 	var userRepo repository.UserRepository
 	var sessionRepo repository.SessionRepository
@@ -43,15 +71,28 @@ func main() {
 	var likeRepo repository.LikeRepository
 	var repostRepo repository.RepostRepository
 	var followRepo repository.FollowRepository
+	var pollRepo repository.PollRepository
+	var pollVoteRepo repository.PollVoteRepository
+	var pinnedPostRepo repository.PinnedPostRepository
+	var collectionRepo repository.CollectionRepository
+
+	var healthChecks []http.HealthCheck
 
 	switch cfg.DB.Driver {
 	case config.DB_DRIVER_PG:
 		pool, err = postgres.NewPgPool(ctx, cfg.DB.BuildDSN())
 		if err != nil {
-			log.Fatalf("Failed to create Postgres pool: %v", err)
+			fatal("Failed to create Postgres pool", err)
 		}
 		defer pool.Close()
-		log.Println("Postgres connection pool established")
+		slog.Info("Postgres connection pool established")
+		m.RegisterPgxPool(pool)
+		healthChecks = []http.HealthCheck{
+			{Name: "database", Check: pool.Ping},
+			{Name: "migrations", Check: func(ctx context.Context) error {
+				return migration.CheckVersion(cfg.DB.Driver, pool.QueryRow(ctx, migration.VersionQuery))
+			}},
+		}
 
 		userRepo = postgres.NewPgUserRepository(pool)
 		sessionRepo = postgres.NewPgSessionRepository(pool)
@@ -60,13 +101,19 @@ func main() {
 		likeRepo = postgres.NewPgLikeRepository(pool)
 		repostRepo = postgres.NewPgRepostRepository(pool)
 		followRepo = postgres.NewPgFollowRepository(pool)
+		pollRepo = postgres.NewPgPollRepository(pool)
+		pollVoteRepo = postgres.NewPgPollVoteRepository(pool)
+		pinnedPostRepo = postgres.NewPgPinnedPostRepository(pool)
+		collectionRepo = postgres.NewPgCollectionRepository(pool)
 	case config.DB_DRIVER_MYSQL:
 		mysqlDB, err = mysql.NewMySQLDB(cfg.DB.BuildDSN())
 		if err != nil {
-			log.Fatalf("Failed to connect to MySQL: %v", err)
+			fatal("Failed to connect to MySQL", err)
 		}
 		defer mysqlDB.Close()
-		log.Println("MySQL connection established")
+		slog.Info("MySQL connection established")
+		m.RegisterSQLDB(mysqlDB, cfg.DB.Name)
+		healthChecks = sqlHealthChecks(cfg.DB.Driver, mysqlDB)
 
 		userRepo = mysql.NewMySQLUserRepository(mysqlDB)
 		sessionRepo = mysql.NewMySQLSessionRepository(mysqlDB)
@@ -75,39 +122,101 @@ func main() {
 		likeRepo = mysql.NewMySQLLikeRepository(mysqlDB)
 		repostRepo = mysql.NewMySQLRepostRepository(mysqlDB)
 		followRepo = mysql.NewMySQLFollowRepository(mysqlDB)
+		pollRepo = mysql.NewMySQLPollRepository(mysqlDB)
+		pollVoteRepo = mysql.NewMySQLPollVoteRepository(mysqlDB)
+		pinnedPostRepo = mysql.NewMySQLPinnedPostRepository(mysqlDB)
+		collectionRepo = mysql.NewMySQLCollectionRepository(mysqlDB)
+	case config.DB_DRIVER_SQLITE:
+		sqliteDB, err = sqlite.NewSQLiteDB(cfg.DB.BuildDSN())
+		if err != nil {
+			fatal("Failed to open SQLite database", err)
+		}
+		defer sqliteDB.Close()
+		slog.Info("SQLite database opened")
+		m.RegisterSQLDB(sqliteDB, cfg.DB.Name)
+		healthChecks = sqlHealthChecks(cfg.DB.Driver, sqliteDB)
+
+		userRepo = sqlite.NewSQLiteUserRepository(sqliteDB)
+		sessionRepo = sqlite.NewSQLiteSessionRepository(sqliteDB)
+		postRepo = sqlite.NewSQLitePostRepository(sqliteDB)
+		favoriteRepo = sqlite.NewSQLiteFavoriteRepository(sqliteDB)
+		likeRepo = sqlite.NewSQLiteLikeRepository(sqliteDB)
+		repostRepo = sqlite.NewSQLiteRepostRepository(sqliteDB)
+		followRepo = sqlite.NewSQLiteFollowRepository(sqliteDB)
+		pollRepo = sqlite.NewSQLitePollRepository(sqliteDB)
+		pollVoteRepo = sqlite.NewSQLitePollVoteRepository(sqliteDB)
+		pinnedPostRepo = sqlite.NewSQLitePinnedPostRepository(sqliteDB)
+		collectionRepo = sqlite.NewSQLiteCollectionRepository(sqliteDB)
 	}
 
-	var cacheClient cache.Cache
-	cacheClient, err = redis.NewRedisCache(ctx, cfg.Redis.Addr(), cfg.Redis.Password, cfg.Redis.DB, cfg.DB.Driver)
+	userRepo = instrumented.NewUserRepository(userRepo, m)
+	sessionRepo = instrumented.NewSessionRepository(sessionRepo, m)
+	postRepo = instrumented.NewPostRepository(postRepo, m)
+	favoriteRepo = instrumented.NewFavoriteRepository(favoriteRepo, m)
+	likeRepo = instrumented.NewLikeRepository(likeRepo, m)
+	repostRepo = instrumented.NewRepostRepository(repostRepo, m)
+	followRepo = instrumented.NewFollowRepository(followRepo, m)
+	pollRepo = instrumented.NewPollRepository(pollRepo, m)
+	pollVoteRepo = instrumented.NewPollVoteRepository(pollVoteRepo, m)
+	pinnedPostRepo = instrumented.NewPinnedPostRepository(pinnedPostRepo, m)
+	collectionRepo = instrumented.NewCollectionRepository(collectionRepo, m)
+
+	redisCache, err := redis.NewRedisCache(ctx, cfg.Redis.Addr(), cfg.Redis.Password, cfg.Redis.DB, cfg.DB.Driver)
 	if err != nil {
-		log.Fatalf("Failed to connect Redis cache: %v", err)
+		fatal("Failed to connect Redis cache", err)
 	}
+	healthChecks = append(healthChecks, http.HealthCheck{Name: "redis", Check: redisCache.Ping})
+	var cacheClient cache.Cache = cache.NewInstrumentedCache(redisCache, m)
 
 	userService := service.NewUserService(userRepo, cacheClient)
 	sessionService := service.NewSessionService(sessionRepo, cacheClient)
-	postService := service.NewPostService(postRepo, cacheClient)
-	favoriteService := service.NewFavoriteService(favoriteRepo, cacheClient)
+	postService := service.NewPostService(postRepo, pollRepo, cacheClient)
+	postService.SetEditWindow(cfg.PostEditWindow)
+	favoriteService := service.NewFavoriteService(favoriteRepo, collectionRepo, cacheClient)
 	likeService := service.NewLikeService(likeRepo, cacheClient)
 	repostService := service.NewRepostService(repostRepo, cacheClient)
 	followService := service.NewFollowService(followRepo, cacheClient)
+	pollService := service.NewPollService(pollRepo, pollVoteRepo, cacheClient)
+	pinnedPostService := service.NewPinnedPostService(pinnedPostRepo, cacheClient)
+	collectionService := service.NewCollectionService(collectionRepo, cacheClient)
 
 	authMiddleware := http.NewAuthMiddleware(sessionService, userService)
+	idempotencyMiddleware := http.NewIdempotencyMiddleware(cacheClient, cfg.IdempotencyTTL)
 
-	userHandler := http.NewUserHandler(userService, sessionService, postService, repostService, followService, favoriteService, authMiddleware)
-	postHandler := http.NewPostHandler(postService, likeService, repostService, favoriteService, sessionService, authMiddleware)
+	userHandler := http.NewUserHandler(userService, sessionService, postService, repostService, followService, favoriteService, authMiddleware, idempotencyMiddleware, m)
+	postHandler := http.NewPostHandler(postService, likeService, repostService, favoriteService, pollService, pinnedPostService, sessionService, authMiddleware, idempotencyMiddleware)
+	collectionHandler := http.NewCollectionHandler(collectionService, favoriteService, postService, authMiddleware, idempotencyMiddleware)
 
-	app := fiber.New()
+	app := fiber.New(fiber.Config{
+		ErrorHandler: http.ErrorHandler,
+		BodyLimit:    http.MaxBodySize,
+	})
+	app.Use(http.RequestLogger(logger))
+	app.Use(http.TracingMiddleware())
+	app.Use(http.MetricsMiddleware(m))
 	app.Use(recover.New())
-	app.Use(logger.New())
 
 	userHandler.RegisterRoutes(app)
 	postHandler.RegisterRoutes(app)
+	collectionHandler.RegisterRoutes(app)
+	http.NewDocsHandler().RegisterRoutes(app)
+	http.NewMetricsHandler(m).RegisterRoutes(app)
+	healthHandler := http.NewHealthHandler(healthChecks...)
+	healthHandler.RegisterRoutes(app)
+
+	// Publish scheduled posts in the background, stopped before the databases close
+	schedulerCtx, stopScheduler := context.WithCancel(logging.NewContext(context.Background(), logger))
+	schedulerDone := make(chan struct{})
+	go func() {
+		defer close(schedulerDone)
+		service.NewPostScheduler(postService, cfg.SchedulerInterval).Run(schedulerCtx)
+	}()
 
 	// Run server on another goroutine such that we can handle graceful shutdown
 	go func() {
-		fmt.Printf("Server running on port %s\n", cfg.AppPort)
+		slog.Info("Server running", "port", cfg.AppPort)
 		if err := app.Listen(":" + cfg.AppPort); err != nil {
-			log.Printf("Fiber server stopped: %v", err)
+			slog.Error("Fiber server stopped", "error", err)
 		}
 	}()
 
@@ -117,30 +226,85 @@ func main() {
 	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
 	<-sigCh
 
-	log.Println("Shutting down server...")
+	slog.Info("Shutting down server...")
+
+	// Load balancers see /readyz fail and stop sending requests before the server stops accepting them
+	healthHandler.SetShuttingDown()
+	slog.Info("Draining connections", "delay", cfg.ShutdownDrainDelay)
+	time.Sleep(cfg.ShutdownDrainDelay)
 
 	if err := app.Shutdown(); err != nil {
-		log.Printf("Error during server shutdown: %v", err)
+		slog.Error("Error during server shutdown", "error", err)
 	}
 
+	stopScheduler()
+	<-schedulerDone
+
 	if pool != nil {
-		log.Println("Closing postgres connection pool...")
+		slog.Info("Closing postgres connection pool...")
 		pool.Close()
 	}
 
 	if mysqlDB != nil {
-		log.Println("Closing MySQL connection...")
+		slog.Info("Closing MySQL connection...")
 		if err := mysqlDB.Close(); err != nil {
-			log.Printf("Error closing MySQL connection: %v", err)
+			slog.Error("Error closing MySQL connection", "error", err)
+		}
+	}
+
+	if sqliteDB != nil {
+		slog.Info("Closing SQLite database...")
+		if err := sqliteDB.Close(); err != nil {
+			slog.Error("Error closing SQLite database", "error", err)
 		}
 	}
 
-	log.Println("Closing cache connection...")
+	slog.Info("Closing cache connection...")
 	if err := cacheClient.Close(); err != nil {
-		log.Printf("Error closing cache connection: %v", err)
+		slog.Error("Error closing cache connection", "error", err)
 	}
 
-	log.Println("Cleanup completed. Exiting application.")
+	slog.Info("Flushing traces...")
+	if err := shutdownTracing(context.Background()); err != nil {
+		slog.Error("Error flushing traces", "error", err)
+	}
+
+	slog.Info("Cleanup completed. Exiting application.")
+
+	slog.Info("Server gracefully stopped.")
+}
+
+// Readiness checks of a database/sql database, the connection and its migration version
+func sqlHealthChecks(driver string, db *sql.DB) []http.HealthCheck {
+	return []http.HealthCheck{
+		{Name: "database", Check: db.PingContext},
+		{Name: "migrations", Check: func(ctx context.Context) error {
+			return migration.CheckVersion(driver, db.QueryRowContext(ctx, migration.VersionQuery))
+		}},
+	}
+}
+
+// Concurrent instances wait on the migration lock, only one of them applies the migrations
+func runMigrations(cfg *config.Config) error {
+	m, err := migration.New(cfg.DB.Driver, cfg.DB.BuildMigrateDSN())
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	if err := m.Up(); err != nil {
+		return err
+	}
+
+	version, _, _, err := m.Version()
+	if err != nil {
+		return err
+	}
+	slog.Info("Database migrated", "version", version)
+	return nil
+}
 
-	log.Println("Server gracefully stopped.")
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
 }
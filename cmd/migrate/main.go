@@ -5,67 +5,171 @@ import (
 	"log"
 	"os"
 	"social-media-go-ddd/internal/application/config"
-
-	"github.com/golang-migrate/migrate/v4"
-	_ "github.com/golang-migrate/migrate/v4/database/mysql"
-	_ "github.com/golang-migrate/migrate/v4/database/postgres"
-	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"social-media-go-ddd/internal/infrastructure/persistence/migration"
+	"strconv"
+	"text/tabwriter"
 )
 
+const usage = `Usage: go run ./cmd/migrate <command> [arg]
+
+Commands:
+  up            apply all pending migrations (default)
+  down [N|all]  roll back N migrations, 1 by default, or all of them
+  steps N       apply N migrations, or roll back if N is negative
+  goto V        migrate up or down to version V
+  force V       set the version to V without running migrations, to recover from a dirty state
+  version       print the current version
+  status        list migrations and whether they are applied
+  create NAME   create empty up and down files for every driver
+
+The database is read from .env, migrations are embedded in the binary.
+Create writes to internal/infrastructure/persistence, set MIGRATIONS_DIR to override.
+`
+
 func main() {
-	// Accept arg "up" or "down", default "up"
+	// Default to "up" such that running without args migrates the database
 	action := "up"
 	if len(os.Args) > 1 {
 		action = os.Args[1]
 	}
-
-	cfg := config.LoadConfig()
-
-	var migrationsPath string
-
-	switch cfg.DB.Driver {
-	case config.DB_DRIVER_PG:
-		migrationsPath = "file://internal/infrastructure/persistence/postgres/migrations"
-	case config.DB_DRIVER_MYSQL:
-		migrationsPath = "file://internal/infrastructure/persistence/mysql/migrations"
-	default:
-		log.Fatalf("Unsupported DB.driver: %s", cfg.DB.Driver)
+	arg := ""
+	if len(os.Args) > 2 {
+		arg = os.Args[2]
 	}
 
-	// DSN compatible with golang-migrate
-	var dsn string
-	switch cfg.DB.Driver {
-	case config.DB_DRIVER_PG:
-		dsn = fmt.Sprintf(
-			"postgres://%s:%s@%s:%s/%s?sslmode=disable",
-			cfg.DB.User, cfg.DB.Password, cfg.DB.Host, cfg.DB.Port, cfg.DB.Name,
-		)
-	case config.DB_DRIVER_MYSQL:
-		dsn = fmt.Sprintf(
-			"mysql://%s:%s@tcp(%s:%s)/%s",
-			cfg.DB.User, cfg.DB.Password, cfg.DB.Host, cfg.DB.Port, cfg.DB.Name,
-		)
+	switch action {
+	case "help", "-h", "--help":
+		fmt.Print(usage)
+		return
+	case "create":
+		create(arg)
+		return
 	}
 
-	fmt.Printf("Equivalent command: migrate -source '%s' -database '%s' '%s'\n", migrationsPath, dsn, action)
+	cfg := config.LoadConfig()
+	if err := cfg.DB.DriverValid(); err != nil {
+		log.Fatalf("Database driver invalid error: %v", err)
+	}
 
-	m, err := migrate.New(migrationsPath, dsn)
+	m, err := migration.New(cfg.DB.Driver, cfg.DB.BuildMigrateDSN())
 	if err != nil {
 		log.Fatalf("Migration init failed: %v", err)
 	}
+	defer m.Close()
 
 	switch action {
 	case "up":
-		if err := m.Up(); err != nil && err != migrate.ErrNoChange {
+		if err := m.Up(); err != nil {
 			log.Fatalf("Migration up failed: %v", err)
 		}
 		fmt.Println("Migrations applied successfully!")
 	case "down":
-		if err := m.Down(); err != nil && err != migrate.ErrNoChange {
+		if arg == "all" {
+			err = m.Down()
+		} else {
+			n := 1
+			if arg != "" {
+				n = parsePositive(arg)
+			}
+			err = m.Steps(-n)
+		}
+		if err != nil {
 			log.Fatalf("Migration down failed: %v", err)
 		}
 		fmt.Println("Rollback applied successfully!")
+	case "steps":
+		n, err := strconv.Atoi(arg)
+		if err != nil || n == 0 {
+			log.Fatalf("steps expects a non zero number, got %q", arg)
+		}
+		if err := m.Steps(n); err != nil {
+			log.Fatalf("Migration steps failed: %v", err)
+		}
+		fmt.Println("Migrations applied successfully!")
+	case "goto":
+		if err := m.Goto(uint(parsePositive(arg))); err != nil {
+			log.Fatalf("Migration goto failed: %v", err)
+		}
+		fmt.Println("Migrations applied successfully!")
+	case "force":
+		v, err := strconv.Atoi(arg)
+		if err != nil || v < -1 {
+			log.Fatalf("force expects a version or -1, got %q", arg)
+		}
+		if err := m.Force(v); err != nil {
+			log.Fatalf("Migration force failed: %v", err)
+		}
+		fmt.Printf("Version forced to %d\n", v)
+	case "version":
+	case "status":
+		printStatus(m)
+		return
 	default:
-		log.Fatalf("Unsupported action: %s. Use 'up' or 'down'", action)
+		log.Fatalf("Unsupported action: %s\n\n%s", action, usage)
+	}
+
+	printVersion(m)
+}
+
+func create(name string) {
+	if name == "" {
+		log.Fatalf("create expects a migration name\n\n%s", usage)
+	}
+
+	dir := os.Getenv("MIGRATIONS_DIR")
+	if dir == "" {
+		dir = "internal/infrastructure/persistence"
+	}
+
+	files, err := migration.Create(dir, name)
+	for _, f := range files {
+		fmt.Println("Created", f)
+	}
+	if err != nil {
+		log.Fatalf("Migration create failed: %v", err)
+	}
+}
+
+func printVersion(m *migration.Migrator) {
+	version, dirty, ok, err := m.Version()
+	if err != nil {
+		log.Fatalf("Failed to read version: %v", err)
+	}
+	if !ok {
+		fmt.Println("Version: none, no migration applied")
+		return
+	}
+	if dirty {
+		fmt.Printf("Version: %d (dirty, fix the database then run force)\n", version)
+		return
+	}
+	fmt.Printf("Version: %d\n", version)
+}
+
+func printStatus(m *migration.Migrator) {
+	statuses, err := m.Status()
+	if err != nil {
+		log.Fatalf("Failed to read status: %v", err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tSTATUS")
+	for _, s := range statuses {
+		status := "pending"
+		if s.Dirty {
+			status = "dirty"
+		} else if s.Applied {
+			status = "applied"
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\n", s.Version, s.Name, status)
+	}
+	w.Flush()
+}
+
+func parsePositive(arg string) int {
+	n, err := strconv.Atoi(arg)
+	if err != nil || n <= 0 {
+		log.Fatalf("expected a positive number, got %q", arg)
 	}
+	return n
 }
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"social-media-go-ddd/internal/application/config"
+	"social-media-go-ddd/internal/infrastructure/persistence/migration"
+	"social-media-go-ddd/internal/infrastructure/persistence/mysql"
+	"social-media-go-ddd/internal/infrastructure/persistence/postgres"
+	"social-media-go-ddd/internal/infrastructure/persistence/sqlite"
+	"social-media-go-ddd/internal/infrastructure/persistence/transfer"
+	"syscall"
+	"text/tabwriter"
+)
+
+func main() {
+	batchSize := flag.Int("batch-size", transfer.DefaultBatchSize, "rows per batch")
+	checkpointPath := flag.String("checkpoint", "transfer-checkpoint.json", "progress file used to resume an interrupted transfer")
+	verifyOnly := flag.Bool("verify-only", false, "only compare row counts and checksums")
+	reset := flag.Bool("reset", false, "ignore the checkpoint and start from the first row")
+	migrateTarget := flag.Bool("migrate", true, "apply pending migrations to the target database first")
+	flag.Parse()
+
+	source, target := config.LoadTransferConfig()
+	if err := source.DriverValid(); err != nil {
+		log.Fatalf("SOURCE_DB_DRIVER invalid: %v", err)
+	}
+	if err := target.DriverValid(); err != nil {
+		log.Fatalf("TARGET_DB_DRIVER invalid: %v", err)
+	}
+
+	// Stop between batches on Ctrl+C, the checkpoint allows resuming later
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// Both schemas must be at the latest version, otherwise the columns may differ
+	if err := checkSchema(source, false); err != nil {
+		log.Fatalf("Source database: %v", err)
+	}
+	if err := checkSchema(target, *migrateTarget); err != nil {
+		log.Fatalf("Target database: %v", err)
+	}
+
+	sourceStore, closeSource, err := openStore(ctx, source)
+	if err != nil {
+		log.Fatalf("Failed to open source database: %v", err)
+	}
+	defer closeSource()
+
+	targetStore, closeTarget, err := openStore(ctx, target)
+	if err != nil {
+		log.Fatalf("Failed to open target database: %v", err)
+	}
+	defer closeTarget()
+
+	checkpoint, err := transfer.LoadCheckpoint(*checkpointPath, source.Fingerprint(), target.Fingerprint(), *reset)
+	if errors.Is(err, transfer.ErrCheckpointMismatch) {
+		log.Fatalf("%v, run with -reset to start over", err)
+	}
+	if err != nil {
+		log.Fatalf("Failed to load checkpoint: %v", err)
+	}
+
+	tr := &transfer.Transfer{
+		Source:     sourceStore,
+		Target:     targetStore,
+		BatchSize:  *batchSize,
+		Checkpoint: checkpoint,
+		Log:        os.Stdout,
+	}
+
+	if !*verifyOnly {
+		fmt.Printf("Transferring %s to %s\n", source.Driver, target.Driver)
+		if err := tr.Run(ctx); err != nil {
+			log.Fatalf("Transfer failed, run again to resume: %v", err)
+		}
+	}
+
+	fmt.Println("Verifying row counts and checksums...")
+	reports, err := tr.Verify(ctx)
+	if err != nil {
+		log.Fatalf("Verification failed: %v", err)
+	}
+
+	ok := printReports(reports)
+	if !ok {
+		log.Fatalf("Source and target differ")
+	}
+
+	// A later transfer starts from scratch
+	if err := checkpoint.Remove(); err != nil {
+		log.Printf("Failed to remove checkpoint: %v", err)
+	}
+	fmt.Println("Transfer verified successfully!")
+}
+
+func checkSchema(cfg config.DBConfig, migrate bool) error {
+	m, err := migration.New(cfg.Driver, cfg.BuildMigrateDSN())
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	if migrate {
+		if err := m.Up(); err != nil {
+			return fmt.Errorf("failed to apply migrations: %w", err)
+		}
+	}
+
+	latest, err := migration.Latest(cfg.Driver)
+	if err != nil {
+		return err
+	}
+	version, dirty, _, err := m.Version()
+	if err != nil {
+		return err
+	}
+	if version != latest || dirty {
+		return fmt.Errorf("schema is at version %d (dirty=%v), expected %d, run cmd/migrate first", version, dirty, latest)
+	}
+	return nil
+}
+
+func openStore(ctx context.Context, cfg config.DBConfig) (transfer.Store, func(), error) {
+	switch cfg.Driver {
+	case config.DB_DRIVER_PG:
+		pool, err := postgres.NewPgPool(ctx, cfg.BuildDSN())
+		if err != nil {
+			return nil, nil, err
+		}
+		return postgres.NewPgTransferStore(pool), pool.Close, nil
+	case config.DB_DRIVER_MYSQL:
+		db, err := mysql.NewMySQLDB(cfg.BuildDSN())
+		if err != nil {
+			return nil, nil, err
+		}
+		return mysql.NewMySQLTransferStore(db), func() { db.Close() }, nil
+	case config.DB_DRIVER_SQLITE:
+		db, err := sqlite.NewSQLiteDB(cfg.BuildDSN())
+		if err != nil {
+			return nil, nil, err
+		}
+		return sqlite.NewSQLiteTransferStore(db), func() { db.Close() }, nil
+	default:
+		return nil, nil, fmt.Errorf("unsupported database driver: %s", cfg.Driver)
+	}
+}
+
+func printReports(reports []transfer.TableReport) bool {
+	ok := true
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TABLE\tSOURCE ROWS\tTARGET ROWS\tCHECKSUM")
+	for _, r := range reports {
+		status := "match"
+		if !r.OK() {
+			status = "MISMATCH"
+			ok = false
+		}
+		fmt.Fprintf(w, "%s\t%d\t%d\t%s\n", r.Table, r.SourceCount, r.TargetCount, status)
+	}
+	w.Flush()
+	return ok
+}
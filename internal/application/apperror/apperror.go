@@ -0,0 +1,152 @@
+// Package apperror is the error taxonomy shared by the services and the http layer.
+// Services return *Error such that the http layer can map them to a status code and a stable error code
+// without knowing about entity or database driver errors.
+package apperror
+
+import (
+	"errors"
+	"fmt"
+)
+
+type Kind string
+
+const (
+	KindValidation         Kind = "validation"
+	KindBadRequest         Kind = "bad_request"
+	KindNotFound           Kind = "not_found"
+	KindConflict           Kind = "conflict"
+	KindForbidden          Kind = "forbidden"
+	KindUnauthorized       Kind = "unauthorized"
+	KindTooLarge           Kind = "too_large"
+	KindPreconditionFailed Kind = "precondition_failed"
+	KindInternal           Kind = "internal"
+)
+
+// Stable codes for errors that are not tied to a resource or a field
+const (
+	CodeValidationFailed = "validation_failed"
+	CodeInvalidBody      = "invalid_body"
+	CodeInvalidInput     = "invalid_input"
+	CodeBodyTooLarge     = "body_too_large"
+	CodeNotFound         = "not_found"
+	CodeConflict         = "conflict"
+	CodeVersionMismatch  = "version_mismatch"
+	CodeInternal         = "internal_error"
+)
+
+// Details of a single invalid field, eg {"field": "content", "code": "content_empty"}
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+type Error struct {
+	Kind Kind
+	// Machine readable, eg "post_not_found", clients may rely on it
+	Code string
+	// Human readable, safe to show to the client
+	Message string
+	Fields  []FieldError
+	// The original error, never shown to the client
+	Err error
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil && e.Err.Error() != e.Message {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func New(kind Kind, code, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message}
+}
+
+func Validation(fields ...FieldError) *Error {
+	message := "validation failed"
+	if len(fields) == 1 {
+		message = fields[0].Message
+	}
+	return &Error{Kind: KindValidation, Code: CodeValidationFailed, Message: message, Fields: fields}
+}
+
+func BadRequest(code string, err error) *Error {
+	return &Error{Kind: KindBadRequest, Code: code, Message: err.Error(), Err: err}
+}
+
+func NotFound(code, message string) *Error {
+	return New(KindNotFound, code, message)
+}
+
+func Conflict(code, message string) *Error {
+	return New(KindConflict, code, message)
+}
+
+func Forbidden(code, message string) *Error {
+	return New(KindForbidden, code, message)
+}
+
+func PreconditionFailed(code, message string) *Error {
+	return New(KindPreconditionFailed, code, message)
+}
+
+func Unauthorized(code, message string) *Error {
+	return New(KindUnauthorized, code, message)
+}
+
+func Internal(err error) *Error {
+	return &Error{Kind: KindInternal, Code: CodeInternal, Message: "internal server error", Err: err}
+}
+
+// Classify any error, *Error is returned as is and unknown errors become internal errors
+func From(err error) *Error {
+	if err == nil {
+		return nil
+	}
+
+	var e *Error
+	if errors.As(err, &e) {
+		return e
+	}
+	if e := domainError(err); e != nil {
+		return e
+	}
+	if field, ok := domainField(err); ok {
+		e := Validation(FieldError{Field: field.Field, Code: field.Code, Message: err.Error()})
+		e.Err = err
+		return e
+	}
+	return fromDriver(err)
+}
+
+// Like From, but not found and conflict errors get a code of the resource, eg "user_not_found" or "user_already_exists"
+func Wrap(err error, resource string) error {
+	if err == nil {
+		return nil
+	}
+
+	e := From(err)
+	switch {
+	case e.Kind == KindNotFound && e.Code == CodeNotFound:
+		return &Error{Kind: e.Kind, Code: resource + "_not_found", Message: resource + " not found", Fields: e.Fields, Err: e.Err}
+	case e.Kind == KindConflict && e.Code == CodeConflict:
+		message := resource + " already exists"
+		if len(e.Fields) == 1 {
+			message = fmt.Sprintf("%s with this %s already exists", resource, e.Fields[0].Field)
+		}
+		return &Error{Kind: e.Kind, Code: resource + "_already_exists", Message: message, Fields: e.Fields, Err: e.Err}
+	}
+	return e
+}
+
+func KindOf(err error) Kind {
+	if err == nil {
+		return ""
+	}
+	return From(err).Kind
+}
@@ -0,0 +1,103 @@
+package apperror
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"social-media-go-ddd/internal/domain/entity"
+	"social-media-go-ddd/internal/domain/repository"
+	"social-media-go-ddd/internal/domain/valueobject"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	_ "modernc.org/sqlite"
+)
+
+func TestFrom(t *testing.T) {
+	tests := []struct {
+		name  string
+		err   error
+		kind  Kind
+		code  string
+		field string
+	}{
+		{"entity error", entity.ErrContentEmpty, KindValidation, CodeValidationFailed, "content"},
+		{"wrapped entity error", fmt.Errorf("create: %w", entity.ErrRepostCommentTooLong), KindValidation, CodeValidationFailed, "comment"},
+		{"password too short", valueobject.ErrPwMinLength(valueobject.PwMinLength), KindValidation, CodeValidationFailed, "password"},
+		{"pgx no rows", pgx.ErrNoRows, KindNotFound, CodeNotFound, ""},
+		{"sql no rows", fmt.Errorf("find: %w", sql.ErrNoRows), KindNotFound, CodeNotFound, ""},
+		{"pg unique", &pgconn.PgError{Code: "23505", TableName: "users", ConstraintName: "users_username_key"}, KindConflict, CodeConflict, "username"},
+		{"pg unique several columns", &pgconn.PgError{Code: "23505", TableName: "reposts", ConstraintName: "reposts_user_id_post_id_key"}, KindConflict, CodeConflict, ""},
+		{"pg invalid uuid", &pgconn.PgError{Code: "22P02"}, KindBadRequest, CodeInvalidInput, ""},
+		{"mysql duplicate", &mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'a@b.c' for key 'users.email'"}, KindConflict, CodeConflict, "email"},
+		{"memory unique", fmt.Errorf("%w: UNIQUE constraint failed: users.username", repository.ErrUniqueViolation), KindConflict, CodeConflict, "username"},
+		{"version mismatch", fmt.Errorf("update: %w", entity.ErrVersionMismatch), KindPreconditionFailed, CodeVersionMismatch, ""},
+		{"memory foreign key", repository.ErrForeignKeyViolation, KindNotFound, CodeNotFound, ""},
+		{"unknown", errors.New("connection refused"), KindInternal, CodeInternal, ""},
+		{"already classified", Forbidden("not_post_owner", "nope"), KindForbidden, "not_post_owner", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := From(tt.err)
+			if e.Kind != tt.kind || e.Code != tt.code {
+				t.Fatalf("From() = %s/%s, want %s/%s", e.Kind, e.Code, tt.kind, tt.code)
+			}
+			field := ""
+			if len(e.Fields) > 0 {
+				field = e.Fields[0].Field
+			}
+			if field != tt.field {
+				t.Fatalf("field = %q, want %q", field, tt.field)
+			}
+			if !errors.Is(e, tt.err) {
+				t.Fatalf("From() does not wrap the original error")
+			}
+		})
+	}
+}
+
+func TestFromSQLiteUnique(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec("CREATE TABLE users (id TEXT PRIMARY KEY, username TEXT UNIQUE)"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec("INSERT INTO users VALUES ('1', 'alice')"); err != nil {
+		t.Fatal(err)
+	}
+	_, err = db.Exec("INSERT INTO users VALUES ('2', 'alice')")
+
+	e := From(err)
+	if e.Kind != KindConflict || len(e.Fields) != 1 || e.Fields[0].Field != "username" {
+		t.Fatalf("From(%v) = %+v, want conflict on username", err, e)
+	}
+}
+
+func TestWrap(t *testing.T) {
+	if err := Wrap(nil, "post"); err != nil {
+		t.Fatalf("Wrap(nil) = %v", err)
+	}
+
+	e := From(Wrap(sql.ErrNoRows, "post"))
+	if e.Kind != KindNotFound || e.Code != "post_not_found" {
+		t.Fatalf("Wrap(no rows) = %s/%s, want not_found/post_not_found", e.Kind, e.Code)
+	}
+
+	e = From(Wrap(fmt.Errorf("%w: UNIQUE constraint failed: users.username", repository.ErrUniqueViolation), "user"))
+	if e.Kind != KindConflict || e.Code != "user_already_exists" || e.Message != "user with this username already exists" {
+		t.Fatalf("Wrap(unique) = %+v", e)
+	}
+
+	// Validation errors keep their code
+	e = From(Wrap(entity.ErrContentEmpty, "post"))
+	if e.Code != CodeValidationFailed {
+		t.Fatalf("Wrap(validation) code = %s", e.Code)
+	}
+}
@@ -0,0 +1,100 @@
+package apperror
+
+import (
+	"errors"
+	"social-media-go-ddd/internal/domain/entity"
+	"social-media-go-ddd/internal/domain/valueobject"
+)
+
+// Entity and value object errors are validation errors of a single field
+var domainFields = []struct {
+	err   error
+	field FieldError
+}{
+	{entity.ErrUsernameEmpty, FieldError{Field: "username", Code: "username_empty"}},
+	{entity.ErrEmailEmpty, FieldError{Field: "email", Code: "email_empty"}},
+	{entity.ErrEmailInvalid, FieldError{Field: "email", Code: "email_invalid"}},
+	{valueobject.ErrPwEmpty, FieldError{Field: "password", Code: "password_empty"}},
+	{valueobject.ErrPwTooShort, FieldError{Field: "password", Code: "password_too_short"}},
+	{valueobject.ErrPwTooLong, FieldError{Field: "password", Code: "password_too_long"}},
+	{entity.ErrContentEmpty, FieldError{Field: "content", Code: "content_empty"}},
+	{entity.ErrContentTooLong, FieldError{Field: "content", Code: "content_too_long"}},
+	{entity.ErrPublishAtEmpty, FieldError{Field: "publish_at", Code: "publish_at_empty"}},
+	{entity.ErrPublishAtInPast, FieldError{Field: "publish_at", Code: "publish_at_past"}},
+	{entity.ErrPostStatusInvalid, FieldError{Field: "status", Code: "status_invalid"}},
+	{entity.ErrPostVisibilityInvalid, FieldError{Field: "visibility", Code: "visibility_invalid"}},
+	{entity.ErrPollChoiceInvalid, FieldError{Field: "poll.choice", Code: "choice_invalid"}},
+	{entity.ErrPollClosesAtEmpty, FieldError{Field: "poll.closes_at", Code: "closes_at_empty"}},
+	{entity.ErrPollClosesAtTooEarly, FieldError{Field: "poll.closes_at", Code: "closes_at_too_early"}},
+	{entity.ErrPollOptionCount, FieldError{Field: "poll.options", Code: "options_count"}},
+	{entity.ErrPollOptionEmpty, FieldError{Field: "poll.options", Code: "option_empty"}},
+	{entity.ErrPollOptionTooLong, FieldError{Field: "poll.options", Code: "option_too_long"}},
+	{entity.ErrPollOptionDuplicate, FieldError{Field: "poll.options", Code: "option_duplicate"}},
+	{entity.ErrPollVoteOptionsEmpty, FieldError{Field: "option_ids", Code: "option_ids_empty"}},
+	{entity.ErrPollVoteSingleChoice, FieldError{Field: "option_ids", Code: "single_choice"}},
+	{entity.ErrPollVoteOptionUnknown, FieldError{Field: "option_ids", Code: "option_unknown"}},
+	{entity.ErrPollVoteOptionRepeated, FieldError{Field: "option_ids", Code: "option_repeated"}},
+	{entity.ErrCollectionNameEmpty, FieldError{Field: "name", Code: "name_empty"}},
+	{entity.ErrCollectionNameTooLong, FieldError{Field: "name", Code: "name_too_long"}},
+	{entity.ErrRepostCommentTooLong, FieldError{Field: "comment", Code: "comment_too_long"}},
+	{entity.ErrLikeReactionInvalid, FieldError{Field: "reaction", Code: "reaction_invalid"}},
+	{entity.ErrFollowSelfFollow, FieldError{Field: "followee_id", Code: "self_follow"}},
+	{entity.ErrUserIDEmpty, FieldError{Field: "user_id", Code: "user_id_empty"}},
+	{entity.ErrLikeUserIDEmpty, FieldError{Field: "user_id", Code: "user_id_empty"}},
+	{entity.ErrFavoriteUserIDEmpty, FieldError{Field: "user_id", Code: "user_id_empty"}},
+	{entity.ErrRepostUserIDEmpty, FieldError{Field: "user_id", Code: "user_id_empty"}},
+	{entity.ErrSessionUserIDEmpty, FieldError{Field: "user_id", Code: "user_id_empty"}},
+	{entity.ErrPollVoteUserIDEmpty, FieldError{Field: "user_id", Code: "user_id_empty"}},
+	{entity.ErrPinUserIDEmpty, FieldError{Field: "user_id", Code: "user_id_empty"}},
+	{entity.ErrCollectionUserIDEmpty, FieldError{Field: "user_id", Code: "user_id_empty"}},
+	{entity.ErrLikePostIDEmpty, FieldError{Field: "post_id", Code: "post_id_empty"}},
+	{entity.ErrFavoritePostIDEmpty, FieldError{Field: "post_id", Code: "post_id_empty"}},
+	{entity.ErrRepostPostIDEmpty, FieldError{Field: "post_id", Code: "post_id_empty"}},
+	{entity.ErrRevisionPostIDEmpty, FieldError{Field: "post_id", Code: "post_id_empty"}},
+	{entity.ErrPollPostIDEmpty, FieldError{Field: "post_id", Code: "post_id_empty"}},
+	{entity.ErrPinPostIDEmpty, FieldError{Field: "post_id", Code: "post_id_empty"}},
+	{entity.ErrFavoriteCollectionIDEmpty, FieldError{Field: "collection_id", Code: "collection_id_empty"}},
+	{entity.ErrPollVotePollIDEmpty, FieldError{Field: "poll_id", Code: "poll_id_empty"}},
+	{entity.ErrRevisionEditorIDEmpty, FieldError{Field: "editor_id", Code: "editor_id_empty"}},
+	{entity.ErrFollowFollowerIDEmpty, FieldError{Field: "follower_id", Code: "follower_id_empty"}},
+	{entity.ErrFollowFolloweeIDEmpty, FieldError{Field: "followee_id", Code: "followee_id_empty"}},
+	{entity.ErrIDEmpty, FieldError{Field: "id", Code: "id_empty"}},
+}
+
+// Entity errors that are not about a single field
+func domainError(err error) *Error {
+	if errors.Is(err, entity.ErrVersionMismatch) {
+		return &Error{Kind: KindPreconditionFailed, Code: CodeVersionMismatch, Message: "modified since it was read", Err: err}
+	}
+	if errors.Is(err, entity.ErrPostAlreadyPublished) {
+		return &Error{Kind: KindConflict, Code: "post_already_published", Message: "post is already published", Err: err}
+	}
+	if errors.Is(err, entity.ErrPollClosed) {
+		return &Error{Kind: KindConflict, Code: "poll_closed", Message: "poll is closed", Err: err}
+	}
+	if errors.Is(err, entity.ErrPinLimitReached) {
+		return &Error{Kind: KindConflict, Code: "pin_limit_reached", Message: "too many pinned posts", Err: err}
+	}
+	if errors.Is(err, entity.ErrPinNotOwnOrRepost) {
+		return &Error{Kind: KindForbidden, Code: "pin_not_allowed", Message: "only own posts and reposts can be pinned", Err: err}
+	}
+	if errors.Is(err, entity.ErrCollectionDefault) {
+		return &Error{Kind: KindConflict, Code: "default_collection", Message: "the default collection cannot be deleted", Err: err}
+	}
+	if errors.Is(err, entity.ErrRepostNotPublic) {
+		return &Error{Kind: KindForbidden, Code: "repost_not_public", Message: "only public posts can be reposted", Err: err}
+	}
+	if errors.Is(err, entity.ErrQuoteNotPublic) {
+		return &Error{Kind: KindForbidden, Code: "quote_not_public", Message: "only public posts can be quoted", Err: err}
+	}
+	return nil
+}
+
+func domainField(err error) (FieldError, bool) {
+	for _, d := range domainFields {
+		if errors.Is(err, d.err) {
+			return d.field, true
+		}
+	}
+	return FieldError{}, false
+}
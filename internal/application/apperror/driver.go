@@ -0,0 +1,111 @@
+package apperror
+
+import (
+	"database/sql"
+	"errors"
+	"regexp"
+	"social-media-go-ddd/internal/domain/repository"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+const (
+	pgUniqueViolation     = "23505"
+	pgForeignKeyViolation = "23503"
+	pgInvalidText         = "22P02"
+
+	mysqlDuplicateEntry    = 1062
+	mysqlNoReferencedRow   = 1452
+	mysqlNoReferencedRowV1 = 1216
+)
+
+var (
+	// "UNIQUE constraint failed: users.username (2067)" in sqlite and the memory backend, constraints over several columns do not match
+	uniqueColumnRe = regexp.MustCompile(`constraint failed: \w+\.(\w+)(?: \(\d+\))?$`)
+	// "Duplicate entry 'alice' for key 'users.username'", MySQL 5.7 omits the table name
+	mysqlKeyRe = regexp.MustCompile(`for key '(?:\w+\.)?(\w+)'`)
+)
+
+// Errors of the persistence backends, anything unknown is an internal error
+func fromDriver(err error) *Error {
+	if errors.Is(err, pgx.ErrNoRows) || errors.Is(err, sql.ErrNoRows) {
+		return &Error{Kind: KindNotFound, Code: CodeNotFound, Message: "not found", Err: err}
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case pgUniqueViolation:
+			return conflict(err, pgConstraintColumn(pgErr))
+		case pgForeignKeyViolation:
+			return &Error{Kind: KindNotFound, Code: CodeNotFound, Message: "referenced resource not found", Err: err}
+		case pgInvalidText:
+			return &Error{Kind: KindBadRequest, Code: CodeInvalidInput, Message: "invalid input", Err: err}
+		}
+	}
+
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		switch mysqlErr.Number {
+		case mysqlDuplicateEntry:
+			return conflict(err, submatch(mysqlKeyRe, mysqlErr.Message))
+		case mysqlNoReferencedRow, mysqlNoReferencedRowV1:
+			return &Error{Kind: KindNotFound, Code: CodeNotFound, Message: "referenced resource not found", Err: err}
+		}
+	}
+
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) {
+		switch sqliteErr.Code() {
+		case sqlite3.SQLITE_CONSTRAINT_UNIQUE, sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY:
+			return conflict(err, submatch(uniqueColumnRe, sqliteErr.Error()))
+		case sqlite3.SQLITE_CONSTRAINT_FOREIGNKEY:
+			return &Error{Kind: KindNotFound, Code: CodeNotFound, Message: "referenced resource not found", Err: err}
+		}
+	}
+
+	switch {
+	case errors.Is(err, repository.ErrUniqueViolation):
+		return conflict(err, submatch(uniqueColumnRe, err.Error()))
+	case errors.Is(err, repository.ErrForeignKeyViolation):
+		return &Error{Kind: KindNotFound, Code: CodeNotFound, Message: "referenced resource not found", Err: err}
+	}
+
+	return Internal(err)
+}
+
+// column is the column of the unique constraint, empty when unknown
+func conflict(err error, column string) *Error {
+	e := &Error{Kind: KindConflict, Code: CodeConflict, Message: "already exists", Err: err}
+	if column != "" && column != "id" && column != "PRIMARY" {
+		e.Fields = []FieldError{{Field: column, Code: "already_exists", Message: column + " already exists"}}
+	}
+	return e
+}
+
+// Postgres names unique constraints <table>_<column>_key, eg users_username_key
+func pgConstraintColumn(pgErr *pgconn.PgError) string {
+	prefix := pgErr.TableName + "_"
+	if pgErr.TableName == "" || !strings.HasPrefix(pgErr.ConstraintName, prefix) || !strings.HasSuffix(pgErr.ConstraintName, "_key") {
+		return ""
+	}
+	name := strings.TrimSuffix(strings.TrimPrefix(pgErr.ConstraintName, prefix), "_key")
+	// Constraint over several columns, eg reposts_user_id_post_id_key
+	if strings.Contains(name, "_id_") {
+		return ""
+	}
+	return name
+}
+
+func submatch(re *regexp.Regexp, s string) string {
+	m := re.FindStringSubmatch(s)
+	if len(m) < 2 {
+		return ""
+	}
+	return m[1]
+}
@@ -4,13 +4,15 @@ import (
 	"fmt"
 	"log"
 	"slices"
+	"time"
 
 	"github.com/spf13/viper"
 )
 
 const (
-	DB_DRIVER_PG    = "postgres"
-	DB_DRIVER_MYSQL = "mysql"
+	DB_DRIVER_PG     = "postgres"
+	DB_DRIVER_MYSQL  = "mysql"
+	DB_DRIVER_SQLITE = "sqlite"
 )
 
 type DBConfig struct {
@@ -19,7 +21,10 @@ type DBConfig struct {
 	Port     string
 	User     string
 	Password string
-	Name     string
+	// Database name, for sqlite this is the path to the database file
+	Name string
+	// Apply pending migrations when the api starts
+	AutoMigrate bool
 }
 
 type RedisCacheConfig struct {
@@ -30,26 +35,72 @@ type RedisCacheConfig struct {
 	DB int
 }
 
+type LogConfig struct {
+	// debug, info, warn or error
+	Level string
+	// json or text
+	Format string
+}
+
+type TraceConfig struct {
+	// none, stdout or otlp
+	Exporter string
+	// Base url of the OTLP/HTTP collector, spans are posted to /v1/traces
+	OTLPEndpoint string
+	ServiceName  string
+}
+
 type Config struct {
 	DB      DBConfig
 	AppPort string
 	Redis   RedisCacheConfig
+	Log     LogConfig
+	Trace   TraceConfig
+	// How long /readyz fails before the server stops, such that load balancers drain the instance first
+	ShutdownDrainDelay time.Duration
+	// How long after its creation a post can be edited, zero means posts can always be edited
+	PostEditWindow time.Duration
+	// How long the response to an Idempotency-Key is kept for retries
+	IdempotencyTTL time.Duration
+	// How often due scheduled posts are published
+	SchedulerInterval time.Duration
 }
 
-func LoadConfig() *Config {
+func readConfigFile() {
 	viper.SetConfigFile(".env")
+	// Environment variables take precedence over .env
+	viper.AutomaticEnv()
 	if err := viper.ReadInConfig(); err != nil {
 		log.Printf("No config file found, reading from environment: %v", err)
 	}
+}
 
-	dbConfig := DBConfig{
-		Driver:   viper.GetString("DB_DRIVER"),
-		Host:     viper.GetString("DB_HOST"),
-		Port:     viper.GetString("DB_PORT"),
-		User:     viper.GetString("DB_USER"),
-		Password: viper.GetString("DB_PASSWORD"),
-		Name:     viper.GetString("DB_NAME"),
+// prefix is empty for the api database, eg "SOURCE_" reads SOURCE_DB_DRIVER, SOURCE_DB_HOST...
+func loadDBConfig(prefix string) DBConfig {
+	return DBConfig{
+		Driver:      viper.GetString(prefix + "DB_DRIVER"),
+		Host:        viper.GetString(prefix + "DB_HOST"),
+		Port:        viper.GetString(prefix + "DB_PORT"),
+		User:        viper.GetString(prefix + "DB_USER"),
+		Password:    viper.GetString(prefix + "DB_PASSWORD"),
+		Name:        viper.GetString(prefix + "DB_NAME"),
+		AutoMigrate: viper.GetBool(prefix + "DB_AUTO_MIGRATE"),
 	}
+}
+
+// Exits on a value that is not a duration or not above zero, viper reads both as 0
+func positiveDuration(key string) time.Duration {
+	d := viper.GetDuration(key)
+	if d <= 0 {
+		log.Fatalf("%s must be a positive duration, eg 30s, got %q", key, viper.GetString(key))
+	}
+	return d
+}
+
+func LoadConfig() *Config {
+	readConfigFile()
+
+	dbConfig := loadDBConfig("")
 
 	appPort := viper.GetString("PORT")
 	if appPort == "" {
@@ -63,15 +114,57 @@ func LoadConfig() *Config {
 		DB:       viper.GetInt("REDIS_DB"),
 	}
 
+	logConfig := LogConfig{
+		Level:  viper.GetString("LOG_LEVEL"),
+		Format: viper.GetString("LOG_FORMAT"),
+	}
+	if logConfig.Level == "" {
+		logConfig.Level = "info"
+	}
+	if logConfig.Format == "" {
+		logConfig.Format = "json"
+	}
+
+	traceConfig := TraceConfig{
+		Exporter:     viper.GetString("TRACE_EXPORTER"),
+		OTLPEndpoint: viper.GetString("OTEL_EXPORTER_OTLP_ENDPOINT"),
+		ServiceName:  viper.GetString("OTEL_SERVICE_NAME"),
+	}
+	if traceConfig.Exporter == "" {
+		traceConfig.Exporter = "none"
+	}
+	if traceConfig.OTLPEndpoint == "" {
+		traceConfig.OTLPEndpoint = "http://localhost:4318"
+	}
+	if traceConfig.ServiceName == "" {
+		traceConfig.ServiceName = "social-media-api"
+	}
+
+	viper.SetDefault("SHUTDOWN_DRAIN_DELAY", "5s")
+	viper.SetDefault("IDEMPOTENCY_TTL", "24h")
+	viper.SetDefault("SCHEDULER_INTERVAL", "30s")
+
 	return &Config{
-		DB:      dbConfig,
-		AppPort: appPort,
-		Redis:   redisConfig,
+		DB:                 dbConfig,
+		AppPort:            appPort,
+		Redis:              redisConfig,
+		Log:                logConfig,
+		Trace:              traceConfig,
+		ShutdownDrainDelay: viper.GetDuration("SHUTDOWN_DRAIN_DELAY"),
+		PostEditWindow:     viper.GetDuration("POST_EDIT_WINDOW"),
+		IdempotencyTTL:     positiveDuration("IDEMPOTENCY_TTL"),
+		SchedulerInterval:  positiveDuration("SCHEDULER_INTERVAL"),
 	}
 }
 
+// Databases of cmd/transfer, SOURCE_DB_* and TARGET_DB_* variables
+func LoadTransferConfig() (source DBConfig, target DBConfig) {
+	readConfigFile()
+	return loadDBConfig("SOURCE_"), loadDBConfig("TARGET_")
+}
+
 func (d *DBConfig) DriverValid() error {
-	drivers := []string{DB_DRIVER_PG, DB_DRIVER_MYSQL}
+	drivers := []string{DB_DRIVER_PG, DB_DRIVER_MYSQL, DB_DRIVER_SQLITE}
 	if !slices.Contains(drivers, d.Driver) {
 		return fmt.Errorf("invalid database driver: %s", d.Driver)
 	}
@@ -87,6 +180,34 @@ func (d *DBConfig) BuildDSN() string {
 	case DB_DRIVER_MYSQL:
 		return fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?parseTime=true",
 			d.User, d.Password, d.Host, d.Port, d.Name)
+	case DB_DRIVER_SQLITE:
+		// foreign keys are off by default in sqlite, and busy_timeout avoids "database is locked" on concurrent writes
+		return fmt.Sprintf("%s?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)", d.Name)
+	default:
+		log.Fatalf("Unsupported DB driver: %s", d.Driver)
+		return ""
+	}
+}
+
+// identifies the database without its password, eg postgres://social@localhost:5432/social_media
+func (d *DBConfig) Fingerprint() string {
+	if d.Driver == DB_DRIVER_SQLITE {
+		return "sqlite://" + d.Name
+	}
+	return fmt.Sprintf("%s://%s@%s:%s/%s", d.Driver, d.User, d.Host, d.Port, d.Name)
+}
+
+// returns a database url compatible with golang-migrate
+func (d *DBConfig) BuildMigrateDSN() string {
+	switch d.Driver {
+	case DB_DRIVER_PG:
+		return fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=disable",
+			d.User, d.Password, d.Host, d.Port, d.Name)
+	case DB_DRIVER_MYSQL:
+		return fmt.Sprintf("mysql://%s:%s@tcp(%s:%s)/%s",
+			d.User, d.Password, d.Host, d.Port, d.Name)
+	case DB_DRIVER_SQLITE:
+		return "sqlite://" + d.BuildDSN()
 	default:
 		log.Fatalf("Unsupported DB driver: %s", d.Driver)
 		return ""
@@ -2,7 +2,11 @@ package http
 
 import (
 	"errors"
+	"log/slog"
+	"social-media-go-ddd/internal/application/apperror"
 	"social-media-go-ddd/internal/application/service"
+	"social-media-go-ddd/internal/domain/entity"
+	"social-media-go-ddd/internal/infrastructure/logging"
 	"strings"
 
 	"github.com/gofiber/fiber/v2"
@@ -42,6 +46,15 @@ func readBearerToken(ctx *fiber.Ctx) (string, error) {
 	return token, nil
 }
 
+// Unknown or malformed tokens are unauthorized, but a database outage stays an internal error
+func invalidSession(err error) error {
+	switch apperror.KindOf(err) {
+	case apperror.KindNotFound, apperror.KindBadRequest:
+		return apperror.Unauthorized("invalid_session", "invalid session")
+	}
+	return err
+}
+
 type AuthMiddlewareService struct {
 	session *service.SessionService
 	user    *service.UserService
@@ -67,25 +80,29 @@ func NewAuthMiddleware(session *service.SessionService, user *service.UserServic
 func (a *AuthMiddleware) Handler(ctx *fiber.Ctx) error {
 	token, err := readBearerToken(ctx)
 	if err != nil {
-		return ErrorResponse(ctx, fiber.StatusUnauthorized, err)
+		return apperror.Unauthorized("invalid_token", err.Error())
 	}
 
-	session, err := a.service.session.GetByID(ctx.Context(), token)
+	session, err := a.service.session.GetByID(ctx.UserContext(), token)
 	if err != nil {
-		return ErrorResponse(ctx, fiber.StatusUnauthorized, errors.New("invalid session"))
+		return invalidSession(err)
 	}
 
 	if session.IsExpired() {
-		return ErrorResponse(ctx, fiber.StatusUnauthorized, errors.New("session expired"))
+		return apperror.Unauthorized("session_expired", entity.ErrSessionExpired.Error())
 	}
 
 	userId := session.UserID.String()
-	user, err := a.service.user.GetByID(ctx.Context(), session.UserID.String(), &userId)
+	user, err := a.service.user.GetByID(ctx.UserContext(), session.UserID.String(), &userId)
 	if err != nil {
-		return ErrorResponse(ctx, fiber.StatusUnauthorized, errors.New("invalid user"))
+		if apperror.KindOf(err) == apperror.KindNotFound {
+			return apperror.Unauthorized("invalid_user", "invalid user")
+		}
+		return err
 	}
 
 	ctx.Locals("session", session)
 	ctx.Locals("user", user)
+	logging.AddAttrs(ctx.UserContext(), slog.String("user_id", userId))
 	return ctx.Next()
 }
@@ -0,0 +1,256 @@
+package http
+
+import (
+	"social-media-go-ddd/internal/application/service"
+	"social-media-go-ddd/internal/domain/aggregate"
+	"social-media-go-ddd/internal/domain/dto"
+	"social-media-go-ddd/internal/domain/entity"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type CollectionHandlerService struct {
+	collection *service.CollectionService
+	favorite   *service.FavoriteService
+	post       *service.PostService
+}
+
+func NewCollectionHandlerService(collection *service.CollectionService, favorite *service.FavoriteService, post *service.PostService) *CollectionHandlerService {
+	return &CollectionHandlerService{
+		collection: collection,
+		favorite:   favorite,
+		post:       post,
+	}
+}
+
+type CollectionHandlerMiddleware struct {
+	auth        *AuthMiddleware
+	idempotency *IdempotencyMiddleware
+}
+
+func NewCollectionHandlerMiddleware(auth *AuthMiddleware, idempotency *IdempotencyMiddleware) *CollectionHandlerMiddleware {
+	return &CollectionHandlerMiddleware{
+		auth:        auth,
+		idempotency: idempotency,
+	}
+}
+
+// Collections are private, every route is about the collections of the current user
+type CollectionHandler struct {
+	service    *CollectionHandlerService
+	middleware *CollectionHandlerMiddleware
+}
+
+func NewCollectionHandler(collectionService *service.CollectionService, favoriteService *service.FavoriteService, postService *service.PostService, authMiddleware *AuthMiddleware, idempotencyMiddleware *IdempotencyMiddleware) *CollectionHandler {
+	return &CollectionHandler{
+		service:    NewCollectionHandlerService(collectionService, favoriteService, postService),
+		middleware: NewCollectionHandlerMiddleware(authMiddleware, idempotencyMiddleware),
+	}
+}
+
+func (h *CollectionHandler) RegisterRoutes(app *fiber.App) {
+	apiCollections := app.Group("/api/v1/collections", h.middleware.auth.Handler, h.middleware.idempotency.Handler)
+	apiCollections.Get("/", h.GetCollections)
+	apiCollections.Post("/", h.CreateCollection)
+	apiCollections.Put("/:id", h.UpdateCollection)
+	apiCollections.Delete("/:id", h.DeleteCollection)
+	apiCollections.Get("/:id/posts", h.GetCollectionPosts)
+	apiCollections.Post("/:id/posts", h.AddCollectionPost)
+	apiCollections.Delete("/:id/posts/:post_id", h.RemoveCollectionPost)
+}
+
+func (h *CollectionHandler) GetCollections(ctx *fiber.Ctx) error {
+	user, err := GetUserFromCtx(ctx)
+	if err != nil {
+		return err
+	}
+
+	collections, err := h.service.collection.GetByUserID(ctx.UserContext(), user.ID.String())
+	if err != nil {
+		return err
+	}
+
+	if collections == nil {
+		collections = []*entity.Collection{}
+	}
+
+	return SuccessResponse(ctx, fiber.Map{
+		"collections": collections,
+	})
+}
+
+func (h *CollectionHandler) CreateCollection(ctx *fiber.Ctx) error {
+	user, err := GetUserFromCtx(ctx)
+	if err != nil {
+		return err
+	}
+
+	var body dto.NewCollection
+	req := bindRequest(ctx)
+	req.Body(&body)
+	if err := req.Err(); err != nil {
+		return err
+	}
+	body.UserID = user.ID
+
+	collection, err := h.service.collection.Create(ctx.UserContext(), body)
+	if err != nil {
+		return err
+	}
+
+	return SuccessResponse(ctx, fiber.Map{
+		"collection": collection,
+	})
+}
+
+// Renames the collection, the default one included
+func (h *CollectionHandler) UpdateCollection(ctx *fiber.Ctx) error {
+	user, err := GetUserFromCtx(ctx)
+	if err != nil {
+		return err
+	}
+
+	req := bindRequest(ctx)
+	id := req.UUIDParam("id").String()
+	var body dto.UpdateCollection
+	req.Body(&body)
+	if err := req.Err(); err != nil {
+		return err
+	}
+	body.UserID = user.ID
+	body.ID = id
+
+	collection, err := h.service.collection.GetByID(ctx.UserContext(), id, user.ID.String())
+	if err != nil {
+		return err
+	}
+
+	updated, err := h.service.collection.Update(ctx.UserContext(), collection, body)
+	if err != nil {
+		return err
+	}
+
+	return SuccessResponse(ctx, fiber.Map{
+		"collection": updated,
+	})
+}
+
+// Deletes the collection, its posts move into the default collection
+func (h *CollectionHandler) DeleteCollection(ctx *fiber.Ctx) error {
+	user, err := GetUserFromCtx(ctx)
+	if err != nil {
+		return err
+	}
+	id, err := idParam(ctx)
+	if err != nil {
+		return err
+	}
+
+	collection, err := h.service.collection.GetByID(ctx.UserContext(), id, user.ID.String())
+	if err != nil {
+		return err
+	}
+
+	if err := h.service.collection.Delete(ctx.UserContext(), collection); err != nil {
+		return err
+	}
+	return SuccessResponse(ctx, nil)
+}
+
+func (h *CollectionHandler) GetCollectionPosts(ctx *fiber.Ctx) error {
+	user, err := GetUserFromCtx(ctx)
+	if err != nil {
+		return err
+	}
+	req := bindRequest(ctx)
+	id := req.UUIDParam("id").String()
+	page := req.CursorPage()
+	if err := req.Err(); err != nil {
+		return err
+	}
+
+	userID := user.ID.String()
+	collection, err := h.service.collection.GetByID(ctx.UserContext(), id, userID)
+	if err != nil {
+		return err
+	}
+
+	posts, next, err := h.service.favorite.GetByCollectionID(ctx.UserContext(), collection.ID.String(), &userID, page)
+	if err != nil {
+		return err
+	}
+
+	if posts == nil {
+		posts = []*aggregate.Post{}
+	}
+
+	return SuccessResponse(ctx, fiber.Map{
+		"posts":      posts,
+		"pagination": newCursorPagination(page, next),
+	})
+}
+
+// Favorites the post in the collection, a post favorited before moves there from its other collection
+func (h *CollectionHandler) AddCollectionPost(ctx *fiber.Ctx) error {
+	user, err := GetUserFromCtx(ctx)
+	if err != nil {
+		return err
+	}
+
+	req := bindRequest(ctx)
+	id := req.UUIDParam("id").String()
+	var body dto.NewFavorite
+	req.Body(&body)
+	if err := req.Err(); err != nil {
+		return err
+	}
+
+	userId := user.ID.String()
+	collection, err := h.service.collection.GetByID(ctx.UserContext(), id, userId)
+	if err != nil {
+		return err
+	}
+	post, err := h.service.post.GetByID(ctx.UserContext(), body.PostID.String(), &userId)
+	if err != nil {
+		return err
+	}
+	if !post.IsPublished() {
+		return errPostNotPublished()
+	}
+
+	_, err = h.service.favorite.Create(ctx.UserContext(), dto.NewFavorite{
+		UserID:       user.ID,
+		PostID:       post.ID,
+		CollectionID: collection.ID,
+	})
+	if err != nil {
+		return err
+	}
+
+	return SuccessResponse(ctx, nil)
+}
+
+// Removes the post from the collection and with it from the favorites of the user
+func (h *CollectionHandler) RemoveCollectionPost(ctx *fiber.Ctx) error {
+	user, err := GetUserFromCtx(ctx)
+	if err != nil {
+		return err
+	}
+
+	req := bindRequest(ctx)
+	id := req.UUIDParam("id").String()
+	postID := req.UUIDParam("post_id").String()
+	if err := req.Err(); err != nil {
+		return err
+	}
+
+	collection, err := h.service.collection.GetByID(ctx.UserContext(), id, user.ID.String())
+	if err != nil {
+		return err
+	}
+
+	if err := h.service.favorite.DeleteFromCollection(ctx.UserContext(), collection.ID.String(), postID); err != nil {
+		return err
+	}
+	return SuccessResponse(ctx, nil)
+}
@@ -2,6 +2,7 @@ package http
 
 import (
 	"errors"
+	"social-media-go-ddd/internal/application/apperror"
 	"social-media-go-ddd/internal/domain/aggregate"
 	"social-media-go-ddd/internal/domain/entity"
 
@@ -12,7 +13,7 @@ import (
 func GetUserFromCtx(c *fiber.Ctx) (*aggregate.User, error) {
 	user := c.Locals("user")
 	if user == nil {
-		return nil, errors.New("user not found in context")
+		return nil, apperror.Unauthorized("unauthorized", "user not found in context")
 	}
 
 	u, ok := user.(*aggregate.User)
@@ -27,7 +28,7 @@ func GetUserFromCtx(c *fiber.Ctx) (*aggregate.User, error) {
 func GetSessionFromCtx(c *fiber.Ctx) (*entity.Session, error) {
 	session := c.Locals("session")
 	if session == nil {
-		return nil, errors.New("session not found in context")
+		return nil, apperror.Unauthorized("unauthorized", "session not found in context")
 	}
 
 	s, ok := session.(*entity.Session)
@@ -37,3 +38,26 @@ func GetSessionFromCtx(c *fiber.Ctx) (*entity.Session, error) {
 
 	return s, nil
 }
+
+// Route of requests no route matched, such that unknown paths do not create a metric series or span name each
+const unmatchedRoute = "unmatched"
+
+// Writes the response of err now such that middlewares see the status sent, requests no route matched are marked as such
+func handleError(ctx *fiber.Ctx, err error) {
+	var fiberErr *fiber.Error
+	// Only fiber itself returns a *fiber.Error, for unknown routes and methods
+	if errors.As(err, &fiberErr) && (fiberErr.Code == fiber.StatusNotFound || fiberErr.Code == fiber.StatusMethodNotAllowed) {
+		ctx.Locals("unmatched", true)
+	}
+	if err := ctx.App().ErrorHandler(ctx, err); err != nil {
+		_ = ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+}
+
+// Template of the matched route, eg /api/v1/posts/:id, only known once the request went through the router
+func routeTemplate(ctx *fiber.Ctx) string {
+	if unmatched, _ := ctx.Locals("unmatched").(bool); unmatched {
+		return unmatchedRoute
+	}
+	return ctx.Route().Path
+}
@@ -0,0 +1,62 @@
+package http
+
+import (
+	"errors"
+	"social-media-go-ddd/internal/application/apperror"
+	"social-media-go-ddd/internal/infrastructure/logging"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+func statusFromKind(kind apperror.Kind) int {
+	switch kind {
+	case apperror.KindValidation:
+		return fiber.StatusUnprocessableEntity
+	case apperror.KindBadRequest:
+		return fiber.StatusBadRequest
+	case apperror.KindNotFound:
+		return fiber.StatusNotFound
+	case apperror.KindConflict:
+		return fiber.StatusConflict
+	case apperror.KindForbidden:
+		return fiber.StatusForbidden
+	case apperror.KindUnauthorized:
+		return fiber.StatusUnauthorized
+	case apperror.KindTooLarge:
+		return fiber.StatusRequestEntityTooLarge
+	case apperror.KindPreconditionFailed:
+		return fiber.StatusPreconditionFailed
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
+
+// Set as fiber.Config.ErrorHandler, handlers return errors and this maps them to the error response
+func ErrorHandler(ctx *fiber.Ctx, err error) error {
+	// Errors of fiber itself, eg unknown route or method not allowed
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) && fiberErr.Code == fiber.StatusRequestEntityTooLarge {
+		// A body over fiber.Config.BodyLimit, rejected before any handler ran
+		err = errBodyTooLarge()
+	} else if errors.As(err, &fiberErr) {
+		resp := NewResponse(false, "Request failed", nil, fiberErr.Code, fiberErr.Message)
+		resp.Code = strings.ReplaceAll(strings.ToLower(utils.StatusMessage(fiberErr.Code)), " ", "_")
+		return ctx.Status(fiberErr.Code).JSON(resp)
+	}
+
+	e := apperror.From(err)
+	status := statusFromKind(e.Kind)
+	if status == fiber.StatusInternalServerError {
+		// The cause may contain sql or connection details, only log it
+		logging.FromContext(ctx.UserContext()).Error("internal error", "error", err)
+	}
+
+	resp := NewResponse(false, "Request failed", nil, status, e.Message)
+	resp.Code = e.Code
+	if len(e.Fields) > 0 {
+		resp.Details = e.Fields
+	}
+	return ctx.Status(status).JSON(resp)
+}
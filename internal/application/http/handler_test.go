@@ -0,0 +1,1268 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net"
+	nethttp "net/http"
+	"net/http/httptest"
+	"reflect"
+	"slices"
+	"social-media-go-ddd/internal/application/service"
+	"social-media-go-ddd/internal/domain/entity"
+	"social-media-go-ddd/internal/infrastructure/cache"
+	cachememory "social-media-go-ddd/internal/infrastructure/cache/memory"
+	"social-media-go-ddd/internal/infrastructure/metrics"
+	"social-media-go-ddd/internal/infrastructure/persistence/instrumented"
+	"social-media-go-ddd/internal/infrastructure/persistence/memory"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+func newTestApp(t *testing.T) *fiber.App {
+	t.Helper()
+	return newTestAppWith(t, slog.New(slog.DiscardHandler), metrics.NewMetrics())
+}
+
+// App wired like cmd/api with the memory backend, logging to logger and recording into m
+func newTestAppWith(t *testing.T, logger *slog.Logger, m *metrics.Metrics) *fiber.App {
+	t.Helper()
+
+	store := memory.NewStore()
+	c := cache.NewInstrumentedCache(cachememory.NewMemoryCache(), m)
+
+	userService := service.NewUserService(instrumented.NewUserRepository(memory.NewMemoryUserRepository(store), m), c)
+	sessionService := service.NewSessionService(instrumented.NewSessionRepository(memory.NewMemorySessionRepository(store), m), c)
+	postService := service.NewPostService(instrumented.NewPostRepository(memory.NewMemoryPostRepository(store), m), instrumented.NewPollRepository(memory.NewMemoryPollRepository(store), m), c)
+	favoriteService := service.NewFavoriteService(instrumented.NewFavoriteRepository(memory.NewMemoryFavoriteRepository(store), m), instrumented.NewCollectionRepository(memory.NewMemoryCollectionRepository(store), m), c)
+	likeService := service.NewLikeService(instrumented.NewLikeRepository(memory.NewMemoryLikeRepository(store), m), c)
+	repostService := service.NewRepostService(instrumented.NewRepostRepository(memory.NewMemoryRepostRepository(store), m), c)
+	followService := service.NewFollowService(instrumented.NewFollowRepository(memory.NewMemoryFollowRepository(store), m), c)
+	pollService := service.NewPollService(instrumented.NewPollRepository(memory.NewMemoryPollRepository(store), m), instrumented.NewPollVoteRepository(memory.NewMemoryPollVoteRepository(store), m), c)
+	pinnedPostService := service.NewPinnedPostService(instrumented.NewPinnedPostRepository(memory.NewMemoryPinnedPostRepository(store), m), c)
+	collectionService := service.NewCollectionService(instrumented.NewCollectionRepository(memory.NewMemoryCollectionRepository(store), m), c)
+
+	authMiddleware := NewAuthMiddleware(sessionService, userService)
+	idempotencyMiddleware := NewIdempotencyMiddleware(c, time.Hour)
+
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler, BodyLimit: MaxBodySize})
+	app.Use(RequestLogger(logger))
+	app.Use(TracingMiddleware())
+	app.Use(MetricsMiddleware(m))
+	NewUserHandler(userService, sessionService, postService, repostService, followService, favoriteService, authMiddleware, idempotencyMiddleware, m).RegisterRoutes(app)
+	NewPostHandler(postService, likeService, repostService, favoriteService, pollService, pinnedPostService, sessionService, authMiddleware, idempotencyMiddleware).RegisterRoutes(app)
+	NewCollectionHandler(collectionService, favoriteService, postService, authMiddleware, idempotencyMiddleware).RegisterRoutes(app)
+	return app
+}
+
+type testResponse struct {
+	Success bool            `json:"success"`
+	Status  int             `json:"status"`
+	Data    json.RawMessage `json:"data"`
+	Error   any             `json:"error"`
+	Code    string          `json:"code"`
+	Details []struct {
+		Field string `json:"field"`
+		Code  string `json:"code"`
+	} `json:"details"`
+}
+
+func doRawRequest(t *testing.T, app *fiber.App, method, path, token string, body any) (int, []byte) {
+	t.Helper()
+
+	var reader io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			t.Fatalf("marshal body: %v", err)
+		}
+		reader = bytes.NewReader(b)
+	}
+
+	req := httptest.NewRequest(method, path, reader)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := app.Test(req, -1)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("read %s %s: %v", method, path, err)
+	}
+	return resp.StatusCode, b
+}
+
+func doRequest(t *testing.T, app *fiber.App, method, path, token string, body any) (int, testResponse) {
+	t.Helper()
+
+	status, b := doRawRequest(t, app, method, path, token, body)
+	var out testResponse
+	if err := json.Unmarshal(b, &out); err != nil {
+		t.Fatalf("decode %s %s: %v", method, path, err)
+	}
+	return status, out
+}
+
+// register and login, returns the session token
+func registerAndLogin(t *testing.T, app *fiber.App, username string) string {
+	t.Helper()
+
+	status, _ := doRequest(t, app, nethttp.MethodPost, "/api/v1/auth/register", "", fiber.Map{
+		"username": username,
+		"password": "password123",
+		"email":    username + "@example.com",
+	})
+	if status != fiber.StatusOK {
+		t.Fatalf("register %s: status %d", username, status)
+	}
+
+	status, resp := doRequest(t, app, nethttp.MethodPost, "/api/v1/auth/login", "", fiber.Map{
+		"username": username,
+		"password": "password123",
+	})
+	if status != fiber.StatusOK {
+		t.Fatalf("login %s: status %d", username, status)
+	}
+
+	var data struct {
+		Session struct {
+			ID string `json:"id"`
+		} `json:"session"`
+	}
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		t.Fatalf("decode login: %v", err)
+	}
+	return data.Session.ID
+}
+
+// Id of the user the token belongs to
+func currentUserID(t *testing.T, app *fiber.App, token string) string {
+	t.Helper()
+
+	status, resp := doRequest(t, app, nethttp.MethodGet, "/api/v1/users/me", token, nil)
+	var me struct {
+		User struct {
+			ID string `json:"id"`
+		} `json:"user"`
+	}
+	if err := json.Unmarshal(resp.Data, &me); err != nil || status != fiber.StatusOK {
+		t.Fatalf("me: status %d err %v", status, err)
+	}
+	return me.User.ID
+}
+
+// Fields of a created post the tests look at
+type createdPost struct {
+	ID           string `json:"id"`
+	Status       string `json:"status"`
+	Visibility   string `json:"visibility"`
+	QuotedPostID string `json:"quotedPostId"`
+}
+
+// Creates a post as the user the token belongs to, failing the test unless it is created
+func createPost(t *testing.T, app *fiber.App, token string, body fiber.Map) createdPost {
+	t.Helper()
+
+	status, resp := doRequest(t, app, nethttp.MethodPost, "/api/v1/posts", token, body)
+	var created struct {
+		Post createdPost `json:"post"`
+	}
+	if err := json.Unmarshal(resp.Data, &created); err != nil || status != fiber.StatusOK {
+		t.Fatalf("create post: status %d err %v", status, err)
+	}
+	return created.Post
+}
+
+func TestAuthFlow(t *testing.T) {
+	app := newTestApp(t)
+	token := registerAndLogin(t, app, "alice")
+
+	status, _ := doRequest(t, app, nethttp.MethodGet, "/api/v1/users/me", token, nil)
+	if status != fiber.StatusOK {
+		t.Fatalf("me: status %d", status)
+	}
+
+	status, _ = doRequest(t, app, nethttp.MethodGet, "/api/v1/users/me", "", nil)
+	if status != fiber.StatusUnauthorized {
+		t.Fatalf("me without token: status %d, want %d", status, fiber.StatusUnauthorized)
+	}
+
+	status, _ = doRequest(t, app, nethttp.MethodDelete, "/api/v1/auth/logout", token, nil)
+	if status != fiber.StatusOK {
+		t.Fatalf("logout: status %d", status)
+	}
+
+	status, _ = doRequest(t, app, nethttp.MethodGet, "/api/v1/users/me", token, nil)
+	if status != fiber.StatusUnauthorized {
+		t.Fatalf("me after logout: status %d, want %d", status, fiber.StatusUnauthorized)
+	}
+}
+
+func TestPostFlow(t *testing.T) {
+	app := newTestApp(t)
+	alice := registerAndLogin(t, app, "alice")
+	bob := registerAndLogin(t, app, "bob")
+
+	created := createPost(t, app, alice, fiber.Map{"content": "hello"})
+	postPath := "/api/v1/posts/" + created.ID
+
+	status, _ := doRequest(t, app, nethttp.MethodPut, postPath, bob, fiber.Map{"content": "hijacked"})
+	if status != fiber.StatusForbidden {
+		t.Fatalf("update someone else's post: status %d, want %d", status, fiber.StatusForbidden)
+	}
+
+	status, _ = doRequest(t, app, nethttp.MethodPost, postPath+"/like", bob, nil)
+	if status != fiber.StatusOK {
+		t.Fatalf("like: status %d", status)
+	}
+
+	status, resp := doRequest(t, app, nethttp.MethodGet, "/api/v1/public/posts/"+created.ID, bob, nil)
+	if status != fiber.StatusOK {
+		t.Fatalf("get post: status %d", status)
+	}
+	var got struct {
+		Post struct {
+			Liked     bool `json:"liked"`
+			LikeCount int  `json:"likeCount"`
+		} `json:"post"`
+	}
+	if err := json.Unmarshal(resp.Data, &got); err != nil {
+		t.Fatalf("decode post: %v", err)
+	}
+	if !got.Post.Liked || got.Post.LikeCount != 1 {
+		t.Fatalf("post = %+v, want liked by bob with 1 like", got.Post)
+	}
+
+	status, _ = doRequest(t, app, nethttp.MethodDelete, postPath, alice, nil)
+	if status != fiber.StatusOK {
+		t.Fatalf("delete post: status %d", status)
+	}
+
+	status, _ = doRequest(t, app, nethttp.MethodGet, "/api/v1/public/posts/"+created.ID, "", nil)
+	if status != fiber.StatusNotFound {
+		t.Fatalf("get deleted post: status %d, want %d", status, fiber.StatusNotFound)
+	}
+}
+
+func TestPostRevisions(t *testing.T) {
+	app := newTestApp(t)
+	alice := registerAndLogin(t, app, "alice")
+
+	created := createPost(t, app, alice, fiber.Map{"content": "first"})
+	for _, content := range []string{"second", "third"} {
+		if status, _ := doRequest(t, app, nethttp.MethodPut, "/api/v1/posts/"+created.ID, alice, fiber.Map{"content": content}); status != fiber.StatusOK {
+			t.Fatalf("update post: status %d", status)
+		}
+	}
+
+	status, resp := doRequest(t, app, nethttp.MethodGet, "/api/v1/public/posts/"+created.ID, "", nil)
+	if status != fiber.StatusOK {
+		t.Fatalf("get post: status %d", status)
+	}
+	var got struct {
+		Post struct {
+			Content   string `json:"content"`
+			Edited    bool   `json:"edited"`
+			EditCount int    `json:"editCount"`
+		} `json:"post"`
+	}
+	if err := json.Unmarshal(resp.Data, &got); err != nil {
+		t.Fatalf("decode post: %v", err)
+	}
+	if got.Post.Content != "third" || !got.Post.Edited || got.Post.EditCount != 2 {
+		t.Fatalf("post = %+v, want third with two edits", got.Post)
+	}
+
+	status, resp = doRequest(t, app, nethttp.MethodGet, "/api/v1/public/posts/"+created.ID+"/revisions", "", nil)
+	if status != fiber.StatusOK {
+		t.Fatalf("get revisions: status %d", status)
+	}
+	var revisions struct {
+		Revisions []struct {
+			Content string `json:"content"`
+		} `json:"revisions"`
+	}
+	if err := json.Unmarshal(resp.Data, &revisions); err != nil {
+		t.Fatalf("decode revisions: %v", err)
+	}
+	if len(revisions.Revisions) != 2 || revisions.Revisions[0].Content != "second" || revisions.Revisions[1].Content != "first" {
+		t.Fatalf("revisions = %+v, want second then first", revisions.Revisions)
+	}
+
+	status, _ = doRequest(t, app, nethttp.MethodGet, "/api/v1/public/posts/"+uuid.NewString()+"/revisions", "", nil)
+	if status != fiber.StatusNotFound {
+		t.Fatalf("revisions of unknown post: status %d, want %d", status, fiber.StatusNotFound)
+	}
+}
+
+func TestUpdatePostIfMatch(t *testing.T) {
+	app := newTestApp(t)
+	alice := registerAndLogin(t, app, "alice")
+
+	created := createPost(t, app, alice, fiber.Map{"content": "first"})
+
+	send := func(method, path, ifMatch string, body any) (*nethttp.Response, testResponse) {
+		t.Helper()
+		var reader io.Reader
+		if body != nil {
+			b, _ := json.Marshal(body)
+			reader = bytes.NewReader(b)
+		}
+		req := httptest.NewRequest(method, path, reader)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+alice)
+		if ifMatch != "" {
+			req.Header.Set("If-Match", ifMatch)
+		}
+		res, err := app.Test(req, -1)
+		if err != nil {
+			t.Fatalf("%s %s: %v", method, path, err)
+		}
+		defer res.Body.Close()
+		var out testResponse
+		if err := json.NewDecoder(res.Body).Decode(&out); err != nil {
+			t.Fatalf("decode %s %s: %v", method, path, err)
+		}
+		return res, out
+	}
+	postPath := "/api/v1/posts/" + created.ID
+
+	res, _ := send(nethttp.MethodGet, "/api/v1/public/posts/"+created.ID, "", nil)
+	etag := res.Header.Get("ETag")
+	if etag != `"1"` {
+		t.Fatalf("ETag of a new post = %q, want %q", etag, `"1"`)
+	}
+
+	res, _ = send(nethttp.MethodPut, postPath, etag, fiber.Map{"content": "second"})
+	if res.StatusCode != fiber.StatusOK || res.Header.Get("ETag") != `"2"` {
+		t.Fatalf("update with current ETag: status %d ETag %q, want 200 with %q", res.StatusCode, res.Header.Get("ETag"), `"2"`)
+	}
+
+	// The first ETag is stale now
+	res, out := send(nethttp.MethodPut, postPath, etag, fiber.Map{"content": "lost update"})
+	if res.StatusCode != fiber.StatusPreconditionFailed || out.Code != "version_mismatch" {
+		t.Fatalf("update with stale ETag: status %d code %q, want 412 version_mismatch", res.StatusCode, out.Code)
+	}
+	for _, header := range []string{`W/"2"`, `"7", "8"`} {
+		if res, _ := send(nethttp.MethodPut, postPath, header, fiber.Map{"content": "lost update"}); res.StatusCode != fiber.StatusPreconditionFailed {
+			t.Fatalf("update with If-Match %s: status %d, want 412", header, res.StatusCode)
+		}
+	}
+
+	for _, header := range []string{`"1", "2"`, "*", ""} {
+		if res, _ := send(nethttp.MethodPut, postPath, header, fiber.Map{"content": "edit " + header}); res.StatusCode != fiber.StatusOK {
+			t.Fatalf("update with If-Match %q: status %d, want 200", header, res.StatusCode)
+		}
+	}
+
+	res, out = send(nethttp.MethodGet, "/api/v1/public/posts/"+created.ID, "", nil)
+	var got struct {
+		Post struct {
+			Content string `json:"content"`
+			Version int    `json:"version"`
+		} `json:"post"`
+	}
+	if err := json.Unmarshal(out.Data, &got); err != nil {
+		t.Fatalf("decode post: %v", err)
+	}
+	if got.Post.Content != "edit" || got.Post.Version != 5 || res.Header.Get("ETag") != `"5"` {
+		t.Fatalf("post = %+v ETag %q, want the last edit at version 5", got.Post, res.Header.Get("ETag"))
+	}
+}
+
+func TestPostDrafts(t *testing.T) {
+	app := newTestApp(t)
+	alice := registerAndLogin(t, app, "alice")
+	bob := registerAndLogin(t, app, "bob")
+
+	aliceID := currentUserID(t, app, alice)
+	doRequest(t, app, nethttp.MethodPost, "/api/v1/users/"+aliceID+"/follow", bob, nil)
+
+	draft := createPost(t, app, alice, fiber.Map{"content": "not yet", "draft": true})
+	if draft.Status != "draft" {
+		t.Fatalf("created post %+v, want a draft", draft)
+	}
+	postPath := "/api/v1/posts/" + draft.ID
+
+	count := func(path, token, key string) int {
+		t.Helper()
+		status, resp := doRequest(t, app, nethttp.MethodGet, path, token, nil)
+		if status != fiber.StatusOK {
+			t.Fatalf("%s: status %d", path, status)
+		}
+		var got map[string][]json.RawMessage
+		_ = json.Unmarshal(resp.Data, &got)
+		return len(got[key])
+	}
+
+	// Only alice knows about the draft
+	if status, _ := doRequest(t, app, nethttp.MethodGet, "/api/v1/public/posts/"+draft.ID, bob, nil); status != fiber.StatusNotFound {
+		t.Fatalf("draft as another user: status %d, want 404", status)
+	}
+	if status, _ := doRequest(t, app, nethttp.MethodGet, "/api/v1/public/posts/"+draft.ID, alice, nil); status != fiber.StatusOK {
+		t.Fatalf("draft as its author: status %d, want 200", status)
+	}
+	if n := count("/api/v1/users/me/feed", bob, "feed"); n != 0 {
+		t.Fatalf("feed of a follower holds %d posts, want the draft left out", n)
+	}
+	if n := count("/api/v1/public/users/"+aliceID+"/posts", alice, "posts"); n != 0 {
+		t.Fatalf("profile holds %d posts, want the draft left out", n)
+	}
+	if n := count("/api/v1/users/me/drafts", alice, "posts"); n != 1 {
+		t.Fatalf("drafts hold %d posts, want 1", n)
+	}
+	status, resp := doRequest(t, app, nethttp.MethodPost, postPath+"/like", alice, nil)
+	if status != fiber.StatusConflict || resp.Code != "post_not_published" {
+		t.Fatalf("like a draft: status %d code %q, want 409 post_not_published", status, resp.Code)
+	}
+
+	// Editing a draft keeps no revisions
+	if status, _ := doRequest(t, app, nethttp.MethodPut, postPath, alice, fiber.Map{"content": "now"}); status != fiber.StatusOK {
+		t.Fatalf("update draft: status %d", status)
+	}
+	if status, _ := doRequest(t, app, nethttp.MethodPost, postPath+"/publish", bob, nil); status != fiber.StatusNotFound {
+		t.Fatalf("publish the draft of another user: status %d, want 404", status)
+	}
+	if status, _ := doRequest(t, app, nethttp.MethodPost, postPath+"/publish", alice, nil); status != fiber.StatusOK {
+		t.Fatalf("publish draft: status %d", status)
+	}
+	status, resp = doRequest(t, app, nethttp.MethodPost, postPath+"/publish", alice, nil)
+	if status != fiber.StatusConflict || resp.Code != "post_already_published" {
+		t.Fatalf("publish again: status %d code %q, want 409 post_already_published", status, resp.Code)
+	}
+
+	status, resp = doRequest(t, app, nethttp.MethodGet, "/api/v1/public/posts/"+draft.ID, bob, nil)
+	var got struct {
+		Post struct {
+			Status    string `json:"status"`
+			EditCount int    `json:"editCount"`
+		} `json:"post"`
+	}
+	if err := json.Unmarshal(resp.Data, &got); err != nil || status != fiber.StatusOK {
+		t.Fatalf("published post as another user: status %d err %v", status, err)
+	}
+	if got.Post.Status != "published" || got.Post.EditCount != 0 {
+		t.Fatalf("published post = %+v, want published without edits", got.Post)
+	}
+	if n := count("/api/v1/users/me/feed", bob, "feed"); n != 1 {
+		t.Fatalf("feed of a follower holds %d posts, want the published post", n)
+	}
+	if n := count("/api/v1/users/me/drafts", alice, "posts"); n != 0 {
+		t.Fatalf("drafts hold %d posts after publishing, want 0", n)
+	}
+
+	status, resp = doRequest(t, app, nethttp.MethodPost, "/api/v1/posts", alice, fiber.Map{"content": "too late", "publish_at": time.Now().Add(-time.Minute)})
+	if status != fiber.StatusUnprocessableEntity || len(resp.Details) != 1 || resp.Details[0].Code != "publish_at_past" {
+		t.Fatalf("schedule in the past: status %d details %+v, want 422 publish_at_past", status, resp.Details)
+	}
+}
+
+func TestPostVisibility(t *testing.T) {
+	app := newTestApp(t)
+	alice := registerAndLogin(t, app, "alice")
+	bob := registerAndLogin(t, app, "bob")
+	carol := registerAndLogin(t, app, "carol")
+
+	aliceID := currentUserID(t, app, alice)
+	doRequest(t, app, nethttp.MethodPost, "/api/v1/users/"+aliceID+"/follow", bob, nil)
+
+	create := func(body fiber.Map) string {
+		t.Helper()
+		created := createPost(t, app, alice, body)
+		if want, ok := body["visibility"]; ok && created.Visibility != want {
+			t.Fatalf("created post visibility %q, want %q", created.Visibility, want)
+		}
+		return created.ID
+	}
+	followers := create(fiber.Map{"content": "followers only", "visibility": "followers"})
+	mentioned := create(fiber.Map{"content": "hey @carol", "visibility": "mentioned"})
+
+	cases := []struct {
+		name   string
+		id     string
+		token  string
+		status int
+	}{
+		{"followers only as anonymous", followers, "", fiber.StatusNotFound},
+		{"followers only as the author", followers, alice, fiber.StatusOK},
+		{"followers only as a follower", followers, bob, fiber.StatusOK},
+		{"followers only as a stranger", followers, carol, fiber.StatusNotFound},
+		{"mentioned as a follower", mentioned, bob, fiber.StatusNotFound},
+		{"mentioned as the mentioned user", mentioned, carol, fiber.StatusOK},
+	}
+	for _, c := range cases {
+		if status, _ := doRequest(t, app, nethttp.MethodGet, "/api/v1/public/posts/"+c.id, c.token, nil); status != c.status {
+			t.Fatalf("%s: status %d, want %d", c.name, status, c.status)
+		}
+	}
+
+	// A post the user may not see is unknown to them, one they see but is not public stays with its audience
+	if status, _ := doRequest(t, app, nethttp.MethodPost, "/api/v1/posts/"+followers+"/like", carol, nil); status != fiber.StatusNotFound {
+		t.Fatalf("like a hidden post: status %d, want 404", status)
+	}
+	status, resp := doRequest(t, app, nethttp.MethodPost, "/api/v1/posts/"+followers+"/repost", bob, nil)
+	if status != fiber.StatusForbidden || resp.Code != "repost_not_public" {
+		t.Fatalf("repost a followers only post: status %d code %q, want 403 repost_not_public", status, resp.Code)
+	}
+
+	status, resp = doRequest(t, app, nethttp.MethodPost, "/api/v1/posts", alice, fiber.Map{"content": "hi", "visibility": "friends"})
+	if status != fiber.StatusUnprocessableEntity || len(resp.Details) != 1 || resp.Details[0].Code != "visibility_invalid" {
+		t.Fatalf("unknown visibility: status %d details %+v, want 422 visibility_invalid", status, resp.Details)
+	}
+}
+
+func TestQuotePosts(t *testing.T) {
+	app := newTestApp(t)
+	alice := registerAndLogin(t, app, "alice")
+	bob := registerAndLogin(t, app, "bob")
+
+	type post struct {
+		ID         string `json:"id"`
+		Type       string `json:"type"`
+		QuoteCount int    `json:"quoteCount"`
+		Quote      *struct {
+			ID string `json:"id"`
+		} `json:"quote"`
+	}
+	get := func(id string) post {
+		t.Helper()
+		status, resp := doRequest(t, app, nethttp.MethodGet, "/api/v1/public/posts/"+id, "", nil)
+		var found struct {
+			Post post `json:"post"`
+		}
+		if err := json.Unmarshal(resp.Data, &found); err != nil || status != fiber.StatusOK {
+			t.Fatalf("get post: status %d err %v", status, err)
+		}
+		return found.Post
+	}
+
+	original := createPost(t, app, alice, fiber.Map{"content": "original"})
+	quote := createPost(t, app, bob, fiber.Map{"content": "quoting alice", "quoted_post_id": original.ID})
+	if quote.QuotedPostID != original.ID {
+		t.Fatalf("created quote %+v, want a quote of the original", quote)
+	}
+	if found := get(original.ID); found.QuoteCount != 1 {
+		t.Fatalf("original has %d quotes, want 1", found.QuoteCount)
+	}
+	if found := get(quote.ID); found.Type != "quote" || found.Quote == nil || found.Quote.ID != original.ID {
+		t.Fatalf("quote has type %q and embeds %+v, want a quote of the original", found.Type, found.Quote)
+	}
+
+	// Only published public posts can be quoted
+	followers := createPost(t, app, alice, fiber.Map{"content": "followers only", "visibility": "followers"})
+	draft := createPost(t, app, alice, fiber.Map{"content": "draft", "draft": true})
+	cases := []struct {
+		name   string
+		id     string
+		status int
+		code   string
+	}{
+		{"own followers only post", followers.ID, fiber.StatusForbidden, "quote_not_public"},
+		{"own draft", draft.ID, fiber.StatusConflict, "post_not_published"},
+		{"unknown post", uuid.NewString(), fiber.StatusNotFound, "post_not_found"},
+	}
+	for _, c := range cases {
+		status, resp := doRequest(t, app, nethttp.MethodPost, "/api/v1/posts", alice, fiber.Map{"content": "quoting", "quoted_post_id": c.id})
+		if status != c.status || resp.Code != c.code {
+			t.Fatalf("quote a %s: status %d code %q, want %d %s", c.name, status, resp.Code, c.status, c.code)
+		}
+	}
+
+	// The quote stays once the quoted post is gone
+	if status, _ := doRequest(t, app, nethttp.MethodDelete, "/api/v1/posts/"+original.ID, alice, nil); status != fiber.StatusOK {
+		t.Fatalf("delete original: status %d", status)
+	}
+	if found := get(quote.ID); found.Type != "text" || found.Quote != nil {
+		t.Fatalf("quote of a deleted post has type %q and embeds %+v, want a text post", found.Type, found.Quote)
+	}
+}
+
+func TestPolls(t *testing.T) {
+	app := newTestApp(t)
+	alice := registerAndLogin(t, app, "alice")
+	bob := registerAndLogin(t, app, "bob")
+
+	type poll struct {
+		Options []struct {
+			ID   string `json:"id"`
+			Text string `json:"text"`
+		} `json:"options"`
+		VoterCount   int      `json:"voterCount"`
+		Voted        bool     `json:"voted"`
+		ViewerChoice []string `json:"viewerChoice"`
+		Tallies      []int    `json:"tallies"`
+	}
+	vote := func(postID string, optionIDs ...string) (int, testResponse) {
+		t.Helper()
+		return doRequest(t, app, nethttp.MethodPost, "/api/v1/posts/"+postID+"/poll/vote", bob, fiber.Map{"option_ids": optionIDs})
+	}
+	closesAt := time.Now().Add(time.Hour)
+
+	postID := createPost(t, app, alice, fiber.Map{"content": "tea or coffee", "poll": fiber.Map{"options": []string{"tea", "coffee"}, "closes_at": closesAt}}).ID
+	status, resp := doRequest(t, app, nethttp.MethodGet, "/api/v1/public/posts/"+postID, bob, nil)
+	var found struct {
+		Post struct {
+			Poll *poll `json:"poll"`
+		} `json:"post"`
+	}
+	if err := json.Unmarshal(resp.Data, &found); err != nil || status != fiber.StatusOK {
+		t.Fatalf("get post: status %d err %v", status, err)
+	}
+	if found.Post.Poll == nil || len(found.Post.Poll.Options) != 2 || found.Post.Poll.Tallies != nil {
+		t.Fatalf("got poll %+v, want 2 options and no tallies before voting", found.Post.Poll)
+	}
+	tea, coffee := found.Post.Poll.Options[0].ID, found.Post.Poll.Options[1].ID
+
+	// Single choice polls take one option, and only options of their own
+	cases := []struct {
+		name    string
+		options []string
+		field   string
+		code    string
+	}{
+		{"two options", []string{tea, coffee}, "option_ids", "single_choice"},
+		{"an unknown option", []string{uuid.NewString()}, "option_ids", "option_unknown"},
+		{"no option", []string{}, "option_ids", "option_ids_empty"},
+	}
+	for _, c := range cases {
+		status, resp := vote(postID, c.options...)
+		if status != fiber.StatusUnprocessableEntity || len(resp.Details) != 1 || resp.Details[0].Field != c.field || resp.Details[0].Code != c.code {
+			t.Fatalf("vote for %s: status %d details %+v, want 422 %s %s", c.name, status, resp.Details, c.field, c.code)
+		}
+	}
+
+	status, resp = vote(postID, coffee)
+	var voted struct {
+		Poll poll `json:"poll"`
+	}
+	if err := json.Unmarshal(resp.Data, &voted); err != nil || status != fiber.StatusOK {
+		t.Fatalf("vote: status %d err %v", status, err)
+	}
+	if !voted.Poll.Voted || voted.Poll.VoterCount != 1 || !slices.Equal(voted.Poll.ViewerChoice, []string{coffee}) || !slices.Equal(voted.Poll.Tallies, []int{0, 1}) {
+		t.Fatalf("got poll %+v after voting, want coffee chosen and tallies [0 1]", voted.Poll)
+	}
+	if status, resp := vote(postID, tea); status != fiber.StatusConflict || resp.Code != "poll_already_voted" {
+		t.Fatalf("vote twice: status %d code %q, want 409 poll_already_voted", status, resp.Code)
+	}
+
+	plain := createPost(t, app, alice, fiber.Map{"content": "no poll"}).ID
+	if status, resp := vote(plain, tea); status != fiber.StatusNotFound || resp.Code != "poll_not_found" {
+		t.Fatalf("vote on a post without a poll: status %d code %q, want 404 poll_not_found", status, resp.Code)
+	}
+
+	// Polls are validated with the post
+	invalid := []struct {
+		name string
+		poll fiber.Map
+		code string
+	}{
+		{"five options", fiber.Map{"options": []string{"a", "b", "c", "d", "e"}, "closes_at": closesAt}, "options_count"},
+		{"duplicate options", fiber.Map{"options": []string{"tea", " tea"}, "closes_at": closesAt}, "option_duplicate"},
+		{"past close", fiber.Map{"options": []string{"tea", "coffee"}, "closes_at": time.Now().Add(-time.Hour)}, "closes_at_too_early"},
+		{"unknown choice", fiber.Map{"options": []string{"tea", "coffee"}, "closes_at": closesAt, "choice": "ranked"}, "choice_invalid"},
+	}
+	for _, c := range invalid {
+		status, resp := doRequest(t, app, nethttp.MethodPost, "/api/v1/posts", alice, fiber.Map{"content": "poll", "poll": c.poll})
+		if status != fiber.StatusUnprocessableEntity || len(resp.Details) != 1 || resp.Details[0].Code != c.code {
+			t.Fatalf("create poll with %s: status %d details %+v, want 422 %s", c.name, status, resp.Details, c.code)
+		}
+	}
+}
+
+func TestPinnedPosts(t *testing.T) {
+	app := newTestApp(t)
+	alice := registerAndLogin(t, app, "alice")
+	bob := registerAndLogin(t, app, "bob")
+
+	aliceID := currentUserID(t, app, alice)
+	create := func(token, content string) string {
+		t.Helper()
+		return createPost(t, app, token, fiber.Map{"content": content}).ID
+	}
+	pin := func(postID string) (int, testResponse) {
+		t.Helper()
+		return doRequest(t, app, nethttp.MethodPost, "/api/v1/posts/"+postID+"/pin", alice, nil)
+	}
+	type item struct {
+		ID     string `json:"id"`
+		Type   string `json:"type"`
+		Pinned bool   `json:"pinned"`
+	}
+	profile := func() []item {
+		t.Helper()
+		status, resp := doRequest(t, app, nethttp.MethodGet, "/api/v1/public/users/"+aliceID+"/posts", bob, nil)
+		var list struct {
+			Posts []item `json:"posts"`
+		}
+		if err := json.Unmarshal(resp.Data, &list); err != nil || status != fiber.StatusOK {
+			t.Fatalf("get posts of alice: status %d err %v", status, err)
+		}
+		return list.Posts
+	}
+
+	older := create(alice, "older")
+	time.Sleep(2 * time.Millisecond)
+	create(alice, "newer")
+	if status, _ := pin(older); status != fiber.StatusOK {
+		t.Fatalf("pin: status %d", status)
+	}
+	if posts := profile(); len(posts) != 2 || posts[0].ID != older || !posts[0].Pinned || posts[1].Pinned {
+		t.Fatalf("got %+v, want the pinned older post first", posts)
+	}
+
+	// Another user's post can only be pinned once reposted
+	other := create(bob, "by bob")
+	if status, resp := pin(other); status != fiber.StatusForbidden || resp.Code != "pin_not_allowed" {
+		t.Fatalf("pin a post of bob: status %d code %q, want 403 pin_not_allowed", status, resp.Code)
+	}
+	doRequest(t, app, nethttp.MethodPost, "/api/v1/posts/"+other+"/repost", alice, nil)
+	if status, _ := pin(other); status != fiber.StatusOK {
+		t.Fatalf("pin a reposted post: status %d", status)
+	}
+	if posts := profile(); len(posts) != 3 || posts[0].ID != other || posts[0].Type != "repost" || !posts[0].Pinned {
+		t.Fatalf("got %+v, want the pinned repost first", posts)
+	}
+
+	for i := 2; i < entity.MaxPinnedPosts; i++ {
+		if status, _ := pin(create(alice, "pinned")); status != fiber.StatusOK {
+			t.Fatalf("pin %d: status %d", i, status)
+		}
+	}
+	if status, resp := pin(create(alice, "one too many")); status != fiber.StatusConflict || resp.Code != "pin_limit_reached" {
+		t.Fatalf("pin over the limit: status %d code %q, want 409 pin_limit_reached", status, resp.Code)
+	}
+
+	if status, _ := doRequest(t, app, nethttp.MethodDelete, "/api/v1/posts/"+older+"/pin", alice, nil); status != fiber.StatusOK {
+		t.Fatalf("unpin: status %d", status)
+	}
+	// Unreposting removes the pin of the repost as well
+	doRequest(t, app, nethttp.MethodDelete, "/api/v1/posts/"+other+"/repost", alice, nil)
+	for _, p := range profile() {
+		if p.Pinned && (p.ID == older || p.ID == other) {
+			t.Fatalf("post %s still pinned", p.ID)
+		}
+	}
+	if status, _ := pin(create(alice, "fits again")); status != fiber.StatusOK {
+		t.Fatalf("pin after unpinning: status %d", status)
+	}
+}
+
+func TestCollections(t *testing.T) {
+	app := newTestApp(t)
+	alice := registerAndLogin(t, app, "alice")
+	bob := registerAndLogin(t, app, "bob")
+
+	postID := createPost(t, app, bob, fiber.Map{"content": "soup recipe"}).ID
+
+	type collection struct {
+		ID      string `json:"id"`
+		Name    string `json:"name"`
+		Default bool   `json:"default"`
+	}
+	collections := func() []collection {
+		t.Helper()
+		_, resp := doRequest(t, app, nethttp.MethodGet, "/api/v1/collections", alice, nil)
+		var list struct {
+			Collections []collection `json:"collections"`
+		}
+		if err := json.Unmarshal(resp.Data, &list); err != nil {
+			t.Fatalf("decode collections: %v", err)
+		}
+		return list.Collections
+	}
+	postsIn := func(id string) []string {
+		t.Helper()
+		status, resp := doRequest(t, app, nethttp.MethodGet, "/api/v1/collections/"+id+"/posts", alice, nil)
+		var list struct {
+			Posts []struct {
+				ID        string `json:"id"`
+				Favorited bool   `json:"favorited"`
+			} `json:"posts"`
+		}
+		if err := json.Unmarshal(resp.Data, &list); err != nil || status != fiber.StatusOK {
+			t.Fatalf("get posts of collection: status %d err %v", status, err)
+		}
+		var ids []string
+		for _, p := range list.Posts {
+			if !p.Favorited {
+				t.Fatalf("post %s in a collection is not favorited", p.ID)
+			}
+			ids = append(ids, p.ID)
+		}
+		return ids
+	}
+
+	// Every user starts with the default collection, favoriting a post puts it there
+	initial := collections()
+	if len(initial) != 1 || !initial[0].Default || initial[0].Name != entity.DefaultCollectionName {
+		t.Fatalf("got %+v, want only the default collection", initial)
+	}
+	defaultID := initial[0].ID
+	doRequest(t, app, nethttp.MethodPost, "/api/v1/posts/"+postID+"/favorite", alice, nil)
+	if ids := postsIn(defaultID); len(ids) != 1 || ids[0] != postID {
+		t.Fatalf("default collection holds %v, want the favorited post", ids)
+	}
+
+	status, resp := doRequest(t, app, nethttp.MethodPost, "/api/v1/collections", alice, fiber.Map{"name": " recipes "})
+	var saved struct {
+		Collection collection `json:"collection"`
+	}
+	if err := json.Unmarshal(resp.Data, &saved); err != nil || status != fiber.StatusOK || saved.Collection.Name != "recipes" {
+		t.Fatalf("create collection: status %d got %+v", status, saved.Collection)
+	}
+	recipes := saved.Collection.ID
+	if status, resp := doRequest(t, app, nethttp.MethodPost, "/api/v1/collections", alice, fiber.Map{"name": "recipes"}); status != fiber.StatusConflict || resp.Code != "collection_already_exists" {
+		t.Fatalf("create a second recipes: status %d code %q, want 409 collection_already_exists", status, resp.Code)
+	}
+
+	// Adding a favorited post to another collection moves it there
+	if status, _ := doRequest(t, app, nethttp.MethodPost, "/api/v1/collections/"+recipes+"/posts", alice, fiber.Map{"post_id": postID}); status != fiber.StatusOK {
+		t.Fatalf("add post to collection: status %d", status)
+	}
+	if ids := postsIn(recipes); len(ids) != 1 || ids[0] != postID {
+		t.Fatalf("recipes holds %v, want the post", ids)
+	}
+	if ids := postsIn(defaultID); len(ids) != 0 {
+		t.Fatalf("default collection holds %v, want the post moved out", ids)
+	}
+
+	// Collections are private
+	for _, c := range []struct{ method, path string }{
+		{nethttp.MethodGet, "/api/v1/collections/" + recipes + "/posts"},
+		{nethttp.MethodDelete, "/api/v1/collections/" + recipes},
+		{nethttp.MethodPost, "/api/v1/collections/" + recipes + "/posts"},
+	} {
+		status, resp := doRequest(t, app, c.method, c.path, bob, fiber.Map{"post_id": postID})
+		if status != fiber.StatusNotFound || resp.Code != "collection_not_found" {
+			t.Fatalf("%s %s by bob: status %d code %q, want 404 collection_not_found", c.method, c.path, status, resp.Code)
+		}
+	}
+
+	if status, resp := doRequest(t, app, nethttp.MethodDelete, "/api/v1/collections/"+defaultID, alice, nil); status != fiber.StatusConflict || resp.Code != "default_collection" {
+		t.Fatalf("delete default collection: status %d code %q, want 409 default_collection", status, resp.Code)
+	}
+	if status, _ := doRequest(t, app, nethttp.MethodPut, "/api/v1/collections/"+defaultID, alice, fiber.Map{"name": "saved"}); status != fiber.StatusOK {
+		t.Fatalf("rename default collection: status %d", status)
+	}
+
+	// Deleting a collection keeps its posts favorited in the default collection
+	if status, _ := doRequest(t, app, nethttp.MethodDelete, "/api/v1/collections/"+recipes, alice, nil); status != fiber.StatusOK {
+		t.Fatalf("delete collection: status %d", status)
+	}
+	if got := collections(); len(got) != 1 || got[0].Name != "saved" {
+		t.Fatalf("got %+v, want only the renamed default collection", got)
+	}
+	if ids := postsIn(defaultID); len(ids) != 1 || ids[0] != postID {
+		t.Fatalf("default collection holds %v, want the post of the deleted collection", ids)
+	}
+
+	if status, _ := doRequest(t, app, nethttp.MethodDelete, "/api/v1/collections/"+defaultID+"/posts/"+postID, alice, nil); status != fiber.StatusOK {
+		t.Fatalf("remove post from collection: status %d", status)
+	}
+	_, resp = doRequest(t, app, nethttp.MethodGet, "/api/v1/public/posts/"+postID, alice, nil)
+	var post struct {
+		Post struct {
+			Favorited     bool `json:"favorited"`
+			FavoriteCount int  `json:"favoriteCount"`
+		} `json:"post"`
+	}
+	if err := json.Unmarshal(resp.Data, &post); err != nil || post.Post.Favorited || post.Post.FavoriteCount != 0 {
+		t.Fatalf("got favorited=%v count=%d (err %v), want the post no longer favorited", post.Post.Favorited, post.Post.FavoriteCount, err)
+	}
+}
+
+func TestReactions(t *testing.T) {
+	app := newTestApp(t)
+	alice := registerAndLogin(t, app, "alice")
+	bob := registerAndLogin(t, app, "bob")
+
+	aliceID := currentUserID(t, app, alice)
+	post := "/api/v1/posts/" + createPost(t, app, alice, fiber.Map{"content": "hello"}).ID
+
+	type reactions struct {
+		Liked     bool           `json:"liked"`
+		LikeCount int            `json:"likeCount"`
+		Reactions map[string]int `json:"reactions"`
+		Reaction  *string        `json:"reaction"`
+	}
+	// The profile is not cached, it always holds the reaction of the viewer
+	viewedBy := func(token string) reactions {
+		t.Helper()
+		status, resp := doRequest(t, app, nethttp.MethodGet, "/api/v1/public/users/"+aliceID+"/posts", token, nil)
+		var list struct {
+			Posts []reactions `json:"posts"`
+		}
+		if err := json.Unmarshal(resp.Data, &list); err != nil || status != fiber.StatusOK || len(list.Posts) != 1 {
+			t.Fatalf("get posts of alice: status %d err %v", status, err)
+		}
+		return list.Posts[0]
+	}
+	react := func(token, reaction string) {
+		t.Helper()
+		if status, _ := doRequest(t, app, nethttp.MethodPut, post+"/reaction", token, fiber.Map{"reaction": reaction}); status != fiber.StatusOK {
+			t.Fatalf("react %s: status %d", reaction, status)
+		}
+	}
+
+	// A like is the reaction like
+	doRequest(t, app, nethttp.MethodPost, post+"/like", bob, nil)
+	if got := viewedBy(bob); !got.Liked || got.Reaction == nil || *got.Reaction != "like" || got.Reactions["like"] != 1 || len(got.Reactions) != 6 {
+		t.Fatalf("after like got %+v, want bob's like", got)
+	}
+
+	// Reacting again replaces the reaction, liking keeps it
+	react(bob, "love")
+	doRequest(t, app, nethttp.MethodPost, post+"/like", bob, nil)
+	react(alice, "haha")
+	got := viewedBy(bob)
+	if got.LikeCount != 2 || got.Reaction == nil || *got.Reaction != "love" || got.Reactions["like"] != 0 || got.Reactions["love"] != 1 || got.Reactions["haha"] != 1 {
+		t.Fatalf("after reacting got %+v, want bob's love next to alice's haha", got)
+	}
+
+	status, resp := doRequest(t, app, nethttp.MethodPut, post+"/reaction", bob, fiber.Map{"reaction": "meh"})
+	if status != fiber.StatusUnprocessableEntity || len(resp.Details) != 1 || resp.Details[0].Code != "reaction_invalid" {
+		t.Fatalf("unknown reaction: status %d details %+v, want 422 reaction_invalid", status, resp.Details)
+	}
+
+	// Unliking removes any reaction
+	doRequest(t, app, nethttp.MethodDelete, post+"/like", bob, nil)
+	doRequest(t, app, nethttp.MethodDelete, post+"/reaction", alice, nil)
+	if got := viewedBy(bob); got.Liked || got.LikeCount != 0 || got.Reaction != nil || got.Reactions["love"] != 0 || got.Reactions["haha"] != 0 {
+		t.Fatalf("after removing got %+v, want no reaction", got)
+	}
+}
+
+func TestPostViewerStateNotShared(t *testing.T) {
+	app := newTestApp(t)
+	alice := registerAndLogin(t, app, "alice")
+	bob := registerAndLogin(t, app, "bob")
+	carol := registerAndLogin(t, app, "carol")
+
+	id := createPost(t, app, alice, fiber.Map{"content": "hello"}).ID
+	post := "/api/v1/posts/" + id
+	type viewed struct {
+		Liked     bool           `json:"liked"`
+		LikeCount int            `json:"likeCount"`
+		Reactions map[string]int `json:"reactions"`
+		Reaction  *string        `json:"reaction"`
+	}
+	viewedBy := func(token string) viewed {
+		t.Helper()
+		status, resp := doRequest(t, app, nethttp.MethodGet, "/api/v1/public/posts/"+id, token, nil)
+		var data struct {
+			Post viewed `json:"post"`
+		}
+		if err := json.Unmarshal(resp.Data, &data); err != nil || status != fiber.StatusOK {
+			t.Fatalf("get post: status %d err %v", status, err)
+		}
+		return data.Post
+	}
+
+	if status, _ := doRequest(t, app, nethttp.MethodPut, post+"/reaction", bob, fiber.Map{"reaction": "love"}); status != fiber.StatusOK {
+		t.Fatalf("react: status %d", status)
+	}
+	// Bob's view comes first, the others must not get his reaction from the cache
+	if got := viewedBy(bob); !got.Liked || got.Reaction == nil || *got.Reaction != "love" {
+		t.Fatalf("bob got %+v, want his love", got)
+	}
+	for name, token := range map[string]string{"carol": carol, "anonymous": ""} {
+		if got := viewedBy(token); got.Liked || got.Reaction != nil || got.LikeCount != 1 {
+			t.Fatalf("%s got %+v, want bob's love counted but not theirs", name, got)
+		}
+	}
+
+	// Carol's like is saved although her last view of the post was not liked by her
+	if status, _ := doRequest(t, app, nethttp.MethodPost, post+"/like", carol, nil); status != fiber.StatusOK {
+		t.Fatalf("like: status %d", status)
+	}
+	got := viewedBy(carol)
+	if !got.Liked || got.Reaction == nil || *got.Reaction != "like" || got.LikeCount != 2 || got.Reactions["love"] != 1 || got.Reactions["like"] != 1 {
+		t.Fatalf("carol got %+v, want her like next to bob's love", got)
+	}
+	if got := viewedBy(""); got.Liked || got.LikeCount != 2 {
+		t.Fatalf("anonymous got %+v, want both reactions counted", got)
+	}
+}
+
+func TestErrorStatusCodes(t *testing.T) {
+	app := newTestApp(t)
+	alice := registerAndLogin(t, app, "alice")
+
+	status, resp := doRequest(t, app, nethttp.MethodPost, "/api/v1/posts", alice, fiber.Map{"content": ""})
+	if status != fiber.StatusUnprocessableEntity || resp.Code != "validation_failed" {
+		t.Fatalf("empty post: status %d code %q, want %d validation_failed", status, resp.Code, fiber.StatusUnprocessableEntity)
+	}
+	if len(resp.Details) != 1 || resp.Details[0].Field != "content" || resp.Details[0].Code != "required" {
+		t.Fatalf("empty post details = %+v", resp.Details)
+	}
+
+	status, resp = doRequest(t, app, nethttp.MethodPost, "/api/v1/auth/register", "", fiber.Map{
+		"username": "bob",
+		"password": "short",
+		"email":    "bob@example.com",
+	})
+	if status != fiber.StatusUnprocessableEntity || len(resp.Details) != 1 || resp.Details[0].Code != "min_length" {
+		t.Fatalf("short password: status %d details %+v", status, resp.Details)
+	}
+
+	status, resp = doRequest(t, app, nethttp.MethodPost, "/api/v1/auth/register", "", fiber.Map{
+		"username": "alice",
+		"password": "password123",
+		"email":    "other@example.com",
+	})
+	if status != fiber.StatusConflict || resp.Code != "user_already_exists" {
+		t.Fatalf("duplicate username: status %d code %q, want %d user_already_exists", status, resp.Code, fiber.StatusConflict)
+	}
+	if len(resp.Details) != 1 || resp.Details[0].Field != "username" {
+		t.Fatalf("duplicate username details = %+v", resp.Details)
+	}
+
+	status, resp = doRequest(t, app, nethttp.MethodGet, "/api/v1/public/posts/00000000-0000-0000-0000-000000000000", "", nil)
+	if status != fiber.StatusNotFound || resp.Code != "post_not_found" {
+		t.Fatalf("unknown post: status %d code %q, want %d post_not_found", status, resp.Code, fiber.StatusNotFound)
+	}
+
+	status, resp = doRequest(t, app, nethttp.MethodGet, "/api/v1/users/me", "unknown-token", nil)
+	if status != fiber.StatusUnauthorized || resp.Code != "invalid_session" {
+		t.Fatalf("unknown token: status %d code %q, want %d invalid_session", status, resp.Code, fiber.StatusUnauthorized)
+	}
+
+	status, resp = doRequest(t, app, nethttp.MethodGet, "/api/v1/unknown", "", nil)
+	if status != fiber.StatusNotFound || resp.Code != "not_found" {
+		t.Fatalf("unknown route: status %d code %q", status, resp.Code)
+	}
+}
+
+func TestRequestValidation(t *testing.T) {
+	app := newTestApp(t)
+	alice := registerAndLogin(t, app, "alice")
+
+	// Every violation is reported at once, before the services are called
+	status, resp := doRequest(t, app, nethttp.MethodPut, "/api/v1/posts/not-a-uuid", alice, fiber.Map{
+		"content": strings.Repeat("a", 5001),
+		"user_id": "00000000-0000-0000-0000-000000000000",
+		"title":   "unknown",
+	})
+	if status != fiber.StatusUnprocessableEntity {
+		t.Fatalf("invalid update: status %d, want %d", status, fiber.StatusUnprocessableEntity)
+	}
+	got := map[string]string{}
+	for _, d := range resp.Details {
+		got[d.Field] = d.Code
+	}
+	want := map[string]string{"id": "uuid", "content": "max_length", "user_id": "unknown_field", "title": "unknown_field"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("details = %v, want %v", got, want)
+	}
+
+	status, resp = doRequest(t, app, nethttp.MethodPost, "/api/v1/auth/register", "", fiber.Map{
+		"username": "bob smith",
+		"password": 12345678,
+		"email":    "not an email",
+	})
+	got = map[string]string{}
+	for _, d := range resp.Details {
+		got[d.Field] = d.Code
+	}
+	want = map[string]string{"username": "username", "password": "type", "email": "email"}
+	if status != fiber.StatusUnprocessableEntity || !reflect.DeepEqual(got, want) {
+		t.Fatalf("invalid register: status %d details %v, want %v", status, got, want)
+	}
+
+	status, _ = doRequest(t, app, nethttp.MethodGet, "/api/v1/public/users/42", "", nil)
+	if status != fiber.StatusUnprocessableEntity {
+		t.Fatalf("invalid user id: status %d, want %d", status, fiber.StatusUnprocessableEntity)
+	}
+
+	status, resp = doRequest(t, app, nethttp.MethodPost, "/api/v1/posts", alice, []string{"hello"})
+	if status != fiber.StatusBadRequest || resp.Code != "invalid_body" {
+		t.Fatalf("array body: status %d code %q, want %d invalid_body", status, resp.Code, fiber.StatusBadRequest)
+	}
+
+	// The comment of a repost is optional, the body may be left out
+	created := createPost(t, app, alice, fiber.Map{"content": "hello"})
+	status, _ = doRequest(t, app, nethttp.MethodPost, "/api/v1/posts/"+created.ID+"/repost", alice, nil)
+	if status != fiber.StatusOK {
+		t.Fatalf("repost without body: status %d", status)
+	}
+}
+
+// Fiber rejects bodies over MaxBodySize before any handler runs, app.Test fails on them such that a listener is used
+func TestBodyLimit(t *testing.T) {
+	app := newTestApp(t)
+	alice := registerAndLogin(t, app, "alice")
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go app.Listener(ln)
+	t.Cleanup(func() { app.Shutdown() })
+
+	body, _ := json.Marshal(strings.Repeat("a", MaxBodySize+1))
+	// Liking never reads the body, it is limited all the same
+	for _, path := range []string{"/api/v1/posts", "/api/v1/posts/" + uuid.NewString() + "/like"} {
+		req, _ := nethttp.NewRequest(nethttp.MethodPost, "http://"+ln.Addr().String()+path, bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+alice)
+		resp, err := nethttp.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("POST %s: %v", path, err)
+		}
+		var out testResponse
+		err = json.NewDecoder(resp.Body).Decode(&out)
+		resp.Body.Close()
+		if err != nil || resp.StatusCode != fiber.StatusRequestEntityTooLarge || out.Code != "body_too_large" {
+			t.Fatalf("POST %s: status %d code %q err %v, want %d body_too_large", path, resp.StatusCode, out.Code, err, fiber.StatusRequestEntityTooLarge)
+		}
+	}
+}
+
+func TestCursorPagination(t *testing.T) {
+	app := newTestApp(t)
+	alice := registerAndLogin(t, app, "alice")
+	for _, content := range []string{"first", "second", "third"} {
+		if status, _ := doRequest(t, app, nethttp.MethodPost, "/api/v1/posts", alice, fiber.Map{"content": content}); status != fiber.StatusOK {
+			t.Fatalf("create post: status %d", status)
+		}
+	}
+
+	type page struct {
+		Posts []struct {
+			ID string `json:"id"`
+		} `json:"posts"`
+		Pagination CursorPagination `json:"pagination"`
+	}
+	get := func(path string) page {
+		t.Helper()
+		status, resp := doRequest(t, app, nethttp.MethodGet, path, alice, nil)
+		if status != fiber.StatusOK {
+			t.Fatalf("%s: status %d", path, status)
+		}
+		var p page
+		if err := json.Unmarshal(resp.Data, &p); err != nil {
+			t.Fatalf("decode %s: %v", path, err)
+		}
+		return p
+	}
+
+	first := get("/api/v1/users/me/posts?pageSize=2")
+	if len(first.Posts) != 2 || first.Pagination.PageSize != 2 || first.Pagination.NextCursor == nil {
+		t.Fatalf("first page %+v", first)
+	}
+	last := get("/api/v1/users/me/posts?pageSize=2&cursor=" + *first.Pagination.NextCursor)
+	if len(last.Posts) != 1 || last.Pagination.NextCursor != nil {
+		t.Fatalf("last page %+v", last)
+	}
+	seen := map[string]bool{}
+	for _, p := range append(first.Posts, last.Posts...) {
+		seen[p.ID] = true
+	}
+	if len(seen) != 3 {
+		t.Fatalf("pages hold %d distinct posts, want 3", len(seen))
+	}
+
+	status, resp := doRequest(t, app, nethttp.MethodGet, "/api/v1/users/me/reposts?cursor=not-a-cursor", alice, nil)
+	if status != fiber.StatusUnprocessableEntity || len(resp.Details) != 1 || resp.Details[0].Field != "cursor" || resp.Details[0].Code != "cursor" {
+		t.Fatalf("invalid cursor: status %d details %+v", status, resp.Details)
+	}
+}
+
+func TestProfileViewer(t *testing.T) {
+	app := newTestApp(t)
+	alice := registerAndLogin(t, app, "alice")
+	bob := registerAndLogin(t, app, "bob")
+
+	aliceID := currentUserID(t, app, alice)
+	postID := createPost(t, app, alice, fiber.Map{"content": "hello"}).ID
+	doRequest(t, app, nethttp.MethodPost, "/api/v1/posts/"+postID+"/like", alice, nil)
+	doRequest(t, app, nethttp.MethodPost, "/api/v1/posts/"+postID+"/repost", alice, nil)
+
+	// The flags are those of whoever views the profile, not of its owner
+	for _, tt := range []struct {
+		path, token string
+		liked       bool
+	}{
+		{"/posts", "", false},
+		{"/posts", bob, false},
+		{"/posts", alice, true},
+		{"/reposts", bob, false},
+		{"/reposts", alice, true},
+	} {
+		path := "/api/v1/public/users/" + aliceID + tt.path
+		status, resp := doRequest(t, app, nethttp.MethodGet, path, tt.token, nil)
+		// Checked below by the number of items
+		var got map[string]json.RawMessage
+		_ = json.Unmarshal(resp.Data, &got)
+		var items []struct {
+			Liked bool `json:"liked"`
+		}
+		_ = json.Unmarshal(got[strings.TrimPrefix(tt.path, "/")], &items)
+		if status != fiber.StatusOK || len(items) != 1 || items[0].Liked != tt.liked {
+			t.Fatalf("%s as %q: status %d items %+v, want liked %v", path, tt.token, status, items, tt.liked)
+		}
+	}
+}
+
+func TestRequestLogger(t *testing.T) {
+	var buf bytes.Buffer
+	app := newTestAppWith(t, slog.New(slog.NewJSONHandler(&buf, nil)), metrics.NewMetrics())
+	token := registerAndLogin(t, app, "alice")
+	buf.Reset()
+
+	req := httptest.NewRequest(nethttp.MethodGet, "/api/v1/users/me", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set(RequestIDHeader, "client-id-1")
+	resp, err := app.Test(req, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := resp.Header.Get(RequestIDHeader); got != "client-id-1" {
+		t.Fatalf("request id header %q, want the one of the client", got)
+	}
+
+	var line map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &line); err != nil {
+		t.Fatalf("decode log line %q: %v", buf.String(), err)
+	}
+	want := map[string]any{
+		"msg":        "request",
+		"request_id": "client-id-1",
+		"method":     nethttp.MethodGet,
+		"route":      "/api/v1/users/me",
+		"status":     float64(fiber.StatusOK),
+	}
+	for k, v := range want {
+		if line[k] != v {
+			t.Errorf("%s = %v, want %v", k, line[k], v)
+		}
+	}
+	if id, _ := line["user_id"].(string); id == "" {
+		t.Errorf("user_id missing in %v", line)
+	}
+
+	// Ids that could break the log line are replaced
+	req = httptest.NewRequest(nethttp.MethodGet, "/api/v1/public/posts/not-a-uuid", nil)
+	req.Header.Set(RequestIDHeader, "bad id\n")
+	resp, err = app.Test(req, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := resp.Header.Get(RequestIDHeader); got == "bad id\n" || got == "" {
+		t.Fatalf("request id header %q, want a generated id", got)
+	}
+}
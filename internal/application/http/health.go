@@ -0,0 +1,105 @@
+package http
+
+import (
+	"context"
+	"social-media-go-ddd/internal/infrastructure/logging"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// How long a readiness check may take before it counts as failed
+const DefaultHealthCheckTimeout = 2 * time.Second
+
+const (
+	healthStatusOK   = "ok"
+	healthStatusFail = "fail"
+)
+
+// Dependency checked by /readyz, Check returns nil when it is usable
+type HealthCheck struct {
+	Name  string
+	Check func(ctx context.Context) error
+}
+
+type HealthCheckResult struct {
+	Status    string  `json:"status"`
+	LatencyMs float64 `json:"latencyMs"`
+}
+
+type Readiness struct {
+	Status string                       `json:"status"`
+	Checks map[string]HealthCheckResult `json:"checks"`
+}
+
+type HealthHandler struct {
+	checks       []HealthCheck
+	timeout      time.Duration
+	shuttingDown atomic.Bool
+}
+
+func NewHealthHandler(checks ...HealthCheck) *HealthHandler {
+	return &HealthHandler{checks: checks, timeout: DefaultHealthCheckTimeout}
+}
+
+func (h *HealthHandler) RegisterRoutes(app *fiber.App) {
+	app.Get("/healthz", h.Liveness)
+	app.Get("/readyz", h.Readiness)
+}
+
+// /readyz fails from now on such that load balancers stop sending requests, call it before app.Shutdown
+func (h *HealthHandler) SetShuttingDown() {
+	h.shuttingDown.Store(true)
+}
+
+// The process is up and serving, dependencies are not checked such that an outage of one does not restart every instance
+func (h *HealthHandler) Liveness(ctx *fiber.Ctx) error {
+	return SuccessResponse(ctx, fiber.Map{"status": healthStatusOK})
+}
+
+// Runs every check concurrently, 503 when one of them fails or the server is shutting down
+func (h *HealthHandler) Readiness(ctx *fiber.Ctx) error {
+	if h.shuttingDown.Load() {
+		resp := NewResponse(false, "Shutting down", Readiness{Status: healthStatusFail, Checks: map[string]HealthCheckResult{}}, fiber.StatusServiceUnavailable, "server is shutting down")
+		resp.Code = "shutting_down"
+		return ctx.Status(fiber.StatusServiceUnavailable).JSON(resp)
+	}
+
+	checkCtx, cancel := context.WithTimeout(ctx.UserContext(), h.timeout)
+	defer cancel()
+
+	readiness := Readiness{Status: healthStatusOK, Checks: make(map[string]HealthCheckResult, len(h.checks))}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, c := range h.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			start := time.Now()
+			err := c.Check(checkCtx)
+			result := HealthCheckResult{Status: healthStatusOK, LatencyMs: float64(time.Since(start).Microseconds()) / 1000}
+			if err != nil {
+				// Errors may name hosts and ports, they are only logged since the endpoint is public
+				result.Status = healthStatusFail
+				logging.FromContext(checkCtx).Warn("readiness check failed", "check", c.Name, "error", err)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			readiness.Checks[c.Name] = result
+			if err != nil {
+				readiness.Status = healthStatusFail
+			}
+		}()
+	}
+	wg.Wait()
+
+	if readiness.Status != healthStatusOK {
+		resp := NewResponse(false, "Not ready", readiness, fiber.StatusServiceUnavailable, "a dependency check failed")
+		resp.Code = "not_ready"
+		return ctx.Status(fiber.StatusServiceUnavailable).JSON(resp)
+	}
+	return SuccessResponse(ctx, readiness)
+}
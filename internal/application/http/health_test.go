@@ -0,0 +1,82 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	nethttp "net/http"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func newHealthApp(h *HealthHandler) *fiber.App {
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	h.RegisterRoutes(app)
+	return app
+}
+
+func readiness(t *testing.T, app *fiber.App) (int, testResponse, Readiness) {
+	t.Helper()
+
+	status, resp := doRequest(t, app, nethttp.MethodGet, "/readyz", "", nil)
+	var r Readiness
+	if err := json.Unmarshal(resp.Data, &r); err != nil {
+		t.Fatalf("decode readiness: %v", err)
+	}
+	return status, resp, r
+}
+
+func TestHealth(t *testing.T) {
+	ok := func(context.Context) error { return nil }
+	down := func(context.Context) error { return errors.New("dial tcp 10.0.0.3:6379: connection refused") }
+	// Blocks until the check times out
+	slow := func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	t.Run("liveness", func(t *testing.T) {
+		app := newHealthApp(NewHealthHandler(HealthCheck{Name: "database", Check: down}))
+		if status, _ := doRequest(t, app, nethttp.MethodGet, "/healthz", "", nil); status != fiber.StatusOK {
+			t.Fatalf("liveness should not check dependencies, status %d", status)
+		}
+	})
+
+	t.Run("ready", func(t *testing.T) {
+		app := newHealthApp(NewHealthHandler(HealthCheck{Name: "database", Check: ok}, HealthCheck{Name: "redis", Check: ok}))
+		status, _, r := readiness(t, app)
+		if status != fiber.StatusOK || r.Status != healthStatusOK || len(r.Checks) != 2 {
+			t.Fatalf("status %d readiness %+v", status, r)
+		}
+	})
+
+	t.Run("dependency down", func(t *testing.T) {
+		h := NewHealthHandler(HealthCheck{Name: "database", Check: ok}, HealthCheck{Name: "redis", Check: down}, HealthCheck{Name: "migrations", Check: slow})
+		h.timeout = 10 * time.Millisecond
+		status, resp, r := readiness(t, newHealthApp(h))
+		if status != fiber.StatusServiceUnavailable || resp.Code != "not_ready" || r.Status != healthStatusFail {
+			t.Fatalf("status %d code %q readiness %+v", status, resp.Code, r)
+		}
+		if r.Checks["database"].Status != healthStatusOK || r.Checks["redis"].Status != healthStatusFail || r.Checks["migrations"].Status != healthStatusFail {
+			t.Fatalf("checks %+v", r.Checks)
+		}
+		if r.Checks["migrations"].LatencyMs < 10 {
+			t.Fatalf("latency %v of the timed out check", r.Checks["migrations"].LatencyMs)
+		}
+	})
+
+	t.Run("shutting down", func(t *testing.T) {
+		h := NewHealthHandler(HealthCheck{Name: "database", Check: ok})
+		app := newHealthApp(h)
+		h.SetShuttingDown()
+		status, resp, _ := readiness(t, app)
+		if status != fiber.StatusServiceUnavailable || resp.Code != "shutting_down" {
+			t.Fatalf("status %d code %q", status, resp.Code)
+		}
+		if status, _ := doRequest(t, app, nethttp.MethodGet, "/healthz", "", nil); status != fiber.StatusOK {
+			t.Fatalf("liveness while draining, status %d", status)
+		}
+	})
+}
@@ -1,27 +1,60 @@
 package http
 
 import (
+	"encoding/base64"
+	"errors"
+	"social-media-go-ddd/internal/domain/dto"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
 )
 
+type Pagination struct {
+	Page     int `json:"page"`
+	PageSize int `json:"pageSize"`
+	Total    int `json:"total"`
+}
+
+// Pagination of a newest first list, pass NextCursor as the cursor query param to get the next page.
+// It is null on the last page
+type CursorPagination struct {
+	PageSize   int     `json:"pageSize"`
+	NextCursor *string `json:"nextCursor"`
+}
+
+func newCursorPagination(page dto.CursorPage, next *dto.Cursor) CursorPagination {
+	p := CursorPagination{PageSize: page.Limit}
+	if next != nil {
+		cursor := encodeCursor(*next)
+		p.NextCursor = &cursor
+	}
+	return p
+}
+
 // return page, pageSize
 func getPaginationParams(ctx *fiber.Ctx) (int, int) {
 	page := ctx.Query("page", "1")
-	pageSize := ctx.Query("pageSize", "10")
 
 	pageInt, err := strconv.Atoi(page)
 	if err != nil || pageInt < 1 {
 		pageInt = 1
 	}
 
+	return pageInt, getPageSize(ctx)
+}
+
+func getPageSize(ctx *fiber.Ctx) int {
+	pageSize := ctx.Query("pageSize", "10")
+
 	pageSizeInt, err := strconv.Atoi(pageSize)
 	if err != nil || pageSizeInt < 1 || pageSizeInt > 100 {
 		pageSizeInt = 20
 	}
 
-	return pageInt, pageSizeInt
+	return pageSizeInt
 }
 
 func paginationToLimitOffset(page, pageSize int) (int, int) {
@@ -29,3 +62,51 @@ func paginationToLimitOffset(page, pageSize int) (int, int) {
 	offset := (page - 1) * pageSize
 	return pageSize, offset
 }
+
+// Cursors are opaque to clients, url safe base64 such that they can be put in a query string as is
+func encodeCursor(c dto.Cursor) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(c.CreatedAt.UTC().Format(time.RFC3339Nano) + " " + c.ID.String()))
+}
+
+func decodeCursor(s string) (*dto.Cursor, error) {
+	errInvalid := errors.New("cursor must be the nextCursor of a previous page")
+
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, errInvalid
+	}
+	createdAt, id, ok := strings.Cut(string(raw), " ")
+	if !ok {
+		return nil, errInvalid
+	}
+	t, err := time.Parse(time.RFC3339Nano, createdAt)
+	if err != nil {
+		return nil, errInvalid
+	}
+	parsedID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, errInvalid
+	}
+	return &dto.Cursor{CreatedAt: t, ID: parsedID}, nil
+}
+
+// Strong entity tag of a resource version, sent as ETag and compared with If-Match
+func versionETag(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
+
+// Whether the If-Match header of the request allows writing the resource tagged etag, requests without the header always do.
+// Weak tags never match, If-Match uses the strong comparison
+func ifMatch(ctx *fiber.Ctx, etag string) bool {
+	header := ctx.Get(fiber.HeaderIfMatch)
+	if header == "" {
+		return true
+	}
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || tag == etag {
+			return true
+		}
+	}
+	return false
+}
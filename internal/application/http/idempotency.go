@@ -0,0 +1,168 @@
+package http
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"social-media-go-ddd/internal/application/apperror"
+	"social-media-go-ddd/internal/infrastructure/cache"
+	"social-media-go-ddd/internal/infrastructure/logging"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	// Header clients set such that retrying a request applies it only once
+	HeaderIdempotencyKey = "Idempotency-Key"
+	// Set on responses replayed from an earlier request with the same key
+	HeaderIdempotentReplayed = "Idempotent-Replayed"
+
+	// Longer keys are rejected, a uuid is plenty
+	maxIdempotencyKeyLength = 255
+)
+
+// First response to a key, Status is zero while that request is still being handled
+type idempotentResponse struct {
+	// Hash of method, url and body, reusing the key for another request is rejected
+	Fingerprint string `json:"fingerprint"`
+	Status      int    `json:"status,omitempty"`
+	ContentType string `json:"contentType,omitempty"`
+	ETag        string `json:"etag,omitempty"`
+	Body        []byte `json:"body,omitempty"`
+}
+
+// Stores the first response of requests carrying an Idempotency-Key and replays it for retries, register it after the auth middleware
+type IdempotencyMiddleware struct {
+	cache cache.Cache
+	ttl   time.Duration
+}
+
+func NewIdempotencyMiddleware(c cache.Cache, ttl time.Duration) *IdempotencyMiddleware {
+	return &IdempotencyMiddleware{
+		cache: c,
+		ttl:   ttl,
+	}
+}
+
+func (m *IdempotencyMiddleware) Handler(ctx *fiber.Ctx) error {
+	key := ctx.Get(HeaderIdempotencyKey)
+	if key == "" || !isUnsafeMethod(ctx.Method()) {
+		return ctx.Next()
+	}
+	if len(key) > maxIdempotencyKeyLength {
+		return apperror.New(apperror.KindBadRequest, "invalid_idempotency_key", "Idempotency-Key must be at most 255 characters")
+	}
+
+	user, err := GetUserFromCtx(ctx)
+	if err != nil {
+		return err
+	}
+
+	cacheKey := idempotencyCacheKey(user.ID.String(), key)
+	fingerprint := requestFingerprint(ctx.Method(), ctx.OriginalURL(), ctx.Body())
+	pending, err := json.Marshal(idempotentResponse{Fingerprint: fingerprint})
+	if err != nil {
+		return err
+	}
+
+	acquired, err := m.cache.SetNX(ctx.UserContext(), cacheKey, pending, m.ttl)
+	if err != nil {
+		// Without the cache the request is handled as if it carried no key
+		logging.FromContext(ctx.UserContext()).Warn("cache set failed", "key", cache.KeyPrefix(cacheKey), "error", err)
+		return ctx.Next()
+	}
+	if !acquired {
+		return m.replay(ctx, cacheKey, fingerprint)
+	}
+
+	if err := ctx.Next(); err != nil {
+		handleError(ctx, err)
+	}
+	m.store(ctx, cacheKey, fingerprint)
+	return nil
+}
+
+// Answer a retry with the stored response
+func (m *IdempotencyMiddleware) replay(ctx *fiber.Ctx, cacheKey, fingerprint string) error {
+	value, err := m.cache.Get(ctx.UserContext(), cacheKey)
+	if cache.IsCacheMiss(err) {
+		// Expired or released between SetNX and Get, the client can simply retry
+		return idempotencyKeyInUse()
+	}
+	if err != nil {
+		logging.FromContext(ctx.UserContext()).Warn("cache get failed", "key", cache.KeyPrefix(cacheKey), "error", err)
+		return ctx.Next()
+	}
+
+	var stored idempotentResponse
+	if err := json.Unmarshal([]byte(value), &stored); err != nil {
+		return err
+	}
+	if stored.Fingerprint != fingerprint {
+		return apperror.New(apperror.KindValidation, "idempotency_key_reused", "Idempotency-Key was already used for a different request")
+	}
+	if stored.Status == 0 {
+		return idempotencyKeyInUse()
+	}
+
+	if stored.ContentType != "" {
+		ctx.Set(fiber.HeaderContentType, stored.ContentType)
+	}
+	if stored.ETag != "" {
+		ctx.Set(fiber.HeaderETag, stored.ETag)
+	}
+	ctx.Set(HeaderIdempotentReplayed, "true")
+	return ctx.Status(stored.Status).Send(stored.Body)
+}
+
+// Keep the response written for the first request, server errors are not kept such that a retry runs the request again
+func (m *IdempotencyMiddleware) store(ctx *fiber.Ctx, cacheKey, fingerprint string) {
+	resp := ctx.Response()
+	status := resp.StatusCode()
+	if status >= fiber.StatusInternalServerError {
+		if err := m.cache.Delete(ctx.UserContext(), cacheKey); err != nil {
+			logging.FromContext(ctx.UserContext()).Error("cache delete failed", "key", cache.KeyPrefix(cacheKey), "error", err)
+		}
+		return
+	}
+
+	value, err := json.Marshal(idempotentResponse{
+		Fingerprint: fingerprint,
+		Status:      status,
+		ContentType: string(resp.Header.ContentType()),
+		ETag:        string(resp.Header.Peek(fiber.HeaderETag)),
+		Body:        resp.Body(),
+	})
+	if err == nil {
+		err = m.cache.Set(ctx.UserContext(), cacheKey, value, m.ttl)
+	}
+	if err != nil {
+		logging.FromContext(ctx.UserContext()).Warn("cache set failed", "key", cache.KeyPrefix(cacheKey), "error", err)
+	}
+}
+
+func idempotencyKeyInUse() error {
+	return apperror.Conflict("idempotency_key_in_use", "a request with this Idempotency-Key is still being processed")
+}
+
+func isUnsafeMethod(method string) bool {
+	switch method {
+	case fiber.MethodPost, fiber.MethodPut, fiber.MethodPatch, fiber.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// Keys are scoped to the user, hashed such that the client chosen key never shows up in cache key logs
+func idempotencyCacheKey(userID, key string) string {
+	sum := sha256.Sum256([]byte(userID + "\n" + key))
+	return "idempotency:" + hex.EncodeToString(sum[:])
+}
+
+func requestFingerprint(method, url string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(method + " " + url + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,155 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	nethttp "net/http"
+	"net/http/httptest"
+	"social-media-go-ddd/internal/domain/aggregate"
+	cachememory "social-media-go-ddd/internal/infrastructure/cache/memory"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+func sendWithIdempotencyKey(t *testing.T, app *fiber.App, method, path, token, key string, body any) (*nethttp.Response, []byte) {
+	t.Helper()
+
+	var reader io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			t.Fatalf("marshal body: %v", err)
+		}
+		reader = bytes.NewReader(b)
+	}
+	req := httptest.NewRequest(method, path, reader)
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	if key != "" {
+		req.Header.Set(HeaderIdempotencyKey, key)
+	}
+
+	res, err := app.Test(req, -1)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, path, err)
+	}
+	defer res.Body.Close()
+	b, err := io.ReadAll(res.Body)
+	if err != nil {
+		t.Fatalf("read %s %s: %v", method, path, err)
+	}
+	return res, b
+}
+
+func TestIdempotencyKey(t *testing.T) {
+	app := newTestApp(t)
+	alice := registerAndLogin(t, app, "alice")
+	bob := registerAndLogin(t, app, "bob")
+
+	first, firstBody := sendWithIdempotencyKey(t, app, nethttp.MethodPost, "/api/v1/posts", alice, "create-1", fiber.Map{"content": "hello"})
+	if first.StatusCode != fiber.StatusOK || first.Header.Get(HeaderIdempotentReplayed) != "" {
+		t.Fatalf("first create: status %d replayed %q", first.StatusCode, first.Header.Get(HeaderIdempotentReplayed))
+	}
+	retry, retryBody := sendWithIdempotencyKey(t, app, nethttp.MethodPost, "/api/v1/posts", alice, "create-1", fiber.Map{"content": "hello"})
+	if retry.StatusCode != fiber.StatusOK || retry.Header.Get(HeaderIdempotentReplayed) != "true" {
+		t.Fatalf("retried create: status %d replayed %q, want 200 replayed", retry.StatusCode, retry.Header.Get(HeaderIdempotentReplayed))
+	}
+	if !bytes.Equal(firstBody, retryBody) {
+		t.Fatalf("retried create responded %s, want the first response %s", retryBody, firstBody)
+	}
+
+	// Another user has its own keys
+	other, _ := sendWithIdempotencyKey(t, app, nethttp.MethodPost, "/api/v1/posts", bob, "create-1", fiber.Map{"content": "hello"})
+	if other.StatusCode != fiber.StatusOK || other.Header.Get(HeaderIdempotentReplayed) != "" {
+		t.Fatalf("create of another user with the same key: status %d replayed %q", other.StatusCode, other.Header.Get(HeaderIdempotentReplayed))
+	}
+
+	status, resp := doRequest(t, app, nethttp.MethodGet, "/api/v1/users/me/posts", alice, nil)
+	var posts struct {
+		Posts []struct {
+			ID string `json:"id"`
+		} `json:"posts"`
+	}
+	if err := json.Unmarshal(resp.Data, &posts); err != nil || status != fiber.StatusOK {
+		t.Fatalf("my posts: status %d err %v", status, err)
+	}
+	if len(posts.Posts) != 1 {
+		t.Fatalf("alice has %d posts, want the retry to create none", len(posts.Posts))
+	}
+
+	res, b := sendWithIdempotencyKey(t, app, nethttp.MethodPost, "/api/v1/posts", alice, "create-1", fiber.Map{"content": "something else"})
+	var out testResponse
+	if err := json.Unmarshal(b, &out); err != nil {
+		t.Fatalf("decode reused key: %v", err)
+	}
+	if res.StatusCode != fiber.StatusUnprocessableEntity || out.Code != "idempotency_key_reused" {
+		t.Fatalf("key reused for another body: status %d code %q, want 422 idempotency_key_reused", res.StatusCode, out.Code)
+	}
+
+	// Without the key a second repost is a conflict, with it the retry gets the first answer
+	repostPath := "/api/v1/posts/" + posts.Posts[0].ID + "/repost"
+	for i := range 2 {
+		res, _ := sendWithIdempotencyKey(t, app, nethttp.MethodPost, repostPath, bob, "repost-1", nil)
+		if res.StatusCode != fiber.StatusOK || (res.Header.Get(HeaderIdempotentReplayed) == "true") != (i > 0) {
+			t.Fatalf("repost attempt %d: status %d replayed %q", i+1, res.StatusCode, res.Header.Get(HeaderIdempotentReplayed))
+		}
+	}
+
+	res, _ = sendWithIdempotencyKey(t, app, nethttp.MethodPost, "/api/v1/posts", alice, string(make([]byte, 256)), fiber.Map{"content": "hello"})
+	if res.StatusCode != fiber.StatusBadRequest {
+		t.Fatalf("overlong key: status %d, want 400", res.StatusCode)
+	}
+}
+
+func TestIdempotencyMiddleware(t *testing.T) {
+	c := cachememory.NewMemoryCache()
+	user := &aggregate.User{ID: uuid.New()}
+	calls := 0
+	fail := true
+
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	app.Use(func(ctx *fiber.Ctx) error {
+		ctx.Locals("user", user)
+		return ctx.Next()
+	}, NewIdempotencyMiddleware(c, time.Hour).Handler)
+	app.Post("/", func(ctx *fiber.Ctx) error {
+		calls++
+		if fail {
+			return errors.New("database went away")
+		}
+		return ctx.JSON(fiber.Map{"calls": calls})
+	})
+
+	// Server errors are not kept, the retry runs the handler again
+	res, _ := sendWithIdempotencyKey(t, app, nethttp.MethodPost, "/", "", "key", nil)
+	if res.StatusCode != fiber.StatusInternalServerError {
+		t.Fatalf("failing request: status %d", res.StatusCode)
+	}
+	fail = false
+	res, _ = sendWithIdempotencyKey(t, app, nethttp.MethodPost, "/", "", "key", nil)
+	if res.StatusCode != fiber.StatusOK || calls != 2 {
+		t.Fatalf("retry after a server error: status %d calls %d, want 200 after 2 calls", res.StatusCode, calls)
+	}
+
+	// A retry while the first request is still running is told to try again later
+	pending, _ := json.Marshal(idempotentResponse{Fingerprint: requestFingerprint(nethttp.MethodPost, "/", nil)})
+	if _, err := c.SetNX(context.Background(), idempotencyCacheKey(user.ID.String(), "in-flight"), pending, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	res, b := sendWithIdempotencyKey(t, app, nethttp.MethodPost, "/", "", "in-flight", nil)
+	var out testResponse
+	if err := json.Unmarshal(b, &out); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if res.StatusCode != fiber.StatusConflict || out.Code != "idempotency_key_in_use" || calls != 2 {
+		t.Fatalf("request with a key in use: status %d code %q calls %d, want 409 idempotency_key_in_use", res.StatusCode, out.Code, calls)
+	}
+}
@@ -0,0 +1,47 @@
+package http
+
+import (
+	"social-media-go-ddd/internal/infrastructure/metrics"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Records the duration and status of every request by route template, register it after RequestLogger and TracingMiddleware
+func MetricsMiddleware(m *metrics.Metrics) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		start := time.Now()
+
+		if err := ctx.Next(); err != nil {
+			handleError(ctx, err)
+		}
+
+		status := strconv.Itoa(ctx.Response().StatusCode())
+		// The method points into a buffer fiber reuses, labels are kept by the registry
+		method := utils.CopyString(ctx.Method())
+		m.HTTPRequestDuration.WithLabelValues(method, routeTemplate(ctx), status).Observe(time.Since(start).Seconds())
+		return nil
+	}
+}
+
+type MetricsHandler struct {
+	handler fiber.Handler
+}
+
+func NewMetricsHandler(m *metrics.Metrics) *MetricsHandler {
+	return &MetricsHandler{
+		handler: adaptor.HTTPHandler(promhttp.HandlerFor(m.Registry, promhttp.HandlerOpts{})),
+	}
+}
+
+func (h *MetricsHandler) RegisterRoutes(app *fiber.App) {
+	app.Get("/metrics", h.Metrics)
+}
+
+func (h *MetricsHandler) Metrics(ctx *fiber.Ctx) error {
+	return h.handler(ctx)
+}
@@ -0,0 +1,98 @@
+package http
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	nethttp "net/http"
+	"net/http/httptest"
+	"social-media-go-ddd/internal/infrastructure/metrics"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestMetrics(t *testing.T) {
+	m := metrics.NewMetrics()
+	app := newTestAppWith(t, slog.New(slog.DiscardHandler), m)
+	NewMetricsHandler(m).RegisterRoutes(app)
+
+	alice := registerAndLogin(t, app, "alice")
+	bob := registerAndLogin(t, app, "bob")
+	status, _ := doRequest(t, app, nethttp.MethodPost, "/api/v1/auth/login", "", fiber.Map{"username": "alice", "password": "wrong-password"})
+	if status != fiber.StatusUnauthorized {
+		t.Fatalf("login with wrong password: status %d", status)
+	}
+
+	_, resp := doRequest(t, app, nethttp.MethodGet, "/api/v1/users/me", bob, nil)
+	var me struct {
+		User struct {
+			ID string `json:"id"`
+		} `json:"user"`
+	}
+	if err := json.Unmarshal(resp.Data, &me); err != nil {
+		t.Fatalf("decode me: %v", err)
+	}
+	if status, _ := doRequest(t, app, nethttp.MethodPost, "/api/v1/users/"+me.User.ID+"/follow", alice, nil); status != fiber.StatusOK {
+		t.Fatalf("follow: status %d", status)
+	}
+
+	_, resp = doRequest(t, app, nethttp.MethodPost, "/api/v1/posts", alice, fiber.Map{"content": "hello"})
+	var created struct {
+		Post struct {
+			ID string `json:"id"`
+		} `json:"post"`
+	}
+	if err := json.Unmarshal(resp.Data, &created); err != nil {
+		t.Fatalf("decode post: %v", err)
+	}
+	if status, _ := doRequest(t, app, nethttp.MethodPost, "/api/v1/posts/"+created.Post.ID+"/like", bob, nil); status != fiber.StatusOK {
+		t.Fatalf("like: status %d", status)
+	}
+	// Liking again and changing the reaction create no like
+	if status, _ := doRequest(t, app, nethttp.MethodPost, "/api/v1/posts/"+created.Post.ID+"/like", bob, nil); status != fiber.StatusOK {
+		t.Fatalf("like again: status %d", status)
+	}
+	if status, _ := doRequest(t, app, nethttp.MethodPut, "/api/v1/posts/"+created.Post.ID+"/reaction", bob, fiber.Map{"reaction": "love"}); status != fiber.StatusOK {
+		t.Fatalf("react: status %d", status)
+	}
+	doRequest(t, app, nethttp.MethodGet, "/api/v1/public/posts/"+created.Post.ID, "", nil)
+	doRequest(t, app, nethttp.MethodGet, "/no/such/route", "", nil)
+
+	resp2, err := app.Test(httptest.NewRequest(nethttp.MethodGet, "/metrics", nil), -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ct := resp2.Header.Get(fiber.HeaderContentType); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Fatalf("content type %q", ct)
+	}
+	b, err := io.ReadAll(resp2.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := string(b)
+
+	for _, want := range []string{
+		`http_request_duration_seconds_count{method="POST",route="/api/v1/posts/:id/like",status="200"} 2`,
+		`http_request_duration_seconds_count{method="POST",route="/api/v1/auth/login",status="401"} 1`,
+		`http_request_duration_seconds_count{method="GET",route="unmatched",status="404"} 1`,
+		`db_query_duration_seconds_count{method="Save",repository="post",result="ok"} 1`,
+		`db_query_duration_seconds_count{method="Save",repository="user",result="ok"} 2`,
+		`cache_operations_total{operation="get",result="hit"}`,
+		`cache_operations_total{operation="get",result="miss"}`,
+		`cache_operations_total{operation="delete",result="ok"}`,
+		"posts_created_total 1\n",
+		"likes_created_total 1\n",
+		"follows_created_total 1\n",
+		`logins_total{result="failed"} 1` + "\n",
+		`logins_total{result="succeeded"} 2` + "\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics miss %s", want)
+		}
+	}
+	if t.Failed() {
+		t.Log(body)
+	}
+}
@@ -0,0 +1,321 @@
+package http
+
+import (
+	nethttp "net/http"
+	"regexp"
+	"social-media-go-ddd/internal/application/apperror"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type OpenAPI struct {
+	OpenAPI    string                           `json:"openapi"`
+	Info       OpenAPIInfo                      `json:"info"`
+	Paths      map[string]map[string]*Operation `json:"paths"`
+	Components OpenAPIComponents                `json:"components"`
+}
+
+type OpenAPIInfo struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+type OpenAPIComponents struct {
+	Schemas         map[string]*Schema          `json:"schemas"`
+	Responses       map[string]*OpenAPIResponse `json:"responses"`
+	SecuritySchemes map[string]*SecurityScheme  `json:"securitySchemes"`
+}
+
+type SecurityScheme struct {
+	Type   string `json:"type"`
+	Scheme string `json:"scheme"`
+}
+
+type Operation struct {
+	OperationID string                      `json:"operationId"`
+	Summary     string                      `json:"summary"`
+	Tags        []string                    `json:"tags"`
+	Parameters  []Parameter                 `json:"parameters,omitempty"`
+	RequestBody *RequestBody                `json:"requestBody,omitempty"`
+	Responses   map[string]*OpenAPIResponse `json:"responses"`
+	Security    []map[string][]string       `json:"security,omitempty"`
+}
+
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Required    bool    `json:"required"`
+	Description string  `json:"description,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+type OpenAPIResponse struct {
+	Ref         string                    `json:"$ref,omitempty"`
+	Description string                    `json:"description,omitempty"`
+	Headers     map[string]*OpenAPIHeader `json:"headers,omitempty"`
+	Content     map[string]MediaType      `json:"content,omitempty"`
+}
+
+type OpenAPIHeader struct {
+	Description string  `json:"description,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type authMode int
+
+const (
+	authNone authMode = iota
+	// Bearer token required, the route is behind AuthMiddleware
+	authRequired
+	// A bearer token is read when present, eg to tell if the viewer liked a post
+	authOptional
+)
+
+// Documentation of one route registered by a handler
+type apiRoute struct {
+	Method string
+	// Fiber path, eg /api/v1/posts/:id
+	Path    string
+	Name    string
+	Summary string
+	Tag     string
+	Auth    authMode
+	Query   []Parameter
+	// Request headers, eg If-Match
+	Headers []Parameter
+	// Headers of the success response, eg ETag
+	ResponseHeaders map[string]*OpenAPIHeader
+	Body            *Schema
+	// Schema of the data field of the envelope, nil when the route responds without data
+	Data *Schema
+	// Error statuses besides 401 for authenticated routes, 422 for routes with params or a body,
+	// 400 and 413 for routes with a body and 500
+	Errors []int
+}
+
+// Object schema of the fiber.Map a handler responds with, values are Go values or *Schema
+type fields map[string]any
+
+func (g *schemaGenerator) object(f fields) *Schema {
+	s := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	for name, v := range f {
+		s.Properties[name] = g.schemaOf(v)
+		s.Required = append(s.Required, name)
+	}
+	sort.Strings(s.Required)
+	return s
+}
+
+var paginationQuery = []Parameter{
+	{Name: "page", In: "query", Description: "Page number starting at 1", Schema: &Schema{Type: "integer"}},
+	{Name: "pageSize", In: "query", Description: "Items per page, between 1 and 100, defaults to 20", Schema: &Schema{Type: "integer"}},
+}
+
+var ifMatchHeader = []Parameter{
+	{Name: fiber.HeaderIfMatch, In: "header", Description: "ETag of the version the update is made from, the update fails with 412 when the resource changed since", Schema: &Schema{Type: "string"}},
+}
+
+var etagHeader = map[string]*OpenAPIHeader{
+	fiber.HeaderETag: {Description: "Version of the resource, send it as If-Match to update it", Schema: &Schema{Type: "string"}},
+}
+
+var idempotencyKeyHeader = Parameter{
+	Name: HeaderIdempotencyKey, In: "header", Schema: &Schema{Type: "string"},
+	Description: "Unique key of the request of at most 255 characters, retries with the same key get the first response replayed with Idempotent-Replayed: true",
+}
+
+var cursorQuery = []Parameter{
+	{Name: "cursor", In: "query", Description: "nextCursor of the previous page, left out for the first page", Schema: &Schema{Type: "string"}},
+	{Name: "pageSize", In: "query", Description: "Items per page, between 1 and 100, defaults to 20", Schema: &Schema{Type: "integer"}},
+}
+
+// Error responses shared by the operations, the description lists the codes a client may see
+var errorResponses = map[int]struct {
+	name  string
+	codes []string
+}{
+	fiber.StatusBadRequest:            {"BadRequest", []string{apperror.CodeInvalidBody, "invalid_idempotency_key"}},
+	fiber.StatusUnauthorized:          {"Unauthorized", []string{"invalid_token", "invalid_session", "session_expired", "invalid_user", "invalid_credentials"}},
+	fiber.StatusForbidden:             {"Forbidden", []string{"not_post_owner", "edit_window_expired", "repost_not_public", "quote_not_public", "pin_not_allowed"}},
+	fiber.StatusNotFound:              {"NotFound", []string{"user_not_found", "post_not_found", "poll_not_found", "collection_not_found", apperror.CodeNotFound}},
+	fiber.StatusConflict:              {"Conflict", []string{"user_already_exists", "post_already_published", "post_not_published", "poll_closed", "poll_already_voted", "pin_limit_reached", "collection_already_exists", "default_collection", "idempotency_key_in_use"}},
+	fiber.StatusPreconditionFailed:    {"PreconditionFailed", []string{apperror.CodeVersionMismatch}},
+	fiber.StatusRequestEntityTooLarge: {"PayloadTooLarge", []string{apperror.CodeBodyTooLarge}},
+	fiber.StatusUnprocessableEntity:   {"UnprocessableEntity", []string{apperror.CodeValidationFailed, "idempotency_key_reused"}},
+	fiber.StatusInternalServerError:   {"InternalServerError", []string{apperror.CodeInternal}},
+}
+
+var pathParamRe = regexp.MustCompile(`:(\w+)`)
+
+// Fiber path to an OpenAPI path, eg /posts/:id to /posts/{id}
+func openAPIPath(path string) string {
+	if len(path) > 1 {
+		path = strings.TrimSuffix(path, "/")
+	}
+	return pathParamRe.ReplaceAllString(path, "{$1}")
+}
+
+func responseEnvelope(data *Schema) *Schema {
+	s := &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"success": {Type: "boolean"},
+			"message": {Type: "string"},
+			"status":  {Type: "integer"},
+		},
+		Required: []string{"message", "status", "success"},
+	}
+	if data != nil {
+		s.Properties["data"] = data
+		s.Required = append(s.Required, "data")
+		sort.Strings(s.Required)
+	}
+	return s
+}
+
+func (g *schemaGenerator) errorEnvelope() *Schema {
+	s := responseEnvelope(nil)
+	s.Properties["error"] = &Schema{Type: "string", Description: "Human readable message"}
+	s.Properties["code"] = &Schema{Type: "string", Description: "Stable machine readable error code"}
+	s.Properties["details"] = &Schema{Type: "array", Items: g.schemaOf(apperror.FieldError{}), Description: "Invalid fields of a validation error"}
+	s.Required = []string{"code", "error", "message", "status", "success"}
+	return s
+}
+
+func jsonContent(s *Schema) map[string]MediaType {
+	return map[string]MediaType{fiber.MIMEApplicationJSON: {Schema: s}}
+}
+
+func (r apiRoute) operation() *Operation {
+	op := &Operation{
+		OperationID: r.Name,
+		Summary:     r.Summary,
+		Tags:        []string{r.Tag},
+		Parameters:  append(append([]Parameter{}, r.Query...), r.Headers...),
+		Responses: map[string]*OpenAPIResponse{
+			"200": {Description: "Success", Headers: r.ResponseHeaders, Content: jsonContent(responseEnvelope(r.Data))},
+		},
+	}
+
+	statuses := append([]int{}, r.Errors...)
+	for _, m := range pathParamRe.FindAllStringSubmatch(r.Path, -1) {
+		p := Parameter{Name: m[1], In: "path", Required: true, Schema: &Schema{Type: "string"}}
+		if m[1] == "id" {
+			p.Schema.Format = "uuid"
+		}
+		op.Parameters = append(op.Parameters, p)
+	}
+
+	if len(op.Parameters) > len(r.Query)+len(r.Headers) {
+		statuses = append(statuses, fiber.StatusUnprocessableEntity)
+	}
+	if r.Body != nil {
+		op.RequestBody = &RequestBody{Required: true, Content: jsonContent(r.Body)}
+		statuses = append(statuses, fiber.StatusBadRequest, fiber.StatusRequestEntityTooLarge, fiber.StatusUnprocessableEntity)
+	}
+
+	switch r.Auth {
+	case authRequired:
+		op.Security = []map[string][]string{{"bearerAuth": {}}}
+		statuses = append(statuses, fiber.StatusUnauthorized)
+		if isUnsafeMethod(r.Method) {
+			op.Parameters = append(op.Parameters, idempotencyKeyHeader)
+			statuses = append(statuses, fiber.StatusBadRequest, fiber.StatusConflict, fiber.StatusUnprocessableEntity)
+		}
+	case authOptional:
+		op.Security = []map[string][]string{{}, {"bearerAuth": {}}}
+	}
+	statuses = append(statuses, fiber.StatusInternalServerError)
+	for _, status := range statuses {
+		op.Responses[strconv.Itoa(status)] = &OpenAPIResponse{Ref: "#/components/responses/" + errorResponses[status].name}
+	}
+	return op
+}
+
+// Spec of every route in apiRoutes, built from the DTOs such that it cannot drift from them
+func NewOpenAPI() *OpenAPI {
+	g := newSchemaGenerator()
+	spec := &OpenAPI{
+		OpenAPI: "3.0.3",
+		Info: OpenAPIInfo{
+			Title:       "Social Media API",
+			Version:     "1.0.0",
+			Description: "Every response is wrapped in the same envelope, failed requests carry a stable error code.",
+		},
+		Paths: map[string]map[string]*Operation{},
+		Components: OpenAPIComponents{
+			Responses: map[string]*OpenAPIResponse{},
+			SecuritySchemes: map[string]*SecurityScheme{
+				"bearerAuth": {Type: "http", Scheme: "bearer"},
+			},
+		},
+	}
+
+	for _, r := range apiRoutes(g) {
+		path := openAPIPath(r.Path)
+		if spec.Paths[path] == nil {
+			spec.Paths[path] = map[string]*Operation{}
+		}
+		spec.Paths[path][strings.ToLower(r.Method)] = r.operation()
+	}
+
+	errorSchema := g.errorEnvelope()
+	for status, e := range errorResponses {
+		spec.Components.Responses[e.name] = &OpenAPIResponse{
+			Description: nethttp.StatusText(status) + ", codes: " + strings.Join(e.codes, ", "),
+			Content:     jsonContent(errorSchema),
+		}
+	}
+	spec.Components.Schemas = g.schemas
+	return spec
+}
+
+const redocHTML = `<!DOCTYPE html>
+<html>
+<head>
+  <title>Social Media API</title>
+  <meta charset="utf-8"/>
+  <meta name="viewport" content="width=device-width, initial-scale=1">
+</head>
+<body>
+  <redoc spec-url="/api/openapi.json"></redoc>
+  <script src="https://cdn.redoc.ly/redoc/latest/bundles/redoc.standalone.js"></script>
+</body>
+</html>`
+
+type DocsHandler struct {
+	spec *OpenAPI
+}
+
+func NewDocsHandler() *DocsHandler {
+	return &DocsHandler{
+		spec: NewOpenAPI(),
+	}
+}
+
+func (h *DocsHandler) RegisterRoutes(app *fiber.App) {
+	app.Get("/api/openapi.json", h.Spec)
+	app.Get("/api/docs", h.UI)
+}
+
+func (h *DocsHandler) Spec(ctx *fiber.Ctx) error {
+	return ctx.JSON(h.spec)
+}
+
+func (h *DocsHandler) UI(ctx *fiber.Ctx) error {
+	ctx.Type("html")
+	return ctx.SendString(redocHTML)
+}
@@ -0,0 +1,259 @@
+package http
+
+import (
+	"social-media-go-ddd/internal/domain/aggregate"
+	"social-media-go-ddd/internal/domain/dto"
+	"social-media-go-ddd/internal/domain/entity"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Every route of UserHandler, PostHandler and CollectionHandler, a test fails when a registered route is missing here
+func apiRoutes(g *schemaGenerator) []apiRoute {
+	posts := []aggregate.Post{}
+	session := fields{"session": entity.Session{}, "user": dto.UserAggregateResponse{}}
+
+	return []apiRoute{
+		// Auth
+		{
+			Method: fiber.MethodPost, Path: "/api/v1/auth/register", Name: "register", Tag: "auth",
+			Summary: "Create a user",
+			Body:    g.requestBody(dto.NewUser{}),
+			Data:    g.object(fields{"user": dto.UserResponse{}}),
+			Errors:  []int{fiber.StatusConflict},
+		},
+		{
+			Method: fiber.MethodPost, Path: "/api/v1/auth/login", Name: "login", Tag: "auth",
+			Summary: "Create a session, its id is the bearer token",
+			Body:    g.requestBody(dto.UserLogin{}),
+			Data:    g.object(session),
+			Errors:  []int{fiber.StatusUnauthorized, fiber.StatusNotFound},
+		},
+		{
+			Method: fiber.MethodDelete, Path: "/api/v1/auth/logout", Name: "logout", Tag: "auth", Auth: authRequired,
+			Summary: "Delete the current session",
+		},
+
+		// Current user
+		{
+			Method: fiber.MethodGet, Path: "/api/v1/users/me", Name: "getMe", Tag: "users", Auth: authRequired,
+			Summary: "Current user and session, the session is extended when it expires within a day",
+			Data:    g.object(session),
+		},
+		{
+			Method: fiber.MethodGet, Path: "/api/v1/users/me/posts", Name: "getMyPosts", Tag: "users", Auth: authRequired,
+			Summary: "Posts of the current user, newest first",
+			Query:   cursorQuery,
+			Data:    g.object(fields{"posts": posts, "pagination": CursorPagination{}}),
+			Errors:  []int{fiber.StatusUnprocessableEntity},
+		},
+		{
+			Method: fiber.MethodGet, Path: "/api/v1/users/me/feed", Name: "getMyFeed", Tag: "users", Auth: authRequired,
+			Summary: "Posts and reposts of the current user and the users they follow, newest first",
+			Query:   paginationQuery,
+			Data:    g.object(fields{"feed": posts, "pagination": Pagination{}}),
+		},
+		{
+			Method: fiber.MethodGet, Path: "/api/v1/users/me/posts/favorites", Name: "getMyFavoritePosts", Tag: "users", Auth: authRequired,
+			Summary: "Posts favorited by the current user, most recently favorited first",
+			Query:   cursorQuery,
+			Data:    g.object(fields{"posts": posts, "pagination": CursorPagination{}}),
+			Errors:  []int{fiber.StatusUnprocessableEntity},
+		},
+		{
+			Method: fiber.MethodGet, Path: "/api/v1/users/me/reposts", Name: "getMyReposts", Tag: "users", Auth: authRequired,
+			Summary: "Reposts of the current user, newest first",
+			Query:   cursorQuery,
+			Data:    g.object(fields{"reposts": posts, "pagination": CursorPagination{}}),
+			Errors:  []int{fiber.StatusUnprocessableEntity},
+		},
+		{
+			Method: fiber.MethodGet, Path: "/api/v1/users/me/drafts", Name: "getMyDrafts", Tag: "users", Auth: authRequired,
+			Summary: "Drafts and scheduled posts of the current user, newest first",
+			Query:   cursorQuery,
+			Data:    g.object(fields{"posts": []entity.Post{}, "pagination": CursorPagination{}}),
+			Errors:  []int{fiber.StatusUnprocessableEntity},
+		},
+		{
+			Method: fiber.MethodPost, Path: "/api/v1/users/:id/follow", Name: "followUser", Tag: "users", Auth: authRequired,
+			Summary: "Follow a user",
+			Errors:  []int{fiber.StatusNotFound},
+		},
+		{
+			Method: fiber.MethodDelete, Path: "/api/v1/users/:id/follow", Name: "unfollowUser", Tag: "users", Auth: authRequired,
+			Summary: "Unfollow a user",
+			Errors:  []int{fiber.StatusNotFound},
+		},
+
+		// Public users
+		{
+			Method: fiber.MethodGet, Path: "/api/v1/public/users/name/:name", Name: "searchUsersByName", Tag: "users", Auth: authOptional,
+			Summary: "Users whose name contains the given name",
+			Data:    g.object(fields{"users": []dto.UserAggregateResponse{}}),
+			Errors:  []int{fiber.StatusNotFound},
+		},
+		{
+			Method: fiber.MethodGet, Path: "/api/v1/public/users/:id", Name: "getUser", Tag: "users", Auth: authOptional,
+			Summary: "A user, followed tells if the viewer follows them",
+			Data:    g.object(fields{"user": dto.UserAggregateResponse{}}),
+			Errors:  []int{fiber.StatusNotFound},
+		},
+		{
+			Method: fiber.MethodGet, Path: "/api/v1/public/users/:id/posts", Name: "getUserPosts", Tag: "users", Auth: authOptional,
+			Summary: "Posts of a user, newest first, liked, favorited and reposted are relative to the viewer. The first page starts with the posts they pinned",
+			Query:   cursorQuery,
+			Data:    g.object(fields{"posts": posts, "pagination": CursorPagination{}}),
+		},
+		{
+			Method: fiber.MethodGet, Path: "/api/v1/public/users/:id/reposts", Name: "getUserReposts", Tag: "users", Auth: authOptional,
+			Summary: "Reposts of a user, newest first, liked, favorited and reposted are relative to the viewer",
+			Query:   cursorQuery,
+			Data:    g.object(fields{"reposts": posts, "pagination": CursorPagination{}}),
+		},
+
+		// Posts
+		{
+			Method: fiber.MethodGet, Path: "/api/v1/public/posts/:id", Name: "getPost", Tag: "posts", Auth: authOptional,
+			Summary:         "A post, liked, favorited and reposted are relative to the viewer",
+			Data:            g.object(fields{"post": aggregate.Post{}}),
+			ResponseHeaders: etagHeader,
+			Errors:          []int{fiber.StatusNotFound},
+		},
+		{
+			Method: fiber.MethodGet, Path: "/api/v1/public/posts/:id/revisions", Name: "getPostRevisions", Tag: "posts",
+			Summary: "Earlier contents of a post, one revision per edit, most recently edited first",
+			Data:    g.object(fields{"revisions": []entity.PostRevision{}}),
+			Errors:  []int{fiber.StatusNotFound},
+		},
+		{
+			Method: fiber.MethodPost, Path: "/api/v1/posts/", Name: "createPost", Tag: "posts", Auth: authRequired,
+			Summary: "Create a post, a draft or a post scheduled to be published at publish_at. Visibility limits who besides the author sees it to followers or mentioned users. A post quoting a public post has the type quote. A poll with 2 to 4 options can be attached",
+			Body:    g.requestBody(dto.NewPost{}),
+			Data:    g.object(fields{"post": entity.Post{}}),
+			Errors:  []int{fiber.StatusForbidden, fiber.StatusNotFound, fiber.StatusConflict},
+		},
+		{
+			Method: fiber.MethodPut, Path: "/api/v1/posts/:id", Name: "updatePost", Tag: "posts", Auth: authRequired,
+			Summary:         "Update the content of an own post, the previous content is kept as a revision. Past the edit window posts can no longer be updated",
+			Headers:         ifMatchHeader,
+			Body:            g.requestBody(dto.UpdatePost{}),
+			Data:            g.object(fields{"post": entity.Post{}}),
+			ResponseHeaders: etagHeader,
+			Errors:          []int{fiber.StatusForbidden, fiber.StatusNotFound, fiber.StatusPreconditionFailed},
+		},
+		{
+			Method: fiber.MethodDelete, Path: "/api/v1/posts/:id", Name: "deletePost", Tag: "posts", Auth: authRequired,
+			Summary: "Delete an own post",
+			Errors:  []int{fiber.StatusForbidden, fiber.StatusNotFound},
+		},
+		{
+			Method: fiber.MethodPost, Path: "/api/v1/posts/:id/publish", Name: "publishPost", Tag: "posts", Auth: authRequired,
+			Summary:         "Publish an own draft or scheduled post right away",
+			Data:            g.object(fields{"post": entity.Post{}}),
+			ResponseHeaders: etagHeader,
+			Errors:          []int{fiber.StatusForbidden, fiber.StatusNotFound, fiber.StatusConflict},
+		},
+		{
+			Method: fiber.MethodPost, Path: "/api/v1/posts/:id/poll/vote", Name: "votePoll", Tag: "posts", Auth: authRequired,
+			Summary: "Vote in the poll of a post, once per user. Tallies are only shown to voters and after the poll closed",
+			Body:    g.requestBody(dto.NewPollVote{}),
+			Data:    g.object(fields{"poll": aggregate.Poll{}}),
+			Errors:  []int{fiber.StatusNotFound, fiber.StatusConflict},
+		},
+		{
+			Method: fiber.MethodPost, Path: "/api/v1/posts/:id/like", Name: "likePost", Tag: "posts", Auth: authRequired,
+			Summary: "Like a post, the reaction like. Does nothing if already liked or reacted",
+			Errors:  []int{fiber.StatusNotFound, fiber.StatusConflict},
+		},
+		{
+			Method: fiber.MethodDelete, Path: "/api/v1/posts/:id/like", Name: "unlikePost", Tag: "posts", Auth: authRequired,
+			Summary: "Remove the like or reaction of a post",
+			Errors:  []int{fiber.StatusNotFound},
+		},
+		{
+			Method: fiber.MethodPut, Path: "/api/v1/posts/:id/reaction", Name: "reactPost", Tag: "posts", Auth: authRequired,
+			Summary: "React to a post with like, love, haha, wow, sad or angry, replacing the reaction of the viewer. A reaction counts as a like",
+			Body:    g.requestBody(dto.NewLike{}),
+			Errors:  []int{fiber.StatusNotFound, fiber.StatusConflict},
+		},
+		{
+			Method: fiber.MethodDelete, Path: "/api/v1/posts/:id/reaction", Name: "unreactPost", Tag: "posts", Auth: authRequired,
+			Summary: "Remove the reaction of a post, same as removing the like",
+			Errors:  []int{fiber.StatusNotFound},
+		},
+		{
+			Method: fiber.MethodPost, Path: "/api/v1/posts/:id/favorite", Name: "favoritePost", Tag: "posts", Auth: authRequired,
+			Summary: "Favorite a post in the default collection, does nothing if already favorited",
+			Errors:  []int{fiber.StatusNotFound, fiber.StatusConflict},
+		},
+		{
+			Method: fiber.MethodDelete, Path: "/api/v1/posts/:id/favorite", Name: "unfavoritePost", Tag: "posts", Auth: authRequired,
+			Summary: "Remove a post from the favorites",
+			Errors:  []int{fiber.StatusNotFound},
+		},
+		{
+			Method: fiber.MethodPost, Path: "/api/v1/posts/:id/repost", Name: "repostPost", Tag: "posts", Auth: authRequired,
+			Summary: "Repost a public post with an optional comment, reposting again replaces the comment",
+			Body:    g.requestBody(dto.NewRepost{}),
+			Data:    g.object(fields{"repost": entity.Repost{}}),
+			Errors:  []int{fiber.StatusForbidden, fiber.StatusNotFound, fiber.StatusConflict},
+		},
+		{
+			Method: fiber.MethodDelete, Path: "/api/v1/posts/:id/repost", Name: "unrepostPost", Tag: "posts", Auth: authRequired,
+			Summary: "Remove the repost of a post",
+		},
+		{
+			Method: fiber.MethodPost, Path: "/api/v1/posts/:id/pin", Name: "pinPost", Tag: "posts", Auth: authRequired,
+			Summary: "Pin an own post, or a reposted one, to the top of the profile, does nothing if already pinned",
+			Errors:  []int{fiber.StatusForbidden, fiber.StatusNotFound, fiber.StatusConflict},
+		},
+		{
+			Method: fiber.MethodDelete, Path: "/api/v1/posts/:id/pin", Name: "unpinPost", Tag: "posts", Auth: authRequired,
+			Summary: "Unpin a post from the profile",
+		},
+
+		// Collections
+		{
+			Method: fiber.MethodGet, Path: "/api/v1/collections/", Name: "getCollections", Tag: "collections", Auth: authRequired,
+			Summary: "Collections of the current user, the default collection first. Collections are private and a favorited post is in exactly one of them",
+			Data:    g.object(fields{"collections": []entity.Collection{}}),
+		},
+		{
+			Method: fiber.MethodPost, Path: "/api/v1/collections/", Name: "createCollection", Tag: "collections", Auth: authRequired,
+			Summary: "Create a named collection of favorites",
+			Body:    g.requestBody(dto.NewCollection{}),
+			Data:    g.object(fields{"collection": entity.Collection{}}),
+			Errors:  []int{fiber.StatusConflict},
+		},
+		{
+			Method: fiber.MethodPut, Path: "/api/v1/collections/:id", Name: "updateCollection", Tag: "collections", Auth: authRequired,
+			Summary: "Rename an own collection, the default collection included",
+			Body:    g.requestBody(dto.UpdateCollection{}),
+			Data:    g.object(fields{"collection": entity.Collection{}}),
+			Errors:  []int{fiber.StatusNotFound, fiber.StatusConflict},
+		},
+		{
+			Method: fiber.MethodDelete, Path: "/api/v1/collections/:id", Name: "deleteCollection", Tag: "collections", Auth: authRequired,
+			Summary: "Delete an own collection, its posts stay favorited in the default collection, which cannot be deleted",
+			Errors:  []int{fiber.StatusNotFound, fiber.StatusConflict},
+		},
+		{
+			Method: fiber.MethodGet, Path: "/api/v1/collections/:id/posts", Name: "getCollectionPosts", Tag: "collections", Auth: authRequired,
+			Summary: "Posts in an own collection, most recently favorited first",
+			Query:   cursorQuery,
+			Data:    g.object(fields{"posts": posts, "pagination": CursorPagination{}}),
+			Errors:  []int{fiber.StatusNotFound},
+		},
+		{
+			Method: fiber.MethodPost, Path: "/api/v1/collections/:id/posts", Name: "addCollectionPost", Tag: "collections", Auth: authRequired,
+			Summary: "Favorite a post in an own collection, a post already favorited moves there from its other collection",
+			Body:    g.requestBody(dto.NewFavorite{}),
+			Errors:  []int{fiber.StatusNotFound, fiber.StatusConflict},
+		},
+		{
+			Method: fiber.MethodDelete, Path: "/api/v1/collections/:id/posts/:post_id", Name: "removeCollectionPost", Tag: "collections", Auth: authRequired,
+			Summary: "Remove a post from an own collection and with it from the favorites, does nothing if it is not in the collection",
+			Errors:  []int{fiber.StatusNotFound},
+		},
+	}
+}
@@ -0,0 +1,233 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"social-media-go-ddd/internal/application/validation"
+	"social-media-go-ddd/internal/domain/aggregate"
+	"social-media-go-ddd/internal/domain/entity"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *bool              `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AllOf                []*Schema          `json:"allOf,omitempty"`
+}
+
+var (
+	timeType = reflect.TypeOf(time.Time{})
+	uuidType = reflect.TypeOf(uuid.UUID{})
+)
+
+// Values of string types used as enums
+var enumValues = map[reflect.Type][]string{
+	reflect.TypeOf(aggregate.PostType("")):    {string(aggregate.PostTypeText), string(aggregate.PostTypeRepost), string(aggregate.PostTypeQuote)},
+	reflect.TypeOf(entity.PostStatus("")):     {string(entity.PostStatusPublished), string(entity.PostStatusDraft), string(entity.PostStatusScheduled)},
+	reflect.TypeOf(entity.PostVisibility("")): {string(entity.PostVisibilityPublic), string(entity.PostVisibilityFollowers), string(entity.PostVisibilityMentioned)},
+	reflect.TypeOf(entity.PollChoice("")):     {string(entity.PollChoiceSingle), string(entity.PollChoiceMultiple)},
+}
+
+// Properties added by a custom MarshalJSON, every type with one must be listed here
+var marshalerProperties = map[reflect.Type]map[string]*Schema{
+	reflect.TypeOf(entity.User{}): {
+		"password": {Type: "string", Description: "bcrypt hash of the password"},
+	},
+}
+
+// Builds schemas from the json tags of Go types, named structs end up in components/schemas
+type schemaGenerator struct {
+	schemas map[string]*Schema
+	names   map[reflect.Type]string
+}
+
+func newSchemaGenerator() *schemaGenerator {
+	return &schemaGenerator{
+		schemas: map[string]*Schema{},
+		names:   map[reflect.Type]string{},
+	}
+}
+
+// Name of the component, aggregates get a suffix such that they do not collide with the entity of the same name
+func schemaName(t reflect.Type) string {
+	pkg := t.PkgPath()[strings.LastIndex(t.PkgPath(), "/")+1:]
+	if pkg == "aggregate" {
+		return t.Name() + "Aggregate"
+	}
+	return t.Name()
+}
+
+func (g *schemaGenerator) schemaOf(v any) *Schema {
+	if s, ok := v.(*Schema); ok {
+		return s
+	}
+	return g.schema(reflect.TypeOf(v))
+}
+
+func (g *schemaGenerator) schema(t reflect.Type) *Schema {
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case uuidType:
+		return &Schema{Type: "string", Format: "uuid"}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		s := g.schema(t.Elem())
+		if s.Ref != "" {
+			return &Schema{AllOf: []*Schema{s}, Nullable: true}
+		}
+		s.Nullable = true
+		return s
+	case reflect.String:
+		return &Schema{Type: "string", Enum: enumValues[t]}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: g.schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object"}
+	case reflect.Interface:
+		return &Schema{}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+		return g.ref(t)
+	}
+	panic(fmt.Sprintf("openapi: unsupported type %s", t))
+}
+
+// Request body of a dto, readonly fields are left out and the validate tags become constraints
+func (g *schemaGenerator) requestBody(v any) *Schema {
+	t := reflect.TypeOf(v)
+	s := &Schema{Type: "object", Properties: map[string]*Schema{}, AdditionalProperties: new(bool)}
+	for name := range validation.BodyFields(t) {
+		f := bodyField(t, name)
+		p := g.schema(f.Type)
+		for _, rule := range strings.Split(f.Tag.Get("validate"), ",") {
+			rule, arg, _ := strings.Cut(rule, "=")
+			switch rule {
+			case "required":
+				s.Required = append(s.Required, name)
+				// Blank strings are rejected as well
+				if p.Pattern == "" {
+					p.Pattern = `\S`
+				}
+			case "min":
+				n, _ := strconv.Atoi(arg)
+				p.MinLength = &n
+			case "max":
+				n, _ := strconv.Atoi(arg)
+				p.MaxLength = &n
+			case "email":
+				p.Format = "email"
+			case "username":
+				p.Pattern = `^[A-Za-z0-9_.-]+$`
+			case "uuid":
+				p.Format = "uuid"
+			}
+		}
+		s.Properties[name] = p
+	}
+	sort.Strings(s.Required)
+	return s
+}
+
+func bodyField(t reflect.Type, name string) reflect.StructField {
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if f.Anonymous && f.Tag.Get("json") == "" && f.Type.Kind() == reflect.Struct {
+			if validation.BodyFields(f.Type)[name] {
+				return bodyField(f.Type, name)
+			}
+			continue
+		}
+		if validation.JSONName(f) == name {
+			return f
+		}
+	}
+	panic(fmt.Sprintf("openapi: %s has no field %s", t, name))
+}
+
+func (g *schemaGenerator) ref(t reflect.Type) *Schema {
+	name, ok := g.names[t]
+	if !ok {
+		name = schemaName(t)
+		g.names[t] = name
+		// Registered before generating the fields, such that recursive types terminate
+		g.schemas[name] = nil
+		g.schemas[name] = g.structSchema(t)
+	}
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+func (g *schemaGenerator) structSchema(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	g.addFields(s, t)
+
+	if reflect.PointerTo(t).Implements(reflect.TypeOf((*json.Marshaler)(nil)).Elem()) {
+		extra, ok := marshalerProperties[t]
+		if !ok {
+			panic(fmt.Sprintf("openapi: %s implements json.Marshaler, list its properties in marshalerProperties", t))
+		}
+		for name, p := range extra {
+			s.Properties[name] = p
+			s.Required = append(s.Required, name)
+		}
+	}
+
+	sort.Strings(s.Required)
+	return s
+}
+
+func (g *schemaGenerator) addFields(s *Schema, t reflect.Type) {
+	for i := range t.NumField() {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" || (!f.IsExported() && !f.Anonymous) {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+
+		// Embedded structs without a name are flattened like encoding/json does
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			g.addFields(s, f.Type)
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		s.Properties[name] = g.schema(f.Type)
+		// omitempty never drops a struct like time.Time or an array like uuid.UUID, so those fields are always present
+		kind := f.Type.Kind()
+		omitted := strings.Contains(opts, "omitempty") && kind != reflect.Struct && kind != reflect.Array
+		if !omitted {
+			s.Required = append(s.Required, name)
+		}
+	}
+}
@@ -0,0 +1,410 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	nethttp "net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// The spec decoded from its json, such that the test checks what clients actually get
+type specDocument map[string]any
+
+func loadSpec(t *testing.T) specDocument {
+	t.Helper()
+
+	b, err := json.Marshal(NewOpenAPI())
+	if err != nil {
+		t.Fatalf("marshal spec: %v", err)
+	}
+	var doc specDocument
+	if err := json.Unmarshal(b, &doc); err != nil {
+		t.Fatalf("decode spec: %v", err)
+	}
+	return doc
+}
+
+func (d specDocument) paths() map[string]any {
+	return d["paths"].(map[string]any)
+}
+
+// Operation of a concrete request path, eg GET /api/v1/public/posts/<uuid>
+func (d specDocument) operation(method, path string) (string, map[string]any) {
+	path, _, _ = strings.Cut(path, "?")
+	for template, item := range d.paths() {
+		segments := strings.Split(template, "/")
+		for i, seg := range segments {
+			if strings.HasPrefix(seg, "{") {
+				segments[i] = `[^/]+`
+			} else {
+				segments[i] = regexp.QuoteMeta(seg)
+			}
+		}
+		if !regexp.MustCompile("^" + strings.Join(segments, "/") + "/?$").MatchString(path) {
+			continue
+		}
+		if op, ok := item.(map[string]any)[strings.ToLower(method)]; ok {
+			return template, op.(map[string]any)
+		}
+	}
+	return "", nil
+}
+
+func (d specDocument) resolve(ref string) map[string]any {
+	var node any = map[string]any(d)
+	for _, part := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+		node = node.(map[string]any)[part]
+	}
+	return node.(map[string]any)
+}
+
+func (d specDocument) validate(schema map[string]any, v any, at string) error {
+	if ref, ok := schema["$ref"].(string); ok {
+		return d.validate(d.resolve(ref), v, at)
+	}
+	if v == nil {
+		if schema["nullable"] == true || len(schema) == 0 {
+			return nil
+		}
+		return fmt.Errorf("%s: null is not nullable", at)
+	}
+	if allOf, ok := schema["allOf"].([]any); ok {
+		for _, s := range allOf {
+			if err := d.validate(s.(map[string]any), v, at); err != nil {
+				return err
+			}
+		}
+	}
+	if enum, ok := schema["enum"].([]any); ok {
+		found := false
+		for _, e := range enum {
+			found = found || e == v
+		}
+		if !found {
+			return fmt.Errorf("%s: %v not in enum %v", at, v, enum)
+		}
+	}
+
+	switch schema["type"] {
+	case "object":
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return fmt.Errorf("%s: got %T, want object", at, v)
+		}
+		props, _ := schema["properties"].(map[string]any)
+		required, _ := schema["required"].([]any)
+		for _, name := range required {
+			if _, ok := obj[name.(string)]; !ok {
+				return fmt.Errorf("%s: missing required property %q", at, name)
+			}
+		}
+		if props == nil {
+			return nil
+		}
+		for name, value := range obj {
+			prop, ok := props[name]
+			if !ok {
+				return fmt.Errorf("%s: property %q is not in the spec", at, name)
+			}
+			if err := d.validate(prop.(map[string]any), value, at+"."+name); err != nil {
+				return err
+			}
+		}
+	case "array":
+		arr, ok := v.([]any)
+		if !ok {
+			return fmt.Errorf("%s: got %T, want array", at, v)
+		}
+		for i, item := range arr {
+			if err := d.validate(schema["items"].(map[string]any), item, at+"["+strconv.Itoa(i)+"]"); err != nil {
+				return err
+			}
+		}
+	case "string":
+		s, ok := v.(string)
+		if !ok {
+			return fmt.Errorf("%s: got %T, want string", at, v)
+		}
+		switch schema["format"] {
+		case "uuid":
+			if _, err := uuid.Parse(s); err != nil {
+				return fmt.Errorf("%s: %q is not a uuid", at, s)
+			}
+		case "date-time":
+			if _, err := time.Parse(time.RFC3339, s); err != nil {
+				return fmt.Errorf("%s: %q is not a date-time", at, s)
+			}
+		}
+	case "integer":
+		n, ok := v.(float64)
+		if !ok || n != math.Trunc(n) {
+			return fmt.Errorf("%s: got %v, want integer", at, v)
+		}
+	case "number":
+		if _, ok := v.(float64); !ok {
+			return fmt.Errorf("%s: got %T, want number", at, v)
+		}
+	case "boolean":
+		if _, ok := v.(bool); !ok {
+			return fmt.Errorf("%s: got %T, want boolean", at, v)
+		}
+	}
+	return nil
+}
+
+func (d specDocument) jsonSchema(node map[string]any) map[string]any {
+	if ref, ok := node["$ref"].(string); ok {
+		node = d.resolve(ref)
+	}
+	return node["content"].(map[string]any)[fiber.MIMEApplicationJSON].(map[string]any)["schema"].(map[string]any)
+}
+
+// Sends the request and checks the request body and the response against the spec
+type specClient struct {
+	t       *testing.T
+	app     *fiber.App
+	spec    specDocument
+	covered map[string]bool
+}
+
+func (c *specClient) do(method, path, token string, body any) (int, testResponse) {
+	c.t.Helper()
+
+	template, op := c.spec.operation(method, path)
+	if op == nil {
+		c.t.Fatalf("%s %s: no operation in the spec", method, path)
+	}
+	c.covered[method+" "+template] = true
+
+	if body != nil {
+		rb, ok := op["requestBody"].(map[string]any)
+		if !ok {
+			c.t.Fatalf("%s %s: body sent but the spec has no request body", method, template)
+		}
+		var generic any
+		b, _ := json.Marshal(body)
+		_ = json.Unmarshal(b, &generic)
+		if err := c.spec.validate(c.spec.jsonSchema(rb), generic, "request"); err != nil {
+			c.t.Fatalf("%s %s: request does not match the spec: %v", method, template, err)
+		}
+	}
+
+	status, raw := doRawRequest(c.t, c.app, method, path, token, body)
+	resp, ok := op["responses"].(map[string]any)[strconv.Itoa(status)]
+	if !ok {
+		c.t.Fatalf("%s %s: status %d is not documented: %s", method, template, status, raw)
+	}
+	var generic any
+	if err := json.Unmarshal(raw, &generic); err != nil {
+		c.t.Fatalf("%s %s: decode response: %v", method, template, err)
+	}
+	if err := c.spec.validate(c.spec.jsonSchema(resp.(map[string]any)), generic, "response"); err != nil {
+		c.t.Fatalf("%s %s: response %d does not match the spec: %v\n%s", method, template, status, err, raw)
+	}
+
+	var out testResponse
+	_ = json.Unmarshal(raw, &out)
+	return status, out
+}
+
+func TestOpenAPICoversAllRoutes(t *testing.T) {
+	app := newTestApp(t)
+	spec := loadSpec(t)
+
+	registered := map[string]bool{}
+	for _, r := range app.GetRoutes(true) {
+		// Fiber registers HEAD for every GET
+		if r.Method == fiber.MethodHead {
+			continue
+		}
+		registered[r.Method+" "+openAPIPath(r.Path)] = true
+	}
+
+	documented := map[string]bool{}
+	for path, item := range spec.paths() {
+		for method := range item.(map[string]any) {
+			documented[strings.ToUpper(method)+" "+path] = true
+		}
+	}
+
+	for route := range registered {
+		if !documented[route] {
+			t.Errorf("route %s has no entry in the OpenAPI spec", route)
+		}
+	}
+	for route := range documented {
+		if !registered[route] {
+			t.Errorf("spec documents %s but no such route is registered", route)
+		}
+	}
+}
+
+func TestOpenAPIMatchesResponses(t *testing.T) {
+	c := &specClient{t: t, app: newTestApp(t), spec: loadSpec(t), covered: map[string]bool{}}
+
+	register := func(name string) (string, string) {
+		_, resp := c.do(nethttp.MethodPost, "/api/v1/auth/register", "", fiber.Map{
+			"username": name, "password": "password123", "email": name + "@example.com",
+		})
+		var reg struct {
+			User struct {
+				ID string `json:"id"`
+			} `json:"user"`
+		}
+		_ = json.Unmarshal(resp.Data, &reg)
+
+		_, resp = c.do(nethttp.MethodPost, "/api/v1/auth/login", "", fiber.Map{"username": name, "password": "password123"})
+		var login struct {
+			Session struct {
+				ID string `json:"id"`
+			} `json:"session"`
+		}
+		_ = json.Unmarshal(resp.Data, &login)
+		return reg.User.ID, login.Session.ID
+	}
+	aliceID, alice := register("alice")
+	bobID, bob := register("bob")
+
+	status, resp := c.do(nethttp.MethodPost, "/api/v1/posts", alice, fiber.Map{"content": "hello"})
+	if status != fiber.StatusOK {
+		t.Fatalf("create post: status %d", status)
+	}
+	var created struct {
+		Post struct {
+			ID string `json:"id"`
+		} `json:"post"`
+	}
+	_ = json.Unmarshal(resp.Data, &created)
+	postID := created.Post.ID
+	post := "/api/v1/posts/" + postID
+
+	c.do(nethttp.MethodPut, post, alice, fiber.Map{"content": "hello again"})
+	c.do(nethttp.MethodPost, post+"/like", bob, nil)
+	c.do(nethttp.MethodPut, post+"/reaction", bob, fiber.Map{"reaction": "love"})
+	c.do(nethttp.MethodPost, post+"/favorite", bob, nil)
+	c.do(nethttp.MethodPost, post+"/repost", bob, fiber.Map{"comment": "nice"})
+	c.do(nethttp.MethodPost, "/api/v1/posts", bob, fiber.Map{"content": "quoting alice", "quoted_post_id": created.Post.ID})
+	c.do(nethttp.MethodPost, "/api/v1/users/"+aliceID+"/follow", bob, nil)
+	c.do(nethttp.MethodGet, "/api/v1/public/posts/"+created.Post.ID, bob, nil)
+	c.do(nethttp.MethodGet, "/api/v1/public/posts/"+created.Post.ID+"/revisions", "", nil)
+
+	c.do(nethttp.MethodGet, "/api/v1/users/me", bob, nil)
+	c.do(nethttp.MethodGet, "/api/v1/users/me/posts", alice, nil)
+	c.do(nethttp.MethodGet, "/api/v1/users/me/feed?page=1&pageSize=10", bob, nil)
+	c.do(nethttp.MethodGet, "/api/v1/users/me/posts/favorites", bob, nil)
+	c.do(nethttp.MethodGet, "/api/v1/users/me/reposts", bob, nil)
+	c.do(nethttp.MethodGet, "/api/v1/public/users/name/ali", bob, nil)
+	c.do(nethttp.MethodGet, "/api/v1/public/users/"+aliceID, bob, nil)
+	c.do(nethttp.MethodGet, "/api/v1/public/users/"+aliceID+"/posts", "", nil)
+	c.do(nethttp.MethodGet, "/api/v1/public/users/"+bobID+"/reposts", "", nil)
+
+	_, resp = c.do(nethttp.MethodPost, "/api/v1/posts", alice, fiber.Map{"content": "later", "draft": true})
+	_ = json.Unmarshal(resp.Data, &created)
+	draft := "/api/v1/posts/" + created.Post.ID
+	c.do(nethttp.MethodGet, "/api/v1/users/me/drafts", alice, nil)
+	c.do(nethttp.MethodPost, draft+"/like", alice, nil)
+	c.do(nethttp.MethodPost, draft+"/publish", alice, nil)
+	c.do(nethttp.MethodPost, draft+"/publish", alice, nil)
+
+	_, resp = c.do(nethttp.MethodPost, "/api/v1/posts", alice, fiber.Map{
+		"content": "tea or coffee",
+		"poll":    fiber.Map{"options": []string{"tea", "coffee"}, "closes_at": time.Now().Add(time.Hour)},
+	})
+	_ = json.Unmarshal(resp.Data, &created)
+	_, resp = c.do(nethttp.MethodGet, "/api/v1/public/posts/"+created.Post.ID, bob, nil)
+	var polled struct {
+		Post struct {
+			Poll struct {
+				Options []struct {
+					ID string `json:"id"`
+				} `json:"options"`
+			} `json:"poll"`
+		} `json:"post"`
+	}
+	_ = json.Unmarshal(resp.Data, &polled)
+	if len(polled.Post.Poll.Options) == 0 {
+		t.Fatalf("post created with a poll has no poll options")
+	}
+	vote := fiber.Map{"option_ids": []string{polled.Post.Poll.Options[0].ID}}
+	c.do(nethttp.MethodPost, "/api/v1/posts/"+created.Post.ID+"/poll/vote", bob, vote)
+	c.do(nethttp.MethodPost, "/api/v1/posts/"+created.Post.ID+"/poll/vote", bob, vote)
+	c.do(nethttp.MethodPost, post+"/poll/vote", bob, vote)
+	c.do(nethttp.MethodPost, post+"/pin", alice, nil)
+	c.do(nethttp.MethodGet, "/api/v1/public/users/"+aliceID+"/posts", "", nil)
+	c.do(nethttp.MethodDelete, post+"/pin", alice, nil)
+
+	c.do(nethttp.MethodGet, "/api/v1/collections", bob, nil)
+	_, resp = c.do(nethttp.MethodPost, "/api/v1/collections", bob, fiber.Map{"name": "greetings"})
+	var collection struct {
+		Collection struct {
+			ID string `json:"id"`
+		} `json:"collection"`
+	}
+	_ = json.Unmarshal(resp.Data, &collection)
+	greetings := "/api/v1/collections/" + collection.Collection.ID
+	c.do(nethttp.MethodPost, "/api/v1/collections", bob, fiber.Map{"name": "greetings"})
+	c.do(nethttp.MethodPut, greetings, bob, fiber.Map{"name": "hellos"})
+	c.do(nethttp.MethodPost, greetings+"/posts", bob, fiber.Map{"post_id": postID})
+	c.do(nethttp.MethodGet, greetings+"/posts", bob, nil)
+	c.do(nethttp.MethodGet, greetings+"/posts", alice, nil)
+	c.do(nethttp.MethodDelete, greetings+"/posts/"+postID, bob, nil)
+	c.do(nethttp.MethodDelete, greetings, bob, nil)
+
+	// Error responses are checked against the spec as well
+	c.do(nethttp.MethodPost, "/api/v1/posts", alice, fiber.Map{"content": ""})
+	c.do(nethttp.MethodGet, "/api/v1/public/posts/"+uuid.NewString(), "", nil)
+	c.do(nethttp.MethodGet, "/api/v1/public/posts/"+uuid.NewString()+"/revisions", "", nil)
+	c.do(nethttp.MethodDelete, post, bob, nil)
+	c.do(nethttp.MethodPut, post+"/reaction", bob, fiber.Map{"reaction": "meh"})
+
+	c.do(nethttp.MethodDelete, post+"/reaction", bob, nil)
+	c.do(nethttp.MethodDelete, post+"/like", bob, nil)
+	c.do(nethttp.MethodDelete, post+"/favorite", bob, nil)
+	c.do(nethttp.MethodDelete, post+"/repost", bob, nil)
+	c.do(nethttp.MethodDelete, "/api/v1/users/"+aliceID+"/follow", bob, nil)
+	c.do(nethttp.MethodDelete, post, alice, nil)
+	c.do(nethttp.MethodDelete, "/api/v1/auth/logout", bob, nil)
+
+	var missing []string
+	for path, item := range c.spec.paths() {
+		for method := range item.(map[string]any) {
+			if key := strings.ToUpper(method) + " " + path; !c.covered[key] {
+				missing = append(missing, key)
+			}
+		}
+	}
+	sort.Strings(missing)
+	if len(missing) > 0 {
+		t.Fatalf("operations not exercised by this test, add a request for them: %v", missing)
+	}
+}
+
+func TestDocsRoutes(t *testing.T) {
+	app := fiber.New()
+	NewDocsHandler().RegisterRoutes(app)
+
+	status, b := doRawRequest(t, app, nethttp.MethodGet, "/api/openapi.json", "", nil)
+	if status != fiber.StatusOK {
+		t.Fatalf("openapi.json: status %d", status)
+	}
+	var doc struct {
+		OpenAPI string `json:"openapi"`
+	}
+	if err := json.Unmarshal(b, &doc); err != nil || !strings.HasPrefix(doc.OpenAPI, "3.") {
+		t.Fatalf("openapi.json: %v, version %q", err, doc.OpenAPI)
+	}
+
+	status, b = doRawRequest(t, app, nethttp.MethodGet, "/api/docs", "", nil)
+	if status != fiber.StatusOK || !strings.Contains(string(b), "/api/openapi.json") {
+		t.Fatalf("docs: status %d", status)
+	}
+}
@@ -1,9 +1,12 @@
 package http
 
 import (
+	"log/slog"
+	"social-media-go-ddd/internal/application/apperror"
 	"social-media-go-ddd/internal/application/service"
 	"social-media-go-ddd/internal/domain/dto"
 	"social-media-go-ddd/internal/domain/entity"
+	"social-media-go-ddd/internal/infrastructure/logging"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -13,26 +16,32 @@ type PostHandlerService struct {
 	like     *service.LikeService
 	repost   *service.RepostService
 	favorite *service.FavoriteService
+	poll     *service.PollService
+	pin      *service.PinnedPostService
 	session  *service.SessionService
 }
 
-func NewPostHandlerService(post *service.PostService, like *service.LikeService, repost *service.RepostService, favorite *service.FavoriteService, session *service.SessionService) *PostHandlerService {
+func NewPostHandlerService(post *service.PostService, like *service.LikeService, repost *service.RepostService, favorite *service.FavoriteService, poll *service.PollService, pin *service.PinnedPostService, session *service.SessionService) *PostHandlerService {
 	return &PostHandlerService{
 		post:     post,
 		like:     like,
 		repost:   repost,
 		favorite: favorite,
+		poll:     poll,
+		pin:      pin,
 		session:  session,
 	}
 }
 
 type PostHandlerMiddleware struct {
-	auth *AuthMiddleware
+	auth        *AuthMiddleware
+	idempotency *IdempotencyMiddleware
 }
 
-func NewPostHandlerMiddleware(auth *AuthMiddleware) *PostHandlerMiddleware {
+func NewPostHandlerMiddleware(auth *AuthMiddleware, idempotency *IdempotencyMiddleware) *PostHandlerMiddleware {
 	return &PostHandlerMiddleware{
-		auth: auth,
+		auth:        auth,
+		idempotency: idempotency,
 	}
 }
 
@@ -41,27 +50,34 @@ type PostHandler struct {
 	middleware *PostHandlerMiddleware
 }
 
-func NewPostHandler(postService *service.PostService, likeService *service.LikeService, repostService *service.RepostService, favoriteService *service.FavoriteService, sessionService *service.SessionService, authMiddleware *AuthMiddleware) *PostHandler {
+func NewPostHandler(postService *service.PostService, likeService *service.LikeService, repostService *service.RepostService, favoriteService *service.FavoriteService, pollService *service.PollService, pinnedPostService *service.PinnedPostService, sessionService *service.SessionService, authMiddleware *AuthMiddleware, idempotencyMiddleware *IdempotencyMiddleware) *PostHandler {
 	return &PostHandler{
-		service:    NewPostHandlerService(postService, likeService, repostService, favoriteService, sessionService),
-		middleware: NewPostHandlerMiddleware(authMiddleware),
+		service:    NewPostHandlerService(postService, likeService, repostService, favoriteService, pollService, pinnedPostService, sessionService),
+		middleware: NewPostHandlerMiddleware(authMiddleware, idempotencyMiddleware),
 	}
 }
 
 func (h *PostHandler) RegisterRoutes(app *fiber.App) {
 	apiPosts := app.Group("/api/v1/public/posts")
 	apiPosts.Get("/:id", h.GetPostByID)
+	apiPosts.Get("/:id/revisions", h.GetPostRevisions)
 
-	apiPostsProtected := app.Group("/api/v1/posts", h.middleware.auth.Handler)
+	apiPostsProtected := app.Group("/api/v1/posts", h.middleware.auth.Handler, h.middleware.idempotency.Handler)
 	apiPostsProtected.Post("/", h.CreatePost)
 	apiPostsProtected.Put("/:id", h.UpdatePost)
 	apiPostsProtected.Delete("/:id", h.DeletePost)
+	apiPostsProtected.Post("/:id/publish", h.PublishPost)
 	apiPostsProtected.Post("/:id/like", h.LikePost)
 	apiPostsProtected.Delete("/:id/like", h.UnlikePost)
+	apiPostsProtected.Put("/:id/reaction", h.ReactPost)
+	apiPostsProtected.Delete("/:id/reaction", h.UnlikePost)
 	apiPostsProtected.Post("/:id/favorite", h.FavoritePost)
 	apiPostsProtected.Delete("/:id/favorite", h.UnfavoritePost)
 	apiPostsProtected.Post("/:id/repost", h.RepostPost)
 	apiPostsProtected.Delete("/:id/repost", h.UnrepostPost)
+	apiPostsProtected.Post("/:id/poll/vote", h.VotePoll)
+	apiPostsProtected.Post("/:id/pin", h.PinPost)
+	apiPostsProtected.Delete("/:id/pin", h.UnpinPost)
 }
 
 func (p *PostHandler) getCurrentUserId(ctx *fiber.Ctx) *string {
@@ -71,10 +87,11 @@ func (p *PostHandler) getCurrentUserId(ctx *fiber.Ctx) *string {
 	// Such that when getting the user, we know if we have followed that person or not yet
 	token, err := readBearerToken(ctx)
 	if err == nil && token != "" {
-		session, err := p.service.session.GetByID(ctx.Context(), token)
+		session, err := p.service.session.GetByID(ctx.UserContext(), token)
 		if err == nil && !session.IsExpired() {
 			uid := session.UserID.String()
 			currentUserID = &uid
+			logging.AddAttrs(ctx.UserContext(), slog.String("user_id", uid))
 		}
 	}
 	return currentUserID
@@ -83,7 +100,7 @@ func (p *PostHandler) getCurrentUserId(ctx *fiber.Ctx) *string {
 func (h *PostHandler) CreatePost(ctx *fiber.Ctx) error {
 	user, err := GetUserFromCtx(ctx)
 	if err != nil {
-		return ErrorResponse(ctx, fiber.StatusUnauthorized, err)
+		return err
 	}
 
 	type request struct {
@@ -91,14 +108,31 @@ func (h *PostHandler) CreatePost(ctx *fiber.Ctx) error {
 	}
 
 	var body request
-	if err := ctx.BodyParser(&body); err != nil {
-		return ErrorResponse(ctx, fiber.StatusBadRequest, err)
+	req := bindRequest(ctx)
+	req.Body(&body)
+	if err := req.Err(); err != nil {
+		return err
 	}
 	body.NewPost.UserID = user.ID
 
-	post, err := h.service.post.Create(ctx.Context(), body.NewPost)
+	// Quoting shows the quoted post to the audience of the quote, like a repost does
+	if body.QuotedPostID != nil {
+		userId := user.ID.String()
+		quoted, err := h.service.post.GetByID(ctx.UserContext(), body.QuotedPostID.String(), &userId)
+		if err != nil {
+			return err
+		}
+		if !quoted.IsPublished() {
+			return errPostNotPublished()
+		}
+		if err := quoted.CanQuote(); err != nil {
+			return apperror.Wrap(err, "post")
+		}
+	}
+
+	post, err := h.service.post.Create(ctx.UserContext(), body.NewPost)
 	if err != nil {
-		return ErrorResponse(ctx, fiber.StatusInternalServerError, err)
+		return err
 	}
 
 	return SuccessResponse(ctx, fiber.Map{
@@ -109,21 +143,24 @@ func (h *PostHandler) CreatePost(ctx *fiber.Ctx) error {
 func (h *PostHandler) DeletePost(ctx *fiber.Ctx) error {
 	user, err := GetUserFromCtx(ctx)
 	if err != nil {
-		return ErrorResponse(ctx, fiber.StatusUnauthorized, err)
+		return err
+	}
+	id, err := idParam(ctx)
+	if err != nil {
+		return err
 	}
-	id := ctx.Params("id")
 
 	userId := user.ID.String()
-	post, err := h.service.post.GetByID(ctx.Context(), id, &userId)
+	post, err := h.service.post.GetByID(ctx.UserContext(), id, &userId)
 	if err != nil {
-		return ErrorResponse(ctx, fiber.StatusNotFound, err)
+		return err
 	}
 	if post.UserID != user.ID {
-		return ErrorResponse(ctx, fiber.StatusForbidden, "you are not allowed to update this post")
+		return apperror.Forbidden("not_post_owner", "you are not allowed to delete this post")
 	}
 
-	if err := h.service.post.Delete(ctx.Context(), dto.DeletePost{ID: id, UserID: user.ID}); err != nil {
-		return ErrorResponse(ctx, fiber.StatusInternalServerError, err)
+	if err := h.service.post.Delete(ctx.UserContext(), dto.DeletePost{ID: id, UserID: user.ID, QuotedPostID: post.QuotedPostID}); err != nil {
+		return err
 	}
 	return SuccessResponse(ctx, nil)
 }
@@ -131,97 +168,205 @@ func (h *PostHandler) DeletePost(ctx *fiber.Ctx) error {
 func (h *PostHandler) UpdatePost(ctx *fiber.Ctx) error {
 	user, err := GetUserFromCtx(ctx)
 	if err != nil {
-		return ErrorResponse(ctx, fiber.StatusUnauthorized, err)
+		return err
 	}
 
-	id := ctx.Params("id")
+	req := bindRequest(ctx)
+	id := req.UUIDParam("id").String()
 	var body dto.UpdatePost
-	if err := ctx.BodyParser(&body); err != nil {
-		return ErrorResponse(ctx, fiber.StatusBadRequest, err)
+	req.Body(&body)
+	if err := req.Err(); err != nil {
+		return err
 	}
 	body.UserID = user.ID
 	body.ID = id
 
 	userId := user.ID.String()
-	post, err := h.service.post.GetByID(ctx.Context(), id, &userId)
+	post, err := h.service.post.GetByID(ctx.UserContext(), id, &userId)
 	if err != nil {
-		return ErrorResponse(ctx, fiber.StatusNotFound, err)
+		return err
 	}
 	if post.UserID != user.ID {
-		return ErrorResponse(ctx, fiber.StatusForbidden, "you are not allowed to update this post")
+		return apperror.Forbidden("not_post_owner", "you are not allowed to update this post")
+	}
+	if !ifMatch(ctx, versionETag(post.Version)) {
+		return apperror.PreconditionFailed(apperror.CodeVersionMismatch, "post was modified since it was read")
 	}
 
-	updatedPost, err := h.service.post.Update(ctx.Context(), &post.Post, body)
+	// The repository rejects the update as well when another one lands between reading and writing the post
+	updatedPost, err := h.service.post.Update(ctx.UserContext(), &post.Post, body)
 	if err != nil {
-		return ErrorResponse(ctx, fiber.StatusInternalServerError, err)
+		return err
 	}
 
+	ctx.Set(fiber.HeaderETag, versionETag(updatedPost.Version))
 	return SuccessResponse(ctx, fiber.Map{
 		"post": updatedPost,
 	})
 }
 
+// Publish a draft or scheduled post of the current user right away
+func (h *PostHandler) PublishPost(ctx *fiber.Ctx) error {
+	user, err := GetUserFromCtx(ctx)
+	if err != nil {
+		return err
+	}
+	id, err := idParam(ctx)
+	if err != nil {
+		return err
+	}
+
+	userId := user.ID.String()
+	post, err := h.service.post.GetByID(ctx.UserContext(), id, &userId)
+	if err != nil {
+		return err
+	}
+	if post.UserID != user.ID {
+		return apperror.Forbidden("not_post_owner", "you are not allowed to publish this post")
+	}
+
+	publishedPost, err := h.service.post.Publish(ctx.UserContext(), &post.Post)
+	if err != nil {
+		return err
+	}
+
+	ctx.Set(fiber.HeaderETag, versionETag(publishedPost.Version))
+	return SuccessResponse(ctx, fiber.Map{
+		"post": publishedPost,
+	})
+}
+
 func (h *PostHandler) GetPostByID(ctx *fiber.Ctx) error {
+	id, err := idParam(ctx)
+	if err != nil {
+		return err
+	}
 	currentUserId := h.getCurrentUserId(ctx)
 
-	id := ctx.Params("id")
-	post, err := h.service.post.GetByID(ctx.Context(), id, currentUserId)
+	post, err := h.service.post.GetByID(ctx.UserContext(), id, currentUserId)
 	if err != nil {
-		return ErrorResponse(ctx, fiber.StatusNotFound, err)
+		return err
 	}
 
+	ctx.Set(fiber.HeaderETag, versionETag(post.Version))
 	return SuccessResponse(ctx, fiber.Map{
 		"post": post,
 	})
 }
 
+func (h *PostHandler) GetPostRevisions(ctx *fiber.Ctx) error {
+	id, err := idParam(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Revisions of an unknown post, or one the user may not see, are a 404 rather than an empty list
+	if _, err := h.service.post.GetByID(ctx.UserContext(), id, h.getCurrentUserId(ctx)); err != nil {
+		return err
+	}
+	revisions, err := h.service.post.GetRevisions(ctx.UserContext(), id)
+	if err != nil {
+		return err
+	}
+	if revisions == nil {
+		revisions = []*entity.PostRevision{}
+	}
+
+	return SuccessResponse(ctx, fiber.Map{
+		"revisions": revisions,
+	})
+}
+
 func (h *PostHandler) LikePost(ctx *fiber.Ctx) error {
 	user, err := GetUserFromCtx(ctx)
 	if err != nil {
-		return ErrorResponse(ctx, fiber.StatusUnauthorized, err)
+		return err
 	}
 
-	id := ctx.Params("id")
+	id, err := idParam(ctx)
+	if err != nil {
+		return err
+	}
 	userId := user.ID.String()
-	post, err := h.service.post.GetByID(ctx.Context(), id, &userId)
+	post, err := h.service.post.GetByID(ctx.UserContext(), id, &userId)
 	if err != nil {
-		return ErrorResponse(ctx, fiber.StatusNotFound, err)
+		return err
 	}
 
-	if !post.Liked {
-		_, err = h.service.like.Create(ctx.Context(), dto.NewLike{
-			UserID: user.ID,
-			PostID: post.ID,
-		})
-		if err != nil {
-			return ErrorResponse(ctx, fiber.StatusInternalServerError, err)
-		}
+	if !post.IsPublished() {
+		return errPostNotPublished()
+	}
+	// Any reaction counts as liked, which keeps it rather than turning it into a like
+	_, err = h.service.like.CreateIfAbsent(ctx.UserContext(), dto.NewLike{
+		UserID:   user.ID,
+		PostID:   post.ID,
+		Reaction: string(entity.ReactionLike),
+	})
+	if err != nil {
+		return err
+	}
+
+	return SuccessResponse(ctx, nil)
+}
+
+// Reacts to the post, replacing the reaction of the user if they already reacted
+func (h *PostHandler) ReactPost(ctx *fiber.Ctx) error {
+	user, err := GetUserFromCtx(ctx)
+	if err != nil {
+		return err
+	}
+
+	req := bindRequest(ctx)
+	id := req.UUIDParam("id").String()
+	var body dto.NewLike
+	req.Body(&body)
+	if err := req.Err(); err != nil {
+		return err
+	}
+
+	userId := user.ID.String()
+	post, err := h.service.post.GetByID(ctx.UserContext(), id, &userId)
+	if err != nil {
+		return err
+	}
+
+	if !post.IsPublished() {
+		return errPostNotPublished()
+	}
+	// Saved whatever post.Reaction is, saving the same reaction again changes nothing
+	body.UserID = user.ID
+	body.PostID = post.ID
+	if _, err = h.service.like.Create(ctx.UserContext(), body); err != nil {
+		return err
 	}
 
 	return SuccessResponse(ctx, nil)
 }
 
+// Removes the reaction of the user whatever it is, a like being one
 func (h *PostHandler) UnlikePost(ctx *fiber.Ctx) error {
 	user, err := GetUserFromCtx(ctx)
 	if err != nil {
-		return ErrorResponse(ctx, fiber.StatusUnauthorized, err)
+		return err
 	}
 
-	id := ctx.Params("id")
+	id, err := idParam(ctx)
+	if err != nil {
+		return err
+	}
 	userId := user.ID.String()
-	post, err := h.service.post.GetByID(ctx.Context(), id, &userId)
+	post, err := h.service.post.GetByID(ctx.UserContext(), id, &userId)
 	if err != nil {
-		return ErrorResponse(ctx, fiber.StatusNotFound, err)
+		return err
 	}
 
-	if post.Liked {
-		err = h.service.like.Delete(ctx.Context(), dto.DeleteLike{
-			UserID: user.ID,
-			PostID: post.ID,
-		})
-		if err != nil {
-			return ErrorResponse(ctx, fiber.StatusInternalServerError, err)
-		}
+	// Deleting a reaction the user does not have changes nothing
+	err = h.service.like.Delete(ctx.UserContext(), dto.DeleteLike{
+		UserID: user.ID,
+		PostID: post.ID,
+	})
+	if err != nil {
+		return err
 	}
 
 	return SuccessResponse(ctx, nil)
@@ -230,23 +375,29 @@ func (h *PostHandler) UnlikePost(ctx *fiber.Ctx) error {
 func (h *PostHandler) FavoritePost(ctx *fiber.Ctx) error {
 	user, err := GetUserFromCtx(ctx)
 	if err != nil {
-		return ErrorResponse(ctx, fiber.StatusUnauthorized, err)
+		return err
 	}
 
-	id := ctx.Params("id")
+	id, err := idParam(ctx)
+	if err != nil {
+		return err
+	}
 	userId := user.ID.String()
-	post, err := h.service.post.GetByID(ctx.Context(), id, &userId)
+	post, err := h.service.post.GetByID(ctx.UserContext(), id, &userId)
 	if err != nil {
-		return ErrorResponse(ctx, fiber.StatusNotFound, err)
+		return err
 	}
 
+	if !post.IsPublished() {
+		return errPostNotPublished()
+	}
 	if !post.Favorited {
-		_, err = h.service.favorite.Create(ctx.Context(), dto.NewFavorite{
+		_, err = h.service.favorite.Create(ctx.UserContext(), dto.NewFavorite{
 			UserID: user.ID,
 			PostID: post.ID,
 		})
 		if err != nil {
-			return ErrorResponse(ctx, fiber.StatusInternalServerError, err)
+			return err
 		}
 	}
 
@@ -256,23 +407,26 @@ func (h *PostHandler) FavoritePost(ctx *fiber.Ctx) error {
 func (h *PostHandler) UnfavoritePost(ctx *fiber.Ctx) error {
 	user, err := GetUserFromCtx(ctx)
 	if err != nil {
-		return ErrorResponse(ctx, fiber.StatusUnauthorized, err)
+		return err
 	}
 
-	id := ctx.Params("id")
+	id, err := idParam(ctx)
+	if err != nil {
+		return err
+	}
 	userId := user.ID.String()
-	post, err := h.service.post.GetByID(ctx.Context(), id, &userId)
+	post, err := h.service.post.GetByID(ctx.UserContext(), id, &userId)
 	if err != nil {
-		return ErrorResponse(ctx, fiber.StatusNotFound, err)
+		return err
 	}
 
 	if post.Favorited {
-		err = h.service.favorite.Delete(ctx.Context(), dto.DeleteFavorite{
+		err = h.service.favorite.Delete(ctx.UserContext(), dto.DeleteFavorite{
 			UserID: user.ID,
 			PostID: post.ID,
 		})
 		if err != nil {
-			return ErrorResponse(ctx, fiber.StatusInternalServerError, err)
+			return err
 		}
 	}
 
@@ -286,26 +440,34 @@ func (h *PostHandler) RepostPost(ctx *fiber.Ctx) error {
 
 	user, err := GetUserFromCtx(ctx)
 	if err != nil {
-		return ErrorResponse(ctx, fiber.StatusUnauthorized, err)
+		return err
 	}
 
-	id := ctx.Params("id")
+	req := bindRequest(ctx)
+	id := req.UUIDParam("id").String()
 	var body request
-	if err := ctx.BodyParser(&body); err != nil {
-		return ErrorResponse(ctx, fiber.StatusBadRequest, err)
+	req.Body(&body)
+	if err := req.Err(); err != nil {
+		return err
 	}
 
 	userId := user.ID.String()
-	post, err := h.service.post.GetByID(ctx.Context(), id, &userId)
+	post, err := h.service.post.GetByID(ctx.UserContext(), id, &userId)
 	if err != nil {
-		return ErrorResponse(ctx, fiber.StatusNotFound, err)
+		return err
+	}
+	if !post.IsPublished() {
+		return errPostNotPublished()
+	}
+	if err := post.CanRepost(); err != nil {
+		return apperror.Wrap(err, "post")
 	}
 	body.UserID = user.ID
 	body.PostID = post.ID
 
-	repost, err := h.service.repost.Create(ctx.Context(), body.NewRepost)
+	repost, err := h.service.repost.Create(ctx.UserContext(), body.NewRepost)
 	if err != nil {
-		return ErrorResponse(ctx, fiber.StatusInternalServerError, err)
+		return err
 	}
 
 	return SuccessResponse(ctx, fiber.Map{
@@ -316,22 +478,123 @@ func (h *PostHandler) RepostPost(ctx *fiber.Ctx) error {
 func (h *PostHandler) UnrepostPost(ctx *fiber.Ctx) error {
 	user, err := GetUserFromCtx(ctx)
 	if err != nil {
-		return ErrorResponse(ctx, fiber.StatusUnauthorized, err)
+		return err
 	}
 
-	postID := ctx.Params("id")
-	postIDUUID, err := entity.StringToUUID(postID)
+	req := bindRequest(ctx)
+	postIDUUID := req.UUIDParam("id")
+	if err := req.Err(); err != nil {
+		return err
+	}
+
+	err = h.service.repost.Delete(ctx.UserContext(), dto.DeleteRepost{
+		UserID: user.ID,
+		PostID: postIDUUID,
+	})
 	if err != nil {
-		return ErrorResponse(ctx, fiber.StatusBadRequest, err)
+		return err
 	}
 
-	err = h.service.repost.Delete(ctx.Context(), dto.DeleteRepost{
+	return SuccessResponse(ctx, nil)
+}
+
+// Vote in the poll of a published post, once per user
+func (h *PostHandler) VotePoll(ctx *fiber.Ctx) error {
+	type request struct {
+		dto.NewPollVote
+	}
+
+	user, err := GetUserFromCtx(ctx)
+	if err != nil {
+		return err
+	}
+
+	req := bindRequest(ctx)
+	id := req.UUIDParam("id").String()
+	var body request
+	req.Body(&body)
+	if err := req.Err(); err != nil {
+		return err
+	}
+
+	userId := user.ID.String()
+	post, err := h.service.post.GetByID(ctx.UserContext(), id, &userId)
+	if err != nil {
+		return err
+	}
+	if !post.IsPublished() {
+		return errPostNotPublished()
+	}
+	if post.Poll == nil {
+		return apperror.NotFound("poll_not_found", "post has no poll")
+	}
+	body.UserID = user.ID
+
+	poll, err := h.service.poll.Vote(ctx.UserContext(), &post.Poll.Poll, body.NewPollVote)
+	if err != nil {
+		return err
+	}
+
+	return SuccessResponse(ctx, fiber.Map{
+		"poll": poll,
+	})
+}
+
+// Pins the post to the profile of the user, another user's post has to be reposted by them first
+func (h *PostHandler) PinPost(ctx *fiber.Ctx) error {
+	user, err := GetUserFromCtx(ctx)
+	if err != nil {
+		return err
+	}
+
+	id, err := idParam(ctx)
+	if err != nil {
+		return err
+	}
+	userId := user.ID.String()
+	post, err := h.service.post.GetByID(ctx.UserContext(), id, &userId)
+	if err != nil {
+		return err
+	}
+	if !post.IsPublished() {
+		return errPostNotPublished()
+	}
+
+	_, err = h.service.pin.Create(ctx.UserContext(), dto.NewPinnedPost{
+		UserID: user.ID,
+		PostID: post.ID,
+	})
+	if err != nil {
+		return err
+	}
+
+	return SuccessResponse(ctx, nil)
+}
+
+func (h *PostHandler) UnpinPost(ctx *fiber.Ctx) error {
+	user, err := GetUserFromCtx(ctx)
+	if err != nil {
+		return err
+	}
+
+	req := bindRequest(ctx)
+	postIDUUID := req.UUIDParam("id")
+	if err := req.Err(); err != nil {
+		return err
+	}
+
+	err = h.service.pin.Delete(ctx.UserContext(), dto.DeletePinnedPost{
 		UserID: user.ID,
 		PostID: postIDUUID,
 	})
 	if err != nil {
-		return ErrorResponse(ctx, fiber.StatusInternalServerError, err)
+		return err
 	}
 
 	return SuccessResponse(ctx, nil)
 }
+
+// Only the author sees an unpublished post, there is nothing to interact with yet
+func errPostNotPublished() error {
+	return apperror.Conflict("post_not_published", "post is not published yet")
+}
@@ -0,0 +1,131 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"social-media-go-ddd/internal/application/apperror"
+	"social-media-go-ddd/internal/application/validation"
+	"social-media-go-ddd/internal/domain/dto"
+	"sort"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// Largest accepted request body in bytes, set as fiber.Config.BodyLimit such that larger bodies are not read at all
+const MaxBodySize = 64 * 1024
+
+func errBodyTooLarge() error {
+	return apperror.New(apperror.KindTooLarge, apperror.CodeBodyTooLarge, "request body exceeds 64 KiB")
+}
+
+// Collects every invalid path param and body field of a request, such that they are returned in one 422
+type requestBinder struct {
+	ctx    *fiber.Ctx
+	fields []apperror.FieldError
+	// Error that stops the binding, eg a body that is not json
+	err error
+}
+
+func bindRequest(ctx *fiber.Ctx) *requestBinder {
+	return &requestBinder{ctx: ctx}
+}
+
+func (b *requestBinder) UUIDParam(name string) uuid.UUID {
+	value := b.ctx.Params(name)
+	if fe := validation.UUID(name, value); fe != nil {
+		b.fields = append(b.fields, *fe)
+		return uuid.Nil
+	}
+	return uuid.MustParse(value)
+}
+
+// Page requested by the cursor and pageSize query params, no cursor is the first page
+func (b *requestBinder) CursorPage() dto.CursorPage {
+	page := dto.CursorPage{Limit: getPageSize(b.ctx)}
+	if value := b.ctx.Query("cursor"); value != "" {
+		cursor, err := decodeCursor(value)
+		if err != nil {
+			b.fields = append(b.fields, apperror.FieldError{Field: "cursor", Code: validation.CodeCursor, Message: err.Error()})
+			return page
+		}
+		page.After = cursor
+	}
+	return page
+}
+
+// Decode the json body into out, a pointer to a dto with validate tags
+func (b *requestBinder) Body(out any) {
+	if b.err != nil {
+		return
+	}
+
+	body := bytes.TrimSpace(b.ctx.Body())
+	if len(body) > MaxBodySize {
+		b.err = errBodyTooLarge()
+		return
+	}
+	// An empty body is an empty object, such that optional fields can be left out entirely
+	if len(body) == 0 {
+		body = []byte("{}")
+	} else if !b.ctx.Is("json") {
+		b.err = apperror.BadRequest(apperror.CodeInvalidBody, errors.New("content type must be application/json"))
+		return
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(body, &raw); err != nil {
+		b.err = apperror.BadRequest(apperror.CodeInvalidBody, errors.New("body must be a json object"))
+		return
+	}
+
+	known := validation.BodyFields(reflect.TypeOf(out).Elem())
+	keys := make([]string, 0, len(raw))
+	for key := range raw {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	// Fields are decoded one by one, such that every field with a wrong type is reported
+	invalid := map[string]bool{}
+	for _, key := range keys {
+		if !known[key] {
+			b.fields = append(b.fields, apperror.FieldError{Field: key, Code: validation.CodeUnknownField, Message: key + " is not a known field"})
+			continue
+		}
+		single, _ := json.Marshal(map[string]json.RawMessage{key: raw[key]})
+		if err := json.Unmarshal(single, out); err != nil {
+			invalid[key] = true
+			b.fields = append(b.fields, apperror.FieldError{Field: key, Code: validation.CodeType, Message: key + " has the wrong type"})
+		}
+	}
+
+	for _, fe := range validation.Struct(out) {
+		if !invalid[fe.Field] {
+			b.fields = append(b.fields, fe)
+		}
+	}
+}
+
+// Nil when the request is valid
+func (b *requestBinder) Err() error {
+	if b.err != nil {
+		return b.err
+	}
+	if len(b.fields) > 0 {
+		return apperror.Validation(b.fields...)
+	}
+	return nil
+}
+
+// Shortcut for handlers whose only input is the id path param
+func idParam(ctx *fiber.Ctx) (string, error) {
+	req := bindRequest(ctx)
+	id := req.UUIDParam("id")
+	if err := req.Err(); err != nil {
+		return "", err
+	}
+	return id.String(), nil
+}
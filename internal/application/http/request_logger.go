@@ -0,0 +1,66 @@
+package http
+
+import (
+	"log/slog"
+	"regexp"
+	"social-media-go-ddd/internal/infrastructure/logging"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/google/uuid"
+)
+
+const RequestIDHeader = "X-Request-ID"
+
+// Ids sent by clients or proxies are kept when they cannot break the log line
+var requestIDRe = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// Route template, resolved when a line is logged since fiber only knows it once routed
+type routeValue struct {
+	ctx *fiber.Ctx
+}
+
+func (r routeValue) LogValue() slog.Value {
+	return slog.StringValue(routeTemplate(r.ctx))
+}
+
+// Gives every request an id, echoed in the X-Request-ID response header, and puts a logger carrying it into the user context.
+// One line is logged per request once the response is written, register it before any other middleware
+func RequestLogger(base *slog.Logger) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		start := time.Now()
+
+		id := ctx.Get(RequestIDHeader)
+		if !requestIDRe.MatchString(id) {
+			id = uuid.NewString()
+		}
+		id = utils.CopyString(id)
+		ctx.Set(RequestIDHeader, id)
+
+		logger := base.With(
+			slog.String("request_id", id),
+			slog.String("method", utils.CopyString(ctx.Method())),
+			slog.String("path", utils.CopyString(ctx.Path())),
+		)
+		userCtx := logging.NewContext(ctx.UserContext(), logger)
+		logging.AddAttrs(userCtx, slog.Any("route", routeValue{ctx}))
+		ctx.SetUserContext(userCtx)
+
+		if err := ctx.Next(); err != nil {
+			handleError(ctx, err)
+		}
+
+		status := ctx.Response().StatusCode()
+		level := slog.LevelInfo
+		if status >= fiber.StatusInternalServerError {
+			level = slog.LevelError
+		}
+		logging.FromContext(userCtx).Log(userCtx, level, "request",
+			slog.Int("status", status),
+			slog.Duration("duration", time.Since(start)),
+			slog.Int("bytes", len(ctx.Response().Body())),
+		)
+		return nil
+	}
+}
@@ -8,6 +8,9 @@ type Response struct {
 	Data    interface{} `json:"data,omitempty"`
 	Status  int         `json:"status"`
 	Error   any         `json:"error,omitempty"`
+	// Stable machine readable error code, eg "post_not_found"
+	Code    string `json:"code,omitempty"`
+	Details any    `json:"details,omitempty"`
 }
 
 func NewResponse(success bool, message string, data interface{}, status int, err any) *Response {
@@ -28,17 +31,3 @@ func SuccessResponse(ctx *fiber.Ctx, data interface{}, message ...string) error
 	resp := NewResponse(true, msg, data, fiber.StatusOK, nil)
 	return ctx.Status(fiber.StatusOK).JSON(resp)
 }
-
-func ErrorResponse(ctx *fiber.Ctx, status int, err any, message ...string) error {
-	msg := "Request failed"
-	if len(message) > 0 && message[0] != "" {
-		msg = message[0]
-	}
-
-	if e, ok := err.(error); ok {
-		err = e.Error()
-	}
-
-	resp := NewResponse(false, msg, nil, status, err)
-	return ctx.Status(status).JSON(resp)
-}
@@ -0,0 +1,74 @@
+package http
+
+import (
+	"log/slog"
+	"social-media-go-ddd/internal/infrastructure/logging"
+	"social-media-go-ddd/internal/infrastructure/tracing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Header carrier over the fasthttp request, fiber has no http.Header to hand to the propagator
+type requestCarrier struct {
+	ctx *fiber.Ctx
+}
+
+func (c requestCarrier) Get(key string) string {
+	return c.ctx.Get(key)
+}
+
+func (c requestCarrier) Set(key, value string) {
+	c.ctx.Request().Header.Set(key, value)
+}
+
+func (c requestCarrier) Keys() []string {
+	keys := []string{}
+	c.ctx.Request().Header.VisitAll(func(key, _ []byte) {
+		keys = append(keys, string(key))
+	})
+	return keys
+}
+
+var _ propagation.TextMapCarrier = requestCarrier{}
+
+// Starts a server span per request, continuing the trace of a traceparent header, and puts it into the user context.
+// The trace id is added to the log lines of the request, register it right after RequestLogger
+func TracingMiddleware() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		method := utils.CopyString(ctx.Method())
+		userCtx := otel.GetTextMapPropagator().Extract(ctx.UserContext(), requestCarrier{ctx})
+		userCtx, span := tracing.Start(userCtx, method, trace.WithSpanKind(trace.SpanKindServer), trace.WithAttributes(
+			semconv.HTTPRequestMethodKey.String(method),
+			semconv.URLPath(utils.CopyString(ctx.Path())),
+		))
+		defer span.End()
+
+		if span.SpanContext().IsValid() {
+			logging.AddAttrs(userCtx, slog.String("trace_id", span.SpanContext().TraceID().String()))
+		}
+		ctx.SetUserContext(userCtx)
+
+		if err := ctx.Next(); err != nil {
+			handleError(ctx, err)
+		}
+
+		route := routeTemplate(ctx)
+		status := ctx.Response().StatusCode()
+		span.SetName(method + " " + route)
+		span.SetAttributes(
+			semconv.HTTPRoute(route),
+			semconv.HTTPResponseStatusCode(status),
+		)
+		// Client errors are the caller's, only server errors fail the span
+		if status >= fiber.StatusInternalServerError {
+			span.SetStatus(codes.Error, "")
+		}
+		return nil
+	}
+}
@@ -0,0 +1,139 @@
+package http
+
+import (
+	"encoding/json"
+	"log/slog"
+	nethttp "net/http"
+	"net/http/httptest"
+	"social-media-go-ddd/internal/application/apperror"
+	"social-media-go-ddd/internal/infrastructure/metrics"
+	"social-media-go-ddd/internal/infrastructure/tracing/tracingtest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func findSpan(t *testing.T, spans tracetest.SpanStubs, name string, parent trace.SpanContext) tracetest.SpanStub {
+	t.Helper()
+	for _, s := range spans {
+		if s.Name == name && s.Parent.SpanID() == parent.SpanID() {
+			return s
+		}
+	}
+	var names []string
+	for _, s := range spans {
+		names = append(names, s.Name)
+	}
+	t.Fatalf("no span %q under %s, got %v", name, parent.SpanID(), names)
+	return tracetest.SpanStub{}
+}
+
+func spanAttr(s tracetest.SpanStub, key attribute.Key) attribute.Value {
+	for _, kv := range s.Attributes {
+		if kv.Key == key {
+			return kv.Value
+		}
+	}
+	return attribute.Value{}
+}
+
+func TestTracing(t *testing.T) {
+	exp := tracingtest.Record(t)
+	app := newTestAppWith(t, slog.New(slog.DiscardHandler), metrics.NewMetrics())
+
+	alice := registerAndLogin(t, app, "alice")
+	_, resp := doRequest(t, app, nethttp.MethodPost, "/api/v1/posts", alice, fiber.Map{"content": "hello"})
+	var created struct {
+		Post struct {
+			ID string `json:"id"`
+		} `json:"post"`
+	}
+	if err := json.Unmarshal(resp.Data, &created); err != nil {
+		t.Fatalf("decode post: %v", err)
+	}
+	exp.Reset()
+
+	// The caller's trace is continued
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	req := httptest.NewRequest(nethttp.MethodGet, "/api/v1/public/posts/"+created.Post.ID, nil)
+	req.Header.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+	res, err := app.Test(req, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+
+	spans := exp.GetSpans()
+	var server tracetest.SpanStub
+	for _, s := range spans {
+		if s.SpanKind == trace.SpanKindServer {
+			server = s
+		}
+	}
+	if server.Name != "GET /api/v1/public/posts/:id" {
+		t.Fatalf("server span %q", server.Name)
+	}
+	if got := server.SpanContext.TraceID().String(); got != traceID {
+		t.Fatalf("trace id %s, want %s", got, traceID)
+	}
+	if got := spanAttr(server, "http.response.status_code").AsInt64(); got != fiber.StatusOK {
+		t.Fatalf("status attribute %d", got)
+	}
+
+	svc := findSpan(t, spans, "PostService.GetByID", server.SpanContext)
+	get := findSpan(t, spans, "cache.get", svc.SpanContext)
+	if prefix := spanAttr(get, "cache.key_prefix").AsString(); prefix != "post" {
+		t.Fatalf("cache key prefix %q", prefix)
+	}
+	if hit := spanAttr(get, "cache.hit").AsBool(); hit {
+		t.Fatal("first read should miss the cache")
+	}
+	findSpan(t, spans, "post.FindByID", svc.SpanContext)
+	findSpan(t, spans, "cache.set", svc.SpanContext)
+
+	// Session keys hold the token, it must not end up in a span
+	exp.Reset()
+	doRequest(t, app, nethttp.MethodGet, "/api/v1/users/me", alice, nil)
+	for _, s := range exp.GetSpans() {
+		for _, kv := range s.Attributes {
+			if strings.Contains(kv.Value.Emit(), alice) {
+				t.Fatalf("span %q attribute %s holds the session token", s.Name, kv.Key)
+			}
+		}
+	}
+}
+
+func TestTracing_ServerErrorFailsSpan(t *testing.T) {
+	exp := tracingtest.Record(t)
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	app.Use(TracingMiddleware())
+	app.Get("/boom", func(*fiber.Ctx) error { return nethttp.ErrAbortHandler })
+	app.Get("/missing", func(ctx *fiber.Ctx) error { return apperror.NotFound("post_not_found", "post not found") })
+
+	for _, path := range []string{"/boom", "/missing", "/no/such/route"} {
+		res, err := app.Test(httptest.NewRequest(nethttp.MethodGet, path, nil), -1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+	}
+
+	spans := exp.GetSpans()
+	if len(spans) != 3 {
+		t.Fatalf("got %d spans", len(spans))
+	}
+	if spans[0].Name != "GET /boom" || spans[0].Status.Code != codes.Error {
+		t.Fatalf("server error span %q status %v", spans[0].Name, spans[0].Status.Code)
+	}
+	if spans[1].Status.Code == codes.Error {
+		t.Fatal("client errors should not fail the span")
+	}
+	if spans[2].Name != "GET "+unmatchedRoute {
+		t.Fatalf("unmatched route span %q", spans[2].Name)
+	}
+}
@@ -1,16 +1,17 @@
 package http
 
 import (
-	"database/sql"
-	"errors"
+	"log/slog"
+	"social-media-go-ddd/internal/application/apperror"
 	"social-media-go-ddd/internal/application/service"
 	"social-media-go-ddd/internal/domain/aggregate"
 	"social-media-go-ddd/internal/domain/dto"
 	"social-media-go-ddd/internal/domain/entity"
+	"social-media-go-ddd/internal/infrastructure/logging"
+	"social-media-go-ddd/internal/infrastructure/metrics"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/jackc/pgx/v5"
 )
 
 type UserHandlerService struct {
@@ -34,34 +35,39 @@ func NewUserHandlerService(user *service.UserService, session *service.SessionSe
 }
 
 type UserHandlerMiddleware struct {
-	auth *AuthMiddleware
+	auth        *AuthMiddleware
+	idempotency *IdempotencyMiddleware
 }
 
-func NewUserHandlerMiddleware(auth *AuthMiddleware) *UserHandlerMiddleware {
+func NewUserHandlerMiddleware(auth *AuthMiddleware, idempotency *IdempotencyMiddleware) *UserHandlerMiddleware {
 	return &UserHandlerMiddleware{
-		auth: auth,
+		auth:        auth,
+		idempotency: idempotency,
 	}
 }
 
 type UserHandler struct {
 	service    *UserHandlerService
 	middleware *UserHandlerMiddleware
+	metrics    *metrics.Metrics
 }
 
-func NewUserHandler(userService *service.UserService, sessionService *service.SessionService, postService *service.PostService, repostService *service.RepostService, followService *service.FollowService, favoriteService *service.FavoriteService, authMiddleware *AuthMiddleware) *UserHandler {
+func NewUserHandler(userService *service.UserService, sessionService *service.SessionService, postService *service.PostService, repostService *service.RepostService, followService *service.FollowService, favoriteService *service.FavoriteService, authMiddleware *AuthMiddleware, idempotencyMiddleware *IdempotencyMiddleware, m *metrics.Metrics) *UserHandler {
 	return &UserHandler{
 		service:    NewUserHandlerService(userService, sessionService, postService, repostService, followService, favoriteService),
-		middleware: NewUserHandlerMiddleware(authMiddleware),
+		middleware: NewUserHandlerMiddleware(authMiddleware, idempotencyMiddleware),
+		metrics:    m,
 	}
 }
 
 func (h *UserHandler) RegisterRoutes(app *fiber.App) {
-	apiUsersProtected := app.Group("/api/v1/users", h.middleware.auth.Handler)
+	apiUsersProtected := app.Group("/api/v1/users", h.middleware.auth.Handler, h.middleware.idempotency.Handler)
 	apiUsersProtected.Get("/me", h.Me)
 	apiUsersProtected.Get("/me/posts", h.GetMyPosts)
 	apiUsersProtected.Get("/me/feed", h.GetMyFeed)
 	apiUsersProtected.Get("/me/posts/favorites", h.GetMyFavoritePosts)
 	apiUsersProtected.Get("/me/reposts", h.GetMyReposts)
+	apiUsersProtected.Get("/me/drafts", h.GetMyDrafts)
 	apiUsersProtected.Post("/:id/follow", h.FollowUser)
 	apiUsersProtected.Delete("/:id/follow", h.UnfollowUser)
 
@@ -86,10 +92,11 @@ func (h *UserHandler) getCurrentUserId(ctx *fiber.Ctx) *string {
 	// Such that when getting the user, we know if we have followed that person or not yet
 	token, err := readBearerToken(ctx)
 	if err == nil && token != "" {
-		session, err := h.service.session.GetByID(ctx.Context(), token)
+		session, err := h.service.session.GetByID(ctx.UserContext(), token)
 		if err == nil && !session.IsExpired() {
 			uid := session.UserID.String()
 			currentUserID = &uid
+			logging.AddAttrs(ctx.UserContext(), slog.String("user_id", uid))
 		}
 	}
 	return currentUserID
@@ -101,13 +108,15 @@ func (h *UserHandler) CreateUser(ctx *fiber.Ctx) error {
 	}
 
 	var body request
-	if err := ctx.BodyParser(&body); err != nil {
-		return ErrorResponse(ctx, fiber.StatusBadRequest, err)
+	req := bindRequest(ctx)
+	req.Body(&body)
+	if err := req.Err(); err != nil {
+		return err
 	}
 
-	user, err := h.service.user.Create(ctx.Context(), body.NewUser)
+	user, err := h.service.user.Create(ctx.UserContext(), body.NewUser)
 	if err != nil {
-		return ErrorResponse(ctx, fiber.StatusInternalServerError, err)
+		return err
 	}
 
 	return SuccessResponse(ctx, fiber.Map{
@@ -116,12 +125,15 @@ func (h *UserHandler) CreateUser(ctx *fiber.Ctx) error {
 }
 
 func (h *UserHandler) GetUserByID(ctx *fiber.Ctx) error {
+	id, err := idParam(ctx)
+	if err != nil {
+		return err
+	}
 	currentUserID := h.getCurrentUserId(ctx)
 
-	id := ctx.Params("id")
-	user, err := h.service.user.GetByID(ctx.Context(), id, currentUserID)
+	user, err := h.service.user.GetByID(ctx.UserContext(), id, currentUserID)
 	if err != nil {
-		return ErrorResponse(ctx, fiber.StatusNotFound, err)
+		return err
 	}
 
 	return SuccessResponse(ctx, fiber.Map{
@@ -133,12 +145,12 @@ func (h *UserHandler) GetUsersByName(ctx *fiber.Ctx) error {
 	currentUserID := h.getCurrentUserId(ctx)
 
 	name := ctx.Params("name")
-	users, err := h.service.user.GetManyByName(ctx.Context(), name, currentUserID)
+	users, err := h.service.user.GetManyByName(ctx.UserContext(), name, currentUserID)
 	if err != nil {
-		if errors.Is(pgx.ErrNoRows, err) || errors.Is(sql.ErrNoRows, err) {
-			return ErrorResponse(ctx, fiber.StatusNotFound, "no users found")
+		if apperror.KindOf(err) == apperror.KindNotFound {
+			return apperror.NotFound("user_not_found", "no users found")
 		}
-		return ErrorResponse(ctx, fiber.StatusInternalServerError, err)
+		return err
 	}
 
 	usersResp := make([]dto.UserAggregateResponse, len(users))
@@ -154,29 +166,29 @@ func (h *UserHandler) GetUsersByName(ctx *fiber.Ctx) error {
 func (h *UserHandler) Me(ctx *fiber.Ctx) error {
 	session, err := GetSessionFromCtx(ctx)
 	if err != nil {
-		return ErrorResponse(ctx, fiber.StatusUnauthorized, err)
+		return err
 	}
 
 	user, err := GetUserFromCtx(ctx)
 	if err != nil {
-		return ErrorResponse(ctx, fiber.StatusUnauthorized, err)
+		return err
 	}
 
 	now := time.Now()
 	if session.ExpireAt.Before(now) {
-		_ = h.service.session.Delete(ctx.Context(), dto.DeleteSession{ID: session.ID.String()})
-		return ErrorResponse(ctx, fiber.StatusUnauthorized, errors.New("session expired"))
+		_ = h.service.session.Delete(ctx.UserContext(), dto.DeleteSession{ID: session.ID.String()})
+		return apperror.Unauthorized("session_expired", entity.ErrSessionExpired.Error())
 	}
 
 	// Refresh session if less than 1 day left
 	refreshThreshold := now.Add(24 * time.Hour)
 	if session.ExpireAt.Before(refreshThreshold) {
-		newSession, err := h.service.session.UpdateExpireAt(ctx.Context(), session, dto.UpdateSessionExpireAt{
+		newSession, err := h.service.session.UpdateExpireAt(ctx.UserContext(), session, dto.UpdateSessionExpireAt{
 			ExpireAt: entity.DefaultSessionExpireAt(),
 			ID:       session.ID.String(),
 		})
 		if err != nil {
-			return ErrorResponse(ctx, fiber.StatusInternalServerError, err)
+			return err
 		}
 
 		return SuccessResponse(ctx, fiber.Map{
@@ -197,29 +209,33 @@ func (h *UserHandler) Login(ctx *fiber.Ctx) error {
 	}
 
 	var body request
-	if err := ctx.BodyParser(&body); err != nil {
-		return ErrorResponse(ctx, fiber.StatusBadRequest, err)
+	req := bindRequest(ctx)
+	req.Body(&body)
+	if err := req.Err(); err != nil {
+		return err
 	}
 
-	user, err := h.service.user.GetByName(ctx.Context(), body.Username, nil)
+	user, err := h.service.user.GetByName(ctx.UserContext(), body.Username, nil)
 	if err != nil {
-		if errors.Is(pgx.ErrNoRows, err) || errors.Is(sql.ErrNoRows, err) {
-			return ErrorResponse(ctx, fiber.StatusNotFound, "user not found")
+		if apperror.KindOf(err) == apperror.KindNotFound {
+			h.metrics.Login(metrics.LoginFailed)
 		}
-		return ErrorResponse(ctx, fiber.StatusInternalServerError, err)
+		return err
 	}
 
 	if !user.Password.Match(body.Password) {
-		return ErrorResponse(ctx, fiber.StatusUnauthorized, "invalid credentials")
+		h.metrics.Login(metrics.LoginFailed)
+		return apperror.Unauthorized("invalid_credentials", "invalid credentials")
 	}
 
-	session, err := h.service.session.Create(ctx.Context(), dto.NewSession{
+	session, err := h.service.session.Create(ctx.UserContext(), dto.NewSession{
 		UserID:   user.ID,
 		ExpireAt: entity.DefaultSessionExpireAt(),
 	})
 	if err != nil {
-		return ErrorResponse(ctx, fiber.StatusInternalServerError, err)
+		return err
 	}
+	h.metrics.Login(metrics.LoginSucceeded)
 
 	return SuccessResponse(ctx, fiber.Map{
 		"session": session,
@@ -230,18 +246,18 @@ func (h *UserHandler) Login(ctx *fiber.Ctx) error {
 func (h *UserHandler) Logout(ctx *fiber.Ctx) error {
 	token, err := readBearerToken(ctx)
 	if err != nil {
-		return ErrorResponse(ctx, fiber.StatusUnauthorized, err)
+		return apperror.Unauthorized("invalid_token", err.Error())
 	}
 
-	session, err := h.service.session.GetByID(ctx.Context(), token)
+	session, err := h.service.session.GetByID(ctx.UserContext(), token)
 	if err != nil {
-		return ErrorResponse(ctx, fiber.StatusUnauthorized, errors.New("invalid session"))
+		return invalidSession(err)
 	}
 
-	if err := h.service.session.Delete(ctx.Context(), dto.DeleteSession{
+	if err := h.service.session.Delete(ctx.UserContext(), dto.DeleteSession{
 		ID: session.ID.String(),
 	}); err != nil {
-		return ErrorResponse(ctx, fiber.StatusInternalServerError, err)
+		return err
 	}
 
 	return SuccessResponse(ctx, nil)
@@ -250,12 +266,18 @@ func (h *UserHandler) Logout(ctx *fiber.Ctx) error {
 func (h *UserHandler) GetMyPosts(ctx *fiber.Ctx) error {
 	user, err := GetUserFromCtx(ctx)
 	if err != nil {
-		return ErrorResponse(ctx, fiber.StatusUnauthorized, err)
+		return err
+	}
+	req := bindRequest(ctx)
+	page := req.CursorPage()
+	if err := req.Err(); err != nil {
+		return err
 	}
 
-	posts, err := h.service.post.GetByUserID(ctx.Context(), user.ID.String())
+	userID := user.ID.String()
+	posts, next, err := h.service.post.GetByUserID(ctx.UserContext(), userID, &userID, page)
 	if err != nil {
-		return ErrorResponse(ctx, fiber.StatusInternalServerError, err)
+		return err
 	}
 
 	if posts == nil {
@@ -263,18 +285,53 @@ func (h *UserHandler) GetMyPosts(ctx *fiber.Ctx) error {
 	}
 
 	return SuccessResponse(ctx, fiber.Map{
-		"posts": posts,
+		"posts":      posts,
+		"pagination": newCursorPagination(page, next),
 	})
 }
+
+// Drafts and scheduled posts of the current user
+func (h *UserHandler) GetMyDrafts(ctx *fiber.Ctx) error {
+	user, err := GetUserFromCtx(ctx)
+	if err != nil {
+		return err
+	}
+	req := bindRequest(ctx)
+	page := req.CursorPage()
+	if err := req.Err(); err != nil {
+		return err
+	}
+
+	posts, next, err := h.service.post.GetDrafts(ctx.UserContext(), user.ID.String(), page)
+	if err != nil {
+		return err
+	}
+
+	if posts == nil {
+		posts = []*entity.Post{}
+	}
+
+	return SuccessResponse(ctx, fiber.Map{
+		"posts":      posts,
+		"pagination": newCursorPagination(page, next),
+	})
+}
+
 func (h *UserHandler) GetMyFavoritePosts(ctx *fiber.Ctx) error {
 	user, err := GetUserFromCtx(ctx)
 	if err != nil {
-		return ErrorResponse(ctx, fiber.StatusUnauthorized, err)
+		return err
+	}
+	req := bindRequest(ctx)
+	page := req.CursorPage()
+	if err := req.Err(); err != nil {
+		return err
 	}
 
-	posts, err := h.service.favorite.GetByUserID(ctx.Context(), user.ID.String())
+	userID := user.ID.String()
+	posts, next, err := h.service.favorite.GetByUserID(ctx.UserContext(), userID, &userID, page)
 	if err != nil {
-		return ErrorResponse(ctx, fiber.StatusInternalServerError, err)
+		return err
 	}
 
 	if posts == nil {
@@ -282,19 +339,26 @@ func (h *UserHandler) GetMyFavoritePosts(ctx *fiber.Ctx) error {
 	}
 
 	return SuccessResponse(ctx, fiber.Map{
-		"posts": posts,
+		"posts":      posts,
+		"pagination": newCursorPagination(page, next),
 	})
 }
 
 func (h *UserHandler) GetMyReposts(ctx *fiber.Ctx) error {
 	user, err := GetUserFromCtx(ctx)
 	if err != nil {
-		return ErrorResponse(ctx, fiber.StatusUnauthorized, err)
+		return err
+	}
+	req := bindRequest(ctx)
+	page := req.CursorPage()
+	if err := req.Err(); err != nil {
+		return err
 	}
 
-	reposts, err := h.service.repost.GetByUserID(ctx.Context(), user.ID.String())
+	userID := user.ID.String()
+	reposts, next, err := h.service.repost.GetByUserID(ctx.UserContext(), userID, &userID, page)
 	if err != nil {
-		return ErrorResponse(ctx, fiber.StatusInternalServerError, err)
+		return err
 	}
 
 	if reposts == nil {
@@ -302,15 +366,21 @@ func (h *UserHandler) GetMyReposts(ctx *fiber.Ctx) error {
 	}
 
 	return SuccessResponse(ctx, fiber.Map{
-		"reposts": reposts,
+		"reposts":    reposts,
+		"pagination": newCursorPagination(page, next),
 	})
 }
 
 func (h *UserHandler) GetUserPosts(ctx *fiber.Ctx) error {
-	id := ctx.Params("id")
-	posts, err := h.service.post.GetByUserID(ctx.Context(), id)
+	req := bindRequest(ctx)
+	id := req.UUIDParam("id")
+	page := req.CursorPage()
+	if err := req.Err(); err != nil {
+		return err
+	}
+	posts, next, err := h.service.post.GetByUserID(ctx.UserContext(), id.String(), h.getCurrentUserId(ctx), page)
 	if err != nil {
-		return ErrorResponse(ctx, fiber.StatusInternalServerError, err)
+		return err
 	}
 
 	if posts == nil {
@@ -318,15 +388,21 @@ func (h *UserHandler) GetUserPosts(ctx *fiber.Ctx) error {
 	}
 
 	return SuccessResponse(ctx, fiber.Map{
-		"posts": posts,
+		"posts":      posts,
+		"pagination": newCursorPagination(page, next),
 	})
 }
 
 func (h *UserHandler) GetUserReposts(ctx *fiber.Ctx) error {
-	id := ctx.Params("id")
-	reposts, err := h.service.repost.GetByUserID(ctx.Context(), id)
+	req := bindRequest(ctx)
+	id := req.UUIDParam("id")
+	page := req.CursorPage()
+	if err := req.Err(); err != nil {
+		return err
+	}
+	reposts, next, err := h.service.repost.GetByUserID(ctx.UserContext(), id.String(), h.getCurrentUserId(ctx), page)
 	if err != nil {
-		return ErrorResponse(ctx, fiber.StatusInternalServerError, err)
+		return err
 	}
 
 	if reposts == nil {
@@ -334,33 +410,33 @@ func (h *UserHandler) GetUserReposts(ctx *fiber.Ctx) error {
 	}
 
 	return SuccessResponse(ctx, fiber.Map{
-		"reposts": reposts,
+		"reposts":    reposts,
+		"pagination": newCursorPagination(page, next),
 	})
 }
 
 func (h *UserHandler) FollowUser(ctx *fiber.Ctx) error {
 	user, err := GetUserFromCtx(ctx)
 	if err != nil {
-		return ErrorResponse(ctx, fiber.StatusUnauthorized, err)
+		return err
 	}
 
-	targetID := ctx.Params("id")
+	targetID, err := idParam(ctx)
+	if err != nil {
+		return err
+	}
 	userId := user.ID.String()
-	targetUser, err := h.service.user.GetByID(ctx.Context(), targetID, &userId)
+	targetUser, err := h.service.user.GetByID(ctx.UserContext(), targetID, &userId)
 	if err != nil {
-		return ErrorResponse(ctx, fiber.StatusNotFound, err)
+		return err
 	}
 
-	_, err = h.service.follow.Create(ctx.Context(), dto.NewFollow{
+	_, err = h.service.follow.Create(ctx.UserContext(), dto.NewFollow{
 		FollowerID: user.ID,
 		FolloweeID: targetUser.ID,
 	})
 	if err != nil {
-		if errors.Is(err, entity.ErrFollowSelfFollow) {
-			return ErrorResponse(ctx, fiber.StatusBadRequest, err)
-		}
-
-		return ErrorResponse(ctx, fiber.StatusInternalServerError, err)
+		return err
 	}
 
 	return SuccessResponse(ctx, nil)
@@ -369,22 +445,25 @@ func (h *UserHandler) FollowUser(ctx *fiber.Ctx) error {
 func (h *UserHandler) UnfollowUser(ctx *fiber.Ctx) error {
 	user, err := GetUserFromCtx(ctx)
 	if err != nil {
-		return ErrorResponse(ctx, fiber.StatusUnauthorized, err)
+		return err
 	}
 
-	targetID := ctx.Params("id")
+	targetID, err := idParam(ctx)
+	if err != nil {
+		return err
+	}
 	userId := user.ID.String()
-	targetUser, err := h.service.user.GetByID(ctx.Context(), targetID, &userId)
+	targetUser, err := h.service.user.GetByID(ctx.UserContext(), targetID, &userId)
 	if err != nil {
-		return ErrorResponse(ctx, fiber.StatusNotFound, err)
+		return err
 	}
 
-	err = h.service.follow.Delete(ctx.Context(), dto.DeleteFollow{
+	err = h.service.follow.Delete(ctx.UserContext(), dto.DeleteFollow{
 		FollowerID: user.ID,
 		FolloweeID: targetUser.ID,
 	})
 	if err != nil {
-		return ErrorResponse(ctx, fiber.StatusInternalServerError, err)
+		return err
 	}
 
 	return SuccessResponse(ctx, nil)
@@ -393,15 +472,15 @@ func (h *UserHandler) UnfollowUser(ctx *fiber.Ctx) error {
 func (h *UserHandler) GetMyFeed(ctx *fiber.Ctx) error {
 	user, err := GetUserFromCtx(ctx)
 	if err != nil {
-		return ErrorResponse(ctx, fiber.StatusUnauthorized, err)
+		return err
 	}
 
 	p, pSize := getPaginationParams(ctx)
 	limit, offset := paginationToLimitOffset(p, pSize)
 
-	feed, total, err := h.service.post.GetFeed(ctx.Context(), user.ID.String(), limit, offset)
+	feed, total, err := h.service.post.GetFeed(ctx.UserContext(), user.ID.String(), limit, offset)
 	if err != nil {
-		return ErrorResponse(ctx, fiber.StatusInternalServerError, err)
+		return err
 	}
 
 	if feed == nil {
@@ -410,10 +489,10 @@ func (h *UserHandler) GetMyFeed(ctx *fiber.Ctx) error {
 
 	return SuccessResponse(ctx, fiber.Map{
 		"feed": feed,
-		"pagination": fiber.Map{
-			"page":     p,
-			"pageSize": pSize,
-			"total":    total,
+		"pagination": Pagination{
+			Page:     p,
+			PageSize: pSize,
+			Total:    total,
 		},
 	})
 }
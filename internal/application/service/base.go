@@ -1,6 +1,11 @@
 package service
 
-import "social-media-go-ddd/internal/infrastructure/cache"
+import (
+	"context"
+	"social-media-go-ddd/internal/infrastructure/cache"
+	"social-media-go-ddd/internal/infrastructure/logging"
+	"time"
+)
 
 type baseService struct {
 	cache     cache.Cache
@@ -13,3 +18,28 @@ func NewBaseService(c cache.Cache) baseService {
 		cacheKeys: NewCacheKeys(),
 	}
 }
+
+// Cached value of key, false on a miss. Cache errors are logged and treated as a miss such that the repository is read instead
+func (s *baseService) getCache(ctx context.Context, key string) (string, bool) {
+	val, err := s.cache.Get(ctx, key)
+	if cache.IsCacheError(err) {
+		logging.FromContext(ctx).Warn("cache get failed", "key", cache.KeyPrefix(key), "error", err)
+	}
+	return val, err == nil
+}
+
+func (s *baseService) setCache(ctx context.Context, key string, value any, expiration time.Duration) {
+	if err := s.cache.Set(ctx, key, value, expiration); err != nil {
+		logging.FromContext(ctx).Warn("cache set failed", "key", cache.KeyPrefix(key), "error", err)
+	}
+}
+
+// Failures are logged rather than returned, the write already went to the repository
+// and a stale entry is dropped at the latest when its ttl expires
+func (s *baseService) deleteCache(ctx context.Context, keys ...string) {
+	for _, key := range keys {
+		if err := s.cache.Delete(ctx, key); err != nil {
+			logging.FromContext(ctx).Error("cache delete failed", "key", cache.KeyPrefix(key), "error", err)
+		}
+	}
+}
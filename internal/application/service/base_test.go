@@ -0,0 +1,47 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"social-media-go-ddd/internal/domain/dto"
+	"social-media-go-ddd/internal/infrastructure/cache"
+	cachememory "social-media-go-ddd/internal/infrastructure/cache/memory"
+	"social-media-go-ddd/internal/infrastructure/logging"
+	"social-media-go-ddd/internal/infrastructure/persistence/memory"
+	"strings"
+	"testing"
+)
+
+// Cache whose deletes fail, eg redis went away after the write
+type failingDeleteCache struct {
+	cache.Cache
+}
+
+func (failingDeleteCache) Delete(ctx context.Context, key string) error {
+	return errors.New("connection refused")
+}
+
+func TestBaseService_LogsCacheDeleteErrors(t *testing.T) {
+	s := newTestServices(t)
+	user := s.createUser(t, "alice")
+	post := s.createPost(t, user, "hello")
+
+	var buf bytes.Buffer
+	ctx := logging.NewContext(context.Background(), slog.New(slog.NewTextHandler(&buf, nil)))
+	like := NewLikeService(memory.NewMemoryLikeRepository(memory.NewStore()), failingDeleteCache{cachememory.NewMemoryCache()})
+	// Deleting the like succeeds even though the post cache could not be invalidated
+	if err := like.Delete(ctx, dto.DeleteLike{UserID: user.ID, PostID: post.ID}); err != nil {
+		t.Fatalf("delete like: %v", err)
+	}
+
+	out := buf.String()
+	if !strings.Contains(out, "cache delete failed") || !strings.Contains(out, "connection refused") || !strings.Contains(out, "key=post") {
+		t.Fatalf("cache error not logged: %q", out)
+	}
+	// Keys hold ids and session tokens, only their prefix is logged
+	if strings.Contains(out, post.ID.String()) {
+		t.Fatalf("cache key logged: %q", out)
+	}
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"social-media-go-ddd/internal/application/apperror"
+	"social-media-go-ddd/internal/domain/dto"
+	"social-media-go-ddd/internal/domain/entity"
+	"social-media-go-ddd/internal/domain/repository"
+	"social-media-go-ddd/internal/infrastructure/cache"
+	"social-media-go-ddd/internal/infrastructure/tracing"
+)
+
+type CollectionService struct {
+	baseService
+	repository repository.CollectionRepository
+}
+
+func NewCollectionService(repo repository.CollectionRepository, c cache.Cache) *CollectionService {
+	return &CollectionService{
+		baseService: NewBaseService(c),
+		repository:  repo,
+	}
+}
+
+func (s *CollectionService) Create(ctx context.Context, nc dto.NewCollection) (*entity.Collection, error) {
+	ctx, span := tracing.Start(ctx, "CollectionService.Create")
+	defer span.End()
+
+	collection, err := entity.NewCollection(nc)
+	if err != nil {
+		return nil, apperror.Wrap(err, "collection")
+	}
+	if err := s.repository.Save(ctx, collection); err != nil {
+		return nil, apperror.Wrap(err, "collection")
+	}
+	return collection, nil
+}
+
+func (s *CollectionService) Update(ctx context.Context, old *entity.Collection, uc dto.UpdateCollection) (*entity.Collection, error) {
+	ctx, span := tracing.Start(ctx, "CollectionService.Update")
+	defer span.End()
+
+	collection, err := entity.NewCollectionForUpdate(old, uc)
+	if err != nil {
+		return nil, apperror.Wrap(err, "collection")
+	}
+	if err := s.repository.Update(ctx, collection); err != nil {
+		return nil, apperror.Wrap(err, "collection")
+	}
+	return collection, nil
+}
+
+// Deletes the collection, its posts stay favorited in the default collection
+func (s *CollectionService) Delete(ctx context.Context, collection *entity.Collection) error {
+	ctx, span := tracing.Start(ctx, "CollectionService.Delete")
+	defer span.End()
+
+	if err := collection.CanDelete(); err != nil {
+		return apperror.Wrap(err, "collection")
+	}
+	if err := s.repository.Delete(ctx, collection.ID.String(), collection.UserID.String()); err != nil {
+		return apperror.Wrap(err, "collection")
+	}
+	return nil
+}
+
+// Collections are private, the collection of another user is not found
+func (s *CollectionService) GetByID(ctx context.Context, id string, userID string) (*entity.Collection, error) {
+	ctx, span := tracing.Start(ctx, "CollectionService.GetByID")
+	defer span.End()
+
+	collection, err := s.repository.FindByID(ctx, id)
+	if err != nil {
+		return nil, apperror.Wrap(err, "collection")
+	}
+	if collection.UserID.String() != userID {
+		return nil, apperror.NotFound("collection_not_found", "collection not found")
+	}
+	return collection, nil
+}
+
+// Collections of the user, the default one first and the others in the order they were created
+func (s *CollectionService) GetByUserID(ctx context.Context, userID string) ([]*entity.Collection, error) {
+	ctx, span := tracing.Start(ctx, "CollectionService.GetByUserID")
+	defer span.End()
+
+	return s.repository.FindByUserID(ctx, userID)
+}
@@ -2,50 +2,91 @@ package service
 
 import (
 	"context"
+	"social-media-go-ddd/internal/application/apperror"
 	"social-media-go-ddd/internal/domain/aggregate"
 	"social-media-go-ddd/internal/domain/dto"
 	"social-media-go-ddd/internal/domain/entity"
 	"social-media-go-ddd/internal/domain/repository"
 	"social-media-go-ddd/internal/infrastructure/cache"
+	"social-media-go-ddd/internal/infrastructure/tracing"
+
+	"github.com/google/uuid"
 )
 
 type FavoriteService struct {
 	baseService
-	repository repository.FavoriteRepository
+	repository  repository.FavoriteRepository
+	collections repository.CollectionRepository
 }
 
-func NewFavoriteService(repo repository.FavoriteRepository, c cache.Cache) *FavoriteService {
+func NewFavoriteService(repo repository.FavoriteRepository, collections repository.CollectionRepository, c cache.Cache) *FavoriteService {
 	return &FavoriteService{
 		baseService: NewBaseService(c),
 		repository:  repo,
+		collections: collections,
 	}
 }
 
+// Favorites the post in the collection of nf, or in the default collection of the user when it has none.
+// A post favorited before moves into that collection
 func (s *FavoriteService) Create(ctx context.Context, nf dto.NewFavorite) (*entity.Favorite, error) {
+	ctx, span := tracing.Start(ctx, "FavoriteService.Create")
+	defer span.End()
+
+	if nf.CollectionID == uuid.Nil {
+		collection, err := s.collections.FindDefault(ctx, nf.UserID.String())
+		if err != nil {
+			return nil, apperror.Wrap(err, "collection")
+		}
+		nf.CollectionID = collection.ID
+	}
 	favorite, err := entity.NewFavorite(nf)
 	if err != nil {
-		return nil, err
+		return nil, apperror.Wrap(err, "favorite")
 	}
 	if err = s.repository.Save(ctx, favorite); err != nil {
-		return nil, err
+		return nil, apperror.Wrap(err, "favorite")
 	}
 
 	// Invalidate post cache since favorite count changed
-	s.cache.Delete(ctx, s.cacheKeys.Post(favorite.PostID.String()))
+	s.deleteCache(ctx, s.cacheKeys.Post(favorite.PostID.String()))
 	return favorite, nil
 }
 
 func (s *FavoriteService) Delete(ctx context.Context, dl dto.DeleteFavorite) error {
+	ctx, span := tracing.Start(ctx, "FavoriteService.Delete")
+	defer span.End()
+
 	// Invalidate post cache since favorite count changed
-	s.cache.Delete(ctx, s.cacheKeys.Post(dl.PostID.String()))
+	s.deleteCache(ctx, s.cacheKeys.Post(dl.PostID.String()))
 	return s.repository.Delete(ctx, dl.UserID.String(), dl.PostID.String())
 }
 
-func (s *FavoriteService) GetByUserID(ctx context.Context, userID string) ([]*aggregate.Post, error) {
-	post, err := s.repository.FindByUserID(ctx, userID)
+// Removes the post from the collection and with it from the favorites, a post is favorited in one collection at most
+func (s *FavoriteService) DeleteFromCollection(ctx context.Context, collectionID, postID string) error {
+	ctx, span := tracing.Start(ctx, "FavoriteService.DeleteFromCollection")
+	defer span.End()
+
+	// Invalidate post cache since favorite count changed
+	s.deleteCache(ctx, s.cacheKeys.Post(postID))
+	return s.repository.DeleteFromCollection(ctx, collectionID, postID)
+}
+
+func (s *FavoriteService) GetByUserID(ctx context.Context, userID string, currentUserID *string, page dto.CursorPage) ([]*aggregate.Post, *dto.Cursor, error) {
+	ctx, span := tracing.Start(ctx, "FavoriteService.GetByUserID")
+	defer span.End()
+
+	post, next, err := s.repository.FindByUserID(ctx, userID, currentUserID, page)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	return post, nil
+	return post, next, nil
+}
+
+func (s *FavoriteService) GetByCollectionID(ctx context.Context, collectionID string, currentUserID *string, page dto.CursorPage) ([]*aggregate.Post, *dto.Cursor, error) {
+	ctx, span := tracing.Start(ctx, "FavoriteService.GetByCollectionID")
+	defer span.End()
+
+	return s.repository.FindByCollectionID(ctx, collectionID, currentUserID, page)
 }
@@ -2,10 +2,12 @@ package service
 
 import (
 	"context"
+	"social-media-go-ddd/internal/application/apperror"
 	"social-media-go-ddd/internal/domain/dto"
 	"social-media-go-ddd/internal/domain/entity"
 	"social-media-go-ddd/internal/domain/repository"
 	"social-media-go-ddd/internal/infrastructure/cache"
+	"social-media-go-ddd/internal/infrastructure/tracing"
 )
 
 type FollowService struct {
@@ -21,25 +23,29 @@ func NewFollowService(repo repository.FollowRepository, c cache.Cache) *FollowSe
 }
 
 func (s *FollowService) Create(ctx context.Context, nf dto.NewFollow) (*entity.Follow, error) {
+	ctx, span := tracing.Start(ctx, "FollowService.Create")
+	defer span.End()
+
 	follow, err := entity.NewFollow(nf)
 	if err != nil {
-		return nil, err
+		return nil, apperror.Wrap(err, "follow")
 	}
 	if err = s.repository.Save(ctx, follow); err != nil {
-		return nil, err
+		return nil, apperror.Wrap(err, "follow")
 	}
 
 	// Update the cache of both users involved in the follow relationship
 	// since their follower/following counts changed
-	s.cache.Delete(ctx, s.cacheKeys.User(follow.FollowerID.String()))
-	s.cache.Delete(ctx, s.cacheKeys.User(follow.FolloweeID.String()))
+	s.deleteCache(ctx, s.cacheKeys.User(follow.FollowerID.String()), s.cacheKeys.User(follow.FolloweeID.String()))
 	return follow, nil
 }
 
 func (s *FollowService) Delete(ctx context.Context, dl dto.DeleteFollow) error {
+	ctx, span := tracing.Start(ctx, "FollowService.Delete")
+	defer span.End()
+
 	// Update the cache of both users involved in the follow relationship
 	// since their follower/following counts changed
-	s.cache.Delete(ctx, s.cacheKeys.User(dl.FolloweeID.String()))
-	s.cache.Delete(ctx, s.cacheKeys.User(dl.FollowerID.String()))
+	s.deleteCache(ctx, s.cacheKeys.User(dl.FolloweeID.String()), s.cacheKeys.User(dl.FollowerID.String()))
 	return s.repository.Delete(ctx, dl.FollowerID.String(), dl.FolloweeID.String())
 }
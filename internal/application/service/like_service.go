@@ -2,10 +2,12 @@ package service
 
 import (
 	"context"
+	"social-media-go-ddd/internal/application/apperror"
 	"social-media-go-ddd/internal/domain/dto"
 	"social-media-go-ddd/internal/domain/entity"
 	"social-media-go-ddd/internal/domain/repository"
 	"social-media-go-ddd/internal/infrastructure/cache"
+	"social-media-go-ddd/internal/infrastructure/tracing"
 )
 
 type LikeService struct {
@@ -21,21 +23,45 @@ func NewLikeService(repo repository.LikeRepository, c cache.Cache) *LikeService
 }
 
 func (s *LikeService) Create(ctx context.Context, nl dto.NewLike) (*entity.Like, error) {
+	ctx, span := tracing.Start(ctx, "LikeService.Create")
+	defer span.End()
+
 	like, err := entity.NewLike(nl)
 	if err != nil {
-		return nil, err
+		return nil, apperror.Wrap(err, "like")
 	}
-	if err = s.repository.Save(ctx, like); err != nil {
-		return nil, err
+	if _, err = s.repository.Save(ctx, like); err != nil {
+		return nil, apperror.Wrap(err, "like")
 	}
 
 	// Invalidate post cache since like count changed
-	s.cache.Delete(ctx, s.cacheKeys.Post(like.PostID.String()))
+	s.deleteCache(ctx, s.cacheKeys.Post(like.PostID.String()))
+	return like, nil
+}
+
+// Like Create, but keeps the reaction the user already has on the post
+func (s *LikeService) CreateIfAbsent(ctx context.Context, nl dto.NewLike) (*entity.Like, error) {
+	ctx, span := tracing.Start(ctx, "LikeService.CreateIfAbsent")
+	defer span.End()
+
+	like, err := entity.NewLike(nl)
+	if err != nil {
+		return nil, apperror.Wrap(err, "like")
+	}
+	if _, err = s.repository.SaveIfAbsent(ctx, like); err != nil {
+		return nil, apperror.Wrap(err, "like")
+	}
+
+	// Invalidate post cache since like count may have changed
+	s.deleteCache(ctx, s.cacheKeys.Post(like.PostID.String()))
 	return like, nil
 }
 
 func (s *LikeService) Delete(ctx context.Context, dl dto.DeleteLike) error {
+	ctx, span := tracing.Start(ctx, "LikeService.Delete")
+	defer span.End()
+
 	// Invalidate post cache since like count changed
-	s.cache.Delete(ctx, s.cacheKeys.Post(dl.PostID.String()))
+	s.deleteCache(ctx, s.cacheKeys.Post(dl.PostID.String()))
 	return s.repository.Delete(ctx, dl.UserID.String(), dl.PostID.String())
 }
@@ -0,0 +1,47 @@
+package service
+
+import (
+	"context"
+	"social-media-go-ddd/internal/application/apperror"
+	"social-media-go-ddd/internal/domain/dto"
+	"social-media-go-ddd/internal/domain/entity"
+	"social-media-go-ddd/internal/domain/repository"
+	"social-media-go-ddd/internal/infrastructure/cache"
+	"social-media-go-ddd/internal/infrastructure/tracing"
+)
+
+type PinnedPostService struct {
+	baseService
+	repository repository.PinnedPostRepository
+}
+
+func NewPinnedPostService(repo repository.PinnedPostRepository, c cache.Cache) *PinnedPostService {
+	return &PinnedPostService{
+		baseService: NewBaseService(c),
+		repository:  repo,
+	}
+}
+
+func (s *PinnedPostService) Create(ctx context.Context, np dto.NewPinnedPost) (*entity.PinnedPost, error) {
+	ctx, span := tracing.Start(ctx, "PinnedPostService.Create")
+	defer span.End()
+
+	pin, err := entity.NewPinnedPost(np)
+	if err != nil {
+		return nil, apperror.Wrap(err, "pinned_post")
+	}
+	if err = s.repository.Save(ctx, pin); err != nil {
+		return nil, apperror.Wrap(err, "pinned_post")
+	}
+	return pin, nil
+}
+
+func (s *PinnedPostService) Delete(ctx context.Context, dp dto.DeletePinnedPost) error {
+	ctx, span := tracing.Start(ctx, "PinnedPostService.Delete")
+	defer span.End()
+
+	if err := s.repository.Delete(ctx, dp.UserID.String(), dp.PostID.String()); err != nil {
+		return apperror.Wrap(err, "pinned_post")
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+	"social-media-go-ddd/internal/application/apperror"
+	"social-media-go-ddd/internal/domain/aggregate"
+	"social-media-go-ddd/internal/domain/dto"
+	"social-media-go-ddd/internal/domain/entity"
+	"social-media-go-ddd/internal/domain/repository"
+	"social-media-go-ddd/internal/infrastructure/cache"
+	"social-media-go-ddd/internal/infrastructure/tracing"
+)
+
+type PollService struct {
+	baseService
+	repository repository.PollRepository
+	votes      repository.PollVoteRepository
+}
+
+func NewPollService(repo repository.PollRepository, votes repository.PollVoteRepository, c cache.Cache) *PollService {
+	return &PollService{
+		baseService: NewBaseService(c),
+		repository:  repo,
+		votes:       votes,
+	}
+}
+
+// Vote of the user in poll, returns the poll with the results the user may now see.
+// Posts with a poll are not cached, there is nothing to invalidate
+func (s *PollService) Vote(ctx context.Context, poll *entity.Poll, nv dto.NewPollVote) (*aggregate.Poll, error) {
+	ctx, span := tracing.Start(ctx, "PollService.Vote")
+	defer span.End()
+
+	vote, err := entity.NewPollVote(poll, nv)
+	if err != nil {
+		return nil, apperror.Wrap(err, "poll")
+	}
+	if err := s.votes.Save(ctx, vote); err != nil {
+		if apperror.KindOf(err) == apperror.KindConflict {
+			return nil, apperror.Conflict("poll_already_voted", "already voted in this poll")
+		}
+		return nil, apperror.Wrap(err, "poll")
+	}
+
+	userID := nv.UserID.String()
+	return s.GetByPostID(ctx, poll.PostID.String(), &userID)
+}
+
+func (s *PollService) GetByPostID(ctx context.Context, postID string, currentUserID *string) (*aggregate.Poll, error) {
+	ctx, span := tracing.Start(ctx, "PollService.GetByPostID")
+	defer span.End()
+
+	poll, err := s.repository.FindByPostID(ctx, postID, currentUserID)
+	if err != nil {
+		return nil, apperror.Wrap(err, "poll")
+	}
+	return poll, nil
+}
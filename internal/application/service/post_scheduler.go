@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"social-media-go-ddd/internal/infrastructure/logging"
+	"time"
+)
+
+// Scheduled posts published per query, due posts beyond it are picked up by the next query of the same tick
+const schedulerBatchSize = 100
+
+// Publishes scheduled posts once they are due. Every api instance runs one, the repository makes sure each post is published once
+type PostScheduler struct {
+	post     *PostService
+	interval time.Duration
+	batch    int
+}
+
+func NewPostScheduler(post *PostService, interval time.Duration) *PostScheduler {
+	return &PostScheduler{
+		post:     post,
+		interval: interval,
+		batch:    schedulerBatchSize,
+	}
+}
+
+// Publish due posts every interval until ctx is done, interval must be above zero which LoadConfig ensures
+func (s *PostScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.publishDue(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *PostScheduler) publishDue(ctx context.Context) {
+	for ctx.Err() == nil {
+		posts, err := s.post.PublishDue(ctx, s.batch)
+		if err != nil {
+			logging.FromContext(ctx).Error("publishing scheduled posts failed", "error", err)
+			return
+		}
+		if len(posts) > 0 {
+			logging.FromContext(ctx).Info("published scheduled posts", "count", len(posts))
+		}
+		if len(posts) < s.batch {
+			return
+		}
+	}
+}
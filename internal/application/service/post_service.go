@@ -3,91 +3,233 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"social-media-go-ddd/internal/application/apperror"
 	"social-media-go-ddd/internal/domain/aggregate"
 	"social-media-go-ddd/internal/domain/dto"
 	"social-media-go-ddd/internal/domain/entity"
 	"social-media-go-ddd/internal/domain/repository"
 	"social-media-go-ddd/internal/infrastructure/cache"
+	"social-media-go-ddd/internal/infrastructure/logging"
+	"social-media-go-ddd/internal/infrastructure/tracing"
+	"time"
 )
 
 type PostService struct {
 	baseService
 	repository repository.PostRepository
+	polls      repository.PollRepository
+	// How long after its creation a post can be edited, zero means forever
+	editWindow time.Duration
 }
 
-func NewPostService(repo repository.PostRepository, c cache.Cache) *PostService {
+func NewPostService(repo repository.PostRepository, polls repository.PollRepository, c cache.Cache) *PostService {
 	return &PostService{
 		baseService: NewBaseService(c),
 		repository:  repo,
+		polls:       polls,
 	}
 }
 
+// Posts older than window can no longer be edited, zero lifts the limit
+func (s *PostService) SetEditWindow(window time.Duration) {
+	s.editWindow = window
+}
+
 func (s *PostService) Create(ctx context.Context, np dto.NewPost) (*entity.Post, error) {
+	ctx, span := tracing.Start(ctx, "PostService.Create")
+	defer span.End()
+
 	post, err := entity.NewPost(np)
 	if err != nil {
-		return nil, err
+		return nil, apperror.Wrap(err, "post")
+	}
+	// Built before the post is saved, such that an invalid poll is rejected without leaving the post behind
+	var poll *entity.Poll
+	if np.Poll != nil {
+		if poll, err = entity.NewPoll(post, *np.Poll); err != nil {
+			return nil, apperror.Wrap(err, "poll")
+		}
 	}
 	if err := s.repository.Save(ctx, post); err != nil {
-		return nil, err
+		return nil, apperror.Wrap(err, "post")
+	}
+	if poll != nil {
+		if err := s.polls.Save(ctx, poll); err != nil {
+			// A post without the poll it was written around is worse than no post, the client retries both
+			if err := s.repository.Delete(ctx, post.ID.String(), post.UserID.String()); err != nil {
+				logging.FromContext(ctx).Error("delete post of failed poll", "post_id", post.ID, "error", err)
+			}
+			return nil, apperror.Wrap(err, "poll")
+		}
+	}
+	if post.IsPublished() {
+		s.deleteCache(ctx, s.quotedCacheKeys(post)...)
 	}
 	return post, nil
 }
 
+// Cache keys of the post quoted by post if any, its quote count changes with post
+func (s *PostService) quotedCacheKeys(post *entity.Post) []string {
+	if post.QuotedPostID == nil {
+		return nil
+	}
+	return []string{s.cacheKeys.Post(post.QuotedPostID.String())}
+}
+
 func (s *PostService) GetByID(ctx context.Context, id string, currentUserID *string) (*aggregate.Post, error) {
+	ctx, span := tracing.Start(ctx, "PostService.GetByID")
+	defer span.End()
+
+	// The post of a signed in viewer holds whether they liked, favorited or reposted it, only the post seen
+	// anonymously is shared through the cache
 	cacheKey := s.cacheKeys.Post(id)
-	val, err := s.cache.Get(ctx, cacheKey)
-	if !cache.IsCacheError(err) {
-		var post aggregate.Post
-		if err := json.Unmarshal([]byte(val), &post); err == nil {
-			return &post, nil
+	if currentUserID == nil {
+		if val, ok := s.getCache(ctx, cacheKey); ok {
+			var post aggregate.Post
+			if err := json.Unmarshal([]byte(val), &post); err == nil {
+				return &post, nil
+			}
 		}
 	}
 
 	post, err := s.repository.FindByID(ctx, id, currentUserID)
 	if err != nil {
-		return nil, err
+		return nil, apperror.Wrap(err, "post")
+	}
+	// A post the viewer may not see is not found, such that its existence is not leaked either
+	if !post.VisibleTo(post.Viewer) {
+		return nil, apperror.NotFound("post_not_found", "post not found")
 	}
 
-	data, err := json.Marshal(post)
-	if err == nil {
-		s.cache.Set(ctx, cacheKey, data, cache.DefaultTTL())
+	// Quotes embed a post the viewer may see or not and which changes on its own, polls hold the choice of the viewer.
+	// Neither is cached
+	if currentUserID == nil && post.QuotedPostID == nil && post.Poll == nil {
+		data, err := json.Marshal(post)
+		if err == nil {
+			s.setCache(ctx, cacheKey, data, cache.DefaultTTL())
+		}
 	}
 
 	return post, nil
 }
 
-func (s *PostService) GetByUserID(ctx context.Context, userID string) ([]*aggregate.Post, error) {
-	posts, err := s.repository.FindByUserID(ctx, userID)
+func (s *PostService) GetByUserID(ctx context.Context, userID string, currentUserID *string, page dto.CursorPage) ([]*aggregate.Post, *dto.Cursor, error) {
+	ctx, span := tracing.Start(ctx, "PostService.GetByUserID")
+	defer span.End()
+
+	posts, next, err := s.repository.FindByUserID(ctx, userID, currentUserID, page)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	return posts, nil
+	return posts, next, nil
 }
 
 func (s *PostService) Delete(ctx context.Context, dp dto.DeletePost) error {
-	// Only invalidate the specific post cache
-	s.cache.Delete(ctx, s.cacheKeys.Post(dp.ID))
+	ctx, span := tracing.Start(ctx, "PostService.Delete")
+	defer span.End()
+
+	// Only invalidate the specific post cache and the one it quotes
+	keys := []string{s.cacheKeys.Post(dp.ID)}
+	if dp.QuotedPostID != nil {
+		keys = append(keys, s.cacheKeys.Post(dp.QuotedPostID.String()))
+	}
+	s.deleteCache(ctx, keys...)
 	return s.repository.Delete(ctx, dp.ID, dp.UserID.String())
 }
 
 func (s *PostService) Update(ctx context.Context, old *entity.Post, up dto.UpdatePost) (*entity.Post, error) {
+	ctx, span := tracing.Start(ctx, "PostService.Update")
+	defer span.End()
+
+	// Nobody saw an unpublished post yet, editing it is neither limited nor recorded
+	published := old.IsPublished()
+	if published && s.editWindow > 0 && time.Since(old.CreatedAt) > s.editWindow {
+		return nil, apperror.Forbidden("edit_window_expired", "post can no longer be edited")
+	}
+
 	post, err := entity.NewPostForUpdate(old, up)
 	if err != nil {
-		return nil, err
+		return nil, apperror.Wrap(err, "post")
 	}
-	err = s.repository.Update(ctx, post)
+	var revision *entity.PostRevision
+	if published {
+		revision, err = entity.NewPostRevision(old, post, up.UserID)
+		if err != nil {
+			return nil, apperror.Wrap(err, "post")
+		}
+	}
+	err = s.repository.Update(ctx, post, revision)
 	if err != nil {
-		return nil, err
+		return nil, apperror.Wrap(err, "post")
 	}
 
 	// Only invalidate the specific post cache
-	s.cache.Delete(ctx, s.cacheKeys.Post(post.ID.String()))
+	s.deleteCache(ctx, s.cacheKeys.Post(post.ID.String()))
 	return post, nil
 }
 
+// Publish a draft or scheduled post right away
+func (s *PostService) Publish(ctx context.Context, old *entity.Post) (*entity.Post, error) {
+	ctx, span := tracing.Start(ctx, "PostService.Publish")
+	defer span.End()
+
+	post := *old
+	if err := post.Publish(time.Now()); err != nil {
+		return nil, apperror.Wrap(err, "post")
+	}
+	if err := s.repository.Publish(ctx, &post); err != nil {
+		return nil, apperror.Wrap(err, "post")
+	}
+
+	s.deleteCache(ctx, append(s.quotedCacheKeys(&post), s.cacheKeys.Post(post.ID.String()))...)
+	return &post, nil
+}
+
+// Publish up to limit scheduled posts that are due, safe to run on several instances at once
+func (s *PostService) PublishDue(ctx context.Context, limit int) ([]*entity.Post, error) {
+	ctx, span := tracing.Start(ctx, "PostService.PublishDue")
+	defer span.End()
+
+	posts, err := s.repository.PublishDue(ctx, time.Now(), limit)
+	if err != nil {
+		return nil, err
+	}
+	for _, post := range posts {
+		s.deleteCache(ctx, append(s.quotedCacheKeys(post), s.cacheKeys.Post(post.ID.String()))...)
+	}
+	return posts, nil
+}
+
+// Drafts and scheduled posts of the user, most recently created first
+func (s *PostService) GetDrafts(ctx context.Context, userID string, page dto.CursorPage) ([]*entity.Post, *dto.Cursor, error) {
+	ctx, span := tracing.Start(ctx, "PostService.GetDrafts")
+	defer span.End()
+
+	posts, next, err := s.repository.FindDrafts(ctx, userID, page)
+	if err != nil {
+		return nil, nil, err
+	}
+	return posts, next, nil
+}
+
+// Earlier contents of the post, most recently edited first
+func (s *PostService) GetRevisions(ctx context.Context, postID string) ([]*entity.PostRevision, error) {
+	ctx, span := tracing.Start(ctx, "PostService.GetRevisions")
+	defer span.End()
+
+	revisions, err := s.repository.FindRevisions(ctx, postID)
+	if err != nil {
+		return nil, apperror.Wrap(err, "post")
+	}
+	return revisions, nil
+}
+
 // return posts, total, error
 func (s *PostService) GetFeed(ctx context.Context, userID string, limit, offset int) ([]*aggregate.Post, int, error) {
+	ctx, span := tracing.Start(ctx, "PostService.GetFeed")
+	defer span.End()
+
 	posts, total, err := s.repository.FindFeed(ctx, userID, limit, offset)
 	if err != nil {
 		return nil, 0, err
@@ -0,0 +1,230 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"social-media-go-ddd/internal/application/apperror"
+	"social-media-go-ddd/internal/domain/dto"
+	"testing"
+	"time"
+)
+
+func TestPostService_LikeInvalidatesPostCache(t *testing.T) {
+	ctx := context.Background()
+	s := newTestServices(t)
+	alice := s.createUser(t, "alice")
+	post := s.createPost(t, alice, "hello")
+
+	// warm the cache
+	if _, err := s.post.GetByID(ctx, post.ID.String(), nil); err != nil {
+		t.Fatalf("get post: %v", err)
+	}
+
+	if _, err := s.like.Create(ctx, dto.NewLike{UserID: alice.ID, PostID: post.ID}); err != nil {
+		t.Fatalf("like: %v", err)
+	}
+
+	got, err := s.post.GetByID(ctx, post.ID.String(), nil)
+	if err != nil {
+		t.Fatalf("get post: %v", err)
+	}
+	if got.LikeCount != 1 {
+		t.Fatalf("like count = %d, want 1", got.LikeCount)
+	}
+}
+
+func TestPostService_QuoteInvalidatesPostCache(t *testing.T) {
+	ctx := context.Background()
+	s := newTestServices(t)
+	alice := s.createUser(t, "alice")
+	bob := s.createUser(t, "bob")
+	post := s.createPost(t, alice, "hello")
+
+	// warm the cache
+	if _, err := s.post.GetByID(ctx, post.ID.String(), nil); err != nil {
+		t.Fatalf("get post: %v", err)
+	}
+
+	quote, err := s.post.Create(ctx, dto.NewPost{UserID: bob.ID, Content: "quoting alice", QuotedPostID: &post.ID})
+	if err != nil {
+		t.Fatalf("quote: %v", err)
+	}
+	got, err := s.post.GetByID(ctx, post.ID.String(), nil)
+	if err != nil {
+		t.Fatalf("get post: %v", err)
+	}
+	if got.QuoteCount != 1 {
+		t.Fatalf("quote count = %d, want 1", got.QuoteCount)
+	}
+
+	// The quote is not cached, it loses the quoted post as soon as it is deleted
+	if _, err := s.post.GetByID(ctx, quote.ID.String(), nil); err != nil {
+		t.Fatalf("get quote: %v", err)
+	}
+	if err := s.post.Delete(ctx, dto.DeletePost{ID: post.ID.String(), UserID: alice.ID}); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	got, err = s.post.GetByID(ctx, quote.ID.String(), nil)
+	if err != nil {
+		t.Fatalf("get quote: %v", err)
+	}
+	if got.Quote != nil || got.QuotedPostID != nil {
+		t.Fatalf("quote still embeds %+v", got.Quote)
+	}
+}
+
+func TestPostService_Update(t *testing.T) {
+	ctx := context.Background()
+	s := newTestServices(t)
+	alice := s.createUser(t, "alice")
+	post := s.createPost(t, alice, "hello")
+
+	if _, err := s.post.Update(ctx, post, dto.UpdatePost{ID: post.ID.String(), UserID: alice.ID, Content: "  "}); err == nil {
+		t.Fatal("expected empty content to be rejected")
+	}
+
+	if _, err := s.post.Update(ctx, post, dto.UpdatePost{ID: post.ID.String(), UserID: alice.ID, Content: "edited"}); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+
+	got, err := s.post.GetByID(ctx, post.ID.String(), nil)
+	if err != nil {
+		t.Fatalf("get post: %v", err)
+	}
+	if got.Content != "edited" || !got.Edited || got.EditCount != 1 {
+		t.Fatalf("content = %q edit count = %d, want %q with one edit", got.Content, got.EditCount, "edited")
+	}
+
+	revisions, err := s.post.GetRevisions(ctx, post.ID.String())
+	if err != nil {
+		t.Fatalf("get revisions: %v", err)
+	}
+	if len(revisions) != 1 || revisions[0].Content != "hello" || revisions[0].EditorID != alice.ID {
+		t.Fatalf("revisions = %+v, want the original content edited by alice", revisions)
+	}
+}
+
+func TestPostService_UpdateEditWindow(t *testing.T) {
+	ctx := context.Background()
+	s := newTestServices(t)
+	s.post.SetEditWindow(time.Minute)
+	alice := s.createUser(t, "alice")
+	post := s.createPost(t, alice, "hello")
+
+	if _, err := s.post.Update(ctx, post, dto.UpdatePost{ID: post.ID.String(), UserID: alice.ID, Content: "in time"}); err != nil {
+		t.Fatalf("update within the window: %v", err)
+	}
+
+	old := *post
+	old.CreatedAt = time.Now().Add(-2 * time.Minute)
+	_, err := s.post.Update(ctx, &old, dto.UpdatePost{ID: post.ID.String(), UserID: alice.ID, Content: "too late"})
+	var appErr *apperror.Error
+	if !errors.As(err, &appErr) || appErr.Kind != apperror.KindForbidden || appErr.Code != "edit_window_expired" {
+		t.Fatalf("update past the window: got %v, want edit_window_expired", err)
+	}
+
+	got, err := s.post.GetByID(ctx, post.ID.String(), nil)
+	if err != nil {
+		t.Fatalf("get post: %v", err)
+	}
+	if got.Content != "in time" || got.EditCount != 1 {
+		t.Fatalf("content = %q edit count = %d, want the edit within the window only", got.Content, got.EditCount)
+	}
+}
+
+func TestPostService_GetFeed(t *testing.T) {
+	ctx := context.Background()
+	s := newTestServices(t)
+	alice := s.createUser(t, "alice")
+	bob := s.createUser(t, "bob")
+	carol := s.createUser(t, "carol")
+
+	s.createPost(t, bob, "from bob")
+	carolPost := s.createPost(t, carol, "from carol")
+
+	if _, err := s.follow.Create(ctx, dto.NewFollow{FollowerID: alice.ID, FolloweeID: bob.ID}); err != nil {
+		t.Fatalf("follow: %v", err)
+	}
+
+	feed, total, err := s.post.GetFeed(ctx, alice.ID.String(), 10, 0)
+	if err != nil {
+		t.Fatalf("get feed: %v", err)
+	}
+	if total != 1 || len(feed) != 1 || feed[0].Content != "from bob" {
+		t.Fatalf("feed = %d items (total %d), want only bob's post", len(feed), total)
+	}
+
+	// bob reposting carol's post brings it into alice's feed
+	if _, err := s.repost.Create(ctx, dto.NewRepost{UserID: bob.ID, PostID: carolPost.ID}); err != nil {
+		t.Fatalf("repost: %v", err)
+	}
+
+	feed, total, err = s.post.GetFeed(ctx, alice.ID.String(), 10, 0)
+	if err != nil {
+		t.Fatalf("get feed: %v", err)
+	}
+	if total != 2 || feed[0].Content != "from carol" || feed[0].RepostUser == nil || feed[0].RepostUser.ID != bob.ID {
+		t.Fatalf("expected bob's repost of carol's post first, got %+v", feed[0])
+	}
+}
+
+func TestPostScheduler(t *testing.T) {
+	ctx := context.Background()
+	s := newTestServices(t)
+	alice := s.createUser(t, "alice")
+	aliceID := alice.ID.String()
+
+	publishAt := time.Now().Add(50 * time.Millisecond)
+	var ids []string
+	for _, content := range []string{"first", "second", "third"} {
+		post, err := s.post.Create(ctx, dto.NewPost{UserID: alice.ID, Content: content, PublishAt: &publishAt})
+		if err != nil {
+			t.Fatalf("schedule post: %v", err)
+		}
+		ids = append(ids, post.ID.String())
+	}
+	// warm the cache with the scheduled post
+	if _, err := s.post.GetByID(ctx, ids[0], &aliceID); err != nil {
+		t.Fatalf("get scheduled post as its author: %v", err)
+	}
+	if _, err := s.post.GetByID(ctx, ids[0], nil); apperror.KindOf(err) != apperror.KindNotFound {
+		t.Fatalf("get scheduled post as anyone: err = %v, want not found", err)
+	}
+
+	scheduler := NewPostScheduler(s.post, 10*time.Millisecond)
+	// More due posts than fit in one batch are published within the same tick
+	scheduler.batch = 2
+	runCtx, stop := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		scheduler.Run(runCtx)
+	}()
+	defer func() {
+		stop()
+		<-done
+	}()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		posts, _, err := s.post.GetByUserID(ctx, aliceID, nil, dto.CursorPage{Limit: 10})
+		if err != nil {
+			t.Fatalf("get posts: %v", err)
+		}
+		if len(posts) == len(ids) {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("%d of %d scheduled posts published", len(posts), len(ids))
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	got, err := s.post.GetByID(ctx, ids[0], nil)
+	if err != nil {
+		t.Fatalf("get published post: %v", err)
+	}
+	if !got.IsPublished() || got.PublishAt != nil || !got.CreatedAt.Equal(publishAt) {
+		t.Fatalf("published post status %q publishAt %v createdAt %v, want published at %v", got.Status, got.PublishAt, got.CreatedAt, publishAt)
+	}
+}
@@ -2,11 +2,13 @@ package service
 
 import (
 	"context"
+	"social-media-go-ddd/internal/application/apperror"
 	"social-media-go-ddd/internal/domain/aggregate"
 	"social-media-go-ddd/internal/domain/dto"
 	"social-media-go-ddd/internal/domain/entity"
 	"social-media-go-ddd/internal/domain/repository"
 	"social-media-go-ddd/internal/infrastructure/cache"
+	"social-media-go-ddd/internal/infrastructure/tracing"
 )
 
 type RepostService struct {
@@ -22,30 +24,39 @@ func NewRepostService(repo repository.RepostRepository, c cache.Cache) *RepostSe
 }
 
 func (s *RepostService) Create(ctx context.Context, nf dto.NewRepost) (*entity.Repost, error) {
+	ctx, span := tracing.Start(ctx, "RepostService.Create")
+	defer span.End()
+
 	repost, err := entity.NewRepost(nf)
 	if err != nil {
-		return nil, err
+		return nil, apperror.Wrap(err, "repost")
 	}
 	if err = s.repository.Save(ctx, repost); err != nil {
-		return nil, err
+		return nil, apperror.Wrap(err, "repost")
 	}
 
 	// Invalidate post cache since repost count changed
-	s.cache.Delete(ctx, s.cacheKeys.Post(repost.PostID.String()))
+	s.deleteCache(ctx, s.cacheKeys.Post(repost.PostID.String()))
 	return repost, nil
 }
 
 func (s *RepostService) Delete(ctx context.Context, dl dto.DeleteRepost) error {
+	ctx, span := tracing.Start(ctx, "RepostService.Delete")
+	defer span.End()
+
 	// Invalidate post cache since repost count changed
-	s.cache.Delete(ctx, s.cacheKeys.Post(dl.PostID.String()))
+	s.deleteCache(ctx, s.cacheKeys.Post(dl.PostID.String()))
 	return s.repository.Delete(ctx, dl.UserID.String(), dl.PostID.String())
 }
 
-func (s *RepostService) GetByUserID(ctx context.Context, userID string) ([]*aggregate.Post, error) {
-	reposts, err := s.repository.FindByUserID(ctx, userID)
+func (s *RepostService) GetByUserID(ctx context.Context, userID string, currentUserID *string, page dto.CursorPage) ([]*aggregate.Post, *dto.Cursor, error) {
+	ctx, span := tracing.Start(ctx, "RepostService.GetByUserID")
+	defer span.End()
+
+	reposts, next, err := s.repository.FindByUserID(ctx, userID, currentUserID, page)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	return reposts, nil
+	return reposts, next, nil
 }
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"social-media-go-ddd/internal/domain/dto"
+	"social-media-go-ddd/internal/domain/entity"
+	cachememory "social-media-go-ddd/internal/infrastructure/cache/memory"
+	"social-media-go-ddd/internal/infrastructure/persistence/memory"
+	"testing"
+)
+
+type testServices struct {
+	user     *UserService
+	session  *SessionService
+	post     *PostService
+	favorite *FavoriteService
+	like     *LikeService
+	repost   *RepostService
+	follow   *FollowService
+	poll     *PollService
+}
+
+func newTestServices(t *testing.T) *testServices {
+	t.Helper()
+
+	store := memory.NewStore()
+	c := cachememory.NewMemoryCache()
+	return &testServices{
+		user:     NewUserService(memory.NewMemoryUserRepository(store), c),
+		session:  NewSessionService(memory.NewMemorySessionRepository(store), c),
+		post:     NewPostService(memory.NewMemoryPostRepository(store), memory.NewMemoryPollRepository(store), c),
+		favorite: NewFavoriteService(memory.NewMemoryFavoriteRepository(store), memory.NewMemoryCollectionRepository(store), c),
+		like:     NewLikeService(memory.NewMemoryLikeRepository(store), c),
+		repost:   NewRepostService(memory.NewMemoryRepostRepository(store), c),
+		follow:   NewFollowService(memory.NewMemoryFollowRepository(store), c),
+		poll:     NewPollService(memory.NewMemoryPollRepository(store), memory.NewMemoryPollVoteRepository(store), c),
+	}
+}
+
+func (s *testServices) createUser(t *testing.T, username string) *entity.User {
+	t.Helper()
+
+	user, err := s.user.Create(context.Background(), dto.NewUser{
+		Username: username,
+		Password: "password123",
+		Email:    username + "@example.com",
+	})
+	if err != nil {
+		t.Fatalf("create user %s: %v", username, err)
+	}
+	return user
+}
+
+func (s *testServices) createPost(t *testing.T, user *entity.User, content string) *entity.Post {
+	t.Helper()
+
+	post, err := s.post.Create(context.Background(), dto.NewPost{UserID: user.ID, Content: content})
+	if err != nil {
+		t.Fatalf("create post: %v", err)
+	}
+	return post
+}
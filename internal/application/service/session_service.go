@@ -3,10 +3,12 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"social-media-go-ddd/internal/application/apperror"
 	"social-media-go-ddd/internal/domain/dto"
 	"social-media-go-ddd/internal/domain/entity"
 	"social-media-go-ddd/internal/domain/repository"
 	"social-media-go-ddd/internal/infrastructure/cache"
+	"social-media-go-ddd/internal/infrastructure/tracing"
 	"time"
 )
 
@@ -23,40 +25,45 @@ func NewSessionService(repo repository.SessionRepository, c cache.Cache) *Sessio
 }
 
 func (s *SessionService) Create(ctx context.Context, ns dto.NewSession) (*entity.Session, error) {
+	ctx, span := tracing.Start(ctx, "SessionService.Create")
+	defer span.End()
+
 	session, err := entity.NewSession(ns)
 	if err != nil {
-		return nil, err
+		return nil, apperror.Wrap(err, "session")
 	}
 	if err = s.repository.Save(ctx, session); err != nil {
-		return nil, err
+		return nil, apperror.Wrap(err, "session")
 	}
 	return session, nil
 }
 
 func (s *SessionService) GetByID(ctx context.Context, id string) (*entity.Session, error) {
+	ctx, span := tracing.Start(ctx, "SessionService.GetByID")
+	defer span.End()
+
 	cacheKey := s.cacheKeys.Session(id)
-	val, err := s.cache.Get(ctx, cacheKey)
-	if !cache.IsCacheError(err) {
+	if val, ok := s.getCache(ctx, cacheKey); ok {
 		var session entity.Session
 		if json.Unmarshal([]byte(val), &session) == nil {
 			if !session.IsExpired() {
 				return &session, nil
 			}
 			// Delete expired session from cache
-			s.cache.Delete(ctx, cacheKey)
+			s.deleteCache(ctx, cacheKey)
 		}
 	}
 
 	session, err := s.repository.FindByID(ctx, id)
 	if err != nil {
-		return nil, err
+		return nil, apperror.Wrap(err, "session")
 	}
 
 	if !session.IsExpired() {
 		data, err := json.Marshal(session)
 		if err == nil {
 			// cache until session expire
-			s.cache.Set(ctx, cacheKey, data, time.Until(session.ExpireAt))
+			s.setCache(ctx, cacheKey, data, time.Until(session.ExpireAt))
 		}
 	}
 
@@ -64,18 +71,24 @@ func (s *SessionService) GetByID(ctx context.Context, id string) (*entity.Sessio
 }
 
 func (s *SessionService) Delete(ctx context.Context, ds dto.DeleteSession) error {
-	s.cache.Delete(ctx, s.cacheKeys.Session(ds.ID))
+	ctx, span := tracing.Start(ctx, "SessionService.Delete")
+	defer span.End()
+
+	s.deleteCache(ctx, s.cacheKeys.Session(ds.ID))
 	return s.repository.Delete(ctx, ds.ID)
 }
 
 func (s *SessionService) UpdateExpireAt(ctx context.Context, old *entity.Session, up dto.UpdateSessionExpireAt) (*entity.Session, error) {
+	ctx, span := tracing.Start(ctx, "SessionService.UpdateExpireAt")
+	defer span.End()
+
 	session, err := entity.NewSessionForUpdate(old, up)
 	if err != nil {
-		return nil, err
+		return nil, apperror.Wrap(err, "session")
 	}
 	if err = s.repository.UpdateExpireAt(ctx, session); err != nil {
-		return nil, err
+		return nil, apperror.Wrap(err, "session")
 	}
-	s.cache.Delete(ctx, s.cacheKeys.Session(session.ID.String()))
+	s.deleteCache(ctx, s.cacheKeys.Session(session.ID.String()))
 	return session, nil
 }
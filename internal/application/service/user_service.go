@@ -3,11 +3,13 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"social-media-go-ddd/internal/application/apperror"
 	"social-media-go-ddd/internal/domain/aggregate"
 	"social-media-go-ddd/internal/domain/dto"
 	"social-media-go-ddd/internal/domain/entity"
 	"social-media-go-ddd/internal/domain/repository"
 	"social-media-go-ddd/internal/infrastructure/cache"
+	"social-media-go-ddd/internal/infrastructure/tracing"
 )
 
 type UserService struct {
@@ -23,20 +25,25 @@ func NewUserService(repo repository.UserRepository, c cache.Cache) *UserService
 }
 
 func (s *UserService) Create(ctx context.Context, nu dto.NewUser) (*entity.User, error) {
+	ctx, span := tracing.Start(ctx, "UserService.Create")
+	defer span.End()
+
 	user, err := entity.NewUser(nu)
 	if err != nil {
-		return nil, err
+		return nil, apperror.Wrap(err, "user")
 	}
 	if err = s.repository.Save(ctx, user); err != nil {
-		return nil, err
+		return nil, apperror.Wrap(err, "user")
 	}
 	return user, nil
 }
 
 func (s *UserService) GetByID(ctx context.Context, id string, currentUserID *string) (*aggregate.User, error) {
+	ctx, span := tracing.Start(ctx, "UserService.GetByID")
+	defer span.End()
+
 	cacheKey := s.cacheKeys.User(id)
-	val, err := s.cache.Get(ctx, cacheKey)
-	if !cache.IsCacheError(err) {
+	if val, ok := s.getCache(ctx, cacheKey); ok {
 		var user aggregate.User
 		if err := json.Unmarshal([]byte(val), &user); err == nil {
 			return &user, nil
@@ -45,21 +52,23 @@ func (s *UserService) GetByID(ctx context.Context, id string, currentUserID *str
 
 	user, err := s.repository.FindByID(ctx, id, currentUserID)
 	if err != nil {
-		return nil, err
+		return nil, apperror.Wrap(err, "user")
 	}
 
 	data, err := json.Marshal(user)
 	if err == nil {
-		s.cache.Set(ctx, cacheKey, data, cache.DefaultTTL())
+		s.setCache(ctx, cacheKey, data, cache.DefaultTTL())
 	}
 
 	return user, nil
 }
 
 func (s *UserService) GetByName(ctx context.Context, name string, currentUserID *string) (*aggregate.User, error) {
+	ctx, span := tracing.Start(ctx, "UserService.GetByName")
+	defer span.End()
+
 	cacheKey := s.cacheKeys.UserByName(name)
-	val, err := s.cache.Get(ctx, cacheKey)
-	if !cache.IsCacheError(err) {
+	if val, ok := s.getCache(ctx, cacheKey); ok {
 		var user aggregate.User
 		if err := json.Unmarshal([]byte(val), &user); err == nil {
 			return &user, nil
@@ -68,18 +77,21 @@ func (s *UserService) GetByName(ctx context.Context, name string, currentUserID
 
 	user, err := s.repository.FindByName(ctx, name, currentUserID)
 	if err != nil {
-		return nil, err
+		return nil, apperror.Wrap(err, "user")
 	}
 
 	data, err := json.Marshal(user)
 	if err == nil {
-		s.cache.Set(ctx, cacheKey, data, cache.DefaultTTL())
+		s.setCache(ctx, cacheKey, data, cache.DefaultTTL())
 	}
 
 	return user, nil
 }
 
 func (s *UserService) GetManyByName(ctx context.Context, name string, currentUserID *string) ([]*aggregate.User, error) {
+	ctx, span := tracing.Start(ctx, "UserService.GetManyByName")
+	defer span.End()
+
 	users, err := s.repository.SearchManyByName(ctx, name, currentUserID)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"social-media-go-ddd/internal/domain/dto"
+	"testing"
+)
+
+func TestUserService_CreateRejectsDuplicateUsername(t *testing.T) {
+	s := newTestServices(t)
+	s.createUser(t, "alice")
+
+	_, err := s.user.Create(context.Background(), dto.NewUser{
+		Username: "alice",
+		Password: "password123",
+		Email:    "other@example.com",
+	})
+	if err == nil {
+		t.Fatal("expected duplicate username to fail")
+	}
+}
+
+func TestUserService_GetByNameMatchesPassword(t *testing.T) {
+	s := newTestServices(t)
+	s.createUser(t, "alice")
+
+	user, err := s.user.GetByName(context.Background(), "alice", nil)
+	if err != nil {
+		t.Fatalf("get by name: %v", err)
+	}
+	if !user.Password.Match("password123") {
+		t.Fatal("expected stored password hash to match")
+	}
+	// second read is served from cache and must keep the password hash
+	cached, err := s.user.GetByName(context.Background(), "alice", nil)
+	if err != nil {
+		t.Fatalf("get by name from cache: %v", err)
+	}
+	if !cached.Password.Match("password123") {
+		t.Fatal("expected cached password hash to match")
+	}
+}
+
+func TestFollowService_InvalidatesUserCache(t *testing.T) {
+	ctx := context.Background()
+	s := newTestServices(t)
+	alice := s.createUser(t, "alice")
+	bob := s.createUser(t, "bob")
+
+	// warm the cache
+	if _, err := s.user.GetByID(ctx, bob.ID.String(), nil); err != nil {
+		t.Fatalf("get bob: %v", err)
+	}
+
+	if _, err := s.follow.Create(ctx, dto.NewFollow{FollowerID: alice.ID, FolloweeID: bob.ID}); err != nil {
+		t.Fatalf("follow: %v", err)
+	}
+
+	got, err := s.user.GetByID(ctx, bob.ID.String(), nil)
+	if err != nil {
+		t.Fatalf("get bob: %v", err)
+	}
+	if got.FollowerCount != 1 {
+		t.Fatalf("follower count = %d, want 1", got.FollowerCount)
+	}
+}
@@ -0,0 +1,176 @@
+// Package validation checks the `validate` struct tags of the dto types.
+//
+// Rules are separated by commas, eg `validate:"required,max=5000"`:
+//
+//	required   not blank for strings, not zero otherwise
+//	min=N      at least N characters
+//	max=N      at most N characters
+//	email      a plain email address without display name
+//	username   letters, digits, dots, dashes and underscores
+//	uuid       a valid uuid
+//	readonly   set by the server, rejected in request bodies
+//
+// Rules other than required are skipped for empty values, such that optional fields can have them.
+package validation
+
+import (
+	"fmt"
+	"net/mail"
+	"reflect"
+	"regexp"
+	"social-media-go-ddd/internal/application/apperror"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// Codes of the field errors, one per rule
+const (
+	CodeRequired     = "required"
+	CodeMinLength    = "min_length"
+	CodeMaxLength    = "max_length"
+	CodeEmail        = "email"
+	CodeUsername     = "username"
+	CodeUUID         = "uuid"
+	CodeUnknownField = "unknown_field"
+	CodeType         = "type"
+	CodeCursor       = "cursor"
+)
+
+var usernameRe = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+// Every violation of the validate tags of v, a struct or a pointer to one
+func Struct(v any) []apperror.FieldError {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	var errs []apperror.FieldError
+	validateFields(rv, &errs)
+	return errs
+}
+
+func validateFields(rv reflect.Value, errs *[]apperror.FieldError) {
+	t := rv.Type()
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if f.Anonymous && f.Type.Kind() == reflect.Struct {
+			validateFields(rv.Field(i), errs)
+			continue
+		}
+		tag := f.Tag.Get("validate")
+		if tag == "" || !f.IsExported() {
+			continue
+		}
+		for _, rule := range strings.Split(tag, ",") {
+			if fe := check(JSONName(f), rv.Field(i), rule); fe != nil {
+				*errs = append(*errs, *fe)
+				// One error per field is enough
+				break
+			}
+		}
+	}
+}
+
+func check(field string, v reflect.Value, rule string) *apperror.FieldError {
+	name, arg, _ := strings.Cut(rule, "=")
+
+	if name == "readonly" {
+		return nil
+	}
+	if name == "required" {
+		if isBlank(v) {
+			return &apperror.FieldError{Field: field, Code: CodeRequired, Message: field + " is required"}
+		}
+		return nil
+	}
+	if isBlank(v) {
+		return nil
+	}
+
+	s := ""
+	if v.Kind() == reflect.String {
+		s = v.String()
+	}
+
+	switch name {
+	case "min", "max":
+		n, err := strconv.Atoi(arg)
+		if err != nil {
+			panic(fmt.Sprintf("validation: invalid %s rule on %s", rule, field))
+		}
+		length := utf8.RuneCountInString(s)
+		if name == "min" && length < n {
+			return &apperror.FieldError{Field: field, Code: CodeMinLength, Message: fmt.Sprintf("%s must be at least %d characters", field, n)}
+		}
+		if name == "max" && length > n {
+			return &apperror.FieldError{Field: field, Code: CodeMaxLength, Message: fmt.Sprintf("%s must be at most %d characters", field, n)}
+		}
+	case "email":
+		if addr, err := mail.ParseAddress(s); err != nil || addr.Address != s {
+			return &apperror.FieldError{Field: field, Code: CodeEmail, Message: field + " must be a valid email address"}
+		}
+	case "username":
+		if !usernameRe.MatchString(s) {
+			return &apperror.FieldError{Field: field, Code: CodeUsername, Message: field + " may only contain letters, digits, dots, dashes and underscores"}
+		}
+	case "uuid":
+		return UUID(field, s)
+	default:
+		panic(fmt.Sprintf("validation: unknown rule %q on %s", rule, field))
+	}
+	return nil
+}
+
+func isBlank(v reflect.Value) bool {
+	if v.Kind() == reflect.String {
+		return strings.TrimSpace(v.String()) == ""
+	}
+	return v.IsZero()
+}
+
+// Nil when s is a valid uuid
+func UUID(field, s string) *apperror.FieldError {
+	if _, err := uuid.Parse(s); err != nil {
+		return &apperror.FieldError{Field: field, Code: CodeUUID, Message: field + " must be a valid uuid"}
+	}
+	return nil
+}
+
+// Name of the field in json, the field name when it has no json tag
+func JSONName(f reflect.StructField) string {
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	if name == "" {
+		return f.Name
+	}
+	return name
+}
+
+// Json names of the fields of the struct type t a client may send, including embedded structs
+func BodyFields(t reflect.Type) map[string]bool {
+	names := map[string]bool{}
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if f.Tag.Get("json") == "-" || (!f.IsExported() && !f.Anonymous) {
+			continue
+		}
+		if f.Anonymous && f.Tag.Get("json") == "" && f.Type.Kind() == reflect.Struct {
+			for name := range BodyFields(f.Type) {
+				names[name] = true
+			}
+			continue
+		}
+		if !ReadOnly(f) {
+			names[JSONName(f)] = true
+		}
+	}
+	return names
+}
+
+func ReadOnly(f reflect.StructField) bool {
+	for _, rule := range strings.Split(f.Tag.Get("validate"), ",") {
+		if rule == "readonly" {
+			return true
+		}
+	}
+	return false
+}
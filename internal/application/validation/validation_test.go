@@ -0,0 +1,72 @@
+package validation
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type testDTO struct {
+	Name    string `json:"name" validate:"required,max=5,username"`
+	Email   string `json:"email" validate:"email"`
+	Comment string `json:"comment" validate:"min=2"`
+	ID      string `json:"id" validate:"uuid"`
+	Owner   string `json:"owner" validate:"readonly"`
+}
+
+func codes(v any) map[string]string {
+	out := map[string]string{}
+	for _, fe := range Struct(v) {
+		out[fe.Field] = fe.Code
+	}
+	return out
+}
+
+func TestStruct(t *testing.T) {
+	tests := []struct {
+		name string
+		dto  testDTO
+		want map[string]string
+	}{
+		{"valid", testDTO{Name: "bob", Email: "bob@example.com", Comment: "hi", ID: "0b3c1c44-2b7e-4c5a-9d0e-6c8a3f2e1d00"}, map[string]string{}},
+		{"optional fields empty", testDTO{Name: "bob"}, map[string]string{}},
+		{"blank is missing", testDTO{Name: "   "}, map[string]string{"name": CodeRequired}},
+		{"length counts characters", testDTO{Name: "bob", Comment: "é"}, map[string]string{"comment": CodeMinLength}},
+		{"too long", testDTO{Name: "abcdef"}, map[string]string{"name": CodeMaxLength}},
+		{"every violation", testDTO{Name: "a b", Email: "Bob <bob@example.com>", Comment: "x", ID: "42"}, map[string]string{
+			"name": CodeUsername, "email": CodeEmail, "comment": CodeMinLength, "id": CodeUUID,
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := codes(tt.dto); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("Struct() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBodyFields(t *testing.T) {
+	type embedding struct {
+		testDTO
+		Extra string `json:"extra"`
+	}
+
+	got := BodyFields(reflect.TypeOf(embedding{}))
+	want := map[string]bool{"name": true, "email": true, "comment": true, "id": true, "extra": true}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("BodyFields() = %v, want %v", got, want)
+	}
+}
+
+func TestUnknownRulePanics(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil || !strings.Contains(r.(string), "unknown rule") {
+			t.Fatalf("recover() = %v, want unknown rule panic", r)
+		}
+	}()
+	Struct(struct {
+		Name string `validate:"nope"`
+	}{Name: "x"})
+}
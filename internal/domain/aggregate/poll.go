@@ -0,0 +1,39 @@
+package aggregate
+
+import (
+	"social-media-go-ddd/internal/domain/entity"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Poll with its results as the viewer may see them
+type Poll struct {
+	entity.Poll
+	Closed bool `json:"closed"`
+	// Users who voted so far
+	VoterCount int `json:"voterCount"`
+	// Whether the viewer voted, ViewerChoice holds the options they chose
+	Voted        bool        `json:"voted"`
+	ViewerChoice []uuid.UUID `json:"viewerChoice"`
+	// Votes per option in the order of Options, left out until the viewer voted or the poll closed
+	Tallies []int `json:"tallies,omitempty"`
+}
+
+// Tallies are hidden from a viewer without a vote until the poll closes at now
+func NewPoll(poll entity.Poll, tallies []int, voterCount int, viewerChoice []uuid.UUID, now time.Time) *Poll {
+	p := &Poll{
+		Poll:         poll,
+		Closed:       poll.IsClosed(now),
+		VoterCount:   voterCount,
+		Voted:        len(viewerChoice) > 0,
+		ViewerChoice: viewerChoice,
+	}
+	if p.ViewerChoice == nil {
+		p.ViewerChoice = []uuid.UUID{}
+	}
+	if p.Voted || p.Closed {
+		p.Tallies = tallies
+	}
+	return p
+}
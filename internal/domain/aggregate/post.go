@@ -12,6 +12,8 @@ const (
 	PostTypeText PostType = "text"
 	// When a user reposts another post
 	PostTypeRepost PostType = "repost"
+	// When a user posts a text quoting another post
+	PostTypeQuote PostType = "quote"
 )
 
 // Post join with like, repost, favorite
@@ -24,13 +26,33 @@ type Post struct {
 	LikeCount     int         `json:"likeCount"`
 	RepostCount   int         `json:"repostCount"`
 	FavoriteCount int         `json:"favoriteCount"`
-	Type          PostType    `json:"type"`
+	QuoteCount    int         `json:"quoteCount"`
+	// Count of each reaction, every reaction of entity.Reactions is present. LikeCount is their sum
+	Reactions map[entity.Reaction]int `json:"reactions"`
+	// Reaction of the viewer, nil when they have not reacted. Liked is true whatever the reaction
+	Reaction *entity.Reaction `json:"reaction"`
+	// Edited is true once the post has at least one revision
+	Edited    bool     `json:"edited"`
+	EditCount int      `json:"editCount"`
+	Type      PostType `json:"type"`
 	// If this post is a repost, Repost refers to the original post
 	Repost     *entity.Repost `json:"repost,omitempty"`
 	RepostUser *entity.User   `json:"repostUser,omitempty"`
+	// If this post is a quote, Quote is the quoted post unless the viewer may not see it. Its own quote is left out
+	Quote *Post `json:"quote,omitempty"`
+	// Poll attached to the post, nil when it has none. A quoted post is embedded without its poll
+	Poll *Poll `json:"poll,omitempty"`
+	// Pinned to the top of the profile being listed, only set by PostRepository.FindByUserID
+	Pinned bool `json:"pinned"`
+	// Relationship of the viewer to the post, set by FindByID to decide whether they may see it
+	Viewer entity.PostViewer `json:"-"`
 }
 
 func NewPost(post entity.Post, user entity.User, cpa dto.CommonPostAggregate) *Post {
+	postType := PostTypeText
+	if post.QuotedPostID != nil {
+		postType = PostTypeQuote
+	}
 	return &Post{
 		Post:          post,
 		User:          user,
@@ -40,8 +62,12 @@ func NewPost(post entity.Post, user entity.User, cpa dto.CommonPostAggregate) *P
 		LikeCount:     cpa.LikeCount,
 		RepostCount:   cpa.RepostCount,
 		FavoriteCount: cpa.FavoriteCount,
-		Type:          PostTypeText,
-		// post type text which mean repost is null
+		Edited:        cpa.EditCount > 0,
+		EditCount:     cpa.EditCount,
+		QuoteCount:    cpa.QuoteCount,
+		Reactions:     newReactionCounts(),
+		Type:          postType,
+		// post type text or quote which mean repost is null
 		Repost: nil,
 	}
 }
@@ -56,8 +82,31 @@ func NewRepost(post entity.Post, repost *entity.Repost, user entity.User, repost
 		LikeCount:     cpa.LikeCount,
 		RepostCount:   cpa.RepostCount,
 		FavoriteCount: cpa.FavoriteCount,
+		Edited:        cpa.EditCount > 0,
+		EditCount:     cpa.EditCount,
+		QuoteCount:    cpa.QuoteCount,
+		Reactions:     newReactionCounts(),
 		Type:          PostTypeRepost,
 		Repost:        repost,
 		RepostUser:    repostUser,
 	}
 }
+
+// AddReactions counts count reactions of kind reaction on the post, viewer tells whether the viewer reacted with it
+func (p *Post) AddReactions(reaction entity.Reaction, count int, viewer bool) {
+	if p.Reactions == nil {
+		p.Reactions = newReactionCounts()
+	}
+	p.Reactions[reaction] += count
+	if viewer {
+		p.Reaction = &reaction
+	}
+}
+
+func newReactionCounts() map[entity.Reaction]int {
+	counts := make(map[entity.Reaction]int, len(entity.Reactions))
+	for _, r := range entity.Reactions {
+		counts[r] = 0
+	}
+	return counts
+}
@@ -0,0 +1,16 @@
+package dto
+
+import "github.com/google/uuid"
+
+type (
+	NewCollection struct {
+		UserID uuid.UUID `json:"user_id" validate:"readonly"`
+		Name   string    `json:"name" validate:"required,max=50"`
+	}
+
+	UpdateCollection struct {
+		ID     string    `json:"id" validate:"readonly"`
+		UserID uuid.UUID `json:"user_id" validate:"readonly"`
+		Name   string    `json:"name" validate:"required,max=50"`
+	}
+)
@@ -4,8 +4,10 @@ import "github.com/google/uuid"
 
 type (
 	NewFavorite struct {
-		UserID uuid.UUID `json:"user_id"`
-		PostID uuid.UUID `json:"post_id"`
+		UserID uuid.UUID `json:"user_id" validate:"readonly"`
+		PostID uuid.UUID `json:"post_id" validate:"required"`
+		// Default collection of the user when empty, see FavoriteService.Create
+		CollectionID uuid.UUID `json:"collection_id" validate:"readonly"`
 	}
 
 	DeleteFavorite struct {
@@ -4,8 +4,11 @@ import "github.com/google/uuid"
 
 type (
 	NewLike struct {
-		UserID uuid.UUID `json:"user_id"`
-		PostID uuid.UUID `json:"post_id"`
+		UserID uuid.UUID `json:"user_id" validate:"readonly"`
+		PostID uuid.UUID `json:"post_id" validate:"readonly"`
+		// One of entity.Reactions, a request reacting to a post must set it. Code that only likes may leave it empty,
+		// entity.NewLike turns an empty one into a like
+		Reaction string `json:"reaction" validate:"required"`
 	}
 
 	DeleteLike struct {
@@ -0,0 +1,36 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type (
+	// Position in a newest first list, the next page holds the rows sorting strictly before it by (created_at, id) desc
+	Cursor struct {
+		CreatedAt time.Time
+		ID        uuid.UUID
+	}
+
+	// Page of a newest first list, a nil After starts at the newest row
+	CursorPage struct {
+		After *Cursor
+		Limit int
+	}
+)
+
+// Rows to fetch, one more than the page such that a following page can be told apart from the end of the list
+func (p CursorPage) FetchLimit() int {
+	return p.Limit + 1
+}
+
+// Cuts rows fetched with FetchLimit down to the page, keys holds the cursor of each row.
+// The returned cursor points after the last row of the page, nil when no rows follow it
+func PageOf[T any](p CursorPage, rows []T, keys []Cursor) ([]T, *Cursor) {
+	if len(rows) <= p.Limit {
+		return rows, nil
+	}
+	next := keys[p.Limit-1]
+	return rows[:p.Limit], &next
+}
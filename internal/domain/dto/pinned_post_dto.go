@@ -0,0 +1,15 @@
+package dto
+
+import "github.com/google/uuid"
+
+type (
+	NewPinnedPost struct {
+		UserID uuid.UUID `json:"user_id"`
+		PostID uuid.UUID `json:"post_id"`
+	}
+
+	DeletePinnedPost struct {
+		UserID uuid.UUID `json:"user_id"`
+		PostID uuid.UUID `json:"post_id"`
+	}
+)
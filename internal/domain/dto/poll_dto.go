@@ -0,0 +1,24 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type (
+	NewPoll struct {
+		// 2 to 4 options in the order they are shown
+		Options []string `json:"options"`
+		// single or multiple, defaults to single
+		Choice string `json:"choice,omitempty"`
+		// No votes are taken from then on and the results are shown to everyone
+		ClosesAt time.Time `json:"closes_at"`
+	}
+
+	NewPollVote struct {
+		UserID uuid.UUID `json:"user_id" validate:"readonly"`
+		// A single option, or one or more when the poll is multiple choice
+		OptionIDs []uuid.UUID `json:"option_ids" validate:"required"`
+	}
+)
@@ -1,22 +1,38 @@
 package dto
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 type (
 	NewPost struct {
-		UserID  uuid.UUID `json:"user_id"`
-		Content string    `json:"content"`
+		UserID  uuid.UUID `json:"user_id" validate:"readonly"`
+		Content string    `json:"content" validate:"required,max=5000"`
+		// Kept private to the author until published
+		Draft bool `json:"draft"`
+		// Published by the scheduler at this time, private to the author until then
+		PublishAt *time.Time `json:"publish_at"`
+		// public, followers or mentioned, defaults to public
+		Visibility string `json:"visibility"`
+		// Post the new post quotes, it must be public and published
+		QuotedPostID *uuid.UUID `json:"quoted_post_id"`
+		// Poll attached to the post, it must close after the post is published
+		Poll *NewPoll `json:"poll"`
 	}
 
 	DeletePost struct {
 		ID     string    `json:"id"`
 		UserID uuid.UUID `json:"user_id"`
+		// Post quoted by the deleted post, its quote count changes
+		QuotedPostID *uuid.UUID `json:"quoted_post_id"`
 	}
 
 	UpdatePost struct {
-		ID      string    `json:"id"`
-		UserID  uuid.UUID `json:"user_id"`
-		Content string    `json:"content"`
+		ID      string    `json:"id" validate:"readonly"`
+		UserID  uuid.UUID `json:"user_id" validate:"readonly"`
+		Content string    `json:"content" validate:"required,max=5000"`
 	}
 
 	CommonPostAggregate struct {
@@ -25,6 +41,8 @@ type (
 		Reposted      bool `json:"reposted"`
 		LikeCount     int  `json:"like_count"`
 		RepostCount   int  `json:"repost_count"`
+		QuoteCount    int  `json:"quote_count"`
 		FavoriteCount int  `json:"favorite_count"`
+		EditCount     int  `json:"edit_count"`
 	}
 )
@@ -6,9 +6,9 @@ import (
 
 type (
 	NewRepost struct {
-		UserID  uuid.UUID `json:"user_id"`
-		PostID  uuid.UUID `json:"post_id"`
-		Comment string    `json:"comment"`
+		UserID  uuid.UUID `json:"user_id" validate:"readonly"`
+		PostID  uuid.UUID `json:"post_id" validate:"readonly"`
+		Comment string    `json:"comment" validate:"max=1000"`
 	}
 
 	DeleteRepost struct {
@@ -8,14 +8,14 @@ import (
 
 type (
 	NewUser struct {
-		Username string `json:"username"`
-		Password string `json:"password"`
-		Email    string `json:"email"`
+		Username string `json:"username" validate:"required,max=50,username"`
+		Password string `json:"password" validate:"required,min=8,max=255"`
+		Email    string `json:"email" validate:"required,max=255,email"`
 	}
 
 	UserLogin struct {
-		Username string `json:"username"`
-		Password string `json:"password"`
+		Username string `json:"username" validate:"required,max=255"`
+		Password string `json:"password" validate:"required,max=255"`
 	}
 
 	CommonUserAggregate struct {
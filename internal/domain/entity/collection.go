@@ -0,0 +1,83 @@
+package entity
+
+import (
+	"social-media-go-ddd/internal/domain/dto"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// Name of the collection every user starts with, favorites without a collection go there
+	DefaultCollectionName   = "Favorites"
+	MaxCollectionNameLength = 50
+)
+
+// Named, private group of a user's favorites, every favorite is in exactly one collection
+type Collection struct {
+	BaseEntity
+	UserID uuid.UUID `json:"userId"`
+	Name   string    `json:"name"`
+	// The default collection is created with the user and cannot be deleted
+	Default bool `json:"default"`
+}
+
+func NewCollection(nc dto.NewCollection) (*Collection, error) {
+	collection := &Collection{
+		BaseEntity: NewBaseEntity(),
+		UserID:     nc.UserID,
+		Name:       strings.TrimSpace(nc.Name),
+	}
+	if err := collection.Validate(); err != nil {
+		return nil, err
+	}
+	return collection, nil
+}
+
+func NewDefaultCollection(userID uuid.UUID) *Collection {
+	return &Collection{
+		BaseEntity: NewBaseEntity(),
+		UserID:     userID,
+		Name:       DefaultCollectionName,
+		Default:    true,
+	}
+}
+
+// Renames the collection, the old one is left untouched
+func NewCollectionForUpdate(old *Collection, uc dto.UpdateCollection) (*Collection, error) {
+	collection := &Collection{
+		BaseEntity: old.BaseEntity,
+		UserID:     old.UserID,
+		Name:       strings.TrimSpace(uc.Name),
+		Default:    old.Default,
+	}
+	collection.UpdateTimestamp()
+	if err := collection.Validate(); err != nil {
+		return nil, err
+	}
+	return collection, nil
+}
+
+func (c *Collection) Validate() error {
+	if err := c.BaseEntity.Validate(); err != nil {
+		return err
+	}
+	if c.UserID == uuid.Nil {
+		return ErrCollectionUserIDEmpty
+	}
+	if c.Name == "" {
+		return ErrCollectionNameEmpty
+	}
+	if utf8.RuneCountInString(c.Name) > MaxCollectionNameLength {
+		return ErrCollectionNameTooLong
+	}
+	return nil
+}
+
+func (c *Collection) CanDelete() error {
+	if c.Default {
+		return ErrCollectionDefault
+	}
+	return nil
+}
@@ -8,6 +8,8 @@ var (
 	ErrCreatedAtEmpty          = errors.New("created_at cannot be zero")
 	ErrUpdatedAtEmpty          = errors.New("updated_at cannot be zero")
 	ErrCreatedAtAfterUpdatedAt = errors.New("created_at must be before updated_at")
+	// Returned by repositories when the stored row is no longer at the version an update was made from
+	ErrVersionMismatch = errors.New("modified since it was read")
 
 	// User errors
 	ErrUsernameEmpty = errors.New("username cannot be empty")
@@ -15,23 +17,68 @@ var (
 	ErrEmailInvalid  = errors.New("invalid email format")
 
 	// Post errors
-	ErrUserIDEmpty    = errors.New("user_id cannot be null")
-	ErrContentEmpty   = errors.New("content cannot be empty")
-	ErrContentTooLong = errors.New("content exceeds maximum length")
+	ErrUserIDEmpty           = errors.New("user_id cannot be null")
+	ErrContentEmpty          = errors.New("content cannot be empty")
+	ErrContentTooLong        = errors.New("content exceeds maximum length")
+	ErrPostStatusInvalid     = errors.New("invalid post status")
+	ErrPublishAtEmpty        = errors.New("publish_at cannot be zero for a scheduled post")
+	ErrPublishAtInPast       = errors.New("publish_at must be in the future")
+	ErrPostAlreadyPublished  = errors.New("post is already published")
+	ErrPostVisibilityInvalid = errors.New("visibility must be public, followers or mentioned")
+	ErrRepostNotPublic       = errors.New("only public posts can be reposted")
+	ErrQuoteNotPublic        = errors.New("only public posts can be quoted")
+
+	// Post revision errors
+	ErrRevisionPostIDEmpty   = errors.New("post_id cannot be null")
+	ErrRevisionEditorIDEmpty = errors.New("editor_id cannot be null")
+	ErrRevisionEditedAtEmpty = errors.New("edited_at cannot be zero")
+
+	// Poll errors
+	ErrPollPostIDEmpty      = errors.New("post_id cannot be null")
+	ErrPollChoiceInvalid    = errors.New("choice must be single or multiple")
+	ErrPollClosesAtEmpty    = errors.New("closes_at cannot be zero")
+	ErrPollClosesAtTooEarly = errors.New("closes_at must be after the post is published")
+	ErrPollOptionCount      = errors.New("a poll has 2 to 4 options")
+	ErrPollOptionEmpty      = errors.New("option cannot be empty")
+	ErrPollOptionTooLong    = errors.New("option exceeds maximum length")
+	ErrPollOptionDuplicate  = errors.New("options must differ")
+
+	// Poll vote errors
+	ErrPollVotePollIDEmpty    = errors.New("poll_id cannot be null")
+	ErrPollVoteUserIDEmpty    = errors.New("user_id cannot be null")
+	ErrPollVoteOptionsEmpty   = errors.New("option_ids cannot be empty")
+	ErrPollVoteSingleChoice   = errors.New("a single choice poll takes one option")
+	ErrPollVoteOptionUnknown  = errors.New("option is not part of the poll")
+	ErrPollVoteOptionRepeated = errors.New("option chosen more than once")
+	ErrPollClosed             = errors.New("poll is closed")
 
 	// Like errors
-	ErrLikeUserIDEmpty = errors.New("user_id cannot be null")
-	ErrLikePostIDEmpty = errors.New("post_id cannot be null")
+	ErrLikeUserIDEmpty     = errors.New("user_id cannot be null")
+	ErrLikePostIDEmpty     = errors.New("post_id cannot be null")
+	ErrLikeReactionInvalid = errors.New("reaction must be like, love, haha, wow, sad or angry")
 
 	// Favorite errors
-	ErrFavoriteUserIDEmpty = errors.New("user_id cannot be null")
-	ErrFavoritePostIDEmpty = errors.New("post_id cannot be null")
+	ErrFavoriteUserIDEmpty       = errors.New("user_id cannot be null")
+	ErrFavoritePostIDEmpty       = errors.New("post_id cannot be null")
+	ErrFavoriteCollectionIDEmpty = errors.New("collection_id cannot be null")
+
+	// Collection errors
+	ErrCollectionUserIDEmpty = errors.New("user_id cannot be null")
+	ErrCollectionNameEmpty   = errors.New("name cannot be empty")
+	ErrCollectionNameTooLong = errors.New("name exceeds maximum length")
+	ErrCollectionDefault     = errors.New("the default collection cannot be deleted")
 
 	// Repost errors
 	ErrRepostUserIDEmpty    = errors.New("user_id cannot be null")
 	ErrRepostPostIDEmpty    = errors.New("post_id cannot be null")
 	ErrRepostCommentTooLong = errors.New("comment exceeds maximum length")
 
+	// Pinned post errors
+	ErrPinUserIDEmpty    = errors.New("user_id cannot be null")
+	ErrPinPostIDEmpty    = errors.New("post_id cannot be null")
+	ErrPinLimitReached   = errors.New("too many pinned posts")
+	ErrPinNotOwnOrRepost = errors.New("only own posts and reposts can be pinned")
+
 	// Session errors
 	ErrSessionUserIDEmpty = errors.New("user_id cannot be null")
 	ErrSessionExpired     = errors.New("session expired")
@@ -8,15 +8,17 @@ import (
 
 type Favorite struct {
 	BaseEntity
-	UserID uuid.UUID `json:"userId"`
-	PostID uuid.UUID `json:"postId"`
+	UserID       uuid.UUID `json:"userId"`
+	PostID       uuid.UUID `json:"postId"`
+	CollectionID uuid.UUID `json:"collectionId"`
 }
 
 func NewFavorite(nf dto.NewFavorite) (*Favorite, error) {
 	favorite := &Favorite{
-		BaseEntity: NewBaseEntity(),
-		UserID:     nf.UserID,
-		PostID:     nf.PostID,
+		BaseEntity:   NewBaseEntity(),
+		UserID:       nf.UserID,
+		PostID:       nf.PostID,
+		CollectionID: nf.CollectionID,
 	}
 	if err := favorite.Validate(); err != nil {
 		return nil, err
@@ -34,5 +36,8 @@ func (f *Favorite) Validate() error {
 	if f.PostID == uuid.Nil {
 		return ErrFavoritePostIDEmpty
 	}
+	if f.CollectionID == uuid.Nil {
+		return ErrFavoriteCollectionIDEmpty
+	}
 	return nil
 }
@@ -1,15 +1,34 @@
 package entity
 
 import (
+	"slices"
 	"social-media-go-ddd/internal/domain/dto"
 
 	"github.com/google/uuid"
 )
 
+// Emoji a user reacts to a post with, a like is the reaction ReactionLike
+type Reaction string
+
+const (
+	// 👍, every like made before reactions existed is one
+	ReactionLike  Reaction = "like"
+	ReactionLove  Reaction = "love"  // ❤️
+	ReactionHaha  Reaction = "haha"  // 😂
+	ReactionWow   Reaction = "wow"   // 😮
+	ReactionSad   Reaction = "sad"   // 😢
+	ReactionAngry Reaction = "angry" // 😡
+)
+
+// Every reaction in the order clients show them
+var Reactions = []Reaction{ReactionLike, ReactionLove, ReactionHaha, ReactionWow, ReactionSad, ReactionAngry}
+
+// Reaction of a user to a post, a user has at most one per post
 type Like struct {
 	BaseEntity
-	UserID uuid.UUID `json:"userId"`
-	PostID uuid.UUID `json:"postId"`
+	UserID   uuid.UUID `json:"userId"`
+	PostID   uuid.UUID `json:"postId"`
+	Reaction Reaction  `json:"reaction"`
 }
 
 func NewLike(nl dto.NewLike) (*Like, error) {
@@ -17,6 +36,10 @@ func NewLike(nl dto.NewLike) (*Like, error) {
 		BaseEntity: NewBaseEntity(),
 		UserID:     nl.UserID,
 		PostID:     nl.PostID,
+		Reaction:   Reaction(nl.Reaction),
+	}
+	if like.Reaction == "" {
+		like.Reaction = ReactionLike
 	}
 	if err := like.Validate(); err != nil {
 		return nil, err
@@ -34,5 +57,12 @@ func (l *Like) Validate() error {
 	if l.PostID == uuid.Nil {
 		return ErrLikePostIDEmpty
 	}
+	if !l.Reaction.Valid() {
+		return ErrLikeReactionInvalid
+	}
 	return nil
 }
+
+func (r Reaction) Valid() bool {
+	return slices.Contains(Reactions, r)
+}
@@ -0,0 +1,42 @@
+package entity
+
+import (
+	"social-media-go-ddd/internal/domain/dto"
+
+	"github.com/google/uuid"
+)
+
+// Most posts a user can pin to their profile
+const MaxPinnedPosts = 3
+
+// Post pinned to the top of a user's profile, either their own or one they reposted
+type PinnedPost struct {
+	BaseEntity
+	UserID uuid.UUID `json:"userId"`
+	PostID uuid.UUID `json:"postId"`
+}
+
+func NewPinnedPost(np dto.NewPinnedPost) (*PinnedPost, error) {
+	pin := &PinnedPost{
+		BaseEntity: NewBaseEntity(),
+		UserID:     np.UserID,
+		PostID:     np.PostID,
+	}
+	if err := pin.Validate(); err != nil {
+		return nil, err
+	}
+	return pin, nil
+}
+
+func (p *PinnedPost) Validate() error {
+	if err := p.BaseEntity.Validate(); err != nil {
+		return err
+	}
+	if p.UserID == uuid.Nil {
+		return ErrPinUserIDEmpty
+	}
+	if p.PostID == uuid.Nil {
+		return ErrPinPostIDEmpty
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package entity
+
+import (
+	"slices"
+	"social-media-go-ddd/internal/domain/dto"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// Whether voters pick one or several options of a poll
+type PollChoice string
+
+const (
+	PollChoiceSingle   PollChoice = "single"
+	PollChoiceMultiple PollChoice = "multiple"
+)
+
+const (
+	MinPollOptions      = 2
+	MaxPollOptions      = 4
+	MaxPollOptionLength = 100
+)
+
+type PollOption struct {
+	ID uuid.UUID `json:"id"`
+	// Options are shown by position, starting at 0
+	Position int    `json:"position"`
+	Text     string `json:"text"`
+}
+
+// Poll attached to a post, users vote on it until it closes
+type Poll struct {
+	BaseEntity
+	PostID   uuid.UUID    `json:"postId"`
+	Choice   PollChoice   `json:"choice"`
+	ClosesAt time.Time    `json:"closesAt"`
+	Options  []PollOption `json:"options"`
+}
+
+// Poll of post, it must close after the post is published
+func NewPoll(post *Post, np dto.NewPoll) (*Poll, error) {
+	poll := &Poll{
+		BaseEntity: NewBaseEntity(),
+		PostID:     post.ID,
+		Choice:     PollChoice(np.Choice),
+		ClosesAt:   np.ClosesAt,
+	}
+	if poll.Choice == "" {
+		poll.Choice = PollChoiceSingle
+	}
+	for i, text := range np.Options {
+		poll.Options = append(poll.Options, PollOption{ID: uuid.New(), Position: i, Text: strings.TrimSpace(text)})
+	}
+	if err := poll.Validate(); err != nil {
+		return nil, err
+	}
+	// A scheduled post is published at PublishAt, any other post is at the earliest when created
+	opens := post.CreatedAt
+	if post.PublishAt != nil {
+		opens = *post.PublishAt
+	}
+	if !poll.ClosesAt.After(opens) {
+		return nil, ErrPollClosesAtTooEarly
+	}
+	return poll, nil
+}
+
+func (p *Poll) Validate() error {
+	if err := p.BaseEntity.Validate(); err != nil {
+		return err
+	}
+	if p.PostID == uuid.Nil {
+		return ErrPollPostIDEmpty
+	}
+	switch p.Choice {
+	case PollChoiceSingle, PollChoiceMultiple:
+	default:
+		return ErrPollChoiceInvalid
+	}
+	if p.ClosesAt.IsZero() {
+		return ErrPollClosesAtEmpty
+	}
+	if len(p.Options) < MinPollOptions || len(p.Options) > MaxPollOptions {
+		return ErrPollOptionCount
+	}
+	var texts []string
+	for _, o := range p.Options {
+		if o.Text == "" {
+			return ErrPollOptionEmpty
+		}
+		if utf8.RuneCountInString(o.Text) > MaxPollOptionLength {
+			return ErrPollOptionTooLong
+		}
+		if slices.Contains(texts, o.Text) {
+			return ErrPollOptionDuplicate
+		}
+		texts = append(texts, o.Text)
+	}
+	return nil
+}
+
+// Whether votes are no longer taken at now
+func (p *Poll) IsClosed(now time.Time) bool {
+	return !now.Before(p.ClosesAt)
+}
+
+func (p *Poll) HasOption(id uuid.UUID) bool {
+	return slices.ContainsFunc(p.Options, func(o PollOption) bool { return o.ID == id })
+}
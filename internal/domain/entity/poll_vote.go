@@ -0,0 +1,67 @@
+package entity
+
+import (
+	"slices"
+	"social-media-go-ddd/internal/domain/dto"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Vote of a user in a poll, a user votes once per poll
+type PollVote struct {
+	ID     uuid.UUID `json:"id"`
+	PollID uuid.UUID `json:"pollId"`
+	UserID uuid.UUID `json:"userId"`
+	// Options the user chose, a single one unless the poll is multiple choice
+	OptionIDs []uuid.UUID `json:"optionIds"`
+	CreatedAt time.Time   `json:"createdAt"`
+}
+
+// Vote in poll, it must still be open and the options must be some of its own
+func NewPollVote(poll *Poll, nv dto.NewPollVote) (*PollVote, error) {
+	vote := &PollVote{
+		ID:        uuid.New(),
+		PollID:    poll.ID,
+		UserID:    nv.UserID,
+		OptionIDs: nv.OptionIDs,
+		CreatedAt: time.Now(),
+	}
+	if poll.IsClosed(vote.CreatedAt) {
+		return nil, ErrPollClosed
+	}
+	if err := vote.Validate(); err != nil {
+		return nil, err
+	}
+	if poll.Choice == PollChoiceSingle && len(vote.OptionIDs) > 1 {
+		return nil, ErrPollVoteSingleChoice
+	}
+	for i, id := range vote.OptionIDs {
+		if !poll.HasOption(id) {
+			return nil, ErrPollVoteOptionUnknown
+		}
+		if slices.Contains(vote.OptionIDs[:i], id) {
+			return nil, ErrPollVoteOptionRepeated
+		}
+	}
+	return vote, nil
+}
+
+func (v *PollVote) Validate() error {
+	if v.ID == uuid.Nil {
+		return ErrIDEmpty
+	}
+	if v.PollID == uuid.Nil {
+		return ErrPollVotePollIDEmpty
+	}
+	if v.UserID == uuid.Nil {
+		return ErrPollVoteUserIDEmpty
+	}
+	if len(v.OptionIDs) == 0 {
+		return ErrPollVoteOptionsEmpty
+	}
+	if v.CreatedAt.IsZero() {
+		return ErrCreatedAtEmpty
+	}
+	return nil
+}
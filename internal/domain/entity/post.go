@@ -1,23 +1,120 @@
 package entity
 
 import (
+	"regexp"
+	"slices"
 	"social-media-go-ddd/internal/domain/dto"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 )
 
+type PostStatus string
+
+const (
+	PostStatusPublished PostStatus = "published"
+	// Only visible to its author until the author publishes it
+	PostStatusDraft PostStatus = "draft"
+	// Only visible to its author until the scheduler publishes it at PublishAt
+	PostStatusScheduled PostStatus = "scheduled"
+)
+
+// Who besides its author can see a published post
+type PostVisibility string
+
+const (
+	PostVisibilityPublic PostVisibility = "public"
+	// Only users following the author
+	PostVisibilityFollowers PostVisibility = "followers"
+	// Only users mentioned as @username in the content
+	PostVisibilityMentioned PostVisibility = "mentioned"
+)
+
+// What a viewer other than the author needs to see a published post
+type PostAccess int
+
+const (
+	PostAccessAnyone PostAccess = iota
+	// Follows the author
+	PostAccessFollower
+	// Mentioned in the content
+	PostAccessMentioned
+)
+
+type PostVisibilityRule struct {
+	Visibility PostVisibility
+	Access     PostAccess
+}
+
+// The rule of VisibleTo, the sql backends build the filter of their lists from it
+var PostVisibilityRules = []PostVisibilityRule{
+	{PostVisibilityPublic, PostAccessAnyone},
+	{PostVisibilityFollowers, PostAccessFollower},
+	{PostVisibilityMentioned, PostAccessMentioned},
+}
+
+// A mention is an @ not preceded by a username character, followed by the username
+var mentionRe = regexp.MustCompile(`(?:^|[^A-Za-z0-9_.@-])@([A-Za-z0-9_.-]+)`)
+
+// Relationship of whoever views a post to it, the zero value is an anonymous viewer
+type PostViewer struct {
+	Author bool
+	// Follows the author of the post
+	Follower bool
+	// Mentioned in the content of the post
+	Mentioned bool
+}
+
+func (v PostViewer) Has(a PostAccess) bool {
+	switch a {
+	case PostAccessAnyone:
+		return true
+	case PostAccessFollower:
+		return v.Follower
+	case PostAccessMentioned:
+		return v.Mentioned
+	}
+	return false
+}
+
 type Post struct {
 	BaseEntity
 	UserID  uuid.UUID `json:"userId"`
 	Content string    `json:"content"`
+	// Incremented by every update, an update only applies to the version it was made from
+	Version int        `json:"version"`
+	Status  PostStatus `json:"status"`
+	// When a scheduled post gets published, nil for other posts
+	PublishAt  *time.Time     `json:"publishAt"`
+	Visibility PostVisibility `json:"visibility"`
+	// Post this one quotes, nil when it quotes none or the quoted post was deleted
+	QuotedPostID *uuid.UUID `json:"quotedPostId"`
 }
 
 func NewPost(np dto.NewPost) (*Post, error) {
 	post := &Post{
-		BaseEntity: NewBaseEntity(),
-		UserID:     np.UserID,
-		Content:    strings.TrimSpace(np.Content),
+		BaseEntity:   NewBaseEntity(),
+		UserID:       np.UserID,
+		Content:      strings.TrimSpace(np.Content),
+		Version:      1,
+		Status:       PostStatusPublished,
+		Visibility:   PostVisibility(np.Visibility),
+		QuotedPostID: np.QuotedPostID,
+	}
+	if post.Visibility == "" {
+		post.Visibility = PostVisibilityPublic
+	}
+	if np.Draft {
+		post.Status = PostStatusDraft
+	}
+	if np.PublishAt != nil {
+		if !np.PublishAt.After(post.CreatedAt) {
+			return nil, ErrPublishAtInPast
+		}
+		publishAt := *np.PublishAt
+		post.Status = PostStatusScheduled
+		post.PublishAt = &publishAt
 	}
 	if err := post.Validate(); err != nil {
 		return nil, err
@@ -27,9 +124,14 @@ func NewPost(np dto.NewPost) (*Post, error) {
 
 func NewPostForUpdate(oldPost *Post, up dto.UpdatePost) (*Post, error) {
 	post := &Post{
-		BaseEntity: oldPost.BaseEntity,
-		UserID:     oldPost.UserID,
-		Content:    strings.TrimSpace(up.Content),
+		BaseEntity:   oldPost.BaseEntity,
+		UserID:       oldPost.UserID,
+		Content:      strings.TrimSpace(up.Content),
+		Version:      oldPost.Version + 1,
+		Status:       oldPost.Status,
+		PublishAt:    oldPost.PublishAt,
+		Visibility:   oldPost.Visibility,
+		QuotedPostID: oldPost.QuotedPostID,
 	}
 	post.UpdateTimestamp()
 	if err := post.Validate(); err != nil {
@@ -54,11 +156,93 @@ func (p *Post) Validate() error {
 	if p.CreatedAt.After(p.UpdatedAt) {
 		return ErrCreatedAtAfterUpdatedAt
 	}
+	switch p.Status {
+	case PostStatusPublished, PostStatusDraft:
+	case PostStatusScheduled:
+		if p.PublishAt == nil || p.PublishAt.IsZero() {
+			return ErrPublishAtEmpty
+		}
+	default:
+		return ErrPostStatusInvalid
+	}
+	switch p.Visibility {
+	case PostVisibilityPublic, PostVisibilityFollowers, PostVisibilityMentioned:
+	default:
+		return ErrPostVisibilityInvalid
+	}
 	return nil
 }
 
+func (p *Post) IsPublished() bool {
+	return p.Status == PostStatusPublished
+}
+
+// Whether v may see the post, the author always can and others only once it is published
+func (p *Post) VisibleTo(v PostViewer) bool {
+	if v.Author {
+		return true
+	}
+	if !p.IsPublished() {
+		return false
+	}
+	for _, rule := range PostVisibilityRules {
+		if rule.Visibility == p.Visibility {
+			return v.Has(rule.Access)
+		}
+	}
+	return false
+}
+
+// Whether lists shown to v hold the post, they only hold published posts, for their author as well
+func (p *Post) ListedTo(v PostViewer) bool {
+	return p.IsPublished() && p.VisibleTo(v)
+}
+
+// Reposting shows a post to the followers of the reposter, only posts everyone can see may be reposted
+func (p *Post) CanRepost() error {
+	if p.Visibility != PostVisibilityPublic {
+		return ErrRepostNotPublic
+	}
+	return nil
+}
+
+// Quoting shows a post to the audience of the quote, only posts everyone can see may be quoted
+func (p *Post) CanQuote() error {
+	if p.Visibility != PostVisibilityPublic {
+		return ErrQuoteNotPublic
+	}
+	return nil
+}
+
+// Usernames mentioned in the content, each once in order of appearance
+func (p *Post) Mentions() []string {
+	var usernames []string
+	for _, m := range mentionRe.FindAllStringSubmatch(p.Content, -1) {
+		// A sentence may end right after the mention
+		username := strings.TrimRight(m[1], ".")
+		if username != "" && !slices.Contains(usernames, username) {
+			usernames = append(usernames, username)
+		}
+	}
+	return usernames
+}
+
+// Make a draft or scheduled post visible to everyone, it counts as created and last updated at
+func (p *Post) Publish(at time.Time) error {
+	if p.IsPublished() {
+		return ErrPostAlreadyPublished
+	}
+	p.Status = PostStatusPublished
+	p.PublishAt = nil
+	p.CreatedAt = at
+	p.UpdatedAt = at
+	p.Version++
+	return p.Validate()
+}
+
 func (p *Post) UpdateContent(content string) error {
 	p.Content = strings.TrimSpace(content)
+	p.Version++
 	p.UpdateTimestamp()
 	return p.Validate()
 }
@@ -0,0 +1,47 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Content a post had before one of its edits, the current content stays on the post
+type PostRevision struct {
+	ID       uuid.UUID `json:"id"`
+	PostID   uuid.UUID `json:"postId"`
+	EditorID uuid.UUID `json:"editorId"`
+	Content  string    `json:"content"`
+	EditedAt time.Time `json:"editedAt"`
+}
+
+// Revision keeping the content of old, edited is the post after the edit
+func NewPostRevision(old *Post, edited *Post, editorID uuid.UUID) (*PostRevision, error) {
+	revision := &PostRevision{
+		ID:       uuid.New(),
+		PostID:   old.ID,
+		EditorID: editorID,
+		Content:  old.Content,
+		EditedAt: edited.UpdatedAt,
+	}
+	if err := revision.Validate(); err != nil {
+		return nil, err
+	}
+	return revision, nil
+}
+
+func (r *PostRevision) Validate() error {
+	if r.ID == uuid.Nil {
+		return ErrIDEmpty
+	}
+	if r.PostID == uuid.Nil {
+		return ErrRevisionPostIDEmpty
+	}
+	if r.EditorID == uuid.Nil {
+		return ErrRevisionEditorIDEmpty
+	}
+	if r.EditedAt.IsZero() {
+		return ErrRevisionEditedAtEmpty
+	}
+	return nil
+}
@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+	"social-media-go-ddd/internal/domain/entity"
+)
+
+// Collections are private to their user. UserRepository.Save creates the default collection of a new user
+type CollectionRepository interface {
+	// A second collection of the user with the same name fails as a unique violation
+	Save(ctx context.Context, c *entity.Collection) error
+	// Saves the new name of c
+	Update(ctx context.Context, c *entity.Collection) error
+	// Moves the favorites of the collection into the default collection of the user, which itself is never deleted
+	Delete(ctx context.Context, id string, userID string) error
+	FindByID(ctx context.Context, id string) (*entity.Collection, error)
+	// Default collection first, then oldest first
+	FindByUserID(ctx context.Context, userID string) ([]*entity.Collection, error)
+	FindDefault(ctx context.Context, userID string) (*entity.Collection, error)
+}
@@ -0,0 +1,10 @@
+package repository
+
+import "errors"
+
+var (
+	// Returned by backends without a database where the sql backends would fail on a UNIQUE constraint
+	ErrUniqueViolation = errors.New("unique constraint violation")
+	// Returned by backends without a database where the sql backends would fail on a FOREIGN KEY constraint
+	ErrForeignKeyViolation = errors.New("foreign key constraint violation")
+)
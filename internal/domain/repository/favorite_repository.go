@@ -3,12 +3,20 @@ package repository
 import (
 	"context"
 	"social-media-go-ddd/internal/domain/aggregate"
+	"social-media-go-ddd/internal/domain/dto"
 	"social-media-go-ddd/internal/domain/entity"
 )
 
 type FavoriteRepository interface {
+	// A post favorited by the user before moves into the collection of f. Fails with not found unless the collection
+	// belongs to the user
 	Save(ctx context.Context, f *entity.Favorite) error
 	// Should delete all favorite in db by user id and post id because one person should be able to favorite only one post
 	Delete(ctx context.Context, userID, postID string) error
-	FindByUserID(ctx context.Context, userID string) ([]*aggregate.Post, error)
+	// Unfavorites the post if it is in the collection
+	DeleteFromCollection(ctx context.Context, collectionID, postID string) error
+	// Favorited posts of the user visible to currentUserID, most recently favorited first
+	FindByUserID(ctx context.Context, userID string, currentUserID *string, page dto.CursorPage) ([]*aggregate.Post, *dto.Cursor, error)
+	// Like FindByUserID, restricted to the posts in the collection
+	FindByCollectionID(ctx context.Context, collectionID string, currentUserID *string, page dto.CursorPage) ([]*aggregate.Post, *dto.Cursor, error)
 }
@@ -6,7 +6,10 @@ import (
 )
 
 type LikeRepository interface {
-	Save(ctx context.Context, l *entity.Like) error
+	// A user has one reaction per post, saving another one replaces it. inserted is false when it replaced one
+	Save(ctx context.Context, l *entity.Like) (inserted bool, err error)
+	// Like Save, but a reaction the user already has on the post is kept
+	SaveIfAbsent(ctx context.Context, l *entity.Like) (inserted bool, err error)
 	// Should delete all like in db by user id and post id because one person should be able to like only one post
 	Delete(ctx context.Context, userID, postID string) error
 }
@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+	"social-media-go-ddd/internal/domain/entity"
+)
+
+// Pins are listed first by PostRepository.FindByUserID
+type PinnedPostRepository interface {
+	// Pinning a pinned post again does nothing. A post of another user the user did not repost fails with
+	// entity.ErrPinNotOwnOrRepost, a pin beyond entity.MaxPinnedPosts with entity.ErrPinLimitReached.
+	// The pin of a repost goes away with the repost
+	Save(ctx context.Context, p *entity.PinnedPost) error
+	Delete(ctx context.Context, userID, postID string) error
+}
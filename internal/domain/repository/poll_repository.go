@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+	"social-media-go-ddd/internal/domain/aggregate"
+	"social-media-go-ddd/internal/domain/entity"
+)
+
+// Post repositories load the poll of every post they return with the results as currentUserID may see them
+type PollRepository interface {
+	// Saves the poll with its options
+	Save(ctx context.Context, p *entity.Poll) error
+	// Poll of the post with its results and the choice of currentUserID
+	FindByPostID(ctx context.Context, postID string, currentUserID *string) (*aggregate.Poll, error)
+}
+
+type PollVoteRepository interface {
+	// A user votes once per poll, a second vote fails like any duplicate row
+	Save(ctx context.Context, v *entity.PollVote) error
+}
@@ -3,15 +3,31 @@ package repository
 import (
 	"context"
 	"social-media-go-ddd/internal/domain/aggregate"
+	"social-media-go-ddd/internal/domain/dto"
 	"social-media-go-ddd/internal/domain/entity"
+	"time"
 )
 
+// Lists only hold posts the viewer may see according to entity.Post.VisibleTo, the users mentioned in a post are kept with it
 type PostRepository interface {
 	Save(ctx context.Context, p *entity.Post) error
+	// Any post with the relationship of currentUserID to it in Viewer, the caller decides whether they may see it
 	FindByID(ctx context.Context, id string, currentUserID *string) (*aggregate.Post, error)
-	FindByUserID(ctx context.Context, userID string) ([]*aggregate.Post, error)
+	// Published posts of the user visible to currentUserID, newest first. The first page starts with the pins of the user,
+	// most recently pinned first and flagged Pinned, a pinned repost has the type repost. Pinned posts are left out of the rest
+	FindByUserID(ctx context.Context, userID string, currentUserID *string, page dto.CursorPage) ([]*aggregate.Post, *dto.Cursor, error)
 	Delete(ctx context.Context, id string, userID string) error
-	Update(ctx context.Context, p *entity.Post) error
-	// Get followed users or own posts, reposts sort by created_at desc
+	// Saves the new content of p together with the revision holding its previous content, revision is nil for unpublished posts
+	Update(ctx context.Context, p *entity.Post, revision *entity.PostRevision) error
+	// Saves p after it was published, only applied to the version p was made from
+	Publish(ctx context.Context, p *entity.Post) error
+	// Publishes at most limit scheduled posts whose publish_at is not after now and returns them.
+	// Concurrent callers, eg several api instances, never publish the same post
+	PublishDue(ctx context.Context, now time.Time, limit int) ([]*entity.Post, error)
+	// Drafts and scheduled posts of the user, newest first
+	FindDrafts(ctx context.Context, userID string, page dto.CursorPage) ([]*entity.Post, *dto.Cursor, error)
+	// Revisions of the post, most recently edited first
+	FindRevisions(ctx context.Context, postID string) ([]*entity.PostRevision, error)
+	// Get followed users or own published posts visible to the user, reposts sort by created_at desc
 	FindFeed(ctx context.Context, userID string, limit, offset int) ([]*aggregate.Post, int, error)
 }
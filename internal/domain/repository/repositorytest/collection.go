@@ -0,0 +1,249 @@
+package repositorytest
+
+import (
+	"context"
+	"fmt"
+	"social-media-go-ddd/internal/domain/aggregate"
+	"social-media-go-ddd/internal/domain/dto"
+	"social-media-go-ddd/internal/domain/entity"
+	"testing"
+)
+
+func RunCollectionRepositorySuite(t *testing.T, factory Factory) {
+	ctx := context.Background()
+
+	t.Run("DefaultCreatedWithUser", func(t *testing.T) {
+		r := factory(t)
+		alice := r.createUser(t, "alice")
+
+		collection := r.defaultCollection(t, alice)
+		if !collection.Default || collection.Name != entity.DefaultCollectionName || collection.UserID != alice.ID {
+			t.Fatalf("got %+v, want the default collection of alice", collection)
+		}
+		collections, err := r.Collection.FindByUserID(ctx, alice.ID.String())
+		if err != nil || len(collections) != 1 || collections[0].ID != collection.ID {
+			t.Fatalf("got %d collections (err %v), want only the default one", len(collections), err)
+		}
+	})
+
+	t.Run("SaveAndFindByUserID", func(t *testing.T) {
+		r := factory(t)
+		alice := r.createUser(t, "alice")
+		bob := r.createUser(t, "bob")
+		r.tick()
+		recipes := r.createCollection(t, alice, "recipes")
+		r.tick()
+		travel := r.createCollection(t, alice, "travel")
+		r.createCollection(t, bob, "recipes")
+
+		found, err := r.Collection.FindByID(ctx, recipes.ID.String())
+		if err != nil {
+			t.Fatalf("find collection: %v", err)
+		}
+		if found.Name != "recipes" || found.UserID != alice.ID || found.Default {
+			t.Fatalf("got %+v, want %+v", found, recipes)
+		}
+
+		collections, err := r.Collection.FindByUserID(ctx, alice.ID.String())
+		if err != nil {
+			t.Fatalf("find collections: %v", err)
+		}
+		if len(collections) != 3 || !collections[0].Default || collections[1].ID != recipes.ID || collections[2].ID != travel.ID {
+			t.Fatalf("got %d collections, want the default one, recipes and travel", len(collections))
+		}
+
+		duplicate, err := entity.NewCollection(dto.NewCollection{UserID: alice.ID, Name: "travel"})
+		if err != nil {
+			t.Fatalf("new collection: %v", err)
+		}
+		if err := r.Collection.Save(ctx, duplicate); err == nil {
+			t.Fatal("expected error when saving a second collection of the same name")
+		}
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		r := factory(t)
+		alice := r.createUser(t, "alice")
+		collection := r.createCollection(t, alice, "recipes")
+
+		renamed, err := entity.NewCollectionForUpdate(collection, dto.UpdateCollection{Name: "cooking"})
+		if err != nil {
+			t.Fatalf("new collection for update: %v", err)
+		}
+		if err := r.Collection.Update(ctx, renamed); err != nil {
+			t.Fatalf("update collection: %v", err)
+		}
+		found, err := r.Collection.FindByID(ctx, collection.ID.String())
+		if err != nil || found.Name != "cooking" {
+			t.Fatalf("got %+v (err %v), want the collection renamed", found, err)
+		}
+	})
+
+	t.Run("FindByCollectionID", func(t *testing.T) {
+		r := factory(t)
+		alice := r.createUser(t, "alice")
+		bob := r.createUser(t, "bob")
+		recipes := r.createCollection(t, alice, "recipes")
+		soup := r.createPost(t, bob, "soup")
+		news := r.createPost(t, bob, "news")
+		r.favoriteIn(t, alice, soup, recipes)
+		r.favorite(t, alice, news)
+
+		for _, c := range []struct {
+			collection *entity.Collection
+			want       *entity.Post
+		}{{recipes, soup}, {r.defaultCollection(t, alice), news}} {
+			posts, _, err := r.Favorite.FindByCollectionID(ctx, c.collection.ID.String(), ptr(alice.ID.String()), allRows)
+			if err != nil {
+				t.Fatalf("find posts of %s: %v", c.collection.Name, err)
+			}
+			if len(posts) != 1 || posts[0].ID != c.want.ID || !posts[0].Favorited || posts[0].FavoriteCount != 1 {
+				t.Fatalf("%s holds %d posts, want %q favorited", c.collection.Name, len(posts), c.want.Content)
+			}
+		}
+		// The favorites of the user span every collection
+		posts, _, err := r.Favorite.FindByUserID(ctx, alice.ID.String(), ptr(alice.ID.String()), allRows)
+		if err != nil || len(posts) != 2 {
+			t.Fatalf("got %d favorites (err %v), want 2", len(posts), err)
+		}
+	})
+
+	t.Run("FindByCollectionIDPages", func(t *testing.T) {
+		r := factory(t)
+		alice := r.createUser(t, "alice")
+		recipes := r.createCollection(t, alice, "recipes")
+		for i := range 5 {
+			r.favoriteIn(t, alice, r.createPost(t, alice, fmt.Sprintf("post %d", i)), recipes)
+		}
+		r.favorite(t, alice, r.createPost(t, alice, "elsewhere"))
+
+		assertCursorPages(t, 2, 5, func(page dto.CursorPage) ([]*aggregate.Post, *dto.Cursor, error) {
+			return r.Favorite.FindByCollectionID(ctx, recipes.ID.String(), nil, page)
+		})
+	})
+
+	t.Run("MoveBetweenCollections", func(t *testing.T) {
+		r := factory(t)
+		alice := r.createUser(t, "alice")
+		recipes := r.createCollection(t, alice, "recipes")
+		post := r.createPost(t, alice, "soup")
+		r.favorite(t, alice, post)
+		r.favoriteIn(t, alice, post, recipes)
+
+		for collection, want := range map[*entity.Collection]int{recipes: 1, r.defaultCollection(t, alice): 0} {
+			posts, _, err := r.Favorite.FindByCollectionID(ctx, collection.ID.String(), nil, allRows)
+			if err != nil || len(posts) != want {
+				t.Fatalf("%s holds %d posts (err %v), want %d", collection.Name, len(posts), err, want)
+			}
+		}
+		found, err := r.Post.FindByID(ctx, post.ID.String(), ptr(alice.ID.String()))
+		if err != nil || !found.Favorited || found.FavoriteCount != 1 {
+			t.Fatalf("got favorited=%v count=%d (err %v), want a single favorite", found.Favorited, found.FavoriteCount, err)
+		}
+	})
+
+	t.Run("SaveIntoCollectionOfAnotherUser", func(t *testing.T) {
+		r := factory(t)
+		alice := r.createUser(t, "alice")
+		bob := r.createUser(t, "bob")
+		post := r.createPost(t, alice, "soup")
+
+		favorite, err := entity.NewFavorite(dto.NewFavorite{UserID: alice.ID, PostID: post.ID, CollectionID: r.defaultCollection(t, bob).ID})
+		if err != nil {
+			t.Fatalf("new favorite: %v", err)
+		}
+		if err := r.Favorite.Save(ctx, favorite); err == nil {
+			t.Fatal("expected error when favoriting into the collection of another user")
+		}
+	})
+
+	t.Run("DeleteFromCollection", func(t *testing.T) {
+		r := factory(t)
+		alice := r.createUser(t, "alice")
+		recipes := r.createCollection(t, alice, "recipes")
+		post := r.createPost(t, alice, "soup")
+		r.favoriteIn(t, alice, post, recipes)
+
+		// Removing the post from a collection it is not in leaves it favorited
+		if err := r.Favorite.DeleteFromCollection(ctx, r.defaultCollection(t, alice).ID.String(), post.ID.String()); err != nil {
+			t.Fatalf("delete from default collection: %v", err)
+		}
+		if found, err := r.Post.FindByID(ctx, post.ID.String(), ptr(alice.ID.String())); err != nil || !found.Favorited {
+			t.Fatalf("got favorited=%v (err %v), want the post still favorited", found.Favorited, err)
+		}
+
+		if err := r.Favorite.DeleteFromCollection(ctx, recipes.ID.String(), post.ID.String()); err != nil {
+			t.Fatalf("delete from collection: %v", err)
+		}
+		if found, err := r.Post.FindByID(ctx, post.ID.String(), ptr(alice.ID.String())); err != nil || found.Favorited || found.FavoriteCount != 0 {
+			t.Fatalf("got favorited=%v count=%d (err %v), want the post no longer favorited", found.Favorited, found.FavoriteCount, err)
+		}
+	})
+
+	t.Run("DeleteMovesToDefault", func(t *testing.T) {
+		r := factory(t)
+		alice := r.createUser(t, "alice")
+		recipes := r.createCollection(t, alice, "recipes")
+		post := r.createPost(t, alice, "soup")
+		r.favoriteIn(t, alice, post, recipes)
+
+		if err := r.Collection.Delete(ctx, recipes.ID.String(), alice.ID.String()); err != nil {
+			t.Fatalf("delete collection: %v", err)
+		}
+		if _, err := r.Collection.FindByID(ctx, recipes.ID.String()); err == nil {
+			t.Fatal("expected the collection to be deleted")
+		}
+		posts, _, err := r.Favorite.FindByCollectionID(ctx, r.defaultCollection(t, alice).ID.String(), ptr(alice.ID.String()), allRows)
+		if err != nil || len(posts) != 1 || posts[0].ID != post.ID || !posts[0].Favorited {
+			t.Fatalf("default collection holds %d posts (err %v), want the favorite of the deleted collection", len(posts), err)
+		}
+	})
+
+	t.Run("DeleteKeepsDefault", func(t *testing.T) {
+		r := factory(t)
+		alice := r.createUser(t, "alice")
+		bob := r.createUser(t, "bob")
+		collection := r.defaultCollection(t, alice)
+		r.favorite(t, alice, r.createPost(t, alice, "soup"))
+		recipes := r.createCollection(t, alice, "recipes")
+
+		if err := r.Collection.Delete(ctx, collection.ID.String(), alice.ID.String()); err != nil {
+			t.Fatalf("delete default collection: %v", err)
+		}
+		// Only the owner deletes a collection
+		if err := r.Collection.Delete(ctx, recipes.ID.String(), bob.ID.String()); err != nil {
+			t.Fatalf("delete collection of another user: %v", err)
+		}
+		collections, err := r.Collection.FindByUserID(ctx, alice.ID.String())
+		if err != nil || len(collections) != 2 {
+			t.Fatalf("got %d collections (err %v), want both kept", len(collections), err)
+		}
+		posts, _, err := r.Favorite.FindByCollectionID(ctx, collection.ID.String(), nil, allRows)
+		if err != nil || len(posts) != 1 {
+			t.Fatalf("default collection holds %d posts (err %v), want 1", len(posts), err)
+		}
+	})
+}
+
+func (r Repositories) createCollection(t testing.TB, user *entity.User, name string) *entity.Collection {
+	t.Helper()
+
+	collection, err := entity.NewCollection(dto.NewCollection{UserID: user.ID, Name: name})
+	if err != nil {
+		t.Fatalf("new collection: %v", err)
+	}
+	if err := r.Collection.Save(context.Background(), collection); err != nil {
+		t.Fatalf("save collection: %v", err)
+	}
+	return collection
+}
+
+func (r Repositories) defaultCollection(t testing.TB, user *entity.User) *entity.Collection {
+	t.Helper()
+
+	collection, err := r.Collection.FindDefault(context.Background(), user.ID.String())
+	if err != nil {
+		t.Fatalf("find default collection of %s: %v", user.Username, err)
+	}
+	return collection
+}
@@ -0,0 +1,113 @@
+package repositorytest
+
+import (
+	"context"
+	"fmt"
+	"social-media-go-ddd/internal/domain/aggregate"
+	"social-media-go-ddd/internal/domain/dto"
+	"social-media-go-ddd/internal/domain/entity"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func RunFavoriteRepositorySuite(t *testing.T, factory Factory) {
+	ctx := context.Background()
+
+	t.Run("SaveIsIdempotent", func(t *testing.T) {
+		r := factory(t)
+		alice := r.createUser(t, "alice")
+		post := r.createPost(t, alice, "hello")
+		r.favorite(t, alice, post)
+		r.favorite(t, alice, post)
+
+		found, err := r.Post.FindByID(ctx, post.ID.String(), ptr(alice.ID.String()))
+		if err != nil {
+			t.Fatalf("find post: %v", err)
+		}
+		if !found.Favorited || found.FavoriteCount != 1 {
+			t.Fatalf("got favorited=%v count=%d, want a single favorite", found.Favorited, found.FavoriteCount)
+		}
+	})
+
+	t.Run("SaveUnknownPost", func(t *testing.T) {
+		r := factory(t)
+		alice := r.createUser(t, "alice")
+
+		favorite, err := entity.NewFavorite(dto.NewFavorite{UserID: alice.ID, PostID: uuid.New(), CollectionID: r.defaultCollection(t, alice).ID})
+		if err != nil {
+			t.Fatalf("new favorite: %v", err)
+		}
+		if err := r.Favorite.Save(ctx, favorite); err == nil {
+			t.Fatal("expected error when favoriting an unknown post")
+		}
+	})
+
+	t.Run("FindByUserID", func(t *testing.T) {
+		r := factory(t)
+		alice := r.createUser(t, "alice")
+		bob := r.createUser(t, "bob")
+		carol := r.createUser(t, "carol")
+		bobPost := r.createPost(t, bob, "bob post")
+		carolPost := r.createPost(t, carol, "carol post")
+		r.createPost(t, carol, "not favorited")
+
+		r.favorite(t, alice, bobPost)
+		r.tick()
+		r.favorite(t, alice, carolPost)
+		r.favorite(t, bob, carolPost)
+
+		posts, _, err := r.Favorite.FindByUserID(ctx, alice.ID.String(), ptr(alice.ID.String()), allRows)
+		if err != nil {
+			t.Fatalf("find favorites: %v", err)
+		}
+		if len(posts) != 2 {
+			t.Fatalf("got %d favorites, want 2", len(posts))
+		}
+		// Newest favorite first, and the user is the author of the post rather than whoever favorited it
+		if posts[0].ID != carolPost.ID || posts[0].User.ID != carol.ID {
+			t.Fatalf("got post %s by %s first, want carol's post", posts[0].ID, posts[0].User.Username)
+		}
+		if posts[1].ID != bobPost.ID || posts[1].User.ID != bob.ID {
+			t.Fatalf("got post %s by %s second, want bob's post", posts[1].ID, posts[1].User.Username)
+		}
+		if !posts[0].Favorited || posts[0].FavoriteCount != 2 {
+			t.Fatalf("got favorited=%v count=%d, want true and 2", posts[0].Favorited, posts[0].FavoriteCount)
+		}
+	})
+
+	t.Run("FindByUserIDPages", func(t *testing.T) {
+		r := factory(t)
+		alice := r.createUser(t, "alice")
+		bob := r.createUser(t, "bob")
+		for i := range 5 {
+			r.favorite(t, alice, r.createPost(t, bob, fmt.Sprintf("post %d", i)))
+		}
+
+		assertCursorPages(t, 2, 5, func(page dto.CursorPage) ([]*aggregate.Post, *dto.Cursor, error) {
+			return r.Favorite.FindByUserID(ctx, alice.ID.String(), nil, page)
+		})
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		r := factory(t)
+		alice := r.createUser(t, "alice")
+		post := r.createPost(t, alice, "hello")
+		r.favorite(t, alice, post)
+
+		if err := r.Favorite.Delete(ctx, alice.ID.String(), post.ID.String()); err != nil {
+			t.Fatalf("delete favorite: %v", err)
+		}
+		if err := r.Favorite.Delete(ctx, alice.ID.String(), post.ID.String()); err != nil {
+			t.Fatalf("delete favorite again: %v", err)
+		}
+
+		posts, _, err := r.Favorite.FindByUserID(ctx, alice.ID.String(), ptr(alice.ID.String()), allRows)
+		if err != nil {
+			t.Fatalf("find favorites: %v", err)
+		}
+		if len(posts) != 0 {
+			t.Fatalf("got %d favorites, want none", len(posts))
+		}
+	})
+}
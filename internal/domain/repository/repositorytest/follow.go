@@ -0,0 +1,93 @@
+package repositorytest
+
+import (
+	"context"
+	"errors"
+	"social-media-go-ddd/internal/domain/dto"
+	"social-media-go-ddd/internal/domain/entity"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func RunFollowRepositorySuite(t *testing.T, factory Factory) {
+	ctx := context.Background()
+
+	followerCount := func(t *testing.T, r Repositories, user *entity.User, viewer *entity.User) (bool, int) {
+		t.Helper()
+
+		found, err := r.User.FindByID(ctx, user.ID.String(), ptr(viewer.ID.String()))
+		if err != nil {
+			t.Fatalf("find user: %v", err)
+		}
+		return found.Followed, found.FollowerCount
+	}
+
+	t.Run("SaveIsIdempotent", func(t *testing.T) {
+		r := factory(t)
+		alice := r.createUser(t, "alice")
+		bob := r.createUser(t, "bob")
+		r.follow(t, alice, bob)
+		r.follow(t, alice, bob)
+
+		if followed, count := followerCount(t, r, bob, alice); !followed || count != 1 {
+			t.Fatalf("got followed=%v count=%d, want a single follow", followed, count)
+		}
+	})
+
+	t.Run("SaveUnknownUser", func(t *testing.T) {
+		r := factory(t)
+		alice := r.createUser(t, "alice")
+
+		follow, err := entity.NewFollow(dto.NewFollow{FollowerID: alice.ID, FolloweeID: uuid.New()})
+		if err != nil {
+			t.Fatalf("new follow: %v", err)
+		}
+		if err := r.Follow.Save(ctx, follow); err == nil {
+			t.Fatal("expected error when following an unknown user")
+		}
+	})
+
+	t.Run("SelfFollow", func(t *testing.T) {
+		r := factory(t)
+		alice := r.createUser(t, "alice")
+
+		// Self follows never reach the repository
+		_, err := entity.NewFollow(dto.NewFollow{FollowerID: alice.ID, FolloweeID: alice.ID})
+		if !errors.Is(err, entity.ErrFollowSelfFollow) {
+			t.Fatalf("got %v, want %v", err, entity.ErrFollowSelfFollow)
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		r := factory(t)
+		alice := r.createUser(t, "alice")
+		bob := r.createUser(t, "bob")
+		carol := r.createUser(t, "carol")
+		r.follow(t, alice, bob)
+		r.follow(t, carol, bob)
+
+		if err := r.Follow.Delete(ctx, alice.ID.String(), bob.ID.String()); err != nil {
+			t.Fatalf("delete follow: %v", err)
+		}
+		if err := r.Follow.Delete(ctx, alice.ID.String(), bob.ID.String()); err != nil {
+			t.Fatalf("delete follow again: %v", err)
+		}
+
+		if followed, count := followerCount(t, r, bob, alice); followed || count != 1 {
+			t.Fatalf("got followed=%v count=%d, want only carol's follow", followed, count)
+		}
+	})
+
+	t.Run("Direction", func(t *testing.T) {
+		r := factory(t)
+		alice := r.createUser(t, "alice")
+		bob := r.createUser(t, "bob")
+		r.follow(t, alice, bob)
+
+		// Following is one way
+		if followed, count := followerCount(t, r, alice, bob); followed || count != 0 {
+			t.Fatalf("got followed=%v count=%d, bob does not follow alice", followed, count)
+		}
+	})
+}
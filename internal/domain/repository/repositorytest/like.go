@@ -0,0 +1,182 @@
+package repositorytest
+
+import (
+	"context"
+	"social-media-go-ddd/internal/domain/dto"
+	"social-media-go-ddd/internal/domain/entity"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func RunLikeRepositorySuite(t *testing.T, factory Factory) {
+	ctx := context.Background()
+
+	t.Run("SaveIsIdempotent", func(t *testing.T) {
+		r := factory(t)
+		alice := r.createUser(t, "alice")
+		post := r.createPost(t, alice, "hello")
+		r.like(t, alice, post)
+		r.like(t, alice, post)
+
+		found, err := r.Post.FindByID(ctx, post.ID.String(), ptr(alice.ID.String()))
+		if err != nil {
+			t.Fatalf("find post: %v", err)
+		}
+		if !found.Liked || found.LikeCount != 1 {
+			t.Fatalf("got liked=%v count=%d, want a single like", found.Liked, found.LikeCount)
+		}
+	})
+
+	t.Run("SaveReplacesReaction", func(t *testing.T) {
+		r := factory(t)
+		alice := r.createUser(t, "alice")
+		bob := r.createUser(t, "bob")
+		post := r.createPost(t, alice, "hello")
+		r.like(t, alice, post)
+		r.react(t, bob, post, entity.ReactionLove)
+		r.react(t, alice, post, entity.ReactionHaha)
+
+		found, err := r.Post.FindByID(ctx, post.ID.String(), ptr(alice.ID.String()))
+		if err != nil {
+			t.Fatalf("find post: %v", err)
+		}
+		if !found.Liked || found.LikeCount != 2 {
+			t.Fatalf("got liked=%v count=%d, want two reactions", found.Liked, found.LikeCount)
+		}
+		if found.Reaction == nil || *found.Reaction != entity.ReactionHaha {
+			t.Fatalf("got reaction %v, want %s", found.Reaction, entity.ReactionHaha)
+		}
+		want := map[entity.Reaction]int{entity.ReactionLove: 1, entity.ReactionHaha: 1}
+		for _, reaction := range entity.Reactions {
+			if found.Reactions[reaction] != want[reaction] {
+				t.Fatalf("got reactions %v, want %v", found.Reactions, want)
+			}
+		}
+	})
+
+	t.Run("SaveReportsInsert", func(t *testing.T) {
+		r := factory(t)
+		alice := r.createUser(t, "alice")
+		post := r.createPost(t, alice, "hello")
+
+		// Only the first reaction is inserted, the others replace it, the same one included
+		for i, reaction := range []entity.Reaction{entity.ReactionLike, entity.ReactionLike, entity.ReactionLove} {
+			like, err := entity.NewLike(dto.NewLike{UserID: alice.ID, PostID: post.ID, Reaction: string(reaction)})
+			if err != nil {
+				t.Fatalf("new like: %v", err)
+			}
+			inserted, err := r.Like.Save(ctx, like)
+			if err != nil {
+				t.Fatalf("save %s: %v", reaction, err)
+			}
+			if inserted != (i == 0) {
+				t.Fatalf("save %s: got inserted=%v, want %v", reaction, inserted, i == 0)
+			}
+		}
+	})
+
+	t.Run("SaveIfAbsentKeepsReaction", func(t *testing.T) {
+		r := factory(t)
+		alice := r.createUser(t, "alice")
+		bob := r.createUser(t, "bob")
+		post := r.createPost(t, alice, "hello")
+		r.react(t, alice, post, entity.ReactionLove)
+		for _, user := range []*entity.User{alice, bob} {
+			like, err := entity.NewLike(dto.NewLike{UserID: user.ID, PostID: post.ID, Reaction: string(entity.ReactionLike)})
+			if err != nil {
+				t.Fatalf("new like: %v", err)
+			}
+			inserted, err := r.Like.SaveIfAbsent(ctx, like)
+			if err != nil {
+				t.Fatalf("save like: %v", err)
+			}
+			if want := user == bob; inserted != want {
+				t.Fatalf("%s: got inserted=%v, want %v", user.Username, inserted, want)
+			}
+		}
+
+		found, err := r.Post.FindByID(ctx, post.ID.String(), ptr(alice.ID.String()))
+		if err != nil {
+			t.Fatalf("find post: %v", err)
+		}
+		if found.Reaction == nil || *found.Reaction != entity.ReactionLove {
+			t.Fatalf("got reaction %v, want alice's love kept", found.Reaction)
+		}
+		if found.Reactions[entity.ReactionLove] != 1 || found.Reactions[entity.ReactionLike] != 1 {
+			t.Fatalf("got reactions %v, want alice's love and bob's like", found.Reactions)
+		}
+
+		like, err := entity.NewLike(dto.NewLike{UserID: alice.ID, PostID: uuid.New(), Reaction: string(entity.ReactionLike)})
+		if err != nil {
+			t.Fatalf("new like: %v", err)
+		}
+		if _, err := r.Like.SaveIfAbsent(ctx, like); err == nil {
+			t.Fatal("expected error when liking an unknown post")
+		}
+	})
+
+	t.Run("ReactionsOfListedPosts", func(t *testing.T) {
+		r := factory(t)
+		alice := r.createUser(t, "alice")
+		bob := r.createUser(t, "bob")
+		post := r.createPost(t, alice, "hello")
+		other := r.createPost(t, alice, "world")
+		r.react(t, bob, post, entity.ReactionWow)
+
+		posts, _, err := r.Post.FindByUserID(ctx, alice.ID.String(), ptr(bob.ID.String()), allRows)
+		if err != nil {
+			t.Fatalf("find posts: %v", err)
+		}
+		for _, p := range posts {
+			switch p.ID {
+			case post.ID:
+				if p.Reactions[entity.ReactionWow] != 1 || p.Reaction == nil || *p.Reaction != entity.ReactionWow {
+					t.Fatalf("got reactions %v reaction %v, want bob's wow", p.Reactions, p.Reaction)
+				}
+			case other.ID:
+				if p.Reaction != nil || len(p.Reactions) != len(entity.Reactions) || p.Reactions[entity.ReactionWow] != 0 {
+					t.Fatalf("got reactions %v reaction %v, want every reaction at zero", p.Reactions, p.Reaction)
+				}
+			}
+		}
+	})
+
+	t.Run("SaveUnknownPost", func(t *testing.T) {
+		r := factory(t)
+		alice := r.createUser(t, "alice")
+
+		like, err := entity.NewLike(dto.NewLike{UserID: alice.ID, PostID: uuid.New()})
+		if err != nil {
+			t.Fatalf("new like: %v", err)
+		}
+		if _, err := r.Like.Save(ctx, like); err == nil {
+			t.Fatal("expected error when liking an unknown post")
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		r := factory(t)
+		alice := r.createUser(t, "alice")
+		bob := r.createUser(t, "bob")
+		post := r.createPost(t, alice, "hello")
+		r.like(t, alice, post)
+		r.like(t, bob, post)
+
+		if err := r.Like.Delete(ctx, alice.ID.String(), post.ID.String()); err != nil {
+			t.Fatalf("delete like: %v", err)
+		}
+		// Deleting a like that does not exist is not an error
+		if err := r.Like.Delete(ctx, alice.ID.String(), post.ID.String()); err != nil {
+			t.Fatalf("delete like again: %v", err)
+		}
+
+		found, err := r.Post.FindByID(ctx, post.ID.String(), ptr(alice.ID.String()))
+		if err != nil {
+			t.Fatalf("find post: %v", err)
+		}
+		if found.Liked || found.LikeCount != 1 {
+			t.Fatalf("got liked=%v count=%d, want only bob's like", found.Liked, found.LikeCount)
+		}
+	})
+}
@@ -0,0 +1,63 @@
+package repositorytest
+
+import (
+	"social-media-go-ddd/internal/domain/aggregate"
+	"social-media-go-ddd/internal/domain/dto"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// Page large enough to hold every row the suites create
+var allRows = dto.CursorPage{Limit: 100}
+
+// Reposts of one post share its id, tell them apart by the repost
+func rowID(p *aggregate.Post) uuid.UUID {
+	if p.Repost != nil {
+		return p.Repost.ID
+	}
+	return p.ID
+}
+
+// Walks a newest first list by pages of size and fails unless the pages hold the want rows of a single page of every row,
+// in the same order. Rows written within the timestamp resolution share created_at, such that the id breaks the tie
+func assertCursorPages(t *testing.T, size, want int, find func(page dto.CursorPage) ([]*aggregate.Post, *dto.Cursor, error)) {
+	t.Helper()
+
+	all, next, err := find(allRows)
+	if err != nil {
+		t.Fatalf("find every row: %v", err)
+	}
+	if len(all) != want || next != nil {
+		t.Fatalf("got %d rows and next cursor %v, want %d rows and no cursor", len(all), next, want)
+	}
+
+	var walked []*aggregate.Post
+	page := dto.CursorPage{Limit: size}
+	for i := 0; ; i++ {
+		rows, next, err := find(page)
+		if err != nil {
+			t.Fatalf("find page %d: %v", i, err)
+		}
+		if len(rows) > size || (next != nil && len(rows) != size) {
+			t.Fatalf("page %d: got %d rows with next cursor %v, want %d", i, len(rows), next, size)
+		}
+		walked = append(walked, rows...)
+		if next == nil {
+			break
+		}
+		if len(walked) > want {
+			t.Fatalf("walked %d rows, want %d", len(walked), want)
+		}
+		page.After = next
+	}
+
+	if len(walked) != want {
+		t.Fatalf("pages hold %d rows, want %d", len(walked), want)
+	}
+	for i := range all {
+		if rowID(walked[i]) != rowID(all[i]) {
+			t.Fatalf("position %d: got %s, want %s", i, rowID(walked[i]), rowID(all[i]))
+		}
+	}
+}
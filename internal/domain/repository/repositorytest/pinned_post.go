@@ -0,0 +1,218 @@
+package repositorytest
+
+import (
+	"context"
+	"errors"
+	"social-media-go-ddd/internal/domain/aggregate"
+	"social-media-go-ddd/internal/domain/dto"
+	"social-media-go-ddd/internal/domain/entity"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func RunPinnedPostRepositorySuite(t *testing.T, factory Factory) {
+	ctx := context.Background()
+
+	profile := func(t *testing.T, r Repositories, owner, viewer *entity.User, page dto.CursorPage) ([]*aggregate.Post, *dto.Cursor) {
+		t.Helper()
+		posts, next, err := r.Post.FindByUserID(ctx, owner.ID.String(), ptr(viewer.ID.String()), page)
+		if err != nil {
+			t.Fatalf("find posts of %s: %v", owner.Username, err)
+		}
+		return posts, next
+	}
+
+	t.Run("PinnedFirst", func(t *testing.T) {
+		r := factory(t)
+		alice := r.createUser(t, "alice")
+		bob := r.createUser(t, "bob")
+		first := r.createPost(t, alice, "first")
+		r.tick()
+		second := r.createPost(t, alice, "second")
+		r.tick()
+		third := r.createPost(t, alice, "third")
+		r.tick()
+		r.pin(t, alice, first)
+		r.tick()
+		r.pin(t, alice, second)
+
+		posts, _ := profile(t, r, alice, bob, allRows)
+		want := []struct {
+			id     uuid.UUID
+			pinned bool
+		}{{second.ID, true}, {first.ID, true}, {third.ID, false}}
+		if len(posts) != len(want) {
+			t.Fatalf("got %d posts, want %d", len(posts), len(want))
+		}
+		for i, w := range want {
+			if posts[i].ID != w.id || posts[i].Pinned != w.pinned || posts[i].Type != aggregate.PostTypeText {
+				t.Fatalf("position %d: got %s pinned=%v type=%s, want %s pinned=%v", i, posts[i].ID, posts[i].Pinned, posts[i].Type, w.id, w.pinned)
+			}
+		}
+	})
+
+	t.Run("PinTwice", func(t *testing.T) {
+		r := factory(t)
+		alice := r.createUser(t, "alice")
+		post := r.createPost(t, alice, "hello")
+		r.pin(t, alice, post)
+		r.pin(t, alice, post)
+
+		posts, _ := profile(t, r, alice, alice, allRows)
+		if len(posts) != 1 || !posts[0].Pinned {
+			t.Fatalf("got %d posts, want the pinned post once", len(posts))
+		}
+	})
+
+	t.Run("PinRepost", func(t *testing.T) {
+		r := factory(t)
+		alice := r.createUser(t, "alice")
+		bob := r.createUser(t, "bob")
+		post := r.createPost(t, bob, "worth sharing")
+
+		pin, err := entity.NewPinnedPost(dto.NewPinnedPost{UserID: alice.ID, PostID: post.ID})
+		if err != nil {
+			t.Fatalf("new pin: %v", err)
+		}
+		if err := r.Pin.Save(ctx, pin); !errors.Is(err, entity.ErrPinNotOwnOrRepost) {
+			t.Fatalf("pinning another user's post without reposting it: got %v, want %v", err, entity.ErrPinNotOwnOrRepost)
+		}
+
+		repost := r.repost(t, alice, post, "so true")
+		r.pin(t, alice, post)
+		posts, _ := profile(t, r, alice, bob, allRows)
+		if len(posts) != 1 {
+			t.Fatalf("got %d posts of alice, want the pinned repost", len(posts))
+		}
+		p := posts[0]
+		if !p.Pinned || p.Type != aggregate.PostTypeRepost || p.ID != post.ID || p.Repost == nil || p.Repost.ID != repost.ID || p.RepostUser == nil || p.RepostUser.ID != alice.ID || p.User.ID != bob.ID {
+			t.Fatalf("got %+v, want bob's post reposted and pinned by alice", p)
+		}
+
+		// Unreposting removes the pin
+		if err := r.Repost.Delete(ctx, alice.ID.String(), post.ID.String()); err != nil {
+			t.Fatalf("delete repost: %v", err)
+		}
+		if posts, _ := profile(t, r, alice, bob, allRows); len(posts) != 0 {
+			t.Fatalf("got %d posts of alice after unreposting, want none", len(posts))
+		}
+	})
+
+	t.Run("Limit", func(t *testing.T) {
+		r := factory(t)
+		alice := r.createUser(t, "alice")
+		for range entity.MaxPinnedPosts {
+			r.pin(t, alice, r.createPost(t, alice, "pinned"))
+		}
+
+		pin, err := entity.NewPinnedPost(dto.NewPinnedPost{UserID: alice.ID, PostID: r.createPost(t, alice, "one too many").ID})
+		if err != nil {
+			t.Fatalf("new pin: %v", err)
+		}
+		if err := r.Pin.Save(ctx, pin); !errors.Is(err, entity.ErrPinLimitReached) {
+			t.Fatalf("got %v, want %v", err, entity.ErrPinLimitReached)
+		}
+	})
+
+	t.Run("Unpin", func(t *testing.T) {
+		r := factory(t)
+		alice := r.createUser(t, "alice")
+		post := r.createPost(t, alice, "hello")
+		r.pin(t, alice, post)
+
+		if err := r.Pin.Delete(ctx, alice.ID.String(), post.ID.String()); err != nil {
+			t.Fatalf("delete pin: %v", err)
+		}
+		posts, _ := profile(t, r, alice, alice, allRows)
+		if len(posts) != 1 || posts[0].Pinned {
+			t.Fatalf("got %d posts, want the unpinned post back in the list", len(posts))
+		}
+		// A post that is not pinned can be unpinned as well
+		if err := r.Pin.Delete(ctx, alice.ID.String(), post.ID.String()); err != nil {
+			t.Fatalf("delete pin twice: %v", err)
+		}
+	})
+
+	t.Run("DeletePostCascades", func(t *testing.T) {
+		r := factory(t)
+		alice := r.createUser(t, "alice")
+		post := r.createPost(t, alice, "hello")
+		r.pin(t, alice, post)
+
+		if err := r.Post.Delete(ctx, post.ID.String(), alice.ID.String()); err != nil {
+			t.Fatalf("delete post: %v", err)
+		}
+		if posts, _ := profile(t, r, alice, alice, allRows); len(posts) != 0 {
+			t.Fatalf("got %d posts, want none", len(posts))
+		}
+		// The pin no longer counts towards the limit
+		for range entity.MaxPinnedPosts {
+			r.pin(t, alice, r.createPost(t, alice, "pinned"))
+		}
+	})
+
+	t.Run("NotListedToViewer", func(t *testing.T) {
+		r := factory(t)
+		alice := r.createUser(t, "alice")
+		bob := r.createUser(t, "bob")
+		r.pin(t, alice, r.createPostVisibleTo(t, alice, "followers only", entity.PostVisibilityFollowers))
+
+		if posts, _ := profile(t, r, alice, bob, allRows); len(posts) != 0 {
+			t.Fatalf("got %d posts, want the pinned post hidden from bob", len(posts))
+		}
+		if posts, _ := profile(t, r, alice, alice, allRows); len(posts) != 1 || !posts[0].Pinned {
+			t.Fatalf("got %d posts, want alice to see her pinned post", len(posts))
+		}
+	})
+
+	t.Run("FirstPageOnly", func(t *testing.T) {
+		r := factory(t)
+		alice := r.createUser(t, "alice")
+		var posts []*entity.Post
+		for range 5 {
+			posts = append(posts, r.createPost(t, alice, "post"))
+			r.tick()
+		}
+		r.pin(t, alice, posts[0])
+
+		// The pins come on top of the page size
+		page, next := profile(t, r, alice, alice, dto.CursorPage{Limit: 2})
+		if len(page) != 3 || page[0].ID != posts[0].ID || !page[0].Pinned || next == nil {
+			t.Fatalf("got %d posts on the first page, want the pinned post and 2 more", len(page))
+		}
+		seen := map[uuid.UUID]bool{}
+		for {
+			for _, p := range page {
+				if seen[p.ID] {
+					t.Fatalf("post %s listed twice", p.ID)
+				}
+				seen[p.ID] = true
+			}
+			if next == nil {
+				break
+			}
+			page, next = profile(t, r, alice, alice, dto.CursorPage{Limit: 2, After: next})
+			for _, p := range page {
+				if p.Pinned {
+					t.Fatalf("pinned post %s listed after the first page", p.ID)
+				}
+			}
+		}
+		if len(seen) != len(posts) {
+			t.Fatalf("pages hold %d posts, want %d", len(seen), len(posts))
+		}
+	})
+}
+
+func (r Repositories) pin(t testing.TB, user *entity.User, post *entity.Post) {
+	t.Helper()
+
+	pin, err := entity.NewPinnedPost(dto.NewPinnedPost{UserID: user.ID, PostID: post.ID})
+	if err != nil {
+		t.Fatalf("new pin: %v", err)
+	}
+	if err := r.Pin.Save(context.Background(), pin); err != nil {
+		t.Fatalf("save pin: %v", err)
+	}
+}
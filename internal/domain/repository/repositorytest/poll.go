@@ -0,0 +1,260 @@
+package repositorytest
+
+import (
+	"context"
+	"slices"
+	"social-media-go-ddd/internal/domain/aggregate"
+	"social-media-go-ddd/internal/domain/dto"
+	"social-media-go-ddd/internal/domain/entity"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func RunPollRepositorySuite(t *testing.T, factory Factory) {
+	ctx := context.Background()
+
+	t.Run("SaveAndFindByPostID", func(t *testing.T) {
+		r := factory(t)
+		alice := r.createUser(t, "alice")
+		post := r.createPost(t, alice, "tea or coffee")
+		poll := r.createPoll(t, post, entity.PollChoiceSingle, "tea", "coffee", "water")
+
+		found, err := r.Poll.FindByPostID(ctx, post.ID.String(), nil)
+		if err != nil {
+			t.Fatalf("find poll: %v", err)
+		}
+		if found.ID != poll.ID || found.PostID != post.ID || found.Choice != entity.PollChoiceSingle || found.ClosesAt.Sub(poll.ClosesAt).Abs() > time.Second {
+			t.Fatalf("got poll %+v, want %+v", found.Poll, poll)
+		}
+		if len(found.Options) != 3 {
+			t.Fatalf("got %d options, want 3", len(found.Options))
+		}
+		for i, o := range found.Options {
+			if o.ID != poll.Options[i].ID || o.Position != i || o.Text != poll.Options[i].Text {
+				t.Fatalf("option %d is %+v, want %+v", i, o, poll.Options[i])
+			}
+		}
+		if found.Closed || found.Voted || found.VoterCount != 0 || found.Tallies != nil || len(found.ViewerChoice) != 0 {
+			t.Fatalf("got an open poll closed=%v voted=%v voters=%d tallies=%v, want no votes", found.Closed, found.Voted, found.VoterCount, found.Tallies)
+		}
+
+		if _, err := r.Poll.FindByPostID(ctx, r.createPost(t, alice, "no poll").ID.String(), nil); err == nil {
+			t.Fatal("expected error when finding the poll of a post without one")
+		}
+	})
+
+	t.Run("SavePollTwice", func(t *testing.T) {
+		r := factory(t)
+		alice := r.createUser(t, "alice")
+		post := r.createPost(t, alice, "tea or coffee")
+		r.createPoll(t, post, entity.PollChoiceSingle, "tea", "coffee")
+
+		second, err := entity.NewPoll(post, dto.NewPoll{Options: []string{"yes", "no"}, ClosesAt: time.Now().Add(time.Hour)})
+		if err != nil {
+			t.Fatalf("new poll: %v", err)
+		}
+		if err := r.Poll.Save(ctx, second); err == nil {
+			t.Fatal("expected error when saving a second poll for a post")
+		}
+	})
+
+	t.Run("Votes", func(t *testing.T) {
+		r := factory(t)
+		alice := r.createUser(t, "alice")
+		bob := r.createUser(t, "bob")
+		carol := r.createUser(t, "carol")
+		post := r.createPost(t, alice, "what do you drink")
+		poll := r.createPoll(t, post, entity.PollChoiceMultiple, "tea", "coffee", "water")
+		tea, coffee := poll.Options[0].ID, poll.Options[1].ID
+		r.vote(t, bob, poll, tea, coffee)
+		r.vote(t, carol, poll, coffee)
+
+		found, err := r.Poll.FindByPostID(ctx, post.ID.String(), ptr(bob.ID.String()))
+		if err != nil {
+			t.Fatalf("find poll: %v", err)
+		}
+		if !found.Voted || found.VoterCount != 2 || !slices.Equal(found.Tallies, []int{1, 2, 0}) {
+			t.Fatalf("bob sees voted=%v voters=%d tallies=%v, want 2 voters and tallies [1 2 0]", found.Voted, found.VoterCount, found.Tallies)
+		}
+		if len(found.ViewerChoice) != 2 || !slices.Contains(found.ViewerChoice, tea) || !slices.Contains(found.ViewerChoice, coffee) {
+			t.Fatalf("bob chose %v, want tea and coffee", found.ViewerChoice)
+		}
+
+		// Tallies stay hidden from viewers who did not vote while the poll is open
+		for _, viewer := range []*string{nil, ptr(alice.ID.String())} {
+			found, err := r.Poll.FindByPostID(ctx, post.ID.String(), viewer)
+			if err != nil {
+				t.Fatalf("find poll: %v", err)
+			}
+			if found.Voted || found.VoterCount != 2 || found.Tallies != nil {
+				t.Fatalf("viewer without a vote sees voted=%v voters=%d tallies=%v, want 2 voters and no tallies", found.Voted, found.VoterCount, found.Tallies)
+			}
+		}
+	})
+
+	t.Run("VoteTwice", func(t *testing.T) {
+		r := factory(t)
+		alice := r.createUser(t, "alice")
+		bob := r.createUser(t, "bob")
+		poll := r.createPoll(t, r.createPost(t, alice, "tea or coffee"), entity.PollChoiceSingle, "tea", "coffee")
+		r.vote(t, bob, poll, poll.Options[0].ID)
+
+		vote, err := entity.NewPollVote(poll, dto.NewPollVote{UserID: bob.ID, OptionIDs: []uuid.UUID{poll.Options[1].ID}})
+		if err != nil {
+			t.Fatalf("new vote: %v", err)
+		}
+		if err := r.PollVote.Save(ctx, vote); err == nil {
+			t.Fatal("expected error when voting twice")
+		}
+	})
+
+	t.Run("VoteUnknownOption", func(t *testing.T) {
+		r := factory(t)
+		alice := r.createUser(t, "alice")
+		bob := r.createUser(t, "bob")
+		poll := r.createPoll(t, r.createPost(t, alice, "tea or coffee"), entity.PollChoiceSingle, "tea", "coffee")
+		other := r.createPoll(t, r.createPost(t, alice, "cats or dogs"), entity.PollChoiceSingle, "cats", "dogs")
+
+		vote := &entity.PollVote{ID: uuid.New(), PollID: poll.ID, UserID: bob.ID, OptionIDs: []uuid.UUID{other.Options[0].ID}, CreatedAt: time.Now()}
+		if err := r.PollVote.Save(ctx, vote); err == nil {
+			t.Fatal("expected error when voting for an option of another poll")
+		}
+		found, err := r.Poll.FindByPostID(ctx, poll.PostID.String(), ptr(bob.ID.String()))
+		if err != nil {
+			t.Fatalf("find poll: %v", err)
+		}
+		if found.Voted || found.VoterCount != 0 {
+			t.Fatalf("got voted=%v voters=%d, want the failed vote to leave nothing behind", found.Voted, found.VoterCount)
+		}
+	})
+
+	t.Run("Closed", func(t *testing.T) {
+		r := factory(t)
+		alice := r.createUser(t, "alice")
+		bob := r.createUser(t, "bob")
+		post := r.createPost(t, alice, "tea or coffee")
+		poll, err := entity.NewPoll(post, dto.NewPoll{Options: []string{"tea", "coffee"}, ClosesAt: time.Now().Add(time.Hour)})
+		if err != nil {
+			t.Fatalf("new poll: %v", err)
+		}
+		vote, err := entity.NewPollVote(poll, dto.NewPollVote{UserID: bob.ID, OptionIDs: []uuid.UUID{poll.Options[1].ID}})
+		if err != nil {
+			t.Fatalf("new vote: %v", err)
+		}
+		// Saved as if it had closed an hour ago, after bob voted
+		poll.ClosesAt = time.Now().Add(-time.Hour)
+		if err := r.Poll.Save(ctx, poll); err != nil {
+			t.Fatalf("save poll: %v", err)
+		}
+		if err := r.PollVote.Save(ctx, vote); err != nil {
+			t.Fatalf("save vote: %v", err)
+		}
+
+		found, err := r.Poll.FindByPostID(ctx, post.ID.String(), nil)
+		if err != nil {
+			t.Fatalf("find poll: %v", err)
+		}
+		if !found.Closed || !slices.Equal(found.Tallies, []int{0, 1}) {
+			t.Fatalf("got closed=%v tallies=%v, want the results of the closed poll shown to everyone", found.Closed, found.Tallies)
+		}
+	})
+
+	t.Run("AttachedToPosts", func(t *testing.T) {
+		r := factory(t)
+		alice := r.createUser(t, "alice")
+		bob := r.createUser(t, "bob")
+		r.follow(t, bob, alice)
+		plain := r.createPost(t, alice, "no poll")
+		r.tick()
+		post := r.createPost(t, alice, "tea or coffee")
+		poll := r.createPoll(t, post, entity.PollChoiceSingle, "tea", "coffee")
+		r.vote(t, bob, poll, poll.Options[0].ID)
+		r.favorite(t, bob, post)
+		r.repost(t, bob, post, "")
+
+		assertPoll := func(name string, p *aggregate.Post) {
+			t.Helper()
+			if p.ID == plain.ID {
+				if p.Poll != nil {
+					t.Fatalf("%s: post without a poll has %+v", name, p.Poll)
+				}
+				return
+			}
+			if p.Poll == nil || p.Poll.ID != poll.ID || len(p.Poll.Options) != 2 || !p.Poll.Voted || !slices.Equal(p.Poll.Tallies, []int{1, 0}) {
+				t.Fatalf("%s: post has poll %+v, want the poll as bob sees it", name, p.Poll)
+			}
+		}
+
+		found, err := r.Post.FindByID(ctx, post.ID.String(), ptr(bob.ID.String()))
+		if err != nil {
+			t.Fatalf("find post: %v", err)
+		}
+		assertPoll("FindByID", found)
+
+		posts, _, err := r.Post.FindByUserID(ctx, alice.ID.String(), ptr(bob.ID.String()), dto.CursorPage{Limit: 10})
+		if err != nil || len(posts) != 2 {
+			t.Fatalf("got %d posts of alice (err %v), want 2", len(posts), err)
+		}
+		feed, _, err := r.Post.FindFeed(ctx, bob.ID.String(), 10, 0)
+		if err != nil {
+			t.Fatalf("find feed: %v", err)
+		}
+		favorites, _, err := r.Favorite.FindByUserID(ctx, bob.ID.String(), ptr(bob.ID.String()), dto.CursorPage{Limit: 10})
+		if err != nil || len(favorites) != 1 {
+			t.Fatalf("got %d favorites of bob (err %v), want 1", len(favorites), err)
+		}
+		reposts, _, err := r.Repost.FindByUserID(ctx, bob.ID.String(), ptr(bob.ID.String()), dto.CursorPage{Limit: 10})
+		if err != nil || len(reposts) != 1 {
+			t.Fatalf("got %d reposts of bob (err %v), want 1", len(reposts), err)
+		}
+		for name, list := range map[string][]*aggregate.Post{"FindByUserID": posts, "FindFeed": feed, "Favorite.FindByUserID": favorites, "Repost.FindByUserID": reposts} {
+			for _, p := range list {
+				assertPoll(name, p)
+			}
+		}
+	})
+
+	t.Run("DeletePostCascades", func(t *testing.T) {
+		r := factory(t)
+		alice := r.createUser(t, "alice")
+		bob := r.createUser(t, "bob")
+		post := r.createPost(t, alice, "tea or coffee")
+		poll := r.createPoll(t, post, entity.PollChoiceSingle, "tea", "coffee")
+		r.vote(t, bob, poll, poll.Options[0].ID)
+
+		if err := r.Post.Delete(ctx, post.ID.String(), alice.ID.String()); err != nil {
+			t.Fatalf("delete post: %v", err)
+		}
+		if _, err := r.Poll.FindByPostID(ctx, post.ID.String(), nil); err == nil {
+			t.Fatal("expected the poll to be deleted with its post")
+		}
+	})
+}
+
+// Saves a poll on post with the given options, closing in an hour
+func (r Repositories) createPoll(t testing.TB, post *entity.Post, choice entity.PollChoice, options ...string) *entity.Poll {
+	t.Helper()
+
+	poll, err := entity.NewPoll(post, dto.NewPoll{Options: options, Choice: string(choice), ClosesAt: time.Now().Add(time.Hour)})
+	if err != nil {
+		t.Fatalf("new poll: %v", err)
+	}
+	if err := r.Poll.Save(context.Background(), poll); err != nil {
+		t.Fatalf("save poll: %v", err)
+	}
+	return poll
+}
+
+func (r Repositories) vote(t testing.TB, user *entity.User, poll *entity.Poll, optionIDs ...uuid.UUID) {
+	t.Helper()
+
+	vote, err := entity.NewPollVote(poll, dto.NewPollVote{UserID: user.ID, OptionIDs: optionIDs})
+	if err != nil {
+		t.Fatalf("new vote: %v", err)
+	}
+	if err := r.PollVote.Save(context.Background(), vote); err != nil {
+		t.Fatalf("save vote: %v", err)
+	}
+}
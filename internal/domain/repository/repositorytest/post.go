@@ -0,0 +1,910 @@
+package repositorytest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"social-media-go-ddd/internal/domain/aggregate"
+	"social-media-go-ddd/internal/domain/dto"
+	"social-media-go-ddd/internal/domain/entity"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func RunPostRepositorySuite(t *testing.T, factory Factory) {
+	ctx := context.Background()
+
+	t.Run("SaveAndFindByID", func(t *testing.T) {
+		r := factory(t)
+		alice := r.createUser(t, "alice")
+		post := r.createPost(t, alice, "hello world")
+
+		found, err := r.Post.FindByID(ctx, post.ID.String(), nil)
+		if err != nil {
+			t.Fatalf("find post: %v", err)
+		}
+		if found.ID != post.ID || found.UserID != alice.ID || found.Content != "hello world" {
+			t.Fatalf("got %+v, want %+v", found.Post, post)
+		}
+		if found.User.ID != alice.ID || found.User.Username != "alice" {
+			t.Fatalf("got owner %+v, want alice", found.User)
+		}
+		if found.Version != 1 {
+			t.Fatalf("got version %d of a new post, want 1", found.Version)
+		}
+		if found.Type != aggregate.PostTypeText || found.Repost != nil {
+			t.Fatalf("got type %q, want text", found.Type)
+		}
+		if found.Liked || found.Favorited || found.Reposted || found.LikeCount != 0 || found.FavoriteCount != 0 || found.RepostCount != 0 {
+			t.Fatalf("fresh post should have no interactions, got %+v", found)
+		}
+	})
+
+	t.Run("FindByIDNotFound", func(t *testing.T) {
+		r := factory(t)
+
+		if _, err := r.Post.FindByID(ctx, uuid.NewString(), nil); err == nil {
+			t.Fatal("expected error for unknown post")
+		}
+	})
+
+	t.Run("ViewerFlags", func(t *testing.T) {
+		r := factory(t)
+		alice := r.createUser(t, "alice")
+		bob := r.createUser(t, "bob")
+		carol := r.createUser(t, "carol")
+		post := r.createPost(t, alice, "hello")
+		r.like(t, bob, post)
+		r.favorite(t, bob, post)
+		r.repost(t, bob, post, "")
+
+		found, err := r.Post.FindByID(ctx, post.ID.String(), ptr(bob.ID.String()))
+		if err != nil {
+			t.Fatalf("find post: %v", err)
+		}
+		if !found.Liked || !found.Favorited || !found.Reposted {
+			t.Fatalf("bob interacted with the post, got %+v", found)
+		}
+		assertCounts(t, found, 1, 1, 1)
+
+		for _, viewer := range []*string{nil, ptr(carol.ID.String())} {
+			found, err := r.Post.FindByID(ctx, post.ID.String(), viewer)
+			if err != nil {
+				t.Fatalf("find post: %v", err)
+			}
+			if found.Liked || found.Favorited || found.Reposted {
+				t.Fatalf("viewer did not interact with the post, got %+v", found)
+			}
+			assertCounts(t, found, 1, 1, 1)
+		}
+	})
+
+	t.Run("FindByUserID", func(t *testing.T) {
+		r := factory(t)
+		alice := r.createUser(t, "alice")
+		bob := r.createUser(t, "bob")
+		first := r.createPost(t, alice, "first")
+		r.tick()
+		second := r.createPost(t, alice, "second")
+		r.createPost(t, bob, "not alice")
+		r.like(t, alice, first)
+
+		posts, _, err := r.Post.FindByUserID(ctx, alice.ID.String(), ptr(alice.ID.String()), allRows)
+		if err != nil {
+			t.Fatalf("find posts: %v", err)
+		}
+		byID := map[uuid.UUID]*aggregate.Post{}
+		for _, p := range posts {
+			byID[p.ID] = p
+			if p.User.ID != alice.ID {
+				t.Fatalf("got owner %s, want alice", p.User.ID)
+			}
+		}
+		if len(posts) != 2 || byID[first.ID] == nil || byID[second.ID] == nil {
+			t.Fatalf("got %d posts, want first and second", len(posts))
+		}
+		if !byID[first.ID].Liked || byID[first.ID].LikeCount != 1 || byID[second.ID].Liked {
+			t.Fatal("like flags do not match")
+		}
+		if posts[0].ID != second.ID {
+			t.Fatal("want the newest post first")
+		}
+	})
+
+	t.Run("FindByUserIDViewer", func(t *testing.T) {
+		r := factory(t)
+		alice := r.createUser(t, "alice")
+		bob := r.createUser(t, "bob")
+		carol := r.createUser(t, "carol")
+		post := r.createPost(t, alice, "hello")
+		// The owner interacts with their own post, the flags must not leak to other viewers
+		r.like(t, alice, post)
+		r.favorite(t, alice, post)
+		r.repost(t, alice, post, "")
+		r.like(t, carol, post)
+
+		owner := alice.ID.String()
+		lists := map[string]func(viewer *string) ([]*aggregate.Post, *dto.Cursor, error){
+			"Post": func(viewer *string) ([]*aggregate.Post, *dto.Cursor, error) {
+				return r.Post.FindByUserID(ctx, owner, viewer, allRows)
+			},
+			"Favorite": func(viewer *string) ([]*aggregate.Post, *dto.Cursor, error) {
+				return r.Favorite.FindByUserID(ctx, owner, viewer, allRows)
+			},
+			"Repost": func(viewer *string) ([]*aggregate.Post, *dto.Cursor, error) {
+				return r.Repost.FindByUserID(ctx, owner, viewer, allRows)
+			},
+		}
+		for name, find := range lists {
+			for _, tt := range []struct {
+				who                        string
+				viewer                     *string
+				liked, favorited, reposted bool
+			}{
+				{"anonymous", nil, false, false, false},
+				{"bob", ptr(bob.ID.String()), false, false, false},
+				{"carol", ptr(carol.ID.String()), true, false, false},
+				{"alice", ptr(owner), true, true, true},
+			} {
+				posts, _, err := find(tt.viewer)
+				if err != nil || len(posts) != 1 {
+					t.Fatalf("%s: got %d posts err %v, want 1", name, len(posts), err)
+				}
+				p := posts[0]
+				if p.Liked != tt.liked || p.Favorited != tt.favorited || p.Reposted != tt.reposted {
+					t.Fatalf("%s viewed by %s: got liked=%v favorited=%v reposted=%v", name, tt.who, p.Liked, p.Favorited, p.Reposted)
+				}
+				assertCounts(t, p, 2, 1, 1)
+			}
+		}
+	})
+
+	t.Run("FindByUserIDPages", func(t *testing.T) {
+		r := factory(t)
+		alice := r.createUser(t, "alice")
+		for i := range 5 {
+			r.createPost(t, alice, fmt.Sprintf("post %d", i))
+		}
+
+		assertCursorPages(t, 2, 5, func(page dto.CursorPage) ([]*aggregate.Post, *dto.Cursor, error) {
+			return r.Post.FindByUserID(ctx, alice.ID.String(), nil, page)
+		})
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		r := factory(t)
+		alice := r.createUser(t, "alice")
+		bob := r.createUser(t, "bob")
+		post := r.createPost(t, alice, "before")
+
+		// Only the owner can update a post
+		forged := *post
+		forged.UserID = bob.ID
+		r.edit(t, bob, &forged, "hijacked")
+
+		r.edit(t, alice, post, "after")
+
+		found, err := r.Post.FindByID(ctx, post.ID.String(), nil)
+		if err != nil {
+			t.Fatalf("find post: %v", err)
+		}
+		if found.Content != "after" || found.UserID != alice.ID {
+			t.Fatalf("got content %q owner %s, want after by alice", found.Content, found.UserID)
+		}
+		if !found.Edited || found.EditCount != 1 {
+			t.Fatalf("got edited %v count %d, want one edit", found.Edited, found.EditCount)
+		}
+	})
+
+	t.Run("UpdateStaleVersion", func(t *testing.T) {
+		r := factory(t)
+		alice := r.createUser(t, "alice")
+		post := r.createPost(t, alice, "first")
+
+		// Two edits made from the same version, the second one is rejected
+		second := r.edit(t, alice, post, "second")
+		stale, err := entity.NewPostForUpdate(post, dto.UpdatePost{Content: "lost update"})
+		if err != nil {
+			t.Fatalf("new post for update: %v", err)
+		}
+		revision, err := entity.NewPostRevision(post, stale, alice.ID)
+		if err != nil {
+			t.Fatalf("new post revision: %v", err)
+		}
+		if err := r.Post.Update(ctx, stale, revision); !errors.Is(err, entity.ErrVersionMismatch) {
+			t.Fatalf("got %v, want ErrVersionMismatch", err)
+		}
+
+		found, err := r.Post.FindByID(ctx, post.ID.String(), nil)
+		if err != nil {
+			t.Fatalf("find post: %v", err)
+		}
+		if found.Content != "second" || found.Version != second.Version || found.EditCount != 1 {
+			t.Fatalf("got %q version %d with %d edits, want second at version %d with 1 edit", found.Content, found.Version, found.EditCount, second.Version)
+		}
+
+		r.edit(t, alice, second, "third")
+		found, err = r.Post.FindByID(ctx, post.ID.String(), nil)
+		if err != nil {
+			t.Fatalf("find post: %v", err)
+		}
+		if found.Content != "third" || found.Version != 3 {
+			t.Fatalf("got %q version %d, want third at version 3", found.Content, found.Version)
+		}
+	})
+
+	t.Run("FindRevisions", func(t *testing.T) {
+		r := factory(t)
+		alice := r.createUser(t, "alice")
+		bob := r.createUser(t, "bob")
+		post := r.createPost(t, alice, "first")
+		r.favorite(t, bob, post)
+		r.repost(t, bob, post, "")
+		r.follow(t, bob, alice)
+
+		revisions, err := r.Post.FindRevisions(ctx, post.ID.String())
+		if err != nil {
+			t.Fatalf("find revisions: %v", err)
+		}
+		if len(revisions) != 0 {
+			t.Fatalf("got %d revisions of a fresh post, want none", len(revisions))
+		}
+
+		r.tick()
+		second := r.edit(t, alice, post, "second")
+		r.tick()
+		r.edit(t, alice, second, "third")
+
+		revisions, err = r.Post.FindRevisions(ctx, post.ID.String())
+		if err != nil {
+			t.Fatalf("find revisions: %v", err)
+		}
+		var contents []string
+		for _, rev := range revisions {
+			if rev.PostID != post.ID || rev.EditorID != alice.ID {
+				t.Fatalf("got revision of post %s by %s, want %s by alice", rev.PostID, rev.EditorID, post.ID)
+			}
+			contents = append(contents, rev.Content)
+		}
+		if fmt.Sprint(contents) != "[second first]" {
+			t.Fatalf("got revisions %v, want [second first]", contents)
+		}
+		if !revisions[0].EditedAt.After(revisions[1].EditedAt) {
+			t.Fatalf("got edited at %v then %v, want most recent first", revisions[0].EditedAt, revisions[1].EditedAt)
+		}
+
+		// Every list of posts carries the edit count
+		posts, _, err := r.Post.FindByUserID(ctx, alice.ID.String(), nil, allRows)
+		if err != nil {
+			t.Fatalf("find posts: %v", err)
+		}
+		favorites, _, err := r.Favorite.FindByUserID(ctx, bob.ID.String(), nil, allRows)
+		if err != nil {
+			t.Fatalf("find favorites: %v", err)
+		}
+		reposts, _, err := r.Repost.FindByUserID(ctx, bob.ID.String(), nil, allRows)
+		if err != nil {
+			t.Fatalf("find reposts: %v", err)
+		}
+		feed, _, err := r.Post.FindFeed(ctx, bob.ID.String(), 10, 0)
+		if err != nil {
+			t.Fatalf("find feed: %v", err)
+		}
+		for _, p := range slices.Concat(posts, favorites, reposts, feed) {
+			if !p.Edited || p.EditCount != 2 || p.Content != "third" {
+				t.Fatalf("got %q edited %v count %d, want third with two edits", p.Content, p.Edited, p.EditCount)
+			}
+		}
+		if len(posts) != 1 || len(favorites) != 1 || len(reposts) != 1 || len(feed) != 2 {
+			t.Fatalf("got %d posts %d favorites %d reposts %d feed items, want 1 1 1 2", len(posts), len(favorites), len(reposts), len(feed))
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		r := factory(t)
+		alice := r.createUser(t, "alice")
+		bob := r.createUser(t, "bob")
+		post := r.createPost(t, alice, "hello")
+
+		// Only the owner can delete a post
+		if err := r.Post.Delete(ctx, post.ID.String(), bob.ID.String()); err != nil {
+			t.Fatalf("delete post: %v", err)
+		}
+		if _, err := r.Post.FindByID(ctx, post.ID.String(), nil); err != nil {
+			t.Fatalf("post deleted by non owner: %v", err)
+		}
+
+		if err := r.Post.Delete(ctx, post.ID.String(), alice.ID.String()); err != nil {
+			t.Fatalf("delete post: %v", err)
+		}
+		if _, err := r.Post.FindByID(ctx, post.ID.String(), nil); err == nil {
+			t.Fatal("expected error for deleted post")
+		}
+	})
+
+	t.Run("DeleteCascades", func(t *testing.T) {
+		r := factory(t)
+		alice := r.createUser(t, "alice")
+		bob := r.createUser(t, "bob")
+		post := r.createPost(t, alice, "hello")
+		r.like(t, bob, post)
+		r.favorite(t, bob, post)
+		r.repost(t, bob, post, "")
+		r.edit(t, alice, post, "edited")
+
+		if err := r.Post.Delete(ctx, post.ID.String(), alice.ID.String()); err != nil {
+			t.Fatalf("delete post: %v", err)
+		}
+
+		favorites, _, err := r.Favorite.FindByUserID(ctx, bob.ID.String(), ptr(bob.ID.String()), allRows)
+		if err != nil {
+			t.Fatalf("find favorites: %v", err)
+		}
+		reposts, _, err := r.Repost.FindByUserID(ctx, bob.ID.String(), ptr(bob.ID.String()), allRows)
+		if err != nil {
+			t.Fatalf("find reposts: %v", err)
+		}
+		feed, total, err := r.Post.FindFeed(ctx, bob.ID.String(), 10, 0)
+		if err != nil {
+			t.Fatalf("find feed: %v", err)
+		}
+		revisions, err := r.Post.FindRevisions(ctx, post.ID.String())
+		if err != nil {
+			t.Fatalf("find revisions: %v", err)
+		}
+		if len(favorites) != 0 || len(reposts) != 0 || len(feed) != 0 || total != 0 || len(revisions) != 0 {
+			t.Fatalf("got %d favorites %d reposts %d feed items %d revisions, want none", len(favorites), len(reposts), len(feed), len(revisions))
+		}
+	})
+
+	t.Run("FindFeed", func(t *testing.T) {
+		r := factory(t)
+		alice := r.createUser(t, "alice")
+		bob := r.createUser(t, "bob")
+		carol := r.createUser(t, "carol")
+		dave := r.createUser(t, "dave")
+		r.follow(t, alice, bob)
+		// Followers of alice must not duplicate her own posts in her feed
+		r.follow(t, carol, alice)
+		r.follow(t, dave, alice)
+		r.follow(t, dave, bob)
+
+		bobPost := r.createPost(t, bob, "bob post")
+		r.tick()
+		carolPost := r.createPost(t, carol, "carol post")
+		r.tick()
+		alicePost := r.createPost(t, alice, "alice post")
+		r.tick()
+		r.repost(t, bob, carolPost, "look at this")
+		r.tick()
+		// Carol is not followed by alice, her repost stays out of the feed
+		r.repost(t, carol, alicePost, "")
+
+		feed, total, err := r.Post.FindFeed(ctx, alice.ID.String(), 10, 0)
+		if err != nil {
+			t.Fatalf("find feed: %v", err)
+		}
+		if total != 3 || len(feed) != 3 {
+			t.Fatalf("got %d items (total %d), want 3", len(feed), total)
+		}
+
+		repost := feed[0]
+		if repost.Type != aggregate.PostTypeRepost || repost.ID != carolPost.ID {
+			t.Fatalf("got %s post %s first, want bob's repost of carol's post", repost.Type, repost.ID)
+		}
+		if repost.User.ID != carol.ID || repost.RepostUser == nil || repost.RepostUser.ID != bob.ID || repost.RepostUser.Username != "bob" {
+			t.Fatalf("repost should be owned by carol and reposted by bob, got %+v", repost)
+		}
+		if repost.Repost == nil || repost.Repost.Comment != "look at this" || repost.Repost.UserID != bob.ID {
+			t.Fatalf("got repost %+v", repost.Repost)
+		}
+		if feed[1].ID != alicePost.ID || feed[1].Type != aggregate.PostTypeText || feed[1].RepostCount != 1 {
+			t.Fatalf("got %s second, want alice's post with one repost", feed[1].ID)
+		}
+		if feed[2].ID != bobPost.ID || feed[2].User.ID != bob.ID {
+			t.Fatalf("got %s third, want bob's post", feed[2].ID)
+		}
+	})
+
+	t.Run("FindFeedPagination", func(t *testing.T) {
+		r := factory(t)
+		alice := r.createUser(t, "alice")
+		var posts []*entity.Post
+		for _, content := range []string{"one", "two", "three", "four", "five"} {
+			posts = append(posts, r.createPost(t, alice, content))
+			r.tick()
+		}
+
+		var seen []uuid.UUID
+		for offset := 0; offset < 6; offset += 2 {
+			page, total, err := r.Post.FindFeed(ctx, alice.ID.String(), 2, offset)
+			if err != nil {
+				t.Fatalf("find feed: %v", err)
+			}
+			if total != 5 {
+				t.Fatalf("got total %d, want 5", total)
+			}
+			for _, p := range page {
+				seen = append(seen, p.ID)
+			}
+		}
+		if len(seen) != 5 {
+			t.Fatalf("got %d posts across pages, want 5", len(seen))
+		}
+		for i, id := range seen {
+			if want := posts[len(posts)-1-i].ID; id != want {
+				t.Fatalf("position %d: got %s, want %s", i, id, want)
+			}
+		}
+	})
+
+	t.Run("FindFeedEmpty", func(t *testing.T) {
+		r := factory(t)
+		alice := r.createUser(t, "alice")
+
+		feed, total, err := r.Post.FindFeed(ctx, alice.ID.String(), 10, 0)
+		if err != nil {
+			t.Fatalf("find feed: %v", err)
+		}
+		if len(feed) != 0 || total != 0 {
+			t.Fatalf("got %d items (total %d), want none", len(feed), total)
+		}
+	})
+
+	t.Run("Drafts", func(t *testing.T) {
+		r := factory(t)
+		alice := r.createUser(t, "alice")
+		bob := r.createUser(t, "bob")
+		r.follow(t, bob, alice)
+
+		published := r.createPost(t, alice, "published")
+		r.tick()
+		draft := r.createDraft(t, alice, "draft", nil)
+		r.tick()
+		scheduled := r.createDraft(t, alice, "scheduled", ptr(time.Now().Add(time.Hour)))
+
+		// Unpublished posts stay out of the profile and the feed until published
+		posts, _, err := r.Post.FindByUserID(ctx, alice.ID.String(), nil, dto.CursorPage{Limit: 10})
+		if err != nil {
+			t.Fatalf("find by user: %v", err)
+		}
+		if len(posts) != 1 || posts[0].ID != published.ID {
+			t.Fatalf("got %d posts, want only the published one", len(posts))
+		}
+		feed, total, err := r.Post.FindFeed(ctx, bob.ID.String(), 10, 0)
+		if err != nil {
+			t.Fatalf("find feed: %v", err)
+		}
+		if total != 1 || len(feed) != 1 || feed[0].ID != published.ID {
+			t.Fatalf("got %d feed items (total %d), want only the published post", len(feed), total)
+		}
+
+		drafts, _, err := r.Post.FindDrafts(ctx, alice.ID.String(), dto.CursorPage{Limit: 10})
+		if err != nil {
+			t.Fatalf("find drafts: %v", err)
+		}
+		if len(drafts) != 2 || drafts[0].ID != scheduled.ID || drafts[1].ID != draft.ID {
+			t.Fatalf("got %d drafts, want the scheduled post then the draft", len(drafts))
+		}
+		if drafts[0].Status != entity.PostStatusScheduled || drafts[0].PublishAt == nil || drafts[1].Status != entity.PostStatusDraft {
+			t.Fatalf("got drafts %+v and %+v", drafts[0], drafts[1])
+		}
+		if drafts, _, err := r.Post.FindDrafts(ctx, bob.ID.String(), dto.CursorPage{Limit: 10}); err != nil || len(drafts) != 0 {
+			t.Fatalf("got %d drafts of bob (err %v), want none", len(drafts), err)
+		}
+
+		// Editing a draft keeps no revision
+		edited, err := entity.NewPostForUpdate(draft, dto.UpdatePost{Content: "draft edited"})
+		if err != nil {
+			t.Fatalf("new post for update: %v", err)
+		}
+		if err := r.Post.Update(ctx, edited, nil); err != nil {
+			t.Fatalf("update draft: %v", err)
+		}
+
+		publish := *edited
+		if err := publish.Publish(time.Now()); err != nil {
+			t.Fatalf("publish: %v", err)
+		}
+		if err := r.Post.Publish(ctx, &publish); err != nil {
+			t.Fatalf("publish draft: %v", err)
+		}
+		// Publishing from a stale copy, eg twice, is rejected
+		stale := *draft
+		if err := stale.Publish(time.Now()); err != nil {
+			t.Fatalf("publish: %v", err)
+		}
+		if err := r.Post.Publish(ctx, &stale); !errors.Is(err, entity.ErrVersionMismatch) {
+			t.Fatalf("got %v, want ErrVersionMismatch", err)
+		}
+
+		found, err := r.Post.FindByID(ctx, draft.ID.String(), nil)
+		if err != nil {
+			t.Fatalf("find post: %v", err)
+		}
+		if !found.IsPublished() || found.PublishAt != nil || found.Content != "draft edited" || found.Version != 3 || found.Edited {
+			t.Fatalf("got %q status %q version %d edited %v, want the edited draft published at version 3", found.Content, found.Status, found.Version, found.Edited)
+		}
+		posts, _, err = r.Post.FindByUserID(ctx, alice.ID.String(), nil, dto.CursorPage{Limit: 10})
+		if err != nil {
+			t.Fatalf("find by user: %v", err)
+		}
+		if len(posts) != 2 || posts[0].ID != draft.ID {
+			t.Fatalf("got %d posts, want the published draft first", len(posts))
+		}
+	})
+
+	t.Run("PublishDue", func(t *testing.T) {
+		r := factory(t)
+		alice := r.createUser(t, "alice")
+		now := time.Now()
+
+		var scheduled []*entity.Post
+		for i := range 5 {
+			scheduled = append(scheduled, r.createDraft(t, alice, fmt.Sprintf("post %d", i), ptr(now.Add(time.Duration(i+1)*time.Hour))))
+		}
+		r.createDraft(t, alice, "draft", nil)
+
+		// Only posts whose time has come, the earliest first
+		due, err := r.Post.PublishDue(ctx, now.Add(150*time.Minute), 10)
+		if err != nil {
+			t.Fatalf("publish due: %v", err)
+		}
+		if len(due) != 2 {
+			t.Fatalf("published %d posts, want 2", len(due))
+		}
+		for _, p := range due {
+			if !p.IsPublished() || p.PublishAt != nil || (p.ID != scheduled[0].ID && p.ID != scheduled[1].ID) {
+				t.Fatalf("published %+v, want one of the two due posts", p)
+			}
+		}
+		found, err := r.Post.FindByID(ctx, scheduled[0].ID.String(), nil)
+		if err != nil {
+			t.Fatalf("find post: %v", err)
+		}
+		// A scheduled post counts as created when it was due, not when the scheduler got to it
+		if !found.IsPublished() || found.Version != 2 || found.CreatedAt.Sub(*scheduled[0].PublishAt).Abs() > time.Second {
+			t.Fatalf("got status %q version %d created at %v, want published at version 2 created at %v", found.Status, found.Version, found.CreatedAt, scheduled[0].PublishAt)
+		}
+
+		// Concurrent schedulers publish every remaining post exactly once
+		var (
+			wg    sync.WaitGroup
+			mu    sync.Mutex
+			count = map[uuid.UUID]int{}
+		)
+		for range 4 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				due, err := r.Post.PublishDue(ctx, now.Add(24*time.Hour), 1)
+				if err != nil {
+					t.Errorf("publish due: %v", err)
+					return
+				}
+				mu.Lock()
+				defer mu.Unlock()
+				for _, p := range due {
+					count[p.ID]++
+				}
+			}()
+		}
+		wg.Wait()
+		if rest, err := r.Post.PublishDue(ctx, now.Add(24*time.Hour), 10); err != nil {
+			t.Fatalf("publish due: %v", err)
+		} else {
+			for _, p := range rest {
+				count[p.ID]++
+			}
+		}
+		if len(count) != 3 {
+			t.Fatalf("published %d of the 3 remaining posts", len(count))
+		}
+		for id, n := range count {
+			if n != 1 {
+				t.Fatalf("post %s published %d times", id, n)
+			}
+		}
+
+		drafts, _, err := r.Post.FindDrafts(ctx, alice.ID.String(), dto.CursorPage{Limit: 10})
+		if err != nil {
+			t.Fatalf("find drafts: %v", err)
+		}
+		if len(drafts) != 1 || drafts[0].Status != entity.PostStatusDraft {
+			t.Fatalf("got %d drafts, want only the draft left", len(drafts))
+		}
+	})
+
+	t.Run("Visibility", func(t *testing.T) {
+		r := factory(t)
+		alice := r.createUser(t, "alice")
+		bob := r.createUser(t, "bob")
+		carol := r.createUser(t, "carol")
+		dave := r.createUser(t, "dave")
+		r.follow(t, bob, alice)
+		r.follow(t, dave, alice)
+
+		public := r.createPostVisibleTo(t, alice, "public", entity.PostVisibilityPublic)
+		r.tick()
+		followers := r.createPostVisibleTo(t, alice, "followers only", entity.PostVisibilityFollowers)
+		r.tick()
+		mentioned := r.createPostVisibleTo(t, alice, "for @carol and @nobody", entity.PostVisibilityMentioned)
+		r.favorite(t, dave, followers)
+		r.favorite(t, dave, mentioned)
+		if err := r.Follow.Delete(ctx, dave.ID.String(), alice.ID.String()); err != nil {
+			t.Fatalf("unfollow: %v", err)
+		}
+
+		// Viewer, nil when anonymous, and the posts they may see
+		cases := []struct {
+			name    string
+			viewer  *entity.User
+			visible map[uuid.UUID]bool
+		}{
+			{"anonymous", nil, map[uuid.UUID]bool{public.ID: true}},
+			{"author", alice, map[uuid.UUID]bool{public.ID: true, followers.ID: true, mentioned.ID: true}},
+			{"follower", bob, map[uuid.UUID]bool{public.ID: true, followers.ID: true}},
+			{"mentioned", carol, map[uuid.UUID]bool{public.ID: true, mentioned.ID: true}},
+			{"stranger", dave, map[uuid.UUID]bool{public.ID: true}},
+		}
+		for _, c := range cases {
+			var viewerID *string
+			if c.viewer != nil {
+				viewerID = ptr(c.viewer.ID.String())
+			}
+
+			// The backend reports the relationship, the entity decides
+			for _, p := range []*entity.Post{public, followers, mentioned} {
+				found, err := r.Post.FindByID(ctx, p.ID.String(), viewerID)
+				if err != nil {
+					t.Fatalf("%s: find post: %v", c.name, err)
+				}
+				if found.VisibleTo(found.Viewer) != c.visible[p.ID] {
+					t.Fatalf("%s: post %q visible %v with viewer %+v, want %v", c.name, p.Content, !c.visible[p.ID], found.Viewer, c.visible[p.ID])
+				}
+			}
+
+			// Lists hold the same posts as VisibleTo
+			posts, _, err := r.Post.FindByUserID(ctx, alice.ID.String(), viewerID, dto.CursorPage{Limit: 10})
+			if err != nil {
+				t.Fatalf("%s: find by user: %v", c.name, err)
+			}
+			if len(posts) != len(c.visible) {
+				t.Fatalf("%s: got %d posts of alice, want %d", c.name, len(posts), len(c.visible))
+			}
+			for _, p := range posts {
+				if !c.visible[p.ID] {
+					t.Fatalf("%s: got post %q of alice", c.name, p.Content)
+				}
+			}
+		}
+
+		feed, total, err := r.Post.FindFeed(ctx, bob.ID.String(), 10, 0)
+		if err != nil {
+			t.Fatalf("find feed: %v", err)
+		}
+		if total != 2 || len(feed) != 2 || feed[0].ID != followers.ID || feed[1].ID != public.ID {
+			t.Fatalf("got %d feed items (total %d), want the followers only and the public post", len(feed), total)
+		}
+		if feed, total, err := r.Post.FindFeed(ctx, alice.ID.String(), 10, 0); err != nil || total != 3 || len(feed) != 3 {
+			t.Fatalf("got %d feed items of alice (total %d, err %v), want all of her posts", len(feed), total, err)
+		}
+
+		// Favorites outlive the follow, the post they point to is hidden with it
+		favorites, _, err := r.Favorite.FindByUserID(ctx, dave.ID.String(), ptr(dave.ID.String()), dto.CursorPage{Limit: 10})
+		if err != nil {
+			t.Fatalf("find favorites: %v", err)
+		}
+		if len(favorites) != 0 {
+			t.Fatalf("got %d favorites of dave, want none once dave no longer follows alice", len(favorites))
+		}
+		r.repost(t, bob, public, "")
+		reposts, _, err := r.Repost.FindByUserID(ctx, bob.ID.String(), nil, dto.CursorPage{Limit: 10})
+		if err != nil || len(reposts) != 1 {
+			t.Fatalf("got %d reposts of bob (err %v), want the public post", len(reposts), err)
+		}
+
+		// Mentions follow the content of the post
+		r.edit(t, alice, mentioned, "for @dave now")
+		if found, err := r.Post.FindByID(ctx, mentioned.ID.String(), ptr(carol.ID.String())); err != nil || found.Viewer.Mentioned {
+			t.Fatalf("carol is still mentioned (err %v)", err)
+		}
+		if found, err := r.Post.FindByID(ctx, mentioned.ID.String(), ptr(dave.ID.String())); err != nil || !found.VisibleTo(found.Viewer) {
+			t.Fatalf("dave does not see the post mentioning him (err %v)", err)
+		}
+	})
+	t.Run("ListsAgreeWithListedTo", func(t *testing.T) {
+		r := factory(t)
+		alice := r.createUser(t, "alice")
+		bob := r.createUser(t, "bob")
+		carol := r.createUser(t, "carol")
+		dave := r.createUser(t, "dave")
+		erin := r.createUser(t, "erin")
+		r.follow(t, bob, alice)
+		r.follow(t, erin, alice)
+
+		// A post of each visibility and a draft, each mentioning carol and erin
+		var posts []*entity.Post
+		for _, rule := range entity.PostVisibilityRules {
+			posts = append(posts, r.createPostVisibleTo(t, alice, string(rule.Visibility)+" for @carol and @erin", rule.Visibility))
+			r.tick()
+		}
+		posts = append(posts, r.createDraft(t, alice, "draft for @carol and @erin", nil))
+		viewers := []*entity.User{nil, alice, bob, carol, dave, erin}
+		for _, viewer := range viewers[1:] {
+			for _, p := range posts[:len(posts)-1] {
+				r.favorite(t, viewer, p)
+			}
+		}
+
+		for _, viewer := range viewers {
+			var viewerID *string
+			name := "anonymous"
+			if viewer != nil {
+				viewerID = ptr(viewer.ID.String())
+				name = viewer.Username
+			}
+			// The backend reports the relationship, the entity decides whether lists hold the post
+			want := map[uuid.UUID]bool{}
+			wantFavorites := map[uuid.UUID]bool{}
+			for i, p := range posts {
+				found, err := r.Post.FindByID(ctx, p.ID.String(), viewerID)
+				if err != nil {
+					t.Fatalf("%s: find post: %v", name, err)
+				}
+				if found.ListedTo(found.Viewer) {
+					want[p.ID] = true
+					wantFavorites[p.ID] = viewer != nil && i < len(posts)-1
+				}
+			}
+
+			listed, _, err := r.Post.FindByUserID(ctx, alice.ID.String(), viewerID, allRows)
+			if err != nil {
+				t.Fatalf("%s: find by user: %v", name, err)
+			}
+			if len(listed) != len(want) {
+				t.Fatalf("%s: got %d posts of alice, want %d", name, len(listed), len(want))
+			}
+			for _, p := range listed {
+				if !want[p.ID] {
+					t.Fatalf("%s: got post %q of alice", name, p.Content)
+				}
+			}
+
+			if viewer == nil {
+				continue
+			}
+			favorites, _, err := r.Favorite.FindByUserID(ctx, viewer.ID.String(), viewerID, allRows)
+			if err != nil {
+				t.Fatalf("%s: find favorites: %v", name, err)
+			}
+			if len(favorites) != len(want) {
+				t.Fatalf("%s: got %d favorites, want %d", name, len(favorites), len(want))
+			}
+			for _, p := range favorites {
+				if !wantFavorites[p.ID] {
+					t.Fatalf("%s: got favorite %q", name, p.Content)
+				}
+			}
+		}
+	})
+
+	t.Run("Quotes", func(t *testing.T) {
+		r := factory(t)
+		alice := r.createUser(t, "alice")
+		bob := r.createUser(t, "bob")
+		carol := r.createUser(t, "carol")
+		r.follow(t, carol, bob)
+
+		original := r.createPost(t, alice, "original")
+		r.tick()
+		quote := r.createQuote(t, bob, original, "quoting alice")
+		r.tick()
+		hidden := r.createPostVisibleTo(t, alice, "followers only", entity.PostVisibilityFollowers)
+		r.tick()
+		quoteOfHidden := r.createQuote(t, alice, hidden, "quoting the hidden post")
+		r.tick()
+		quoteOfQuote := r.createQuote(t, carol, quote, "quoting bob")
+		draft, err := entity.NewPost(dto.NewPost{UserID: carol.ID, Content: "draft quote", Draft: true, QuotedPostID: &original.ID})
+		if err != nil {
+			t.Fatalf("new draft: %v", err)
+		}
+		if err := r.Post.Save(ctx, draft); err != nil {
+			t.Fatalf("save draft: %v", err)
+		}
+		r.like(t, alice, quote)
+
+		// The original counts published quotes only
+		found, err := r.Post.FindByID(ctx, original.ID.String(), nil)
+		if err != nil {
+			t.Fatalf("find original: %v", err)
+		}
+		if found.Type != aggregate.PostTypeText || found.QuoteCount != 1 || found.Quote != nil {
+			t.Fatalf("original has type %q and %d quotes, want a text post quoted once", found.Type, found.QuoteCount)
+		}
+
+		// A quote is a post of its own with the quoted post embedded
+		found, err = r.Post.FindByID(ctx, quote.ID.String(), ptr(alice.ID.String()))
+		if err != nil {
+			t.Fatalf("find quote: %v", err)
+		}
+		if found.Type != aggregate.PostTypeQuote || found.QuotedPostID == nil || *found.QuotedPostID != original.ID {
+			t.Fatalf("quote has type %q quoting %v, want a quote of the original", found.Type, found.QuotedPostID)
+		}
+		assertCounts(t, found, 1, 0, 0)
+		if !found.Liked || found.QuoteCount != 1 {
+			t.Fatalf("quote liked %v with %d quotes, want liked by alice and quoted once", found.Liked, found.QuoteCount)
+		}
+		if found.Quote == nil || found.Quote.ID != original.ID || found.Quote.User.ID != alice.ID || found.Quote.QuoteCount != 1 {
+			t.Fatalf("quote embeds %+v, want the original of alice", found.Quote)
+		}
+
+		// The quote of a quote embeds the quote without its own quote
+		found, err = r.Post.FindByID(ctx, quoteOfQuote.ID.String(), nil)
+		if err != nil {
+			t.Fatalf("find quote of quote: %v", err)
+		}
+		if found.Quote == nil || found.Quote.ID != quote.ID || found.Quote.Type != aggregate.PostTypeQuote || found.Quote.Quote != nil {
+			t.Fatalf("quote of quote embeds %+v, want the quote of bob alone", found.Quote)
+		}
+
+		// The quoted post is left out for viewers who may not see it
+		for viewer, visible := range map[*entity.User]bool{alice: true, bob: false} {
+			found, err := r.Post.FindByID(ctx, quoteOfHidden.ID.String(), ptr(viewer.ID.String()))
+			if err != nil {
+				t.Fatalf("find quote of hidden post: %v", err)
+			}
+			if found.QuotedPostID == nil || (found.Quote != nil) != visible {
+				t.Fatalf("%s sees quoted post %+v, want visible %v", viewer.Username, found.Quote, visible)
+			}
+		}
+
+		// Lists embed quoted posts too
+		posts, _, err := r.Post.FindByUserID(ctx, bob.ID.String(), nil, dto.CursorPage{Limit: 10})
+		if err != nil || len(posts) != 1 || posts[0].Quote == nil || posts[0].Quote.ID != original.ID {
+			t.Fatalf("got %d posts of bob (err %v), want the quote with the original embedded", len(posts), err)
+		}
+		feed, _, err := r.Post.FindFeed(ctx, carol.ID.String(), 10, 0)
+		if err != nil {
+			t.Fatalf("find feed: %v", err)
+		}
+		if len(feed) != 2 || feed[0].Quote == nil || feed[0].Quote.ID != quote.ID || feed[1].Quote == nil || feed[1].Quote.ID != original.ID {
+			t.Fatalf("got %d feed items of carol, want both quotes with their quoted post", len(feed))
+		}
+		r.favorite(t, carol, quote)
+		favorites, _, err := r.Favorite.FindByUserID(ctx, carol.ID.String(), ptr(carol.ID.String()), dto.CursorPage{Limit: 10})
+		if err != nil || len(favorites) != 1 || favorites[0].Quote == nil || favorites[0].Quote.ID != original.ID {
+			t.Fatalf("got %d favorites of carol (err %v), want the quote with the original embedded", len(favorites), err)
+		}
+		r.repost(t, carol, quote, "")
+		reposts, _, err := r.Repost.FindByUserID(ctx, carol.ID.String(), nil, dto.CursorPage{Limit: 10})
+		if err != nil || len(reposts) != 1 || reposts[0].Quote == nil || reposts[0].Quote.ID != original.ID {
+			t.Fatalf("got %d reposts of carol (err %v), want the quote with the original embedded", len(reposts), err)
+		}
+
+		// Deleting the quoted post keeps the quote as a plain post
+		if err := r.Post.Delete(ctx, original.ID.String(), alice.ID.String()); err != nil {
+			t.Fatalf("delete original: %v", err)
+		}
+		found, err = r.Post.FindByID(ctx, quote.ID.String(), nil)
+		if err != nil {
+			t.Fatalf("find quote after delete: %v", err)
+		}
+		if found.Type != aggregate.PostTypeText || found.QuotedPostID != nil || found.Quote != nil {
+			t.Fatalf("quote of a deleted post has type %q quoting %v, want a text post", found.Type, found.QuotedPostID)
+		}
+	})
+}
+
+func assertCounts(t *testing.T, p *aggregate.Post, likes, favorites, reposts int) {
+	t.Helper()
+
+	if p.LikeCount != likes || p.FavoriteCount != favorites || p.RepostCount != reposts {
+		t.Fatalf("got %d likes %d favorites %d reposts, want %d %d %d",
+			p.LikeCount, p.FavoriteCount, p.RepostCount, likes, favorites, reposts)
+	}
+}
@@ -0,0 +1,139 @@
+package repositorytest
+
+import (
+	"context"
+	"fmt"
+	"social-media-go-ddd/internal/domain/entity"
+	"testing"
+)
+
+type feedUsers struct {
+	// Follows bob and carol, favorites and reposts every post of bob
+	alice *entity.User
+	// Favorites and reposts a single post
+	dave *entity.User
+}
+
+// Seeds n posts of bob, each reposted by carol, such that the feed of alice has 3n entries with reposts by two users
+func (r Repositories) seedFeed(tb testing.TB, n int) feedUsers {
+	tb.Helper()
+
+	users := feedUsers{alice: r.createUser(tb, "alice"), dave: r.createUser(tb, "dave")}
+	bob := r.createUser(tb, "bob")
+	carol := r.createUser(tb, "carol")
+	r.follow(tb, users.alice, bob)
+	r.follow(tb, users.alice, carol)
+
+	for i := range n {
+		post := r.createPost(tb, bob, fmt.Sprintf("post %d", i))
+		r.repost(tb, carol, post, "")
+		r.repost(tb, users.alice, post, "")
+		r.favorite(tb, users.alice, post)
+		if i == 0 {
+			r.repost(tb, users.dave, post, "")
+			r.favorite(tb, users.dave, post)
+		}
+	}
+	return users
+}
+
+// Queries fn runs
+func (r Repositories) countQueries(tb testing.TB, fn func() error) int {
+	tb.Helper()
+
+	before := r.QueryCount()
+	if err := fn(); err != nil {
+		tb.Fatal(err)
+	}
+	return r.QueryCount() - before
+}
+
+// Reads must run a fixed number of queries whatever the number of rows, related users are joined rather than loaded per row
+func RunQueryCountSuite(t *testing.T, factory Factory) {
+	ctx := context.Background()
+	r := factory(t)
+	if r.QueryCount == nil {
+		t.Skip("backend does not count queries")
+	}
+	users := r.seedFeed(t, 20)
+
+	t.Run("FindFeed", func(t *testing.T) {
+		small := r.countQueries(t, func() error {
+			_, _, err := r.Post.FindFeed(ctx, users.alice.ID.String(), 1, 0)
+			return err
+		})
+		large := r.countQueries(t, func() error {
+			feed, _, err := r.Post.FindFeed(ctx, users.alice.ID.String(), 60, 0)
+			if err == nil && len(feed) != 60 {
+				return fmt.Errorf("got %d feed entries, want 60", len(feed))
+			}
+			return err
+		})
+		if small != large {
+			t.Fatalf("a page of 1 ran %d queries, a page of 60 ran %d", small, large)
+		}
+	})
+
+	for name, find := range map[string]func(userID string) error{
+		"Favorite.FindByUserID": func(userID string) error {
+			_, _, err := r.Favorite.FindByUserID(ctx, userID, &userID, allRows)
+			return err
+		},
+		"Repost.FindByUserID": func(userID string) error {
+			_, _, err := r.Repost.FindByUserID(ctx, userID, &userID, allRows)
+			return err
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			one := r.countQueries(t, func() error { return find(users.dave.ID.String()) })
+			many := r.countQueries(t, func() error { return find(users.alice.ID.String()) })
+			if one != many {
+				t.Fatalf("1 row ran %d queries, 20 rows ran %d", one, many)
+			}
+		})
+	}
+}
+
+// Reports the queries/op of the feed by page size and of the favorite and repost lists by rows, which should not change
+// with either. r must be backed by an empty database
+func BenchmarkReads(b *testing.B, r Repositories) {
+	ctx := context.Background()
+	users := r.seedFeed(b, 100)
+
+	run := func(b *testing.B, fn func() error) {
+		start := r.QueryCount()
+		for i := 0; i < b.N; i++ {
+			if err := fn(); err != nil {
+				b.Fatal(err)
+			}
+		}
+		b.ReportMetric(float64(r.QueryCount()-start)/float64(b.N), "queries/op")
+	}
+
+	for _, size := range []int{10, 50, 100} {
+		b.Run(fmt.Sprintf("FindFeed/pageSize=%d", size), func(b *testing.B) {
+			run(b, func() error {
+				_, _, err := r.Post.FindFeed(ctx, users.alice.ID.String(), size, 0)
+				return err
+			})
+		})
+	}
+
+	for _, u := range []struct {
+		rows int
+		user *entity.User
+	}{{1, users.dave}, {100, users.alice}} {
+		b.Run(fmt.Sprintf("Favorite.FindByUserID/rows=%d", u.rows), func(b *testing.B) {
+			run(b, func() error {
+				_, _, err := r.Favorite.FindByUserID(ctx, u.user.ID.String(), ptr(u.user.ID.String()), allRows)
+				return err
+			})
+		})
+		b.Run(fmt.Sprintf("Repost.FindByUserID/rows=%d", u.rows), func(b *testing.B) {
+			run(b, func() error {
+				_, _, err := r.Repost.FindByUserID(ctx, u.user.ID.String(), ptr(u.user.ID.String()), allRows)
+				return err
+			})
+		})
+	}
+}
@@ -0,0 +1,230 @@
+// Package repositorytest is a backend agnostic conformance suite for the repository interfaces.
+// Every persistence backend runs it from its own tests, such that they all behave the same way.
+package repositorytest
+
+import (
+	"context"
+	"social-media-go-ddd/internal/domain/dto"
+	"social-media-go-ddd/internal/domain/entity"
+	"social-media-go-ddd/internal/domain/repository"
+	"social-media-go-ddd/internal/domain/valueobject"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+type Repositories struct {
+	User       repository.UserRepository
+	Session    repository.SessionRepository
+	Post       repository.PostRepository
+	Like       repository.LikeRepository
+	Favorite   repository.FavoriteRepository
+	Repost     repository.RepostRepository
+	Follow     repository.FollowRepository
+	Poll       repository.PollRepository
+	PollVote   repository.PollVoteRepository
+	Pin        repository.PinnedPostRepository
+	Collection repository.CollectionRepository
+	// Smallest difference between two created_at values the backend can store, eg 1s for DATETIME in MySQL.
+	// The suite waits that long between writes whose order it asserts on.
+	TimestampResolution time.Duration
+	// Number of queries run so far, nil for backends without SQL
+	QueryCount func() int
+}
+
+// Factory returns repositories backed by a fresh, empty database for each test
+type Factory func(t *testing.T) Repositories
+
+// Run every suite
+func RunAll(t *testing.T, factory Factory) {
+	t.Run("User", func(t *testing.T) { RunUserRepositorySuite(t, factory) })
+	t.Run("Session", func(t *testing.T) { RunSessionRepositorySuite(t, factory) })
+	t.Run("Post", func(t *testing.T) { RunPostRepositorySuite(t, factory) })
+	t.Run("Like", func(t *testing.T) { RunLikeRepositorySuite(t, factory) })
+	t.Run("Favorite", func(t *testing.T) { RunFavoriteRepositorySuite(t, factory) })
+	t.Run("Repost", func(t *testing.T) { RunRepostRepositorySuite(t, factory) })
+	t.Run("Follow", func(t *testing.T) { RunFollowRepositorySuite(t, factory) })
+	t.Run("Poll", func(t *testing.T) { RunPollRepositorySuite(t, factory) })
+	t.Run("PinnedPost", func(t *testing.T) { RunPinnedPostRepositorySuite(t, factory) })
+	t.Run("Collection", func(t *testing.T) { RunCollectionRepositorySuite(t, factory) })
+	t.Run("QueryCount", func(t *testing.T) { RunQueryCountSuite(t, factory) })
+}
+
+var (
+	passwordHashOnce sync.Once
+	passwordHash     string
+)
+
+const testPassword = "password123"
+
+// bcrypt with the default cost takes tens of milliseconds, hash once with the minimum cost instead
+func testPasswordHash(t testing.TB) valueobject.Password {
+	t.Helper()
+
+	passwordHashOnce.Do(func() {
+		b, err := bcrypt.GenerateFromPassword([]byte(testPassword), bcrypt.MinCost)
+		if err != nil {
+			panic(err)
+		}
+		passwordHash = string(b)
+	})
+	return valueobject.PasswordFromHash(passwordHash)
+}
+
+// Wait such that the next write gets a strictly later created_at than the previous one
+func (r Repositories) tick() {
+	time.Sleep(max(r.TimestampResolution, time.Millisecond) + time.Millisecond)
+}
+
+func (r Repositories) createUser(t testing.TB, username string) *entity.User {
+	t.Helper()
+
+	user := &entity.User{
+		BaseEntity: entity.NewBaseEntity(),
+		Username:   username,
+		Email:      username + "@example.com",
+		Password:   testPasswordHash(t),
+	}
+	if err := r.User.Save(context.Background(), user); err != nil {
+		t.Fatalf("save user %s: %v", username, err)
+	}
+	return user
+}
+
+func (r Repositories) createPost(t testing.TB, user *entity.User, content string) *entity.Post {
+	t.Helper()
+
+	post, err := entity.NewPost(dto.NewPost{UserID: user.ID, Content: content})
+	if err != nil {
+		t.Fatalf("new post: %v", err)
+	}
+	if err := r.Post.Save(context.Background(), post); err != nil {
+		t.Fatalf("save post: %v", err)
+	}
+	return post
+}
+
+func (r Repositories) createPostVisibleTo(t testing.TB, user *entity.User, content string, visibility entity.PostVisibility) *entity.Post {
+	t.Helper()
+
+	post, err := entity.NewPost(dto.NewPost{UserID: user.ID, Content: content, Visibility: string(visibility)})
+	if err != nil {
+		t.Fatalf("new post: %v", err)
+	}
+	if err := r.Post.Save(context.Background(), post); err != nil {
+		t.Fatalf("save post: %v", err)
+	}
+	return post
+}
+
+// Saves a published post of user quoting quoted
+func (r Repositories) createQuote(t testing.TB, user *entity.User, quoted *entity.Post, content string) *entity.Post {
+	t.Helper()
+
+	post, err := entity.NewPost(dto.NewPost{UserID: user.ID, Content: content, QuotedPostID: &quoted.ID})
+	if err != nil {
+		t.Fatalf("new post: %v", err)
+	}
+	if err := r.Post.Save(context.Background(), post); err != nil {
+		t.Fatalf("save post: %v", err)
+	}
+	return post
+}
+
+// Saves an unpublished post, a draft when publishAt is nil and scheduled otherwise
+func (r Repositories) createDraft(t testing.TB, user *entity.User, content string, publishAt *time.Time) *entity.Post {
+	t.Helper()
+
+	post, err := entity.NewPost(dto.NewPost{UserID: user.ID, Content: content, Draft: publishAt == nil, PublishAt: publishAt})
+	if err != nil {
+		t.Fatalf("new post: %v", err)
+	}
+	if err := r.Post.Save(context.Background(), post); err != nil {
+		t.Fatalf("save post: %v", err)
+	}
+	return post
+}
+
+// Replaces the content of post by editor, post is left untouched and the edited post is returned
+func (r Repositories) edit(t testing.TB, editor *entity.User, post *entity.Post, content string) *entity.Post {
+	t.Helper()
+
+	edited, err := entity.NewPostForUpdate(post, dto.UpdatePost{Content: content})
+	if err != nil {
+		t.Fatalf("new post for update: %v", err)
+	}
+	revision, err := entity.NewPostRevision(post, edited, editor.ID)
+	if err != nil {
+		t.Fatalf("new post revision: %v", err)
+	}
+	if err := r.Post.Update(context.Background(), edited, revision); err != nil {
+		t.Fatalf("update post: %v", err)
+	}
+	return edited
+}
+
+func (r Repositories) like(t testing.TB, user *entity.User, post *entity.Post) {
+	t.Helper()
+	r.react(t, user, post, entity.ReactionLike)
+}
+
+func (r Repositories) react(t testing.TB, user *entity.User, post *entity.Post, reaction entity.Reaction) {
+	t.Helper()
+
+	like, err := entity.NewLike(dto.NewLike{UserID: user.ID, PostID: post.ID, Reaction: string(reaction)})
+	if err != nil {
+		t.Fatalf("new like: %v", err)
+	}
+	if _, err := r.Like.Save(context.Background(), like); err != nil {
+		t.Fatalf("save like: %v", err)
+	}
+}
+
+// Favorites post into the default collection of user
+func (r Repositories) favorite(t testing.TB, user *entity.User, post *entity.Post) {
+	t.Helper()
+	r.favoriteIn(t, user, post, r.defaultCollection(t, user))
+}
+
+func (r Repositories) favoriteIn(t testing.TB, user *entity.User, post *entity.Post, collection *entity.Collection) {
+	t.Helper()
+
+	favorite, err := entity.NewFavorite(dto.NewFavorite{UserID: user.ID, PostID: post.ID, CollectionID: collection.ID})
+	if err != nil {
+		t.Fatalf("new favorite: %v", err)
+	}
+	if err := r.Favorite.Save(context.Background(), favorite); err != nil {
+		t.Fatalf("save favorite: %v", err)
+	}
+}
+
+func (r Repositories) repost(t testing.TB, user *entity.User, post *entity.Post, comment string) *entity.Repost {
+	t.Helper()
+
+	repost, err := entity.NewRepost(dto.NewRepost{UserID: user.ID, PostID: post.ID, Comment: comment})
+	if err != nil {
+		t.Fatalf("new repost: %v", err)
+	}
+	if err := r.Repost.Save(context.Background(), repost); err != nil {
+		t.Fatalf("save repost: %v", err)
+	}
+	return repost
+}
+
+func (r Repositories) follow(t testing.TB, follower, followee *entity.User) {
+	t.Helper()
+
+	follow, err := entity.NewFollow(dto.NewFollow{FollowerID: follower.ID, FolloweeID: followee.ID})
+	if err != nil {
+		t.Fatalf("new follow: %v", err)
+	}
+	if err := r.Follow.Save(context.Background(), follow); err != nil {
+		t.Fatalf("save follow: %v", err)
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,162 @@
+package repositorytest
+
+import (
+	"context"
+	"fmt"
+	"social-media-go-ddd/internal/domain/aggregate"
+	"social-media-go-ddd/internal/domain/dto"
+	"social-media-go-ddd/internal/domain/entity"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func RunRepostRepositorySuite(t *testing.T, factory Factory) {
+	ctx := context.Background()
+
+	t.Run("SaveAndFindByID", func(t *testing.T) {
+		r := factory(t)
+		alice := r.createUser(t, "alice")
+		post := r.createPost(t, alice, "hello")
+		repost := r.repost(t, alice, post, "my own post")
+
+		found, err := r.Repost.FindByID(ctx, repost.ID.String())
+		if err != nil {
+			t.Fatalf("find repost: %v", err)
+		}
+		if found.ID != repost.ID || found.UserID != alice.ID || found.PostID != post.ID || found.Comment != "my own post" {
+			t.Fatalf("got %+v, want %+v", found, repost)
+		}
+
+		if _, err := r.Repost.FindByID(ctx, uuid.NewString()); err == nil {
+			t.Fatal("expected error for unknown repost")
+		}
+	})
+
+	t.Run("SaveUpdatesComment", func(t *testing.T) {
+		r := factory(t)
+		alice := r.createUser(t, "alice")
+		bob := r.createUser(t, "bob")
+		post := r.createPost(t, alice, "hello")
+		first := r.repost(t, bob, post, "first")
+		r.repost(t, bob, post, "second")
+
+		found, err := r.Post.FindByID(ctx, post.ID.String(), ptr(bob.ID.String()))
+		if err != nil {
+			t.Fatalf("find post: %v", err)
+		}
+		if !found.Reposted || found.RepostCount != 1 {
+			t.Fatalf("got reposted=%v count=%d, want a single repost", found.Reposted, found.RepostCount)
+		}
+
+		// The original repost is kept with the new comment
+		repost, err := r.Repost.FindByID(ctx, first.ID.String())
+		if err != nil {
+			t.Fatalf("find repost: %v", err)
+		}
+		if repost.Comment != "second" {
+			t.Fatalf("got comment %q, want second", repost.Comment)
+		}
+	})
+
+	t.Run("SaveUnknownPost", func(t *testing.T) {
+		r := factory(t)
+		alice := r.createUser(t, "alice")
+
+		repost, err := entity.NewRepost(dto.NewRepost{UserID: alice.ID, PostID: uuid.New()})
+		if err != nil {
+			t.Fatalf("new repost: %v", err)
+		}
+		if err := r.Repost.Save(ctx, repost); err == nil {
+			t.Fatal("expected error when reposting an unknown post")
+		}
+	})
+
+	t.Run("FindByUserID", func(t *testing.T) {
+		r := factory(t)
+		alice := r.createUser(t, "alice")
+		bob := r.createUser(t, "bob")
+		carol := r.createUser(t, "carol")
+		bobPost := r.createPost(t, bob, "bob post")
+		carolPost := r.createPost(t, carol, "carol post")
+
+		r.repost(t, alice, bobPost, "")
+		r.tick()
+		r.repost(t, alice, carolPost, "nice")
+		r.repost(t, bob, carolPost, "")
+
+		posts, _, err := r.Repost.FindByUserID(ctx, alice.ID.String(), ptr(alice.ID.String()), allRows)
+		if err != nil {
+			t.Fatalf("find reposts: %v", err)
+		}
+		if len(posts) != 2 {
+			t.Fatalf("got %d reposts, want 2", len(posts))
+		}
+
+		for i, want := range []struct {
+			post  uuid.UUID
+			owner uuid.UUID
+		}{{carolPost.ID, carol.ID}, {bobPost.ID, bob.ID}} {
+			p := posts[i]
+			if p.Type != aggregate.PostTypeRepost || p.ID != want.post {
+				t.Fatalf("position %d: got %s post %s", i, p.Type, p.ID)
+			}
+			// User is the author of the original post, RepostUser whoever reposted it
+			if p.User.ID != want.owner {
+				t.Fatalf("position %d: got owner %s, want %s", i, p.User.ID, want.owner)
+			}
+			if p.RepostUser == nil || p.RepostUser.ID != alice.ID || p.RepostUser.Username != "alice" || p.Repost == nil || p.Repost.UserID != alice.ID {
+				t.Fatalf("position %d: expected repost by alice", i)
+			}
+			if !p.Reposted {
+				t.Fatalf("position %d: expected reposted flag", i)
+			}
+		}
+		if posts[0].Repost.Comment != "nice" || posts[0].RepostCount != 2 {
+			t.Fatalf("got comment %q count %d, want nice and 2", posts[0].Repost.Comment, posts[0].RepostCount)
+		}
+	})
+
+	t.Run("FindByUserIDWithoutReposts", func(t *testing.T) {
+		r := factory(t)
+		alice := r.createUser(t, "alice")
+
+		posts, _, err := r.Repost.FindByUserID(ctx, alice.ID.String(), ptr(alice.ID.String()), allRows)
+		if err != nil || len(posts) != 0 {
+			t.Fatalf("got %d reposts err %v, want none", len(posts), err)
+		}
+		if _, _, err := r.Repost.FindByUserID(ctx, uuid.NewString(), ptr(uuid.NewString()), allRows); err == nil {
+			t.Fatal("expected error for unknown user")
+		}
+	})
+
+	t.Run("FindByUserIDPages", func(t *testing.T) {
+		r := factory(t)
+		alice := r.createUser(t, "alice")
+		bob := r.createUser(t, "bob")
+		for i := range 5 {
+			r.repost(t, alice, r.createPost(t, bob, fmt.Sprintf("post %d", i)), "")
+		}
+
+		assertCursorPages(t, 2, 5, func(page dto.CursorPage) ([]*aggregate.Post, *dto.Cursor, error) {
+			return r.Repost.FindByUserID(ctx, alice.ID.String(), nil, page)
+		})
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		r := factory(t)
+		alice := r.createUser(t, "alice")
+		post := r.createPost(t, alice, "hello")
+		repost := r.repost(t, alice, post, "")
+
+		if err := r.Repost.Delete(ctx, alice.ID.String(), post.ID.String()); err != nil {
+			t.Fatalf("delete repost: %v", err)
+		}
+		if err := r.Repost.Delete(ctx, alice.ID.String(), post.ID.String()); err != nil {
+			t.Fatalf("delete repost again: %v", err)
+		}
+		if _, err := r.Repost.FindByID(ctx, repost.ID.String()); err == nil {
+			t.Fatal("expected error for deleted repost")
+		}
+	})
+}
@@ -0,0 +1,91 @@
+package repositorytest
+
+import (
+	"context"
+	"social-media-go-ddd/internal/domain/dto"
+	"social-media-go-ddd/internal/domain/entity"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func RunSessionRepositorySuite(t *testing.T, factory Factory) {
+	ctx := context.Background()
+
+	newSession := func(t *testing.T, r Repositories) *entity.Session {
+		t.Helper()
+
+		user := r.createUser(t, "alice")
+		session, err := entity.NewSession(dto.NewSession{UserID: user.ID, ExpireAt: time.Now().Add(time.Hour)})
+		if err != nil {
+			t.Fatalf("new session: %v", err)
+		}
+		if err := r.Session.Save(ctx, session); err != nil {
+			t.Fatalf("save session: %v", err)
+		}
+		return session
+	}
+
+	t.Run("SaveAndFindByID", func(t *testing.T) {
+		r := factory(t)
+		session := newSession(t, r)
+
+		found, err := r.Session.FindByID(ctx, session.ID.String())
+		if err != nil {
+			t.Fatalf("find session: %v", err)
+		}
+		if found.ID != session.ID || found.UserID != session.UserID {
+			t.Fatalf("got %+v, want %+v", found, session)
+		}
+		// Backends store timestamps with different precisions
+		if d := found.ExpireAt.Sub(session.ExpireAt).Abs(); d > time.Second {
+			t.Fatalf("expire_at off by %s", d)
+		}
+	})
+
+	t.Run("FindByIDNotFound", func(t *testing.T) {
+		r := factory(t)
+
+		if _, err := r.Session.FindByID(ctx, uuid.NewString()); err == nil {
+			t.Fatal("expected error for unknown session")
+		}
+	})
+
+	t.Run("UpdateExpireAt", func(t *testing.T) {
+		r := factory(t)
+		session := newSession(t, r)
+
+		expireAt := time.Now().Add(48 * time.Hour)
+		if err := session.UpdateExpireAt(expireAt); err != nil {
+			t.Fatalf("update expire_at: %v", err)
+		}
+		if err := r.Session.UpdateExpireAt(ctx, session); err != nil {
+			t.Fatalf("save expire_at: %v", err)
+		}
+
+		found, err := r.Session.FindByID(ctx, session.ID.String())
+		if err != nil {
+			t.Fatalf("find session: %v", err)
+		}
+		if d := found.ExpireAt.Sub(expireAt).Abs(); d > time.Second {
+			t.Fatalf("expire_at off by %s", d)
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		r := factory(t)
+		session := newSession(t, r)
+
+		if err := r.Session.Delete(ctx, session.ID.String()); err != nil {
+			t.Fatalf("delete session: %v", err)
+		}
+		if _, err := r.Session.FindByID(ctx, session.ID.String()); err == nil {
+			t.Fatal("expected error for deleted session")
+		}
+		// Deleting twice is not an error
+		if err := r.Session.Delete(ctx, session.ID.String()); err != nil {
+			t.Fatalf("delete session again: %v", err)
+		}
+	})
+}
@@ -0,0 +1,156 @@
+package repositorytest
+
+import (
+	"context"
+	"social-media-go-ddd/internal/domain/entity"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func RunUserRepositorySuite(t *testing.T, factory Factory) {
+	ctx := context.Background()
+
+	t.Run("SaveAndFindByID", func(t *testing.T) {
+		r := factory(t)
+		user := r.createUser(t, "alice")
+
+		found, err := r.User.FindByID(ctx, user.ID.String(), nil)
+		if err != nil {
+			t.Fatalf("find user: %v", err)
+		}
+		if found.ID != user.ID || found.Username != user.Username || found.Email != user.Email {
+			t.Fatalf("got %+v, want %+v", found, user)
+		}
+		if !found.Password.Match(testPassword) {
+			t.Fatal("stored password hash does not match")
+		}
+		if found.Followed || found.FollowerCount != 0 || found.FollowingCount != 0 {
+			t.Fatalf("fresh user should have no follow stats, got %+v", found)
+		}
+	})
+
+	t.Run("FindByIDNotFound", func(t *testing.T) {
+		r := factory(t)
+
+		if _, err := r.User.FindByID(ctx, uuid.NewString(), nil); err == nil {
+			t.Fatal("expected error for unknown user")
+		}
+	})
+
+	t.Run("DuplicateUsername", func(t *testing.T) {
+		r := factory(t)
+		r.createUser(t, "alice")
+
+		dup := &entity.User{
+			BaseEntity: entity.NewBaseEntity(),
+			Username:   "alice",
+			Email:      "other@example.com",
+			Password:   testPasswordHash(t),
+		}
+		if err := r.User.Save(ctx, dup); err == nil {
+			t.Fatal("expected error for duplicate username")
+		}
+	})
+
+	t.Run("DuplicateEmail", func(t *testing.T) {
+		r := factory(t)
+		r.createUser(t, "alice")
+
+		dup := &entity.User{
+			BaseEntity: entity.NewBaseEntity(),
+			Username:   "bob",
+			Email:      "alice@example.com",
+			Password:   testPasswordHash(t),
+		}
+		if err := r.User.Save(ctx, dup); err == nil {
+			t.Fatal("expected error for duplicate email")
+		}
+	})
+
+	t.Run("FindByName", func(t *testing.T) {
+		r := factory(t)
+		user := r.createUser(t, "alice")
+
+		found, err := r.User.FindByName(ctx, "alice", nil)
+		if err != nil {
+			t.Fatalf("find user by name: %v", err)
+		}
+		if found.ID != user.ID {
+			t.Fatalf("got user %s, want %s", found.ID, user.ID)
+		}
+
+		if _, err := r.User.FindByName(ctx, "nobody", nil); err == nil {
+			t.Fatal("expected error for unknown username")
+		}
+	})
+
+	t.Run("FollowStats", func(t *testing.T) {
+		r := factory(t)
+		alice := r.createUser(t, "alice")
+		bob := r.createUser(t, "bob")
+		carol := r.createUser(t, "carol")
+		r.follow(t, bob, alice)
+		r.follow(t, carol, alice)
+		r.follow(t, alice, bob)
+
+		found, err := r.User.FindByID(ctx, alice.ID.String(), ptr(bob.ID.String()))
+		if err != nil {
+			t.Fatalf("find user: %v", err)
+		}
+		if !found.Followed {
+			t.Fatal("bob follows alice, expected followed")
+		}
+		if found.FollowerCount != 2 || found.FollowingCount != 1 {
+			t.Fatalf("got %d followers %d following, want 2 and 1", found.FollowerCount, found.FollowingCount)
+		}
+
+		found, err = r.User.FindByName(ctx, "bob", ptr(carol.ID.String()))
+		if err != nil {
+			t.Fatalf("find user by name: %v", err)
+		}
+		if found.Followed {
+			t.Fatal("carol does not follow bob, expected not followed")
+		}
+		if found.FollowerCount != 1 || found.FollowingCount != 1 {
+			t.Fatalf("got %d followers %d following, want 1 and 1", found.FollowerCount, found.FollowingCount)
+		}
+	})
+
+	t.Run("SearchManyByName", func(t *testing.T) {
+		r := factory(t)
+		alice := r.createUser(t, "alice")
+		r.createUser(t, "alicia")
+		r.createUser(t, "bob")
+		alfred := r.createUser(t, "alfred")
+		r.follow(t, alice, alfred)
+
+		users, err := r.User.SearchManyByName(ctx, "ALI", ptr(alice.ID.String()))
+		if err != nil {
+			t.Fatalf("search users: %v", err)
+		}
+		names := map[string]bool{}
+		for _, u := range users {
+			names[u.Username] = true
+		}
+		if len(users) != 2 || !names["alice"] || !names["alicia"] {
+			t.Fatalf("got %v, want alice and alicia", names)
+		}
+
+		users, err = r.User.SearchManyByName(ctx, "alf", ptr(alice.ID.String()))
+		if err != nil {
+			t.Fatalf("search users: %v", err)
+		}
+		if len(users) != 1 || !users[0].Followed || users[0].FollowerCount != 1 {
+			t.Fatalf("expected alfred followed by alice, got %+v", users)
+		}
+
+		users, err = r.User.SearchManyByName(ctx, "zed", nil)
+		if err != nil {
+			t.Fatalf("search users: %v", err)
+		}
+		if len(users) != 0 {
+			t.Fatalf("expected no users, got %d", len(users))
+		}
+	})
+}
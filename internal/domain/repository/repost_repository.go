@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"social-media-go-ddd/internal/domain/aggregate"
+	"social-media-go-ddd/internal/domain/dto"
 	"social-media-go-ddd/internal/domain/entity"
 )
 
@@ -11,5 +12,6 @@ type RepostRepository interface {
 	// Should delete all reposts in db by user id and post id because one person should be able to repost only one post
 	Delete(ctx context.Context, userID string, postID string) error
 	FindByID(ctx context.Context, id string) (*entity.Repost, error)
-	FindByUserID(ctx context.Context, userID string) ([]*aggregate.Post, error)
+	// Reposts of the user visible to currentUserID, newest first
+	FindByUserID(ctx context.Context, userID string, currentUserID *string, page dto.CursorPage) ([]*aggregate.Post, *dto.Cursor, error)
 }
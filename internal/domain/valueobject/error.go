@@ -5,12 +5,26 @@ import (
 	"strconv"
 )
 
+// Matched with errors.Is, the errors returned by ErrPwMaxLength and ErrPwMinLength wrap them
+var (
+	ErrPwTooLong  = errors.New("password too long")
+	ErrPwTooShort = errors.New("password too short")
+)
+
+type pwLengthError struct {
+	msg  string
+	kind error
+}
+
+func (e pwLengthError) Error() string { return e.msg }
+func (e pwLengthError) Unwrap() error { return e.kind }
+
 func ErrPwMaxLength(max int) error {
-	return errors.New("password length must not exceed " + strconv.Itoa(max) + " character")
+	return pwLengthError{msg: "password length must not exceed " + strconv.Itoa(max) + " character", kind: ErrPwTooLong}
 }
 
 func ErrPwMinLength(min int) error {
-	return errors.New("password length must at least be " + strconv.Itoa(min) + " character long")
+	return pwLengthError{msg: "password length must at least be " + strconv.Itoa(min) + " character long", kind: ErrPwTooShort}
 }
 
 var ErrPwEmpty = errors.New("password must not be empty")
@@ -3,6 +3,7 @@ package cache
 import (
 	"context"
 	"errors"
+	"strings"
 	"time"
 )
 
@@ -11,12 +12,22 @@ var ErrCacheMiss = errors.New("cache: key not found")
 type Cache interface {
 	Get(ctx context.Context, key string) (string, error)
 	Set(ctx context.Context, key string, value any, expiration time.Duration) error
+	// Set only when key does not exist yet, false when it does. Atomic such that it can serve as a lock
+	SetNX(ctx context.Context, key string, value any, expiration time.Duration) (bool, error)
 	Delete(ctx context.Context, key string) error
 	// usage pattern could be "user:feed:%s:%d:%d" and we delete by passing "user:feed:*"
 	DeleteByPattern(ctx context.Context, pattern string) error
 	Close() error
 }
 
+// Key without its last segment, eg "session" for "session:<token>", safe to log since ids and tokens are left out
+func KeyPrefix(key string) string {
+	if i := strings.LastIndex(key, ":"); i >= 0 {
+		return key[:i]
+	}
+	return key
+}
+
 func IsCacheMiss(err error) bool {
 	return errors.Is(err, ErrCacheMiss)
 }
@@ -0,0 +1,77 @@
+package cache
+
+import (
+	"context"
+	"social-media-go-ddd/internal/infrastructure/metrics"
+	"social-media-go-ddd/internal/infrastructure/tracing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Counts the operations of the wrapped cache by result, a get is a hit, a miss or an error, and starts a span for each.
+// Spans carry the key prefix only, keys hold ids and session tokens
+type InstrumentedCache struct {
+	next    Cache
+	metrics *metrics.Metrics
+}
+
+func NewInstrumentedCache(next Cache, m *metrics.Metrics) *InstrumentedCache {
+	return &InstrumentedCache{next: next, metrics: m}
+}
+
+func (c *InstrumentedCache) Get(ctx context.Context, key string) (string, error) {
+	ctx, span := tracing.StartCache(ctx, "get", KeyPrefix(key))
+	val, err := c.next.Get(ctx, key)
+	span.SetAttributes(attribute.Bool("cache.hit", err == nil))
+	if IsCacheMiss(err) {
+		span.End()
+	} else {
+		tracing.End(span, err)
+	}
+	result := metrics.ResultHit
+	switch {
+	case IsCacheMiss(err):
+		result = metrics.ResultMiss
+	case err != nil:
+		result = metrics.ResultError
+	}
+	c.metrics.CacheOperations.WithLabelValues("get", result).Inc()
+	return val, err
+}
+
+func (c *InstrumentedCache) Set(ctx context.Context, key string, value any, expiration time.Duration) error {
+	ctx, span := tracing.StartCache(ctx, "set", KeyPrefix(key))
+	err := c.next.Set(ctx, key, value, expiration)
+	tracing.End(span, err)
+	c.metrics.CacheOperations.WithLabelValues("set", metrics.Result(err)).Inc()
+	return err
+}
+
+func (c *InstrumentedCache) SetNX(ctx context.Context, key string, value any, expiration time.Duration) (bool, error) {
+	ctx, span := tracing.StartCache(ctx, "set_nx", KeyPrefix(key))
+	ok, err := c.next.SetNX(ctx, key, value, expiration)
+	tracing.End(span, err)
+	c.metrics.CacheOperations.WithLabelValues("set_nx", metrics.Result(err)).Inc()
+	return ok, err
+}
+
+func (c *InstrumentedCache) Delete(ctx context.Context, key string) error {
+	ctx, span := tracing.StartCache(ctx, "delete", KeyPrefix(key))
+	err := c.next.Delete(ctx, key)
+	tracing.End(span, err)
+	c.metrics.CacheOperations.WithLabelValues("delete", metrics.Result(err)).Inc()
+	return err
+}
+
+func (c *InstrumentedCache) DeleteByPattern(ctx context.Context, pattern string) error {
+	ctx, span := tracing.StartCache(ctx, "delete_by_pattern", KeyPrefix(pattern))
+	err := c.next.DeleteByPattern(ctx, pattern)
+	tracing.End(span, err)
+	c.metrics.CacheOperations.WithLabelValues("delete_by_pattern", metrics.Result(err)).Inc()
+	return err
+}
+
+func (c *InstrumentedCache) Close() error {
+	return c.next.Close()
+}
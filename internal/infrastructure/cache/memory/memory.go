@@ -0,0 +1,113 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"social-media-go-ddd/internal/infrastructure/cache"
+	"sync"
+	"time"
+)
+
+type entry struct {
+	value string
+	// zero means no expiration
+	expireAt time.Time
+}
+
+func (e entry) expired(now time.Time) bool {
+	return !e.expireAt.IsZero() && now.After(e.expireAt)
+}
+
+// MemoryCache is a process local cache.Cache, meant for tests and single instance development setups
+type MemoryCache struct {
+	mu      sync.Mutex
+	entries map[string]entry
+}
+
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{entries: make(map[string]entry)}
+}
+
+func (m *MemoryCache) Close() error {
+	return nil
+}
+
+func (m *MemoryCache) Get(ctx context.Context, key string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e, ok := m.entries[key]
+	if !ok {
+		return "", cache.ErrCacheMiss
+	}
+	if e.expired(time.Now()) {
+		delete(m.entries, key)
+		return "", cache.ErrCacheMiss
+	}
+	return e.value, nil
+}
+
+// Values are stored the way redis would stringify them
+func (m *MemoryCache) Set(ctx context.Context, key string, value any, expiration time.Duration) error {
+	e := newEntry(value, expiration)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries[key] = e
+	return nil
+}
+
+func (m *MemoryCache) SetNX(ctx context.Context, key string, value any, expiration time.Duration) (bool, error) {
+	e := newEntry(value, expiration)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if existing, ok := m.entries[key]; ok && !existing.expired(time.Now()) {
+		return false, nil
+	}
+	m.entries[key] = e
+	return true, nil
+}
+
+func newEntry(value any, expiration time.Duration) entry {
+	var s string
+	switch v := value.(type) {
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	default:
+		s = fmt.Sprint(v)
+	}
+
+	e := entry{value: s}
+	if expiration > 0 {
+		e.expireAt = time.Now().Add(expiration)
+	}
+	return e
+}
+
+func (m *MemoryCache) Delete(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.entries, key)
+	return nil
+}
+
+// pattern uses glob syntax like redis SCAN MATCH, eg "user:feed:*"
+func (m *MemoryCache) DeleteByPattern(ctx context.Context, pattern string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for key := range m.entries {
+		matched, err := path.Match(pattern, key)
+		if err != nil {
+			return err
+		}
+		if matched {
+			delete(m.entries, key)
+		}
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package redis
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"social-media-go-ddd/internal/infrastructure/cache"
 	"time"
@@ -28,6 +29,11 @@ func NewRedisCache(ctx context.Context, addr, password string, db int, keyPrefix
 	return &RedisCache{client: rdb, keyPrefix: keyPrefix}, nil
 }
 
+// Used by the readiness check
+func (r *RedisCache) Ping(ctx context.Context) error {
+	return r.client.Ping(ctx).Err()
+}
+
 func (r *RedisCache) Close() error {
 	return r.client.Close()
 }
@@ -44,6 +50,10 @@ func (r *RedisCache) Set(ctx context.Context, key string, value any, expiration
 	return r.client.Set(ctx, fmt.Sprintf("%s:%s", r.keyPrefix, key), value, expiration).Err()
 }
 
+func (r *RedisCache) SetNX(ctx context.Context, key string, value any, expiration time.Duration) (bool, error) {
+	return r.client.SetNX(ctx, fmt.Sprintf("%s:%s", r.keyPrefix, key), value, expiration).Result()
+}
+
 func (r *RedisCache) Delete(ctx context.Context, key string) error {
 	return r.client.Del(ctx, fmt.Sprintf("%s:%s", r.keyPrefix, key)).Err()
 }
@@ -56,8 +66,11 @@ func (r *RedisCache) DeleteByPattern(ctx context.Context, pattern string) error
 	// docs: https://redis.io/docs/latest/commands/scan/
 	// in conclusion, this function will delete all keys matching the given pattern
 	iter := r.client.Scan(ctx, 0, fullPattern, 0).Iterator()
+	var errs []error
 	for iter.Next(ctx) {
-		_ = r.client.Del(ctx, iter.Val()) // ignore errors
+		if err := r.client.Del(ctx, iter.Val()).Err(); err != nil {
+			errs = append(errs, err)
+		}
 	}
-	return iter.Err()
+	return errors.Join(append(errs, iter.Err())...)
 }
@@ -0,0 +1,90 @@
+// Package logging builds the slog logger of the app and carries a request scoped logger in context.Context,
+// such that handlers, services and repositories log with the request id, route and user of the request.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	FormatJSON = "json"
+	FormatText = "text"
+)
+
+// Logger writing to w, level is one of debug, info, warn or error and format is json or text
+func New(w io.Writer, level, format string) (*slog.Logger, error) {
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		return nil, fmt.Errorf("invalid log level %q: %w", level, err)
+	}
+
+	opts := &slog.HandlerOptions{Level: lvl}
+	switch strings.ToLower(format) {
+	case FormatJSON:
+		return slog.New(slog.NewJSONHandler(w, opts)), nil
+	case FormatText:
+		return slog.New(slog.NewTextHandler(w, opts)), nil
+	default:
+		return nil, fmt.Errorf("invalid log format %q, want %s or %s", format, FormatJSON, FormatText)
+	}
+}
+
+type scopeKey struct{}
+
+// Logger of a request, attributes may be added after the context is created, eg the user once authenticated
+type scope struct {
+	logger *slog.Logger
+	mu     sync.Mutex
+	attrs  []any
+}
+
+func NewContext(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, scopeKey{}, &scope{logger: logger})
+}
+
+// Logger of the context with every attribute added by AddAttrs, slog.Default() when the context has none
+func FromContext(ctx context.Context) *slog.Logger {
+	s, ok := ctx.Value(scopeKey{}).(*scope)
+	if !ok {
+		return slog.Default()
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.logger.With(s.attrs...)
+}
+
+// Adds attributes to every line logged through FromContext afterwards, a no-op when the context has no logger.
+// slog.LogValuer attributes are resolved on every FromContext call
+func AddAttrs(ctx context.Context, attrs ...slog.Attr) {
+	s, ok := ctx.Value(scopeKey{}).(*scope)
+	if !ok {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, a := range attrs {
+		s.attrs = append(s.attrs, a)
+	}
+}
+
+// Debug line for a database query, the sql is collapsed to a single line
+func LogQuery(ctx context.Context, sql string, start time.Time, err error) {
+	logger := FromContext(ctx)
+	if !logger.Enabled(ctx, slog.LevelDebug) {
+		return
+	}
+	attrs := []any{
+		slog.String("sql", strings.Join(strings.Fields(sql), " ")),
+		slog.Duration("duration", time.Since(start)),
+	}
+	if err != nil {
+		attrs = append(attrs, slog.Any("error", err))
+	}
+	logger.DebugContext(ctx, "query", attrs...)
+}
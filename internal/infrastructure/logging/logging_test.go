@@ -0,0 +1,64 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func TestNew(t *testing.T) {
+	for _, tc := range []struct {
+		level, format string
+		ok            bool
+	}{
+		{"info", "json", true},
+		{"DEBUG", "text", true},
+		{"warn", "JSON", true},
+		{"verbose", "json", false},
+		{"info", "xml", false},
+	} {
+		_, err := New(&bytes.Buffer{}, tc.level, tc.format)
+		if (err == nil) != tc.ok {
+			t.Errorf("New(%q, %q) error %v", tc.level, tc.format, err)
+		}
+	}
+}
+
+func TestContext(t *testing.T) {
+	if FromContext(context.Background()) != slog.Default() {
+		t.Fatal("context without logger should use the default logger")
+	}
+	// A no-op without logger
+	AddAttrs(context.Background(), slog.String("user_id", "u1"))
+
+	var buf bytes.Buffer
+	logger, err := New(&buf, "debug", "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := NewContext(context.Background(), logger.With("request_id", "r1"))
+	AddAttrs(ctx, slog.String("user_id", "u1"))
+	LogQuery(ctx, "SELECT *\n\t\tFROM posts", time.Now(), errors.New("boom"))
+
+	var line map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &line); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{"msg": "query", "request_id": "r1", "user_id": "u1", "sql": "SELECT * FROM posts", "error": "boom"}
+	for k, v := range want {
+		if line[k] != v {
+			t.Errorf("%s = %v, want %v", k, line[k], v)
+		}
+	}
+
+	buf.Reset()
+	logger, _ = New(&buf, "info", "json")
+	LogQuery(NewContext(context.Background(), logger), "SELECT 1", time.Now(), nil)
+	if buf.Len() != 0 {
+		t.Fatalf("query logged above debug level: %s", buf.String())
+	}
+}
@@ -0,0 +1,158 @@
+// Package metrics holds the Prometheus collectors of the app, exposed at /metrics.
+package metrics
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+// Metrics of the app, recorded by the http middleware and the repository and cache decorators
+type Metrics struct {
+	Registry *prometheus.Registry
+
+	// Labels method, route and status, route is the template such as /api/v1/posts/:id
+	HTTPRequestDuration *prometheus.HistogramVec
+	// Labels repository, method and result (ok or error)
+	QueryDuration *prometheus.HistogramVec
+	// Labels operation (get, set, delete, delete_by_pattern) and result (hit, miss, ok or error)
+	CacheOperations *prometheus.CounterVec
+
+	PostsCreated   prometheus.Counter
+	LikesCreated   prometheus.Counter
+	FollowsCreated prometheus.Counter
+	// Label result (succeeded or failed)
+	Logins *prometheus.CounterVec
+}
+
+const (
+	ResultOK    = "ok"
+	ResultError = "error"
+	ResultHit   = "hit"
+	ResultMiss  = "miss"
+
+	LoginSucceeded = "succeeded"
+	LoginFailed    = "failed"
+)
+
+// Metrics in a new registry, along with the go runtime and process metrics
+func NewMetrics() *Metrics {
+	m := &Metrics{
+		Registry: prometheus.NewRegistry(),
+		HTTPRequestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name: "http_request_duration_seconds",
+			Help: "Duration of HTTP requests by route template.",
+		}, []string{"method", "route", "status"}),
+		QueryDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name: "db_query_duration_seconds",
+			Help: "Duration of repository calls.",
+		}, []string{"repository", "method", "result"}),
+		CacheOperations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "cache_operations_total",
+			Help: "Cache operations by result, the hit ratio is hit / (hit + miss).",
+		}, []string{"operation", "result"}),
+		PostsCreated: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "posts_created_total",
+			Help: "Posts created.",
+		}),
+		LikesCreated: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "likes_created_total",
+			Help: "Likes given.",
+		}),
+		FollowsCreated: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "follows_created_total",
+			Help: "Follows created.",
+		}),
+		Logins: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "logins_total",
+			Help: "Login attempts by result.",
+		}, []string{"result"}),
+	}
+	m.Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.HTTPRequestDuration,
+		m.QueryDuration,
+		m.CacheOperations,
+		m.PostsCreated,
+		m.LikesCreated,
+		m.FollowsCreated,
+		m.Logins,
+	)
+	// Exposed as 0 before the first login such that rates work from the start
+	m.Logins.WithLabelValues(LoginSucceeded)
+	m.Logins.WithLabelValues(LoginFailed)
+	return m
+}
+
+// Result label of err
+func Result(err error) string {
+	if err != nil {
+		return ResultError
+	}
+	return ResultOK
+}
+
+func (m *Metrics) ObserveQuery(repository, method string, start time.Time, err error) {
+	m.QueryDuration.WithLabelValues(repository, method, Result(err)).Observe(time.Since(start).Seconds())
+}
+
+func (m *Metrics) Login(result string) {
+	m.Logins.WithLabelValues(result).Inc()
+}
+
+// Connection stats of a database/sql pool as go_sql_* metrics, used by mysql and sqlite
+func (m *Metrics) RegisterSQLDB(db *sql.DB, name string) {
+	m.Registry.MustRegister(collectors.NewDBStatsCollector(db, name))
+}
+
+// Connection stats of the postgres pool as pgxpool_* metrics
+func (m *Metrics) RegisterPgxPool(pool *pgxpool.Pool) {
+	m.Registry.MustRegister(newPgxPoolCollector(pool))
+}
+
+type pgxPoolCollector struct {
+	pool *pgxpool.Pool
+
+	maxConns        *prometheus.Desc
+	totalConns      *prometheus.Desc
+	acquiredConns   *prometheus.Desc
+	idleConns       *prometheus.Desc
+	acquireCount    *prometheus.Desc
+	emptyAcquire    *prometheus.Desc
+	emptyAcquireDur *prometheus.Desc
+	canceledAcquire *prometheus.Desc
+}
+
+func newPgxPoolCollector(pool *pgxpool.Pool) *pgxPoolCollector {
+	return &pgxPoolCollector{
+		pool:            pool,
+		maxConns:        prometheus.NewDesc("pgxpool_max_conns", "Maximum size of the pool.", nil, nil),
+		totalConns:      prometheus.NewDesc("pgxpool_total_conns", "Connections in the pool, acquired, idle and constructing.", nil, nil),
+		acquiredConns:   prometheus.NewDesc("pgxpool_acquired_conns", "Connections in use.", nil, nil),
+		idleConns:       prometheus.NewDesc("pgxpool_idle_conns", "Idle connections.", nil, nil),
+		acquireCount:    prometheus.NewDesc("pgxpool_acquire_total", "Connections acquired from the pool.", nil, nil),
+		emptyAcquire:    prometheus.NewDesc("pgxpool_empty_acquire_total", "Acquires that had to wait for a connection.", nil, nil),
+		emptyAcquireDur: prometheus.NewDesc("pgxpool_empty_acquire_wait_seconds_total", "Time spent waiting for a connection.", nil, nil),
+		canceledAcquire: prometheus.NewDesc("pgxpool_canceled_acquire_total", "Acquires canceled by their context.", nil, nil),
+	}
+}
+
+func (c *pgxPoolCollector) Describe(ch chan<- *prometheus.Desc) {
+	prometheus.DescribeByCollect(c, ch)
+}
+
+func (c *pgxPoolCollector) Collect(ch chan<- prometheus.Metric) {
+	s := c.pool.Stat()
+	ch <- prometheus.MustNewConstMetric(c.maxConns, prometheus.GaugeValue, float64(s.MaxConns()))
+	ch <- prometheus.MustNewConstMetric(c.totalConns, prometheus.GaugeValue, float64(s.TotalConns()))
+	ch <- prometheus.MustNewConstMetric(c.acquiredConns, prometheus.GaugeValue, float64(s.AcquiredConns()))
+	ch <- prometheus.MustNewConstMetric(c.idleConns, prometheus.GaugeValue, float64(s.IdleConns()))
+	ch <- prometheus.MustNewConstMetric(c.acquireCount, prometheus.CounterValue, float64(s.AcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.emptyAcquire, prometheus.CounterValue, float64(s.EmptyAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.emptyAcquireDur, prometheus.CounterValue, s.EmptyAcquireWaitTime().Seconds())
+	ch <- prometheus.MustNewConstMetric(c.canceledAcquire, prometheus.CounterValue, float64(s.CanceledAcquireCount()))
+}
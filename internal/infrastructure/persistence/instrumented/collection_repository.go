@@ -0,0 +1,57 @@
+package instrumented
+
+import (
+	"context"
+	"social-media-go-ddd/internal/domain/entity"
+	"social-media-go-ddd/internal/domain/repository"
+	"social-media-go-ddd/internal/infrastructure/metrics"
+)
+
+type CollectionRepository struct {
+	next    repository.CollectionRepository
+	metrics *metrics.Metrics
+}
+
+func NewCollectionRepository(next repository.CollectionRepository, m *metrics.Metrics) *CollectionRepository {
+	return &CollectionRepository{next: next, metrics: m}
+}
+
+func (r *CollectionRepository) Save(ctx context.Context, c *entity.Collection) (err error) {
+	ctx, end := r.start(ctx, "Save")
+	defer end(&err)
+	return r.next.Save(ctx, c)
+}
+
+func (r *CollectionRepository) Update(ctx context.Context, c *entity.Collection) (err error) {
+	ctx, end := r.start(ctx, "Update")
+	defer end(&err)
+	return r.next.Update(ctx, c)
+}
+
+func (r *CollectionRepository) Delete(ctx context.Context, id string, userID string) (err error) {
+	ctx, end := r.start(ctx, "Delete")
+	defer end(&err)
+	return r.next.Delete(ctx, id, userID)
+}
+
+func (r *CollectionRepository) FindByID(ctx context.Context, id string) (_ *entity.Collection, err error) {
+	ctx, end := r.start(ctx, "FindByID")
+	defer end(&err)
+	return r.next.FindByID(ctx, id)
+}
+
+func (r *CollectionRepository) FindByUserID(ctx context.Context, userID string) (_ []*entity.Collection, err error) {
+	ctx, end := r.start(ctx, "FindByUserID")
+	defer end(&err)
+	return r.next.FindByUserID(ctx, userID)
+}
+
+func (r *CollectionRepository) FindDefault(ctx context.Context, userID string) (_ *entity.Collection, err error) {
+	ctx, end := r.start(ctx, "FindDefault")
+	defer end(&err)
+	return r.next.FindDefault(ctx, userID)
+}
+
+func (r *CollectionRepository) start(ctx context.Context, method string) (context.Context, func(*error)) {
+	return start(ctx, r.metrics, "collection", method)
+}
@@ -0,0 +1,4 @@
+// Package instrumented decorates the repositories with metrics and traces, every call is timed by repository, method and result
+// and gets a span the queries it runs are children of.
+// Saves of posts, likes and follows also count the business counters.
+package instrumented
@@ -0,0 +1,53 @@
+package instrumented
+
+import (
+	"context"
+	"social-media-go-ddd/internal/domain/aggregate"
+	"social-media-go-ddd/internal/domain/dto"
+	"social-media-go-ddd/internal/domain/entity"
+	"social-media-go-ddd/internal/domain/repository"
+	"social-media-go-ddd/internal/infrastructure/metrics"
+)
+
+type FavoriteRepository struct {
+	next    repository.FavoriteRepository
+	metrics *metrics.Metrics
+}
+
+func NewFavoriteRepository(next repository.FavoriteRepository, m *metrics.Metrics) *FavoriteRepository {
+	return &FavoriteRepository{next: next, metrics: m}
+}
+
+func (r *FavoriteRepository) Save(ctx context.Context, f *entity.Favorite) (err error) {
+	ctx, end := r.start(ctx, "Save")
+	defer end(&err)
+	return r.next.Save(ctx, f)
+}
+
+func (r *FavoriteRepository) Delete(ctx context.Context, userID, postID string) (err error) {
+	ctx, end := r.start(ctx, "Delete")
+	defer end(&err)
+	return r.next.Delete(ctx, userID, postID)
+}
+
+func (r *FavoriteRepository) DeleteFromCollection(ctx context.Context, collectionID, postID string) (err error) {
+	ctx, end := r.start(ctx, "DeleteFromCollection")
+	defer end(&err)
+	return r.next.DeleteFromCollection(ctx, collectionID, postID)
+}
+
+func (r *FavoriteRepository) FindByUserID(ctx context.Context, userID string, currentUserID *string, page dto.CursorPage) (_ []*aggregate.Post, _ *dto.Cursor, err error) {
+	ctx, end := r.start(ctx, "FindByUserID")
+	defer end(&err)
+	return r.next.FindByUserID(ctx, userID, currentUserID, page)
+}
+
+func (r *FavoriteRepository) FindByCollectionID(ctx context.Context, collectionID string, currentUserID *string, page dto.CursorPage) (_ []*aggregate.Post, _ *dto.Cursor, err error) {
+	ctx, end := r.start(ctx, "FindByCollectionID")
+	defer end(&err)
+	return r.next.FindByCollectionID(ctx, collectionID, currentUserID, page)
+}
+
+func (r *FavoriteRepository) start(ctx context.Context, method string) (context.Context, func(*error)) {
+	return start(ctx, r.metrics, "favorite", method)
+}
@@ -0,0 +1,36 @@
+package instrumented
+
+import (
+	"context"
+	"social-media-go-ddd/internal/domain/entity"
+	"social-media-go-ddd/internal/domain/repository"
+	"social-media-go-ddd/internal/infrastructure/metrics"
+)
+
+type FollowRepository struct {
+	next    repository.FollowRepository
+	metrics *metrics.Metrics
+}
+
+func NewFollowRepository(next repository.FollowRepository, m *metrics.Metrics) *FollowRepository {
+	return &FollowRepository{next: next, metrics: m}
+}
+
+func (r *FollowRepository) Save(ctx context.Context, f *entity.Follow) (err error) {
+	ctx, end := r.start(ctx, "Save")
+	defer end(&err)
+	if err = r.next.Save(ctx, f); err == nil {
+		r.metrics.FollowsCreated.Inc()
+	}
+	return err
+}
+
+func (r *FollowRepository) Delete(ctx context.Context, followerID, followeeID string) (err error) {
+	ctx, end := r.start(ctx, "Delete")
+	defer end(&err)
+	return r.next.Delete(ctx, followerID, followeeID)
+}
+
+func (r *FollowRepository) start(ctx context.Context, method string) (context.Context, func(*error)) {
+	return start(ctx, r.metrics, "follow", method)
+}
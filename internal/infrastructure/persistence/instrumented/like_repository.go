@@ -0,0 +1,46 @@
+package instrumented
+
+import (
+	"context"
+	"social-media-go-ddd/internal/domain/entity"
+	"social-media-go-ddd/internal/domain/repository"
+	"social-media-go-ddd/internal/infrastructure/metrics"
+)
+
+type LikeRepository struct {
+	next    repository.LikeRepository
+	metrics *metrics.Metrics
+}
+
+func NewLikeRepository(next repository.LikeRepository, m *metrics.Metrics) *LikeRepository {
+	return &LikeRepository{next: next, metrics: m}
+}
+
+// Only a like the user did not have counts as created, not a reaction replacing another
+func (r *LikeRepository) Save(ctx context.Context, l *entity.Like) (inserted bool, err error) {
+	ctx, end := r.start(ctx, "Save")
+	defer end(&err)
+	if inserted, err = r.next.Save(ctx, l); inserted {
+		r.metrics.LikesCreated.Inc()
+	}
+	return inserted, err
+}
+
+func (r *LikeRepository) SaveIfAbsent(ctx context.Context, l *entity.Like) (inserted bool, err error) {
+	ctx, end := r.start(ctx, "SaveIfAbsent")
+	defer end(&err)
+	if inserted, err = r.next.SaveIfAbsent(ctx, l); inserted {
+		r.metrics.LikesCreated.Inc()
+	}
+	return inserted, err
+}
+
+func (r *LikeRepository) Delete(ctx context.Context, userID, postID string) (err error) {
+	ctx, end := r.start(ctx, "Delete")
+	defer end(&err)
+	return r.next.Delete(ctx, userID, postID)
+}
+
+func (r *LikeRepository) start(ctx context.Context, method string) (context.Context, func(*error)) {
+	return start(ctx, r.metrics, "like", method)
+}
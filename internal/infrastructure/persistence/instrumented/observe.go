@@ -0,0 +1,18 @@
+package instrumented
+
+import (
+	"context"
+	"social-media-go-ddd/internal/infrastructure/metrics"
+	"social-media-go-ddd/internal/infrastructure/tracing"
+	"time"
+)
+
+// Starts the span of a repository call, eg post.FindFeed, the returned func records the duration and ends the span with err
+func start(ctx context.Context, m *metrics.Metrics, repository, method string) (context.Context, func(err *error)) {
+	ctx, span := tracing.Start(ctx, repository+"."+method)
+	begin := time.Now()
+	return ctx, func(err *error) {
+		m.ObserveQuery(repository, method, begin, *err)
+		tracing.End(span, *err)
+	}
+}
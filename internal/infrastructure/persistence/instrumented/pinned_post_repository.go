@@ -0,0 +1,33 @@
+package instrumented
+
+import (
+	"context"
+	"social-media-go-ddd/internal/domain/entity"
+	"social-media-go-ddd/internal/domain/repository"
+	"social-media-go-ddd/internal/infrastructure/metrics"
+)
+
+type PinnedPostRepository struct {
+	next    repository.PinnedPostRepository
+	metrics *metrics.Metrics
+}
+
+func NewPinnedPostRepository(next repository.PinnedPostRepository, m *metrics.Metrics) *PinnedPostRepository {
+	return &PinnedPostRepository{next: next, metrics: m}
+}
+
+func (r *PinnedPostRepository) Save(ctx context.Context, p *entity.PinnedPost) (err error) {
+	ctx, end := r.start(ctx, "Save")
+	defer end(&err)
+	return r.next.Save(ctx, p)
+}
+
+func (r *PinnedPostRepository) Delete(ctx context.Context, userID, postID string) (err error) {
+	ctx, end := r.start(ctx, "Delete")
+	defer end(&err)
+	return r.next.Delete(ctx, userID, postID)
+}
+
+func (r *PinnedPostRepository) start(ctx context.Context, method string) (context.Context, func(*error)) {
+	return start(ctx, r.metrics, "pinned_post", method)
+}
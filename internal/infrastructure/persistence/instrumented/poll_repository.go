@@ -0,0 +1,34 @@
+package instrumented
+
+import (
+	"context"
+	"social-media-go-ddd/internal/domain/aggregate"
+	"social-media-go-ddd/internal/domain/entity"
+	"social-media-go-ddd/internal/domain/repository"
+	"social-media-go-ddd/internal/infrastructure/metrics"
+)
+
+type PollRepository struct {
+	next    repository.PollRepository
+	metrics *metrics.Metrics
+}
+
+func NewPollRepository(next repository.PollRepository, m *metrics.Metrics) *PollRepository {
+	return &PollRepository{next: next, metrics: m}
+}
+
+func (r *PollRepository) Save(ctx context.Context, p *entity.Poll) (err error) {
+	ctx, end := r.start(ctx, "Save")
+	defer end(&err)
+	return r.next.Save(ctx, p)
+}
+
+func (r *PollRepository) FindByPostID(ctx context.Context, postID string, currentUserID *string) (_ *aggregate.Poll, err error) {
+	ctx, end := r.start(ctx, "FindByPostID")
+	defer end(&err)
+	return r.next.FindByPostID(ctx, postID, currentUserID)
+}
+
+func (r *PollRepository) start(ctx context.Context, method string) (context.Context, func(*error)) {
+	return start(ctx, r.metrics, "poll", method)
+}
@@ -0,0 +1,27 @@
+package instrumented
+
+import (
+	"context"
+	"social-media-go-ddd/internal/domain/entity"
+	"social-media-go-ddd/internal/domain/repository"
+	"social-media-go-ddd/internal/infrastructure/metrics"
+)
+
+type PollVoteRepository struct {
+	next    repository.PollVoteRepository
+	metrics *metrics.Metrics
+}
+
+func NewPollVoteRepository(next repository.PollVoteRepository, m *metrics.Metrics) *PollVoteRepository {
+	return &PollVoteRepository{next: next, metrics: m}
+}
+
+func (r *PollVoteRepository) Save(ctx context.Context, v *entity.PollVote) (err error) {
+	ctx, end := r.start(ctx, "Save")
+	defer end(&err)
+	return r.next.Save(ctx, v)
+}
+
+func (r *PollVoteRepository) start(ctx context.Context, method string) (context.Context, func(*error)) {
+	return start(ctx, r.metrics, "poll_vote", method)
+}
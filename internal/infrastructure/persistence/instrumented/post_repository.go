@@ -0,0 +1,87 @@
+package instrumented
+
+import (
+	"context"
+	"social-media-go-ddd/internal/domain/aggregate"
+	"social-media-go-ddd/internal/domain/dto"
+	"social-media-go-ddd/internal/domain/entity"
+	"social-media-go-ddd/internal/domain/repository"
+	"social-media-go-ddd/internal/infrastructure/metrics"
+	"time"
+)
+
+type PostRepository struct {
+	next    repository.PostRepository
+	metrics *metrics.Metrics
+}
+
+func NewPostRepository(next repository.PostRepository, m *metrics.Metrics) *PostRepository {
+	return &PostRepository{next: next, metrics: m}
+}
+
+func (r *PostRepository) Save(ctx context.Context, p *entity.Post) (err error) {
+	ctx, end := r.start(ctx, "Save")
+	defer end(&err)
+	if err = r.next.Save(ctx, p); err == nil {
+		r.metrics.PostsCreated.Inc()
+	}
+	return err
+}
+
+func (r *PostRepository) FindByID(ctx context.Context, id string, currentUserID *string) (_ *aggregate.Post, err error) {
+	ctx, end := r.start(ctx, "FindByID")
+	defer end(&err)
+	return r.next.FindByID(ctx, id, currentUserID)
+}
+
+func (r *PostRepository) FindByUserID(ctx context.Context, userID string, currentUserID *string, page dto.CursorPage) (_ []*aggregate.Post, _ *dto.Cursor, err error) {
+	ctx, end := r.start(ctx, "FindByUserID")
+	defer end(&err)
+	return r.next.FindByUserID(ctx, userID, currentUserID, page)
+}
+
+func (r *PostRepository) Delete(ctx context.Context, id string, userID string) (err error) {
+	ctx, end := r.start(ctx, "Delete")
+	defer end(&err)
+	return r.next.Delete(ctx, id, userID)
+}
+
+func (r *PostRepository) Update(ctx context.Context, p *entity.Post, revision *entity.PostRevision) (err error) {
+	ctx, end := r.start(ctx, "Update")
+	defer end(&err)
+	return r.next.Update(ctx, p, revision)
+}
+
+func (r *PostRepository) Publish(ctx context.Context, p *entity.Post) (err error) {
+	ctx, end := r.start(ctx, "Publish")
+	defer end(&err)
+	return r.next.Publish(ctx, p)
+}
+
+func (r *PostRepository) PublishDue(ctx context.Context, now time.Time, limit int) (_ []*entity.Post, err error) {
+	ctx, end := r.start(ctx, "PublishDue")
+	defer end(&err)
+	return r.next.PublishDue(ctx, now, limit)
+}
+
+func (r *PostRepository) FindDrafts(ctx context.Context, userID string, page dto.CursorPage) (_ []*entity.Post, _ *dto.Cursor, err error) {
+	ctx, end := r.start(ctx, "FindDrafts")
+	defer end(&err)
+	return r.next.FindDrafts(ctx, userID, page)
+}
+
+func (r *PostRepository) FindRevisions(ctx context.Context, postID string) (_ []*entity.PostRevision, err error) {
+	ctx, end := r.start(ctx, "FindRevisions")
+	defer end(&err)
+	return r.next.FindRevisions(ctx, postID)
+}
+
+func (r *PostRepository) FindFeed(ctx context.Context, userID string, limit, offset int) (_ []*aggregate.Post, _ int, err error) {
+	ctx, end := r.start(ctx, "FindFeed")
+	defer end(&err)
+	return r.next.FindFeed(ctx, userID, limit, offset)
+}
+
+func (r *PostRepository) start(ctx context.Context, method string) (context.Context, func(*error)) {
+	return start(ctx, r.metrics, "post", method)
+}
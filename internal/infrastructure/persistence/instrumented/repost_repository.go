@@ -0,0 +1,47 @@
+package instrumented
+
+import (
+	"context"
+	"social-media-go-ddd/internal/domain/aggregate"
+	"social-media-go-ddd/internal/domain/dto"
+	"social-media-go-ddd/internal/domain/entity"
+	"social-media-go-ddd/internal/domain/repository"
+	"social-media-go-ddd/internal/infrastructure/metrics"
+)
+
+type RepostRepository struct {
+	next    repository.RepostRepository
+	metrics *metrics.Metrics
+}
+
+func NewRepostRepository(next repository.RepostRepository, m *metrics.Metrics) *RepostRepository {
+	return &RepostRepository{next: next, metrics: m}
+}
+
+func (r *RepostRepository) Save(ctx context.Context, rp *entity.Repost) (err error) {
+	ctx, end := r.start(ctx, "Save")
+	defer end(&err)
+	return r.next.Save(ctx, rp)
+}
+
+func (r *RepostRepository) Delete(ctx context.Context, userID string, postID string) (err error) {
+	ctx, end := r.start(ctx, "Delete")
+	defer end(&err)
+	return r.next.Delete(ctx, userID, postID)
+}
+
+func (r *RepostRepository) FindByID(ctx context.Context, id string) (_ *entity.Repost, err error) {
+	ctx, end := r.start(ctx, "FindByID")
+	defer end(&err)
+	return r.next.FindByID(ctx, id)
+}
+
+func (r *RepostRepository) FindByUserID(ctx context.Context, userID string, currentUserID *string, page dto.CursorPage) (_ []*aggregate.Post, _ *dto.Cursor, err error) {
+	ctx, end := r.start(ctx, "FindByUserID")
+	defer end(&err)
+	return r.next.FindByUserID(ctx, userID, currentUserID, page)
+}
+
+func (r *RepostRepository) start(ctx context.Context, method string) (context.Context, func(*error)) {
+	return start(ctx, r.metrics, "repost", method)
+}
@@ -0,0 +1,45 @@
+package instrumented
+
+import (
+	"context"
+	"social-media-go-ddd/internal/domain/entity"
+	"social-media-go-ddd/internal/domain/repository"
+	"social-media-go-ddd/internal/infrastructure/metrics"
+)
+
+type SessionRepository struct {
+	next    repository.SessionRepository
+	metrics *metrics.Metrics
+}
+
+func NewSessionRepository(next repository.SessionRepository, m *metrics.Metrics) *SessionRepository {
+	return &SessionRepository{next: next, metrics: m}
+}
+
+func (r *SessionRepository) Save(ctx context.Context, s *entity.Session) (err error) {
+	ctx, end := r.start(ctx, "Save")
+	defer end(&err)
+	return r.next.Save(ctx, s)
+}
+
+func (r *SessionRepository) Delete(ctx context.Context, id string) (err error) {
+	ctx, end := r.start(ctx, "Delete")
+	defer end(&err)
+	return r.next.Delete(ctx, id)
+}
+
+func (r *SessionRepository) FindByID(ctx context.Context, id string) (_ *entity.Session, err error) {
+	ctx, end := r.start(ctx, "FindByID")
+	defer end(&err)
+	return r.next.FindByID(ctx, id)
+}
+
+func (r *SessionRepository) UpdateExpireAt(ctx context.Context, s *entity.Session) (err error) {
+	ctx, end := r.start(ctx, "UpdateExpireAt")
+	defer end(&err)
+	return r.next.UpdateExpireAt(ctx, s)
+}
+
+func (r *SessionRepository) start(ctx context.Context, method string) (context.Context, func(*error)) {
+	return start(ctx, r.metrics, "session", method)
+}
@@ -0,0 +1,46 @@
+package instrumented
+
+import (
+	"context"
+	"social-media-go-ddd/internal/domain/aggregate"
+	"social-media-go-ddd/internal/domain/entity"
+	"social-media-go-ddd/internal/domain/repository"
+	"social-media-go-ddd/internal/infrastructure/metrics"
+)
+
+type UserRepository struct {
+	next    repository.UserRepository
+	metrics *metrics.Metrics
+}
+
+func NewUserRepository(next repository.UserRepository, m *metrics.Metrics) *UserRepository {
+	return &UserRepository{next: next, metrics: m}
+}
+
+func (r *UserRepository) Save(ctx context.Context, u *entity.User) (err error) {
+	ctx, end := r.start(ctx, "Save")
+	defer end(&err)
+	return r.next.Save(ctx, u)
+}
+
+func (r *UserRepository) FindByID(ctx context.Context, id string, currentUserID *string) (_ *aggregate.User, err error) {
+	ctx, end := r.start(ctx, "FindByID")
+	defer end(&err)
+	return r.next.FindByID(ctx, id, currentUserID)
+}
+
+func (r *UserRepository) FindByName(ctx context.Context, name string, currentUserID *string) (_ *aggregate.User, err error) {
+	ctx, end := r.start(ctx, "FindByName")
+	defer end(&err)
+	return r.next.FindByName(ctx, name, currentUserID)
+}
+
+func (r *UserRepository) SearchManyByName(ctx context.Context, name string, currentUserID *string) (_ []*aggregate.User, err error) {
+	ctx, end := r.start(ctx, "SearchManyByName")
+	defer end(&err)
+	return r.next.SearchManyByName(ctx, name, currentUserID)
+}
+
+func (r *UserRepository) start(ctx context.Context, method string) (context.Context, func(*error)) {
+	return start(ctx, r.metrics, "user", method)
+}
@@ -0,0 +1,9 @@
+package memory
+
+type baseMemoryRepository struct {
+	store *Store
+}
+
+func NewBaseMemoryRepository(store *Store) baseMemoryRepository {
+	return baseMemoryRepository{store: store}
+}
@@ -0,0 +1,138 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"social-media-go-ddd/internal/domain/entity"
+	"social-media-go-ddd/internal/domain/repository"
+
+	"github.com/google/uuid"
+)
+
+type MemoryCollectionRepository struct {
+	baseMemoryRepository
+}
+
+func NewMemoryCollectionRepository(store *Store) *MemoryCollectionRepository {
+	return &MemoryCollectionRepository{
+		baseMemoryRepository: NewBaseMemoryRepository(store),
+	}
+}
+
+func (r *MemoryCollectionRepository) Save(ctx context.Context, c *entity.Collection) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if !r.store.userExists(c.UserID) {
+		return repository.ErrForeignKeyViolation
+	}
+	if err := r.checkName(c); err != nil {
+		return err
+	}
+	r.store.collections[c.ID] = *c
+	return nil
+}
+
+func (r *MemoryCollectionRepository) Update(ctx context.Context, c *entity.Collection) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	existing, ok := r.store.collections[c.ID]
+	if !ok || existing.UserID != c.UserID {
+		return nil
+	}
+	if err := r.checkName(c); err != nil {
+		return err
+	}
+	existing.Name = c.Name
+	existing.UpdatedAt = c.UpdatedAt
+	r.store.collections[c.ID] = existing
+	return nil
+}
+
+// Name the column like sqlite does, such that callers can tell which field is taken
+// Caller must hold the write lock
+func (r *MemoryCollectionRepository) checkName(c *entity.Collection) error {
+	for _, existing := range r.store.collections {
+		if existing.ID != c.ID && existing.UserID == c.UserID && existing.Name == c.Name {
+			return fmt.Errorf("%w: UNIQUE constraint failed: collections.user_id, collections.name", repository.ErrUniqueViolation)
+		}
+	}
+	return nil
+}
+
+func (r *MemoryCollectionRepository) Delete(ctx context.Context, id string, userID string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	collectionID, err := uuid.Parse(id)
+	if err != nil {
+		return nil
+	}
+	c, ok := r.store.collections[collectionID]
+	if !ok || c.UserID.String() != userID || c.Default {
+		return nil
+	}
+	defaultCollection := r.store.defaultCollection(c.UserID)
+	for k, f := range r.store.favorites {
+		if f.CollectionID == c.ID {
+			f.CollectionID = defaultCollection.ID
+			r.store.favorites[k] = f
+		}
+	}
+	delete(r.store.collections, c.ID)
+	return nil
+}
+
+func (r *MemoryCollectionRepository) FindByID(ctx context.Context, id string) (*entity.Collection, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, c := range r.store.collections {
+		if c.ID.String() == id {
+			return &c, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+// Default collection first, then oldest first
+func (r *MemoryCollectionRepository) FindByUserID(ctx context.Context, userID string) ([]*entity.Collection, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var collections []*entity.Collection
+	for _, c := range r.store.collections {
+		if c.UserID.String() == userID {
+			collections = append(collections, &c)
+		}
+	}
+	slices.SortFunc(collections, func(a, b *entity.Collection) int {
+		if a.Default != b.Default {
+			if a.Default {
+				return -1
+			}
+			return 1
+		}
+		return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), cmp.Compare(a.ID.String(), b.ID.String()))
+	})
+	return collections, nil
+}
+
+func (r *MemoryCollectionRepository) FindDefault(ctx context.Context, userID string) (*entity.Collection, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, sql.ErrNoRows
+	}
+	c := r.store.defaultCollection(id)
+	if c == nil {
+		return nil, sql.ErrNoRows
+	}
+	return c, nil
+}
@@ -0,0 +1,97 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"social-media-go-ddd/internal/domain/aggregate"
+	"social-media-go-ddd/internal/domain/dto"
+	"social-media-go-ddd/internal/domain/entity"
+	"social-media-go-ddd/internal/domain/repository"
+)
+
+type MemoryFavoriteRepository struct {
+	baseMemoryRepository
+}
+
+func NewMemoryFavoriteRepository(store *Store) *MemoryFavoriteRepository {
+	return &MemoryFavoriteRepository{
+		baseMemoryRepository: NewBaseMemoryRepository(store),
+	}
+}
+
+// Moves the favorite into the collection of f if the post was favorited before
+func (r *MemoryFavoriteRepository) Save(ctx context.Context, f *entity.Favorite) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if c, ok := r.store.collections[f.CollectionID]; !ok || c.UserID != f.UserID {
+		return sql.ErrNoRows
+	}
+	for k, existing := range r.store.favorites {
+		if existing.UserID == f.UserID && existing.PostID == f.PostID {
+			existing.CollectionID = f.CollectionID
+			existing.UpdatedAt = f.UpdatedAt
+			r.store.favorites[k] = existing
+			return nil
+		}
+	}
+	if !r.store.userExists(f.UserID) || !r.store.postExists(f.PostID) {
+		return repository.ErrForeignKeyViolation
+	}
+	r.store.favorites[f.ID] = *f
+	return nil
+}
+
+func (r *MemoryFavoriteRepository) Delete(ctx context.Context, userID, postID string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for k, f := range r.store.favorites {
+		if f.UserID.String() == userID && f.PostID.String() == postID {
+			delete(r.store.favorites, k)
+		}
+	}
+	return nil
+}
+
+func (r *MemoryFavoriteRepository) DeleteFromCollection(ctx context.Context, collectionID, postID string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for k, f := range r.store.favorites {
+		if f.CollectionID.String() == collectionID && f.PostID.String() == postID {
+			delete(r.store.favorites, k)
+		}
+	}
+	return nil
+}
+
+// Newest favorite first
+func (r *MemoryFavoriteRepository) FindByUserID(ctx context.Context, userID string, currentUserID *string, page dto.CursorPage) ([]*aggregate.Post, *dto.Cursor, error) {
+	return r.find(func(f entity.Favorite) bool { return f.UserID.String() == userID }, currentUserID, page)
+}
+
+func (r *MemoryFavoriteRepository) FindByCollectionID(ctx context.Context, collectionID string, currentUserID *string, page dto.CursorPage) ([]*aggregate.Post, *dto.Cursor, error) {
+	return r.find(func(f entity.Favorite) bool { return f.CollectionID.String() == collectionID }, currentUserID, page)
+}
+
+func (r *MemoryFavoriteRepository) find(match func(f entity.Favorite) bool, currentUserID *string, page dto.CursorPage) ([]*aggregate.Post, *dto.Cursor, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	viewerID := parseOptionalID(currentUserID)
+	var favorites []entity.Favorite
+	for _, f := range r.store.favorites {
+		if match(f) && r.store.postListed(f.PostID, viewerID) {
+			favorites = append(favorites, f)
+		}
+	}
+	favorites, next := cursorPage(favorites, func(f entity.Favorite) dto.Cursor { return dto.Cursor{CreatedAt: f.CreatedAt, ID: f.ID} }, page)
+
+	var posts []*aggregate.Post
+	for _, f := range favorites {
+		p := r.store.posts[f.PostID]
+		posts = append(posts, r.store.attach(aggregate.NewPost(p, r.store.postUser(p.UserID), r.store.commonPostAggregate(p.ID, viewerID)), viewerID))
+	}
+	return posts, next, nil
+}
@@ -0,0 +1,44 @@
+package memory
+
+import (
+	"context"
+	"social-media-go-ddd/internal/domain/entity"
+	"social-media-go-ddd/internal/domain/repository"
+)
+
+type MemoryFollowRepository struct {
+	baseMemoryRepository
+}
+
+func NewMemoryFollowRepository(store *Store) *MemoryFollowRepository {
+	return &MemoryFollowRepository{
+		baseMemoryRepository: NewBaseMemoryRepository(store),
+	}
+}
+
+// If already follow, does nothing
+func (r *MemoryFollowRepository) Save(ctx context.Context, f *entity.Follow) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if r.store.isFollowing(f.FollowerID, f.FolloweeID) {
+		return nil
+	}
+	if !r.store.userExists(f.FollowerID) || !r.store.userExists(f.FolloweeID) {
+		return repository.ErrForeignKeyViolation
+	}
+	r.store.follows[f.ID] = *f
+	return nil
+}
+
+func (r *MemoryFollowRepository) Delete(ctx context.Context, followerID, followeeID string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for k, f := range r.store.follows {
+		if f.FollowerID.String() == followerID && f.FolloweeID.String() == followeeID {
+			delete(r.store.follows, k)
+		}
+	}
+	return nil
+}
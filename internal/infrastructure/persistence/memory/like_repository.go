@@ -0,0 +1,67 @@
+package memory
+
+import (
+	"context"
+	"social-media-go-ddd/internal/domain/entity"
+	"social-media-go-ddd/internal/domain/repository"
+	"time"
+)
+
+type MemoryLikeRepository struct {
+	baseMemoryRepository
+}
+
+func NewMemoryLikeRepository(store *Store) *MemoryLikeRepository {
+	return &MemoryLikeRepository{
+		baseMemoryRepository: NewBaseMemoryRepository(store),
+	}
+}
+
+// Replaces the reaction if the user already reacted to the post
+func (r *MemoryLikeRepository) Save(ctx context.Context, l *entity.Like) (bool, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for k, existing := range r.store.likes {
+		if existing.UserID == l.UserID && existing.PostID == l.PostID {
+			existing.Reaction = l.Reaction
+			existing.UpdatedAt = time.Now()
+			r.store.likes[k] = existing
+			return false, nil
+		}
+	}
+	if !r.store.userExists(l.UserID) || !r.store.postExists(l.PostID) {
+		return false, repository.ErrForeignKeyViolation
+	}
+	r.store.likes[l.ID] = *l
+	return true, nil
+}
+
+// Keeps the reaction if the user already reacted to the post
+func (r *MemoryLikeRepository) SaveIfAbsent(ctx context.Context, l *entity.Like) (bool, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, existing := range r.store.likes {
+		if existing.UserID == l.UserID && existing.PostID == l.PostID {
+			return false, nil
+		}
+	}
+	if !r.store.userExists(l.UserID) || !r.store.postExists(l.PostID) {
+		return false, repository.ErrForeignKeyViolation
+	}
+	r.store.likes[l.ID] = *l
+	return true, nil
+}
+
+func (r *MemoryLikeRepository) Delete(ctx context.Context, userID, postID string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for k, l := range r.store.likes {
+		if l.UserID.String() == userID && l.PostID.String() == postID {
+			delete(r.store.likes, k)
+		}
+	}
+	return nil
+}
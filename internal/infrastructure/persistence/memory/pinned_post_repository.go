@@ -0,0 +1,58 @@
+package memory
+
+import (
+	"context"
+	"social-media-go-ddd/internal/domain/entity"
+	"social-media-go-ddd/internal/domain/repository"
+)
+
+type MemoryPinnedPostRepository struct {
+	baseMemoryRepository
+}
+
+func NewMemoryPinnedPostRepository(store *Store) *MemoryPinnedPostRepository {
+	return &MemoryPinnedPostRepository{
+		baseMemoryRepository: NewBaseMemoryRepository(store),
+	}
+}
+
+// If already pinned, does nothing
+func (r *MemoryPinnedPostRepository) Save(ctx context.Context, p *entity.PinnedPost) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	pinned := 0
+	for _, existing := range r.store.pinnedPosts {
+		if existing.UserID != p.UserID {
+			continue
+		}
+		if existing.PostID == p.PostID {
+			return nil
+		}
+		pinned++
+	}
+	post, ok := r.store.posts[p.PostID]
+	if !ok || !r.store.userExists(p.UserID) {
+		return repository.ErrForeignKeyViolation
+	}
+	if post.UserID != p.UserID && r.store.userRepost(p.UserID, p.PostID) == nil {
+		return entity.ErrPinNotOwnOrRepost
+	}
+	if pinned >= entity.MaxPinnedPosts {
+		return entity.ErrPinLimitReached
+	}
+	r.store.pinnedPosts[p.ID] = *p
+	return nil
+}
+
+func (r *MemoryPinnedPostRepository) Delete(ctx context.Context, userID, postID string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for k, p := range r.store.pinnedPosts {
+		if p.UserID.String() == userID && p.PostID.String() == postID {
+			delete(r.store.pinnedPosts, k)
+		}
+	}
+	return nil
+}
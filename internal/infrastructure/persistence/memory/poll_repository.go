@@ -0,0 +1,56 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"social-media-go-ddd/internal/domain/aggregate"
+	"social-media-go-ddd/internal/domain/entity"
+	"social-media-go-ddd/internal/domain/repository"
+
+	"github.com/google/uuid"
+)
+
+type MemoryPollRepository struct {
+	baseMemoryRepository
+}
+
+func NewMemoryPollRepository(store *Store) *MemoryPollRepository {
+	return &MemoryPollRepository{
+		baseMemoryRepository: NewBaseMemoryRepository(store),
+	}
+}
+
+func (r *MemoryPollRepository) Save(ctx context.Context, p *entity.Poll) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, existing := range r.store.polls {
+		if existing.ID == p.ID {
+			return repository.ErrUniqueViolation
+		}
+		if existing.PostID == p.PostID {
+			return fmt.Errorf("%w: UNIQUE constraint failed: polls.post_id", repository.ErrUniqueViolation)
+		}
+	}
+	if !r.store.postExists(p.PostID) {
+		return repository.ErrForeignKeyViolation
+	}
+	r.store.polls[p.ID] = *p
+	return nil
+}
+
+func (r *MemoryPollRepository) FindByPostID(ctx context.Context, postID string, currentUserID *string) (*aggregate.Poll, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	id, err := uuid.Parse(postID)
+	if err != nil {
+		return nil, sql.ErrNoRows
+	}
+	poll := r.store.postPoll(id, parseOptionalID(currentUserID))
+	if poll == nil {
+		return nil, sql.ErrNoRows
+	}
+	return poll, nil
+}
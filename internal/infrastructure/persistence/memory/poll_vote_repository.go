@@ -0,0 +1,39 @@
+package memory
+
+import (
+	"context"
+	"social-media-go-ddd/internal/domain/entity"
+	"social-media-go-ddd/internal/domain/repository"
+)
+
+type MemoryPollVoteRepository struct {
+	baseMemoryRepository
+}
+
+func NewMemoryPollVoteRepository(store *Store) *MemoryPollVoteRepository {
+	return &MemoryPollVoteRepository{
+		baseMemoryRepository: NewBaseMemoryRepository(store),
+	}
+}
+
+func (r *MemoryPollVoteRepository) Save(ctx context.Context, v *entity.PollVote) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, existing := range r.store.pollVotes {
+		if existing.ID == v.ID || (existing.PollID == v.PollID && existing.UserID == v.UserID) {
+			return repository.ErrUniqueViolation
+		}
+	}
+	poll, ok := r.store.polls[v.PollID]
+	if !ok || !r.store.userExists(v.UserID) {
+		return repository.ErrForeignKeyViolation
+	}
+	for _, id := range v.OptionIDs {
+		if !poll.HasOption(id) {
+			return repository.ErrForeignKeyViolation
+		}
+	}
+	r.store.pollVotes[v.ID] = *v
+	return nil
+}
@@ -0,0 +1,271 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"social-media-go-ddd/internal/domain/aggregate"
+	"social-media-go-ddd/internal/domain/dto"
+	"social-media-go-ddd/internal/domain/entity"
+	"social-media-go-ddd/internal/domain/repository"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type MemoryPostRepository struct {
+	baseMemoryRepository
+}
+
+func NewMemoryPostRepository(store *Store) *MemoryPostRepository {
+	return &MemoryPostRepository{baseMemoryRepository: NewBaseMemoryRepository(store)}
+}
+
+func (r *MemoryPostRepository) Save(ctx context.Context, p *entity.Post) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if r.store.postExists(p.ID) {
+		return repository.ErrUniqueViolation
+	}
+	if !r.store.userExists(p.UserID) || (p.QuotedPostID != nil && !r.store.postExists(*p.QuotedPostID)) {
+		return repository.ErrForeignKeyViolation
+	}
+	r.store.posts[p.ID] = *p
+	r.store.saveMentions(*p)
+	return nil
+}
+
+func (r *MemoryPostRepository) FindByID(ctx context.Context, id string, currentUserID *string) (*aggregate.Post, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	postID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, sql.ErrNoRows
+	}
+	p, ok := r.store.posts[postID]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	viewerID := parseOptionalID(currentUserID)
+	post := aggregate.NewPost(p, r.store.postUser(p.UserID), r.store.commonPostAggregate(p.ID, viewerID))
+	post.Viewer = r.store.postViewer(p, viewerID)
+	return r.store.attach(post, viewerID), nil
+}
+
+// Newest published post visible to the viewer first
+func (r *MemoryPostRepository) FindByUserID(ctx context.Context, userID string, currentUserID *string, page dto.CursorPage) ([]*aggregate.Post, *dto.Cursor, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	ownerID, err := uuid.Parse(userID)
+	if err != nil || !r.store.userExists(ownerID) {
+		return nil, nil, sql.ErrNoRows
+	}
+
+	viewerID := parseOptionalID(currentUserID)
+	var userPosts []entity.Post
+	for _, p := range r.store.posts {
+		if p.UserID == ownerID && r.store.postListed(p.ID, viewerID) && !r.store.isPinned(ownerID, p.ID) {
+			userPosts = append(userPosts, p)
+		}
+	}
+	userPosts, next := cursorPage(userPosts, func(p entity.Post) dto.Cursor { return dto.Cursor{CreatedAt: p.CreatedAt, ID: p.ID} }, page)
+
+	var posts []*aggregate.Post
+	if page.After == nil {
+		posts = r.store.pinnedPostsOf(ownerID, viewerID)
+	}
+	for _, p := range userPosts {
+		posts = append(posts, r.store.attach(aggregate.NewPost(p, r.store.postUser(ownerID), r.store.commonPostAggregate(p.ID, viewerID)), viewerID))
+	}
+	return posts, next, nil
+}
+
+// Deleting a post also deletes its likes, favorites and reposts
+func (r *MemoryPostRepository) Delete(ctx context.Context, id string, userID string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for k, p := range r.store.posts {
+		if p.ID.String() == id && p.UserID.String() == userID {
+			r.store.deletePost(k)
+		}
+	}
+	return nil
+}
+
+func (r *MemoryPostRepository) Update(ctx context.Context, p *entity.Post, revision *entity.PostRevision) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	existing, ok := r.store.posts[p.ID]
+	if !ok || existing.UserID != p.UserID {
+		return nil
+	}
+	if existing.Version != p.Version-1 {
+		return entity.ErrVersionMismatch
+	}
+	if revision != nil && !r.store.userExists(revision.EditorID) {
+		return repository.ErrForeignKeyViolation
+	}
+	existing.Content = p.Content
+	existing.UpdatedAt = p.UpdatedAt
+	existing.Version = p.Version
+	r.store.posts[p.ID] = existing
+	r.store.saveMentions(existing)
+	if revision != nil {
+		stored := *revision
+		stored.PostID = p.ID
+		r.store.revisions[revision.ID] = stored
+	}
+	return nil
+}
+
+func (r *MemoryPostRepository) Publish(ctx context.Context, p *entity.Post) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	existing, ok := r.store.posts[p.ID]
+	if !ok || existing.UserID != p.UserID {
+		return nil
+	}
+	if existing.Version != p.Version-1 || existing.IsPublished() {
+		return entity.ErrVersionMismatch
+	}
+	r.store.posts[p.ID] = *p
+	return nil
+}
+
+// The store lock makes every call see the posts published by the calls before it
+func (r *MemoryPostRepository) PublishDue(ctx context.Context, now time.Time, limit int) ([]*entity.Post, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var due []entity.Post
+	for _, p := range r.store.posts {
+		if p.Status == entity.PostStatusScheduled && !p.PublishAt.After(now) {
+			due = append(due, p)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool {
+		if !due[i].PublishAt.Equal(*due[j].PublishAt) {
+			return due[i].PublishAt.Before(*due[j].PublishAt)
+		}
+		return due[i].ID.String() < due[j].ID.String()
+	})
+
+	var published []*entity.Post
+	for _, p := range due[:min(limit, len(due))] {
+		if err := p.Publish(*p.PublishAt); err != nil {
+			return nil, err
+		}
+		r.store.posts[p.ID] = p
+		published = append(published, &p)
+	}
+	return published, nil
+}
+
+// Newest draft first
+func (r *MemoryPostRepository) FindDrafts(ctx context.Context, userID string, page dto.CursorPage) ([]*entity.Post, *dto.Cursor, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var drafts []entity.Post
+	for _, p := range r.store.posts {
+		if p.UserID.String() == userID && !p.IsPublished() {
+			drafts = append(drafts, p)
+		}
+	}
+	drafts, next := cursorPage(drafts, func(p entity.Post) dto.Cursor { return dto.Cursor{CreatedAt: p.CreatedAt, ID: p.ID} }, page)
+
+	var posts []*entity.Post
+	for _, p := range drafts {
+		posts = append(posts, &p)
+	}
+	return posts, next, nil
+}
+
+// Revisions of the post, most recently edited first
+func (r *MemoryPostRepository) FindRevisions(ctx context.Context, postID string) ([]*entity.PostRevision, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	id, err := uuid.Parse(postID)
+	if err != nil {
+		return nil, nil
+	}
+	var revisions []*entity.PostRevision
+	for _, rev := range r.store.revisions {
+		if rev.PostID == id {
+			revisions = append(revisions, &rev)
+		}
+	}
+	sort.Slice(revisions, func(i, j int) bool {
+		a, b := revisions[i], revisions[j]
+		if !a.EditedAt.Equal(b.EditedAt) {
+			return a.EditedAt.After(b.EditedAt)
+		}
+		return a.ID.String() > b.ID.String()
+	})
+	return revisions, nil
+}
+
+type feedItem struct {
+	post     entity.Post
+	repost   *entity.Repost
+	feedTime time.Time
+}
+
+// Own and followed users' posts and reposts, ordered by post time or repost time, newest first
+func (r *MemoryPostRepository) FindFeed(ctx context.Context, userID string, limit, offset int) ([]*aggregate.Post, int, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	viewerID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, 0, nil
+	}
+	inFeed := func(authorID uuid.UUID) bool {
+		return authorID == viewerID || r.store.isFollowing(viewerID, authorID)
+	}
+
+	var items []feedItem
+	for _, p := range r.store.posts {
+		if inFeed(p.UserID) && r.store.postListed(p.ID, &viewerID) {
+			items = append(items, feedItem{post: p, feedTime: p.CreatedAt})
+		}
+	}
+	for _, rp := range r.store.reposts {
+		if inFeed(rp.UserID) && r.store.postListed(rp.PostID, &viewerID) {
+			items = append(items, feedItem{post: r.store.posts[rp.PostID], repost: &rp, feedTime: rp.CreatedAt})
+		}
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if !items[i].feedTime.Equal(items[j].feedTime) {
+			return items[i].feedTime.After(items[j].feedTime)
+		}
+		// map iteration order is random, keep pages stable
+		return items[i].post.ID.String() < items[j].post.ID.String()
+	})
+
+	total := len(items)
+	if offset >= total {
+		return nil, total, nil
+	}
+	items = items[offset:min(offset+limit, total)]
+
+	var feed []*aggregate.Post
+	for _, item := range items {
+		postUser := r.store.postUser(item.post.UserID)
+		cpa := r.store.commonPostAggregate(item.post.ID, &viewerID)
+		if item.repost != nil {
+			repostUser := r.store.postUser(item.repost.UserID)
+			feed = append(feed, r.store.attach(aggregate.NewRepost(item.post, item.repost, postUser, &repostUser, cpa), &viewerID))
+		} else {
+			feed = append(feed, r.store.attach(aggregate.NewPost(item.post, postUser, cpa), &viewerID))
+		}
+	}
+	return feed, total, nil
+}
@@ -0,0 +1,25 @@
+package memory
+
+import (
+	"social-media-go-ddd/internal/domain/repository/repositorytest"
+	"testing"
+)
+
+func TestRepositories(t *testing.T) {
+	repositorytest.RunAll(t, func(t *testing.T) repositorytest.Repositories {
+		store := NewStore()
+		return repositorytest.Repositories{
+			User:       NewMemoryUserRepository(store),
+			Session:    NewMemorySessionRepository(store),
+			Post:       NewMemoryPostRepository(store),
+			Like:       NewMemoryLikeRepository(store),
+			Favorite:   NewMemoryFavoriteRepository(store),
+			Repost:     NewMemoryRepostRepository(store),
+			Follow:     NewMemoryFollowRepository(store),
+			Poll:       NewMemoryPollRepository(store),
+			PollVote:   NewMemoryPollVoteRepository(store),
+			Pin:        NewMemoryPinnedPostRepository(store),
+			Collection: NewMemoryCollectionRepository(store),
+		}
+	})
+}
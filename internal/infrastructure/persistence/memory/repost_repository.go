@@ -0,0 +1,103 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"social-media-go-ddd/internal/domain/aggregate"
+	"social-media-go-ddd/internal/domain/dto"
+	"social-media-go-ddd/internal/domain/entity"
+	"social-media-go-ddd/internal/domain/repository"
+
+	"github.com/google/uuid"
+)
+
+type MemoryRepostRepository struct {
+	baseMemoryRepository
+}
+
+func NewMemoryRepostRepository(store *Store) *MemoryRepostRepository {
+	return &MemoryRepostRepository{
+		baseMemoryRepository: NewBaseMemoryRepository(store),
+	}
+}
+
+func (r *MemoryRepostRepository) FindByID(ctx context.Context, id string) (*entity.Repost, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	repostID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, sql.ErrNoRows
+	}
+	rp, ok := r.store.reposts[repostID]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return &rp, nil
+}
+
+// One user can only repost one, if already exist, update comment
+func (r *MemoryRepostRepository) Save(ctx context.Context, rp *entity.Repost) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for k, existing := range r.store.reposts {
+		if existing.UserID == rp.UserID && existing.PostID == rp.PostID {
+			existing.Comment = rp.Comment
+			existing.UpdatedAt = rp.UpdatedAt
+			r.store.reposts[k] = existing
+			return nil
+		}
+	}
+	if !r.store.userExists(rp.UserID) || !r.store.postExists(rp.PostID) {
+		return repository.ErrForeignKeyViolation
+	}
+	r.store.reposts[rp.ID] = *rp
+	return nil
+}
+
+func (r *MemoryRepostRepository) Delete(ctx context.Context, userID string, postID string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for k, rp := range r.store.reposts {
+		if rp.UserID.String() == userID && rp.PostID.String() == postID {
+			delete(r.store.reposts, k)
+			// Pins of own posts stay, those do not depend on the repost
+			for kp, p := range r.store.pinnedPosts {
+				if p.UserID == rp.UserID && p.PostID == rp.PostID && r.store.posts[rp.PostID].UserID != rp.UserID {
+					delete(r.store.pinnedPosts, kp)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Newest repost first
+func (r *MemoryRepostRepository) FindByUserID(ctx context.Context, userID string, currentUserID *string, page dto.CursorPage) ([]*aggregate.Post, *dto.Cursor, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	repostUserID, err := uuid.Parse(userID)
+	if err != nil || !r.store.userExists(repostUserID) {
+		return nil, nil, sql.ErrNoRows
+	}
+	repostUser := r.store.postUser(repostUserID)
+
+	viewerID := parseOptionalID(currentUserID)
+	var reposts []entity.Repost
+	for _, rp := range r.store.reposts {
+		if rp.UserID == repostUserID && r.store.postListed(rp.PostID, viewerID) {
+			reposts = append(reposts, rp)
+		}
+	}
+	reposts, next := cursorPage(reposts, func(rp entity.Repost) dto.Cursor { return dto.Cursor{CreatedAt: rp.CreatedAt, ID: rp.ID} }, page)
+
+	var posts []*aggregate.Post
+	for _, rp := range reposts {
+		p := r.store.posts[rp.PostID]
+		posts = append(posts, r.store.attach(aggregate.NewRepost(p, &rp, r.store.postUser(p.UserID), &repostUser, r.store.commonPostAggregate(p.ID, viewerID)), viewerID))
+	}
+	return posts, next, nil
+}
@@ -0,0 +1,71 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"social-media-go-ddd/internal/domain/entity"
+	"social-media-go-ddd/internal/domain/repository"
+
+	"github.com/google/uuid"
+)
+
+type MemorySessionRepository struct {
+	baseMemoryRepository
+}
+
+func NewMemorySessionRepository(store *Store) *MemorySessionRepository {
+	return &MemorySessionRepository{baseMemoryRepository: NewBaseMemoryRepository(store)}
+}
+
+func (r *MemorySessionRepository) Save(ctx context.Context, s *entity.Session) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.sessions[s.ID]; ok {
+		return repository.ErrUniqueViolation
+	}
+	if !r.store.userExists(s.UserID) {
+		return repository.ErrForeignKeyViolation
+	}
+	r.store.sessions[s.ID] = *s
+	return nil
+}
+
+func (r *MemorySessionRepository) FindByID(ctx context.Context, id string) (*entity.Session, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	sessionID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, sql.ErrNoRows
+	}
+	s, ok := r.store.sessions[sessionID]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return &s, nil
+}
+
+func (r *MemorySessionRepository) Delete(ctx context.Context, id string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if sessionID, err := uuid.Parse(id); err == nil {
+		delete(r.store.sessions, sessionID)
+	}
+	return nil
+}
+
+func (r *MemorySessionRepository) UpdateExpireAt(ctx context.Context, s *entity.Session) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	existing, ok := r.store.sessions[s.ID]
+	if !ok {
+		return nil
+	}
+	existing.ExpireAt = s.ExpireAt
+	existing.UpdatedAt = s.UpdatedAt
+	r.store.sessions[s.ID] = existing
+	return nil
+}
@@ -0,0 +1,366 @@
+package memory
+
+import (
+	"slices"
+	"social-media-go-ddd/internal/domain/aggregate"
+	"social-media-go-ddd/internal/domain/dto"
+	"social-media-go-ddd/internal/domain/entity"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Store holds the tables shared by every memory repository, such that deleting a user or a post
+// cascades to the rows referencing it like ON DELETE CASCADE does in sql.
+type Store struct {
+	mu        sync.RWMutex
+	users     map[uuid.UUID]entity.User
+	posts     map[uuid.UUID]entity.Post
+	likes     map[uuid.UUID]entity.Like
+	favorites map[uuid.UUID]entity.Favorite
+	reposts   map[uuid.UUID]entity.Repost
+	sessions  map[uuid.UUID]entity.Session
+	follows   map[uuid.UUID]entity.Follow
+	revisions map[uuid.UUID]entity.PostRevision
+	// Users mentioned per post id
+	mentions  map[uuid.UUID][]uuid.UUID
+	polls     map[uuid.UUID]entity.Poll
+	pollVotes map[uuid.UUID]entity.PollVote
+	// A pin of another user's post goes away with the repost, like repost_id ON DELETE CASCADE
+	pinnedPosts map[uuid.UUID]entity.PinnedPost
+	collections map[uuid.UUID]entity.Collection
+}
+
+func NewStore() *Store {
+	return &Store{
+		users:       make(map[uuid.UUID]entity.User),
+		posts:       make(map[uuid.UUID]entity.Post),
+		likes:       make(map[uuid.UUID]entity.Like),
+		favorites:   make(map[uuid.UUID]entity.Favorite),
+		reposts:     make(map[uuid.UUID]entity.Repost),
+		sessions:    make(map[uuid.UUID]entity.Session),
+		follows:     make(map[uuid.UUID]entity.Follow),
+		revisions:   make(map[uuid.UUID]entity.PostRevision),
+		mentions:    make(map[uuid.UUID][]uuid.UUID),
+		polls:       make(map[uuid.UUID]entity.Poll),
+		pollVotes:   make(map[uuid.UUID]entity.PollVote),
+		pinnedPosts: make(map[uuid.UUID]entity.PinnedPost),
+		collections: make(map[uuid.UUID]entity.Collection),
+	}
+}
+
+// Caller must hold the write lock
+func (s *Store) deletePost(id uuid.UUID) {
+	delete(s.posts, id)
+	for k, l := range s.likes {
+		if l.PostID == id {
+			delete(s.likes, k)
+		}
+	}
+	for k, f := range s.favorites {
+		if f.PostID == id {
+			delete(s.favorites, k)
+		}
+	}
+	for k, r := range s.reposts {
+		if r.PostID == id {
+			delete(s.reposts, k)
+		}
+	}
+	for k, r := range s.revisions {
+		if r.PostID == id {
+			delete(s.revisions, k)
+		}
+	}
+	for k, p := range s.pinnedPosts {
+		if p.PostID == id {
+			delete(s.pinnedPosts, k)
+		}
+	}
+	delete(s.mentions, id)
+	for k, p := range s.polls {
+		if p.PostID == id {
+			delete(s.polls, k)
+			for kv, v := range s.pollVotes {
+				if v.PollID == p.ID {
+					delete(s.pollVotes, kv)
+				}
+			}
+		}
+	}
+	// Quotes of the post are kept, without it
+	for k, p := range s.posts {
+		if p.QuotedPostID != nil && *p.QuotedPostID == id {
+			p.QuotedPostID = nil
+			s.posts[k] = p
+		}
+	}
+}
+
+// Nil for an unknown user
+// Caller must hold the read lock
+func (s *Store) defaultCollection(userID uuid.UUID) *entity.Collection {
+	for _, c := range s.collections {
+		if c.UserID == userID && c.Default {
+			return &c
+		}
+	}
+	return nil
+}
+
+// Keep the users mentioned in p like the sql backends do, unknown usernames are skipped
+// Caller must hold the write lock
+func (s *Store) saveMentions(p entity.Post) {
+	var ids []uuid.UUID
+	for _, username := range p.Mentions() {
+		for _, u := range s.users {
+			if u.Username == username {
+				ids = append(ids, u.ID)
+			}
+		}
+	}
+	s.mentions[p.ID] = ids
+}
+
+// Caller must hold the read lock
+func (s *Store) userExists(id uuid.UUID) bool {
+	_, ok := s.users[id]
+	return ok
+}
+
+// Caller must hold the read lock
+func (s *Store) postExists(id uuid.UUID) bool {
+	_, ok := s.posts[id]
+	return ok
+}
+
+// Relationship of the viewer to p, a nil viewer is anonymous
+// Caller must hold the read lock
+func (s *Store) postViewer(p entity.Post, viewerID *uuid.UUID) entity.PostViewer {
+	if viewerID == nil {
+		return entity.PostViewer{}
+	}
+	return entity.PostViewer{
+		Author:    p.UserID == *viewerID,
+		Follower:  s.isFollowing(*viewerID, p.UserID),
+		Mentioned: slices.Contains(s.mentions[p.ID], *viewerID),
+	}
+}
+
+// Whether the post shows up in the timelines of the viewer, the feed and the lists built on them
+// Caller must hold the read lock
+func (s *Store) postListed(id uuid.UUID, viewerID *uuid.UUID) bool {
+	p := s.posts[id]
+	return p.ListedTo(s.postViewer(p, viewerID))
+}
+
+// Post owner as the sql backends join it, only id, username and email are selected
+// Caller must hold the read lock
+func (s *Store) postUser(id uuid.UUID) entity.User {
+	u := s.users[id]
+	return entity.User{
+		BaseEntity: entity.BaseEntity{ID: u.ID},
+		Username:   u.Username,
+		Email:      u.Email,
+	}
+}
+
+// Caller must hold the read lock
+func (s *Store) commonPostAggregate(postID uuid.UUID, currentUserID *uuid.UUID) dto.CommonPostAggregate {
+	var cpa dto.CommonPostAggregate
+	for _, l := range s.likes {
+		if l.PostID == postID {
+			cpa.LikeCount++
+			if currentUserID != nil && l.UserID == *currentUserID {
+				cpa.Liked = true
+			}
+		}
+	}
+	for _, f := range s.favorites {
+		if f.PostID == postID {
+			cpa.FavoriteCount++
+			if currentUserID != nil && f.UserID == *currentUserID {
+				cpa.Favorited = true
+			}
+		}
+	}
+	for _, r := range s.reposts {
+		if r.PostID == postID {
+			cpa.RepostCount++
+			if currentUserID != nil && r.UserID == *currentUserID {
+				cpa.Reposted = true
+			}
+		}
+	}
+	for _, r := range s.revisions {
+		if r.PostID == postID {
+			cpa.EditCount++
+		}
+	}
+	for _, p := range s.posts {
+		if p.QuotedPostID != nil && *p.QuotedPostID == postID && p.IsPublished() {
+			cpa.QuoteCount++
+		}
+	}
+	return cpa
+}
+
+// Sets the quoted post of post if the viewer may see it, its poll and reactions, like the sql backends load them after the posts of a page
+// Caller must hold the read lock
+func (s *Store) attach(post *aggregate.Post, viewerID *uuid.UUID) *aggregate.Post {
+	post.Poll = s.postPoll(post.ID, viewerID)
+	s.addReactions(post, viewerID)
+	if post.QuotedPostID == nil || !s.postListed(*post.QuotedPostID, viewerID) {
+		return post
+	}
+	quoted := s.posts[*post.QuotedPostID]
+	post.Quote = aggregate.NewPost(quoted, s.postUser(quoted.UserID), s.commonPostAggregate(quoted.ID, viewerID))
+	s.addReactions(post.Quote, viewerID)
+	return post
+}
+
+// Caller must hold the read lock
+func (s *Store) addReactions(post *aggregate.Post, viewerID *uuid.UUID) {
+	for _, l := range s.likes {
+		if l.PostID == post.ID {
+			post.AddReactions(l.Reaction, 1, viewerID != nil && l.UserID == *viewerID)
+		}
+	}
+}
+
+// Poll of the post with its results as the viewer may see them, nil when the post has none
+// Caller must hold the read lock
+func (s *Store) postPoll(postID uuid.UUID, viewerID *uuid.UUID) *aggregate.Poll {
+	for _, p := range s.polls {
+		if p.PostID != postID {
+			continue
+		}
+		tallies := make([]int, len(p.Options))
+		voters := 0
+		var choice []uuid.UUID
+		for _, v := range s.pollVotes {
+			if v.PollID != p.ID {
+				continue
+			}
+			voters++
+			for i, o := range p.Options {
+				if slices.Contains(v.OptionIDs, o.ID) {
+					tallies[i]++
+				}
+			}
+			if viewerID != nil && v.UserID == *viewerID {
+				choice = v.OptionIDs
+			}
+		}
+		// Chosen options in the order of the poll, like the sql backends return them
+		var viewerChoice []uuid.UUID
+		for _, o := range p.Options {
+			if slices.Contains(choice, o.ID) {
+				viewerChoice = append(viewerChoice, o.ID)
+			}
+		}
+		return aggregate.NewPoll(p, tallies, voters, viewerChoice, time.Now())
+	}
+	return nil
+}
+
+// Repost of the post by the user, nil when they did not repost it
+// Caller must hold the read lock
+func (s *Store) userRepost(userID, postID uuid.UUID) *entity.Repost {
+	for _, rp := range s.reposts {
+		if rp.UserID == userID && rp.PostID == postID {
+			return &rp
+		}
+	}
+	return nil
+}
+
+// Caller must hold the read lock
+func (s *Store) isPinned(userID, postID uuid.UUID) bool {
+	for _, p := range s.pinnedPosts {
+		if p.UserID == userID && p.PostID == postID {
+			return true
+		}
+	}
+	return false
+}
+
+// Pins of the owner the viewer may see, most recently pinned first. Pinned reposts have the type repost
+// Caller must hold the read lock
+func (s *Store) pinnedPostsOf(ownerID uuid.UUID, viewerID *uuid.UUID) []*aggregate.Post {
+	var pins []entity.PinnedPost
+	for _, p := range s.pinnedPosts {
+		if p.UserID == ownerID && s.postListed(p.PostID, viewerID) {
+			pins = append(pins, p)
+		}
+	}
+	sort.Slice(pins, func(i, j int) bool {
+		return newerThan(dto.Cursor{CreatedAt: pins[i].CreatedAt, ID: pins[i].ID}, dto.Cursor{CreatedAt: pins[j].CreatedAt, ID: pins[j].ID})
+	})
+
+	owner := s.postUser(ownerID)
+	var posts []*aggregate.Post
+	for _, pin := range pins {
+		p := s.posts[pin.PostID]
+		cpa := s.commonPostAggregate(p.ID, viewerID)
+		post := aggregate.NewPost(p, s.postUser(p.UserID), cpa)
+		if p.UserID != ownerID {
+			post = aggregate.NewRepost(p, s.userRepost(ownerID, p.ID), s.postUser(p.UserID), &owner, cpa)
+		}
+		post.Pinned = true
+		posts = append(posts, s.attach(post, viewerID))
+	}
+	return posts
+}
+
+// Caller must hold the read lock
+func (s *Store) isFollowing(followerID, followeeID uuid.UUID) bool {
+	for _, f := range s.follows {
+		if f.FollowerID == followerID && f.FolloweeID == followeeID {
+			return true
+		}
+	}
+	return false
+}
+
+// Parse an optional id the way the sql backends treat it: nil or not a valid uuid matches no row
+func parseOptionalID(id *string) *uuid.UUID {
+	if id == nil {
+		return nil
+	}
+	parsed, err := uuid.Parse(*id)
+	if err != nil {
+		return nil
+	}
+	return &parsed
+}
+
+// Whether a sorts before b in the (created_at, id) desc order the sql backends page by
+func newerThan(a, b dto.Cursor) bool {
+	if !a.CreatedAt.Equal(b.CreatedAt) {
+		return a.CreatedAt.After(b.CreatedAt)
+	}
+	return a.ID.String() > b.ID.String()
+}
+
+// Sorts rows newest first and cuts the page out of them, key returns the cursor of a row
+func cursorPage[T any](rows []T, key func(T) dto.Cursor, page dto.CursorPage) ([]T, *dto.Cursor) {
+	sort.Slice(rows, func(i, j int) bool { return newerThan(key(rows[i]), key(rows[j])) })
+
+	var kept []T
+	var keys []dto.Cursor
+	for _, row := range rows {
+		k := key(row)
+		if page.After != nil && !newerThan(*page.After, k) {
+			continue
+		}
+		kept = append(kept, row)
+		keys = append(keys, k)
+		if len(kept) == page.FetchLimit() {
+			break
+		}
+	}
+	return dto.PageOf(page, kept, keys)
+}
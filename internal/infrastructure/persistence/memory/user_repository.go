@@ -0,0 +1,109 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"social-media-go-ddd/internal/domain/aggregate"
+	"social-media-go-ddd/internal/domain/dto"
+	"social-media-go-ddd/internal/domain/entity"
+	"social-media-go-ddd/internal/domain/repository"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+type MemoryUserRepository struct {
+	baseMemoryRepository
+}
+
+func NewMemoryUserRepository(store *Store) *MemoryUserRepository {
+	return &MemoryUserRepository{
+		baseMemoryRepository: NewBaseMemoryRepository(store),
+	}
+}
+
+func (r *MemoryUserRepository) Save(ctx context.Context, u *entity.User) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	// Name the column like sqlite does, such that callers can tell which field is taken
+	for _, existing := range r.store.users {
+		switch {
+		case existing.ID == u.ID:
+			return repository.ErrUniqueViolation
+		case existing.Username == u.Username:
+			return fmt.Errorf("%w: UNIQUE constraint failed: users.username", repository.ErrUniqueViolation)
+		case existing.Email == u.Email:
+			return fmt.Errorf("%w: UNIQUE constraint failed: users.email", repository.ErrUniqueViolation)
+		}
+	}
+	r.store.users[u.ID] = *u
+	// The default collection of the user is created along with them
+	collection := entity.NewDefaultCollection(u.ID)
+	r.store.collections[collection.ID] = *collection
+	return nil
+}
+
+// Caller must hold the read lock
+func (r *MemoryUserRepository) toAggregate(u entity.User, currentUserID *string) *aggregate.User {
+	var cua dto.CommonUserAggregate
+	viewerID := parseOptionalID(currentUserID)
+	for _, f := range r.store.follows {
+		if f.FollowerID == u.ID {
+			cua.FollowingCount++
+		}
+		if f.FolloweeID == u.ID {
+			cua.FollowerCount++
+			if viewerID != nil && f.FollowerID == *viewerID {
+				cua.Followed = true
+			}
+		}
+	}
+	return aggregate.NewUser(u, cua)
+}
+
+func (r *MemoryUserRepository) FindByID(ctx context.Context, id string, currentUserID *string) (*aggregate.User, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	userID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, sql.ErrNoRows
+	}
+	u, ok := r.store.users[userID]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return r.toAggregate(u, currentUserID), nil
+}
+
+func (r *MemoryUserRepository) FindByName(ctx context.Context, name string, currentUserID *string) (*aggregate.User, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, u := range r.store.users {
+		if u.Username == name {
+			return r.toAggregate(u, currentUserID), nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+// Case-insensitive prefix match like ILIKE 'name%'
+func (r *MemoryUserRepository) SearchManyByName(ctx context.Context, name string, currentUserID *string) ([]*aggregate.User, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	prefix := strings.ToLower(name)
+	var users []*aggregate.User
+	for _, u := range r.store.users {
+		if strings.HasPrefix(strings.ToLower(u.Username), prefix) {
+			users = append(users, r.toAggregate(u, currentUserID))
+		}
+	}
+	// map iteration order is random, keep results stable
+	sort.Slice(users, func(i, j int) bool { return users[i].Username < users[j].Username })
+	return users, nil
+}
@@ -0,0 +1,60 @@
+package migration
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"social-media-go-ddd/internal/application/config"
+	"strconv"
+)
+
+// Every driver has its own migrations folder, they must be kept in sync
+var Drivers = []string{config.DB_DRIVER_PG, config.DB_DRIVER_MYSQL, config.DB_DRIVER_SQLITE}
+
+var versionPattern = regexp.MustCompile(`^(\d+)_.*\.(up|down)\.sql$`)
+
+// Create empty up and down files for name in the migrations folder of every driver, all with the same next version.
+// dir is the persistence folder in the source tree, eg internal/infrastructure/persistence.
+func Create(dir, name string) ([]string, error) {
+	name = sanitizeName(name)
+	if name == "" {
+		return nil, errors.New("migration name cannot be empty")
+	}
+
+	var next uint64
+	for _, driver := range Drivers {
+		entries, err := os.ReadDir(filepath.Join(dir, driver, "migrations"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s migrations: %w", driver, err)
+		}
+		for _, e := range entries {
+			match := versionPattern.FindStringSubmatch(e.Name())
+			if match == nil {
+				continue
+			}
+			v, err := strconv.ParseUint(match[1], 10, 64)
+			if err != nil {
+				return nil, err
+			}
+			next = max(next, v)
+		}
+	}
+	next++
+
+	var files []string
+	for _, driver := range Drivers {
+		for _, direction := range []string{"up", "down"} {
+			path := filepath.Join(dir, driver, "migrations", fmt.Sprintf("%06d_%s.%s.sql", next, name, direction))
+			// O_EXCL such that an existing migration is never overwritten
+			f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+			if err != nil {
+				return files, err
+			}
+			f.Close()
+			files = append(files, path)
+		}
+	}
+	return files, nil
+}
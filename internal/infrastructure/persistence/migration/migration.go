@@ -0,0 +1,252 @@
+// Package migration runs the SQL migrations embedded in each persistence package.
+package migration
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"social-media-go-ddd/internal/application/config"
+	"social-media-go-ddd/internal/infrastructure/persistence/mysql"
+	"social-media-go-ddd/internal/infrastructure/persistence/postgres"
+	"social-media-go-ddd/internal/infrastructure/persistence/sqlite"
+	"strings"
+	"time"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database"
+	_ "github.com/golang-migrate/migrate/v4/database/mysql"
+	_ "github.com/golang-migrate/migrate/v4/database/postgres"
+	_ "github.com/golang-migrate/migrate/v4/database/sqlite"
+	"github.com/golang-migrate/migrate/v4/source"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+)
+
+// How long to wait for the migration lock held by another process, eg a concurrent deploy
+const DefaultLockTimeout = 5 * time.Minute
+
+type Migrator struct {
+	m      *migrate.Migrate
+	source source.Driver
+	// Postgres blocks on an advisory lock, MySQL gives up after 10 seconds so we retry until the timeout.
+	// SQLite only locks within the process, run a single instance against a database file.
+	LockTimeout time.Duration
+}
+
+type Status struct {
+	Version uint
+	Name    string
+	Applied bool
+	// The migration failed halfway, fix the database by hand then use force
+	Dirty bool
+}
+
+// Embedded migrations of a driver
+func Source(driver string) (fs.FS, error) {
+	var files fs.FS
+	switch driver {
+	case config.DB_DRIVER_PG:
+		files = postgres.Migrations
+	case config.DB_DRIVER_MYSQL:
+		files = mysql.Migrations
+	case config.DB_DRIVER_SQLITE:
+		files = sqlite.Migrations
+	default:
+		return nil, fmt.Errorf("unsupported database driver: %s", driver)
+	}
+	return fs.Sub(files, "migrations")
+}
+
+// databaseURL is in golang-migrate format, see config.DBConfig.BuildMigrateDSN
+func New(driver, databaseURL string) (*Migrator, error) {
+	files, err := Source(driver)
+	if err != nil {
+		return nil, err
+	}
+
+	src, err := iofs.New(files, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read embedded migrations: %w", err)
+	}
+
+	m, err := migrate.NewWithSourceInstance("iofs", src, databaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to init migrations: %w", err)
+	}
+
+	migrator := &Migrator{m: m, LockTimeout: DefaultLockTimeout}
+	// golang-migrate closes the source it is given, keep our own instance to list migrations
+	migrator.source, err = iofs.New(files, ".")
+	if err != nil {
+		m.Close()
+		return nil, fmt.Errorf("failed to read embedded migrations: %w", err)
+	}
+	return migrator, nil
+}
+
+func (m *Migrator) Close() error {
+	srcErr, dbErr := m.m.Close()
+	return errors.Join(srcErr, dbErr, m.source.Close())
+}
+
+// Apply all pending migrations, does nothing if there are none
+func (m *Migrator) Up() error {
+	return m.run(m.m.Up)
+}
+
+// Roll back every migration
+func (m *Migrator) Down() error {
+	return m.run(m.m.Down)
+}
+
+// Apply n migrations, or roll back n migrations if n is negative
+func (m *Migrator) Steps(n int) error {
+	return m.run(func() error { return m.m.Steps(n) })
+}
+
+// Migrate up or down to the given version
+func (m *Migrator) Goto(version uint) error {
+	return m.run(func() error { return m.m.Migrate(version) })
+}
+
+// Set the version without running any migration, used to recover from a dirty state.
+// Version -1 means no migration applied.
+func (m *Migrator) Force(version int) error {
+	return m.run(func() error { return m.m.Force(version) })
+}
+
+// Current version, ok is false when no migration is applied yet
+func (m *Migrator) Version() (version uint, dirty bool, ok bool, err error) {
+	version, dirty, err = m.m.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		return 0, false, false, nil
+	}
+	if err != nil {
+		return 0, false, false, err
+	}
+	return version, dirty, true, nil
+}
+
+// Every embedded migration and whether it is applied
+func (m *Migrator) Status() ([]Status, error) {
+	current, dirty, ok, err := m.Version()
+	if err != nil {
+		return nil, err
+	}
+
+	var statuses []Status
+	version, err := m.source.First()
+	for err == nil {
+		s := Status{Version: version, Name: m.name(version)}
+		if ok {
+			s.Applied = version <= current
+			s.Dirty = dirty && version == current
+		}
+		statuses = append(statuses, s)
+		version, err = m.source.Next(version)
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	return statuses, nil
+}
+
+func (m *Migrator) name(version uint) string {
+	r, identifier, err := m.source.ReadUp(version)
+	if err != nil {
+		return ""
+	}
+	r.Close()
+	return identifier
+}
+
+// Latest embedded version of a driver, the version a fully migrated database is at
+func Latest(driver string) (uint, error) {
+	files, err := Source(driver)
+	if err != nil {
+		return 0, err
+	}
+	src, err := iofs.New(files, ".")
+	if err != nil {
+		return 0, err
+	}
+	defer src.Close()
+
+	version, err := src.First()
+	if err != nil {
+		return 0, err
+	}
+	for {
+		next, err := src.Next(version)
+		if errors.Is(err, fs.ErrNotExist) {
+			return version, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+		version = next
+	}
+}
+
+// Reads the version golang-migrate recorded, the table is the same on every driver
+const VersionQuery = "SELECT version, dirty FROM schema_migrations LIMIT 1"
+
+// Row of VersionQuery, *sql.Row or pgx.Row
+type versionRow interface {
+	Scan(dest ...any) error
+}
+
+// Error unless the database is at the latest embedded version of driver or ahead of it, as during a rolling deploy where
+// the new release migrated first, and no migration failed halfway
+func CheckVersion(driver string, row versionRow) error {
+	latest, err := Latest(driver)
+	if err != nil {
+		return err
+	}
+
+	var version int64
+	var dirty bool
+	// pgx.ErrNoRows is sql.ErrNoRows as well
+	if err := row.Scan(&version, &dirty); errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("no migration applied, latest is %d", latest)
+	} else if err != nil {
+		return err
+	}
+	if dirty {
+		return fmt.Errorf("migration %d is dirty", version)
+	}
+	if version < int64(latest) {
+		return fmt.Errorf("database at version %d, latest is %d", version, latest)
+	}
+	return nil
+}
+
+// Retry while another process holds the lock, no change is not an error
+func (m *Migrator) run(fn func() error) error {
+	m.m.LockTimeout = m.LockTimeout
+	deadline := time.Now().Add(m.LockTimeout)
+	for {
+		err := fn()
+		if errors.Is(err, migrate.ErrNoChange) {
+			return nil
+		}
+		if !errors.Is(err, database.ErrLocked) || time.Now().After(deadline) {
+			return err
+		}
+		time.Sleep(time.Second)
+	}
+}
+
+func sanitizeName(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '_':
+			return r
+		case r == ' ' || r == '-':
+			return '_'
+		default:
+			return -1
+		}
+	}, name)
+}
@@ -0,0 +1,190 @@
+package migration
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"social-media-go-ddd/internal/application/config"
+	"social-media-go-ddd/internal/infrastructure/persistence/sqlite"
+	"strings"
+	"testing"
+)
+
+func newSQLiteMigrator(t *testing.T) *Migrator {
+	t.Helper()
+
+	cfg := config.DBConfig{Driver: config.DB_DRIVER_SQLITE, Name: filepath.Join(t.TempDir(), "test.db")}
+	m, err := New(cfg.Driver, cfg.BuildMigrateDSN())
+	if err != nil {
+		t.Fatalf("new migrator: %v", err)
+	}
+	t.Cleanup(func() { m.Close() })
+	return m
+}
+
+func TestUpDownGoto(t *testing.T) {
+	m := newSQLiteMigrator(t)
+	latest, err := Latest(config.DB_DRIVER_SQLITE)
+	if err != nil {
+		t.Fatalf("latest: %v", err)
+	}
+
+	if _, _, ok, err := m.Version(); err != nil || ok {
+		t.Fatalf("fresh database should have no version, got ok=%v err=%v", ok, err)
+	}
+
+	if err := m.Up(); err != nil {
+		t.Fatalf("up: %v", err)
+	}
+	// Nothing to apply is not an error
+	if err := m.Up(); err != nil {
+		t.Fatalf("up again: %v", err)
+	}
+	assertVersion(t, m, latest)
+
+	if err := m.Steps(-1); err != nil {
+		t.Fatalf("steps -1: %v", err)
+	}
+	assertVersion(t, m, latest-1)
+
+	if err := m.Goto(1); err != nil {
+		t.Fatalf("goto 1: %v", err)
+	}
+	assertVersion(t, m, 1)
+
+	statuses, err := m.Status()
+	if err != nil {
+		t.Fatalf("status: %v", err)
+	}
+	if uint(len(statuses)) != latest {
+		t.Fatalf("got %d statuses, want %d", len(statuses), latest)
+	}
+	if !statuses[0].Applied || statuses[1].Applied || statuses[0].Name == "" {
+		t.Fatalf("only the first migration should be applied, got %+v", statuses)
+	}
+
+	if err := m.Down(); err != nil {
+		t.Fatalf("down: %v", err)
+	}
+	if _, _, ok, err := m.Version(); err != nil || ok {
+		t.Fatalf("expected no version after down, got ok=%v err=%v", ok, err)
+	}
+}
+
+func TestForce(t *testing.T) {
+	m := newSQLiteMigrator(t)
+
+	if err := m.Force(2); err != nil {
+		t.Fatalf("force: %v", err)
+	}
+	assertVersion(t, m, 2)
+}
+
+func TestCheckVersion(t *testing.T) {
+	cfg := config.DBConfig{Driver: config.DB_DRIVER_SQLITE, Name: filepath.Join(t.TempDir(), "test.db")}
+	m, err := New(cfg.Driver, cfg.BuildMigrateDSN())
+	if err != nil {
+		t.Fatalf("new migrator: %v", err)
+	}
+	t.Cleanup(func() { m.Close() })
+	db, err := sqlite.NewSQLiteDB(cfg.BuildDSN())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	check := func() error {
+		return CheckVersion(cfg.Driver, db.QueryRowContext(context.Background(), VersionQuery))
+	}
+
+	if err := check(); err == nil || !strings.Contains(err.Error(), "no migration") {
+		t.Fatalf("expected an error for a fresh database, got %v", err)
+	}
+	if err := m.Goto(1); err != nil {
+		t.Fatalf("goto 1: %v", err)
+	}
+	if err := check(); err == nil {
+		t.Fatal("expected an error for a database behind the latest version")
+	}
+	if err := m.Up(); err != nil {
+		t.Fatalf("up: %v", err)
+	}
+	if err := check(); err != nil {
+		t.Fatalf("migrated database: %v", err)
+	}
+	if _, err := db.Exec("UPDATE schema_migrations SET dirty = 1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := check(); err == nil || !strings.Contains(err.Error(), "dirty") {
+		t.Fatalf("expected a dirty error, got %v", err)
+	}
+}
+
+// A migration added for one driver must be added for every driver
+func TestDriversInSync(t *testing.T) {
+	var want []string
+	for _, driver := range Drivers {
+		files, err := Source(driver)
+		if err != nil {
+			t.Fatalf("source %s: %v", driver, err)
+		}
+		names, err := fs.Glob(files, "*.sql")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want == nil {
+			want = names
+			continue
+		}
+		if !slices.Equal(names, want) {
+			t.Fatalf("%s migrations %v differ from %v", driver, names, want)
+		}
+	}
+}
+
+func TestCreate(t *testing.T) {
+	dir := t.TempDir()
+	for _, driver := range Drivers {
+		migrations := filepath.Join(dir, driver, "migrations")
+		if err := os.MkdirAll(migrations, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(migrations, "000002_existing.up.sql"), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	files, err := Create(dir, "Add Bookmarks")
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if len(files) != 2*len(Drivers) {
+		t.Fatalf("got %d files, want %d", len(files), 2*len(Drivers))
+	}
+	for _, driver := range Drivers {
+		for _, direction := range []string{"up", "down"} {
+			path := filepath.Join(dir, driver, "migrations", "000003_add_bookmarks."+direction+".sql")
+			if _, err := os.Stat(path); err != nil {
+				t.Fatalf("missing %s: %v", path, err)
+			}
+		}
+	}
+
+	if _, err := Create(dir, "  "); err == nil {
+		t.Fatal("expected error for empty name")
+	}
+}
+
+func assertVersion(t *testing.T, m *Migrator, want uint) {
+	t.Helper()
+
+	version, dirty, ok, err := m.Version()
+	if err != nil {
+		t.Fatalf("version: %v", err)
+	}
+	if !ok || dirty || version != want {
+		t.Fatalf("got version %d (ok=%v dirty=%v), want %d", version, ok, dirty, want)
+	}
+}
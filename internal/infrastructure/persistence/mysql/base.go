@@ -1,11 +1,115 @@
 package mysql
 
-import "database/sql"
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"social-media-go-ddd/internal/domain/dto"
+	"social-media-go-ddd/internal/infrastructure/logging"
+	"social-media-go-ddd/internal/infrastructure/persistence/sqlfilter"
+	"social-media-go-ddd/internal/infrastructure/tracing"
+	"time"
+)
 
 type baseMysqlRepository struct {
-	db *sql.DB
+	db instrumentedDB
 }
 
 func NewBaseMysqlRepository(db *sql.DB) baseMysqlRepository {
-	return baseMysqlRepository{db: db}
+	return baseMysqlRepository{db: instrumentedDB{db}}
+}
+
+// Logs every query with the logger of the context and traces it as a child of the span of the context
+type instrumentedDB struct {
+	*sql.DB
+}
+
+func (db instrumentedDB) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	ctx, span := tracing.StartQuery(ctx, "mysql", query)
+	start := time.Now()
+	res, err := db.DB.ExecContext(ctx, query, args...)
+	logging.LogQuery(ctx, query, start, err)
+	tracing.End(span, err)
+	return res, err
+}
+
+// The span ends once the query ran, reading the rows is left out
+func (db instrumentedDB) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	ctx, span := tracing.StartQuery(ctx, "mysql", query)
+	start := time.Now()
+	rows, err := db.DB.QueryContext(ctx, query, args...)
+	logging.LogQuery(ctx, query, start, err)
+	tracing.End(span, err)
+	return rows, err
+}
+
+// row.Err() only holds errors of running the query, errors of Scan such as sql.ErrNoRows are not logged
+func (db instrumentedDB) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	ctx, span := tracing.StartQuery(ctx, "mysql", query)
+	start := time.Now()
+	row := db.DB.QueryRowContext(ctx, query, args...)
+	logging.LogQuery(ctx, query, start, row.Err())
+	tracing.End(span, row.Err())
+	return row
+}
+
+// Runs fn in a transaction, committed when fn returns nil and rolled back otherwise
+func (db instrumentedDB) inTx(ctx context.Context, fn func(tx instrumentedTx) error) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := fn(instrumentedTx{tx}); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// Logs and traces the statements of a transaction like instrumentedDB
+type instrumentedTx struct {
+	*sql.Tx
+}
+
+func (tx instrumentedTx) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	ctx, span := tracing.StartQuery(ctx, "mysql", query)
+	start := time.Now()
+	res, err := tx.Tx.ExecContext(ctx, query, args...)
+	logging.LogQuery(ctx, query, start, err)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (tx instrumentedTx) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	ctx, span := tracing.StartQuery(ctx, "mysql", query)
+	start := time.Now()
+	rows, err := tx.Tx.QueryContext(ctx, query, args...)
+	logging.LogQuery(ctx, query, start, err)
+	tracing.End(span, err)
+	return rows, err
+}
+
+func (tx instrumentedTx) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	ctx, span := tracing.StartQuery(ctx, "mysql", query)
+	start := time.Now()
+	row := tx.Tx.QueryRowContext(ctx, query, args...)
+	logging.LogQuery(ctx, query, start, row.Err())
+	tracing.End(span, row.Err())
+	return row
+}
+
+// Condition selecting the rows that follow the cursor in the (created_at, id) desc order of table, always true on the first page
+func afterCursor(table string, after *dto.Cursor) (string, []any) {
+	if after == nil {
+		return "TRUE", nil
+	}
+	return fmt.Sprintf("(%s.created_at, %s.id) < (?, ?)", table, table), []any{after.CreatedAt, after.ID.String()}
+}
+
+// Condition selecting the published posts of table the viewer may see, see sqlfilter.PostListedTo.
+// A nil viewer only sees public posts
+func listedTo(table string, viewer any) (string, []any) {
+	cond, n := sqlfilter.PostListedTo(table, "?")
+	return cond, slices.Repeat([]any{viewer}, n)
 }
@@ -0,0 +1,99 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"social-media-go-ddd/internal/domain/entity"
+)
+
+type MySQLCollectionRepository struct {
+	baseMysqlRepository
+}
+
+func NewMySQLCollectionRepository(db *sql.DB) *MySQLCollectionRepository {
+	return &MySQLCollectionRepository{
+		baseMysqlRepository: NewBaseMysqlRepository(db),
+	}
+}
+
+const collectionColumns = `id, user_id, name, is_default, created_at, updated_at`
+
+func (r *MySQLCollectionRepository) Save(ctx context.Context, c *entity.Collection) error {
+	return r.db.inTx(ctx, func(tx instrumentedTx) error {
+		return insertCollection(ctx, tx, c)
+	})
+}
+
+// Also used by MySQLUserRepository.Save for the default collection
+func insertCollection(ctx context.Context, tx instrumentedTx, c *entity.Collection) error {
+	query := `INSERT INTO collections (id, user_id, name, is_default) VALUES (?, ?, ?, ?)`
+	_, err := tx.ExecContext(ctx, query, c.ID, c.UserID, c.Name, c.Default)
+	return err
+}
+
+func (r *MySQLCollectionRepository) Update(ctx context.Context, c *entity.Collection) error {
+	query := `UPDATE collections SET name = ?, updated_at = ? WHERE id = ? AND user_id = ?`
+	_, err := r.db.ExecContext(ctx, query, c.Name, c.UpdatedAt, c.ID, c.UserID)
+	return err
+}
+
+func (r *MySQLCollectionRepository) Delete(ctx context.Context, id string, userID string) error {
+	return r.db.inTx(ctx, func(tx instrumentedTx) error {
+		// Favorites saved into the collection meanwhile wait, rather than going away with it
+		var collectionID string
+		query := `SELECT id FROM collections WHERE id = ? AND user_id = ? AND NOT is_default FOR UPDATE`
+		if err := tx.QueryRowContext(ctx, query, id, userID).Scan(&collectionID); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil
+			}
+			return err
+		}
+
+		var defaultID string
+		query = `SELECT id FROM collections WHERE user_id = ? AND is_default`
+		if err := tx.QueryRowContext(ctx, query, userID).Scan(&defaultID); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, `UPDATE favorites SET collection_id = ? WHERE collection_id = ?`, defaultID, id); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, `DELETE FROM collections WHERE id = ?`, id)
+		return err
+	})
+}
+
+func (r *MySQLCollectionRepository) FindByID(ctx context.Context, id string) (*entity.Collection, error) {
+	return scanCollection(r.db.QueryRowContext(ctx, `SELECT `+collectionColumns+` FROM collections WHERE id = ?`, id))
+}
+
+func (r *MySQLCollectionRepository) FindDefault(ctx context.Context, userID string) (*entity.Collection, error) {
+	return scanCollection(r.db.QueryRowContext(ctx, `SELECT `+collectionColumns+` FROM collections WHERE user_id = ? AND is_default`, userID))
+}
+
+func (r *MySQLCollectionRepository) FindByUserID(ctx context.Context, userID string) ([]*entity.Collection, error) {
+	query := `SELECT ` + collectionColumns + ` FROM collections WHERE user_id = ? ORDER BY is_default DESC, created_at, id`
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var collections []*entity.Collection
+	for rows.Next() {
+		collection, err := scanCollection(rows)
+		if err != nil {
+			return nil, err
+		}
+		collections = append(collections, collection)
+	}
+	return collections, rows.Err()
+}
+
+func scanCollection(row interface{ Scan(dest ...any) error }) (*entity.Collection, error) {
+	var c Collection
+	if err := row.Scan(&c.ID, &c.UserID, &c.Name, &c.IsDefault, &c.CreatedAt, &c.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return c.ToEntity()
+}
@@ -3,6 +3,7 @@ package mysql
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"social-media-go-ddd/internal/domain/aggregate"
 	"social-media-go-ddd/internal/domain/dto"
 	"social-media-go-ddd/internal/domain/entity"
@@ -18,33 +19,19 @@ func NewMySQLFavoriteRepository(db *sql.DB) *MySQLFavoriteRepository {
 	}
 }
 
-// If already favorited, does nothing
+// Moves the favorite into the collection of f if the post was favorited before
 func (r *MySQLFavoriteRepository) Save(ctx context.Context, f *entity.Favorite) error {
-	tx, err := r.db.BeginTx(ctx, nil)
-	if err != nil {
-		return err
-	}
-	defer tx.Rollback()
-
-	checkQuery := `SELECT id FROM favorites WHERE user_id = ? AND post_id = ?`
-	row := tx.QueryRowContext(ctx, checkQuery, f.UserID, f.PostID)
-
-	var existingID string
-	err = row.Scan(&existingID)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			// No existing favorite found, insert new favorite
-			insertQuery := `INSERT INTO favorites (id, user_id, post_id) VALUES (?, ?, ?)`
-			_, err := tx.ExecContext(ctx, insertQuery, f.ID, f.UserID, f.PostID)
-			if err != nil {
-				return err
-			}
-		} else {
+	return r.db.inTx(ctx, func(tx instrumentedTx) error {
+		// The collection must belong to the user, rows affected cannot tell as an unchanged upsert affects none
+		var collectionID string
+		query := `SELECT id FROM collections WHERE id = ? AND user_id = ? LOCK IN SHARE MODE`
+		if err := tx.QueryRowContext(ctx, query, f.CollectionID, f.UserID).Scan(&collectionID); err != nil {
 			return err
 		}
-	}
-
-	return tx.Commit()
+		query = `INSERT INTO favorites (id, user_id, post_id, collection_id) VALUES (?, ?, ?, ?) ON DUPLICATE KEY UPDATE collection_id = VALUES(collection_id)`
+		_, err := tx.ExecContext(ctx, query, f.ID, f.UserID, f.PostID, f.CollectionID)
+		return err
+	})
 }
 
 func (r *MySQLFavoriteRepository) Delete(ctx context.Context, userID, postID string) error {
@@ -53,39 +40,68 @@ func (r *MySQLFavoriteRepository) Delete(ctx context.Context, userID, postID str
 	return err
 }
 
-func (r *MySQLFavoriteRepository) FindByUserID(ctx context.Context, userID string) ([]*aggregate.Post, error) {
-	query := `
-		SELECT p.id, p.user_id, p.content, p.created_at, p.updated_at,
+func (r *MySQLFavoriteRepository) DeleteFromCollection(ctx context.Context, collectionID, postID string) error {
+	query := `DELETE FROM favorites WHERE collection_id = ? AND post_id = ?`
+	_, err := r.db.ExecContext(ctx, query, collectionID, postID)
+	return err
+}
+
+func (r *MySQLFavoriteRepository) FindByUserID(ctx context.Context, userID string, currentUserID *string, page dto.CursorPage) ([]*aggregate.Post, *dto.Cursor, error) {
+	return r.find(ctx, "favorites.user_id", userID, currentUserID, page)
+}
+
+func (r *MySQLFavoriteRepository) FindByCollectionID(ctx context.Context, collectionID string, currentUserID *string, page dto.CursorPage) ([]*aggregate.Post, *dto.Cursor, error) {
+	return r.find(ctx, "favorites.collection_id", collectionID, currentUserID, page)
+}
+
+// Favorites whose column equals id
+func (r *MySQLFavoriteRepository) find(ctx context.Context, column, id string, currentUserID *string, page dto.CursorPage) ([]*aggregate.Post, *dto.Cursor, error) {
+	listed, listedArgs := listedTo("p", currentUserID)
+	after, afterArgs := afterCursor("favorites", page.After)
+	query := fmt.Sprintf(`
+		SELECT p.id, p.user_id, p.content, p.created_at, p.updated_at, p.version, p.status, p.publish_at, p.visibility,
+			post_quotes.quoted_post_id,
 			COALESCE(likes_count.count, 0) AS like_count,
 			COALESCE(favorites_count.count, 0) AS favorite_count,
 			COALESCE(reposts_count.count, 0) AS repost_count,
+			COALESCE(quotes_count.count, 0) AS quote_count,
+			COALESCE(revisions_count.count, 0) AS edit_count,
 			users.id, users.username, users.email,
+			favorites.id, favorites.created_at,
 			-- Check if the current user has liked, favorited, or reposted the post
 			EXISTS (SELECT 1 FROM likes l WHERE l.post_id = p.id AND l.user_id = ?) AS liked,
 			EXISTS (SELECT 1 FROM favorites f WHERE f.post_id = p.id AND f.user_id = ?) AS favorited,
 			EXISTS (SELECT 1 FROM reposts r WHERE r.post_id = p.id AND r.user_id = ?) AS reposted
 		FROM favorites
-		INNER JOIN users ON favorites.user_id = users.id
 		INNER JOIN posts p ON favorites.post_id = p.id
+		INNER JOIN users ON p.user_id = users.id
 		LEFT JOIN (SELECT post_id, COUNT(*) AS count FROM likes GROUP BY post_id) likes_count ON likes_count.post_id = p.id
 		LEFT JOIN (SELECT post_id, COUNT(*) AS count FROM favorites GROUP BY post_id) favorites_count ON favorites_count.post_id = p.id
 		LEFT JOIN (SELECT post_id, COUNT(*) AS count FROM reposts GROUP BY post_id) reposts_count ON reposts_count.post_id = p.id
-		WHERE favorites.user_id = ?
-		ORDER BY favorites.created_at DESC
-	`
-
-	rows, err := r.db.QueryContext(ctx, query, userID, userID, userID, userID)
+		LEFT JOIN (SELECT post_quotes.quoted_post_id AS post_id, COUNT(*) AS count FROM post_quotes INNER JOIN posts quotes ON quotes.id = post_quotes.post_id WHERE quotes.status = 'published' GROUP BY post_quotes.quoted_post_id) quotes_count ON quotes_count.post_id = p.id
+		LEFT JOIN post_quotes ON post_quotes.post_id = p.id
+		LEFT JOIN (SELECT post_id, COUNT(*) AS count FROM post_revisions GROUP BY post_id) revisions_count ON revisions_count.post_id = p.id
+		WHERE %s = ? AND %s AND %s
+		ORDER BY favorites.created_at DESC, favorites.id DESC
+		LIMIT ?
+	`, column, listed, after)
+
+	args := append([]any{currentUserID, currentUserID, currentUserID, id}, listedArgs...)
+	args = append(args, afterArgs...)
+	rows, err := r.db.QueryContext(ctx, query, append(args, page.FetchLimit())...)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	defer rows.Close()
 
 	var posts []*aggregate.Post
+	var keys []dto.Cursor
 	for rows.Next() {
 		var post Post
-		var likeCount, favoriteCount, repostCount int
+		var likeCount, favoriteCount, repostCount, quoteCount, editCount int
 		var liked, favorited, reposted bool
 		var user User
+		var favorite BaseModel
 
 		if err := rows.Scan(
 			&post.ID,
@@ -93,33 +109,50 @@ func (r *MySQLFavoriteRepository) FindByUserID(ctx context.Context, userID strin
 			&post.Content,
 			&post.CreatedAt,
 			&post.UpdatedAt,
+			&post.Version,
+			&post.Status,
+			&post.PublishAt,
+			&post.Visibility,
+			&post.QuotedPostID,
 			&likeCount,
 			&favoriteCount,
 			&repostCount,
+			&quoteCount,
+			&editCount,
 			&user.ID,
 			&user.Username,
 			&user.Email,
+			&favorite.ID,
+			&favorite.CreatedAt,
 			&liked,
 			&favorited,
 			&reposted,
 		); err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
 		ePost, err := post.ToEntity()
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
 		eUser, err := user.ToEntity()
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
+		key, err := favorite.ToEntity()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		keys = append(keys, dto.Cursor{CreatedAt: key.CreatedAt, ID: key.ID})
 		posts = append(posts, aggregate.NewPost(*ePost, *eUser, dto.CommonPostAggregate{
 			LikeCount:     likeCount,
 			FavoriteCount: favoriteCount,
 			RepostCount:   repostCount,
+			QuoteCount:    quoteCount,
+			EditCount:     editCount,
 			Liked:         liked,
 			Favorited:     favorited,
 			Reposted:      reposted,
@@ -127,8 +160,18 @@ func (r *MySQLFavoriteRepository) FindByUserID(ctx context.Context, userID strin
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	return posts, nil
+	posts, next := dto.PageOf(page, posts, keys)
+	if err := attachQuotes(ctx, r.db, posts, currentUserID); err != nil {
+		return nil, nil, err
+	}
+	if err := attachPolls(ctx, r.db, posts, currentUserID); err != nil {
+		return nil, nil, err
+	}
+	if err := attachReactions(ctx, r.db, posts, currentUserID); err != nil {
+		return nil, nil, err
+	}
+	return posts, next, nil
 }
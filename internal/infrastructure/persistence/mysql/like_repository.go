@@ -3,7 +3,11 @@ package mysql
 import (
 	"context"
 	"database/sql"
+	"fmt"
+	"slices"
+	"social-media-go-ddd/internal/domain/aggregate"
 	"social-media-go-ddd/internal/domain/entity"
+	"strings"
 )
 
 type MySQLLikeRepository struct {
@@ -16,33 +20,32 @@ func NewMySQLLikeRepository(db *sql.DB) *MySQLLikeRepository {
 	}
 }
 
-// If already liked, does nothing
-func (r *MySQLLikeRepository) Save(ctx context.Context, l *entity.Like) error {
-	tx, err := r.db.BeginTx(ctx, nil)
-	if err != nil {
-		return err
-	}
-	defer tx.Rollback()
+// Replaces the reaction if the user already reacted to the post
+func (r *MySQLLikeRepository) Save(ctx context.Context, l *entity.Like) (bool, error) {
+	query := `
+		INSERT INTO likes (id, user_id, post_id, reaction) VALUES (?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE reaction = VALUES(reaction), updated_at = CURRENT_TIMESTAMP
+	`
+	return insertedRow(r.db.ExecContext(ctx, query, l.ID, l.UserID, l.PostID, l.Reaction))
+}
 
-	checkQuery := `SELECT id FROM likes WHERE user_id = ? AND post_id = ?`
-	row := tx.QueryRowContext(ctx, checkQuery, l.UserID, l.PostID)
+// Keeps the reaction if the user already reacted to the post. Not INSERT IGNORE, which would ignore an unknown post too
+func (r *MySQLLikeRepository) SaveIfAbsent(ctx context.Context, l *entity.Like) (bool, error) {
+	query := `
+		INSERT INTO likes (id, user_id, post_id, reaction) VALUES (?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE id = id
+	`
+	return insertedRow(r.db.ExecContext(ctx, query, l.ID, l.UserID, l.PostID, l.Reaction))
+}
 
-	var existingID string
-	err = row.Scan(&existingID)
+// Whether an INSERT ... ON DUPLICATE KEY UPDATE inserted its row, MySQL reports 1 affected row for an insert, 2 for
+// an update and 0 for a duplicate left as it was
+func insertedRow(res sql.Result, err error) (bool, error) {
 	if err != nil {
-		if err == sql.ErrNoRows {
-			// No existing like found, insert new like
-			insertQuery := `INSERT INTO likes (id, user_id, post_id) VALUES (?, ?, ?)`
-			_, err := tx.ExecContext(ctx, insertQuery, l.ID, l.UserID, l.PostID)
-			if err != nil {
-				return err
-			}
-		} else {
-			return err
-		}
+		return false, err
 	}
-
-	return tx.Commit()
+	n, err := res.RowsAffected()
+	return n == 1, err
 }
 
 func (r *MySQLLikeRepository) Delete(ctx context.Context, userID, postID string) error {
@@ -50,3 +53,54 @@ func (r *MySQLLikeRepository) Delete(ctx context.Context, userID, postID string)
 	_, err := r.db.ExecContext(ctx, query, userID, postID)
 	return err
 }
+
+// Sets the reaction counts and the viewer's reaction of the posts and their quoted posts, loaded with a single query
+func attachReactions(ctx context.Context, db instrumentedDB, posts []*aggregate.Post, currentUserID *string) error {
+	// A page may hold a post next to its repost or quote, both get the reactions
+	byID := map[string][]*aggregate.Post{}
+	var ids []any
+	for _, p := range slices.Concat(posts, quotedPosts(posts)) {
+		id := p.ID.String()
+		if _, ok := byID[id]; !ok {
+			ids = append(ids, id)
+		}
+		byID[id] = append(byID[id], p)
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	query := fmt.Sprintf(`SELECT post_id, reaction, COUNT(*) AS count, COALESCE(MAX(user_id = ?), 0) AS reacted
+	FROM likes
+	WHERE post_id IN (?%s)
+	GROUP BY post_id, reaction`, strings.Repeat(", ?", len(ids)-1))
+
+	rows, err := db.QueryContext(ctx, query, append([]any{currentUserID}, ids...)...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var postID, reaction string
+		var count int
+		var reacted bool
+		if err := rows.Scan(&postID, &reaction, &count, &reacted); err != nil {
+			return err
+		}
+		for _, p := range byID[postID] {
+			p.AddReactions(entity.Reaction(reaction), count, reacted)
+		}
+	}
+	return rows.Err()
+}
+
+func quotedPosts(posts []*aggregate.Post) []*aggregate.Post {
+	var quoted []*aggregate.Post
+	for _, p := range posts {
+		if p.Quote != nil {
+			quoted = append(quoted, p.Quote)
+		}
+	}
+	return quoted
+}
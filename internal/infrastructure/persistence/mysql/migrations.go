@@ -0,0 +1,8 @@
+package mysql
+
+import "embed"
+
+// Migrations are embedded such that the binaries don't depend on the working directory
+//
+//go:embed migrations/*.sql
+var Migrations embed.FS
@@ -0,0 +1 @@
+ALTER TABLE reposts DROP INDEX reposts_user_id_post_id_key;
@@ -0,0 +1,7 @@
+-- Keep only the latest repost per user and post before enforcing uniqueness
+DELETE a FROM reposts a
+    INNER JOIN reposts b ON a.user_id = b.user_id AND a.post_id = b.post_id
+WHERE a.created_at < b.created_at
+   OR (a.created_at = b.created_at AND a.id < b.id);
+
+ALTER TABLE reposts ADD UNIQUE KEY reposts_user_id_post_id_key (user_id, post_id);
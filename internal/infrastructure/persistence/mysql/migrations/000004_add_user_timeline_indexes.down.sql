@@ -0,0 +1,5 @@
+DROP INDEX reposts_user_id_created_at_idx ON reposts;
+DROP INDEX favorites_user_id_created_at_idx ON favorites;
+-- The new index may have replaced the one MySQL created for the foreign key on posts.user_id, which then needs another
+CREATE INDEX posts_user_id_idx ON posts (user_id);
+DROP INDEX posts_user_id_created_at_idx ON posts;
@@ -0,0 +1,11 @@
+-- Content of a post before each of its edits
+CREATE TABLE post_revisions (
+    id CHAR(36) PRIMARY KEY DEFAULT (UUID()),
+    post_id CHAR(36) NOT NULL,
+    editor_id CHAR(36) NOT NULL,
+    content TEXT NOT NULL,
+    edited_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX post_revisions_post_id_edited_at_idx (post_id, edited_at, id),
+    FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE,
+    FOREIGN KEY (editor_id) REFERENCES users(id) ON DELETE CASCADE
+);
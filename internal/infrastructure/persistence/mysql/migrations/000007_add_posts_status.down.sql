@@ -0,0 +1,3 @@
+DROP INDEX posts_status_publish_at_idx ON posts;
+ALTER TABLE posts DROP COLUMN publish_at;
+ALTER TABLE posts DROP COLUMN status;
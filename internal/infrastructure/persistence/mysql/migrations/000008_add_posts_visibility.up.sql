@@ -0,0 +1,11 @@
+-- Who besides the author can see a published post: public, followers or mentioned
+ALTER TABLE posts ADD COLUMN visibility VARCHAR(16) NOT NULL DEFAULT 'public';
+-- Users mentioned as @username in the content of a post, mentioned posts are visible to them
+CREATE TABLE post_mentions (
+    id CHAR(36) PRIMARY KEY DEFAULT (UUID()),
+    post_id CHAR(36) NOT NULL,
+    user_id CHAR(36) NOT NULL,
+    UNIQUE (post_id, user_id),
+    FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
@@ -0,0 +1,9 @@
+-- Post quoted by another post, a quote loses its quoted post when that one is deleted
+CREATE TABLE post_quotes (
+    id CHAR(36) PRIMARY KEY DEFAULT (UUID()),
+    post_id CHAR(36) NOT NULL UNIQUE,
+    quoted_post_id CHAR(36) NOT NULL,
+    INDEX post_quotes_quoted_post_id_idx (quoted_post_id),
+    FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE,
+    FOREIGN KEY (quoted_post_id) REFERENCES posts(id) ON DELETE CASCADE
+);
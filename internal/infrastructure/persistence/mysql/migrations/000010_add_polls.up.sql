@@ -0,0 +1,40 @@
+-- Poll attached to a post, choice is single or multiple
+CREATE TABLE polls (
+    id CHAR(36) PRIMARY KEY DEFAULT (UUID()),
+    post_id CHAR(36) NOT NULL UNIQUE,
+    choice VARCHAR(16) NOT NULL DEFAULT 'single',
+    closes_at DATETIME NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE
+);
+
+CREATE TABLE poll_options (
+    id CHAR(36) PRIMARY KEY DEFAULT (UUID()),
+    poll_id CHAR(36) NOT NULL,
+    position INT NOT NULL,
+    text VARCHAR(100) NOT NULL,
+    UNIQUE (poll_id, position),
+    FOREIGN KEY (poll_id) REFERENCES polls(id) ON DELETE CASCADE
+);
+
+-- One vote per user and poll, the options it chose are in poll_vote_options
+CREATE TABLE poll_votes (
+    id CHAR(36) PRIMARY KEY DEFAULT (UUID()),
+    poll_id CHAR(36) NOT NULL,
+    user_id CHAR(36) NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (poll_id, user_id),
+    FOREIGN KEY (poll_id) REFERENCES polls(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE poll_vote_options (
+    id CHAR(36) PRIMARY KEY DEFAULT (UUID()),
+    vote_id CHAR(36) NOT NULL,
+    option_id CHAR(36) NOT NULL,
+    UNIQUE (vote_id, option_id),
+    INDEX poll_vote_options_option_id_idx (option_id),
+    FOREIGN KEY (vote_id) REFERENCES poll_votes(id) ON DELETE CASCADE,
+    FOREIGN KEY (option_id) REFERENCES poll_options(id) ON DELETE CASCADE
+);
@@ -0,0 +1,13 @@
+-- Posts pinned to the top of a profile, repost_id is set when the user pinned their repost of another user's post
+CREATE TABLE pinned_posts (
+    id CHAR(36) PRIMARY KEY DEFAULT (UUID()),
+    user_id CHAR(36) NOT NULL,
+    post_id CHAR(36) NOT NULL,
+    repost_id CHAR(36),
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE,
+    FOREIGN KEY (repost_id) REFERENCES reposts(id) ON DELETE CASCADE,
+    UNIQUE (user_id, post_id)
+);
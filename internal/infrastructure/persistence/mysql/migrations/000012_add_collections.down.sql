@@ -0,0 +1,3 @@
+ALTER TABLE favorites DROP FOREIGN KEY favorites_collection_id_fk;
+ALTER TABLE favorites DROP INDEX favorites_collection_id_created_at_idx, DROP COLUMN collection_id;
+DROP TABLE IF EXISTS collections;
@@ -0,0 +1,24 @@
+-- Named, private groups of favorites, every user has a default collection that cannot be deleted
+CREATE TABLE collections (
+    id CHAR(36) PRIMARY KEY DEFAULT (UUID()),
+    user_id CHAR(36) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    is_default BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    UNIQUE (user_id, name)
+);
+
+INSERT INTO collections (id, user_id, name, is_default) SELECT UUID(), id, 'Favorites', TRUE FROM users;
+
+-- Existing favorites move into the default collection of their user
+ALTER TABLE favorites ADD COLUMN collection_id CHAR(36) NULL;
+UPDATE favorites
+INNER JOIN collections ON collections.user_id = favorites.user_id AND collections.is_default
+SET favorites.collection_id = collections.id;
+ALTER TABLE favorites
+    MODIFY collection_id CHAR(36) NOT NULL,
+    -- Collections are paginated like the favorites of a user
+    ADD INDEX favorites_collection_id_created_at_idx (collection_id, created_at, id),
+    ADD CONSTRAINT favorites_collection_id_fk FOREIGN KEY (collection_id) REFERENCES collections(id) ON DELETE CASCADE;
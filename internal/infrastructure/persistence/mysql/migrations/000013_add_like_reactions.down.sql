@@ -0,0 +1 @@
+ALTER TABLE likes DROP COLUMN reaction;
@@ -1,9 +1,12 @@
 package mysql
 
 import (
+	"database/sql"
 	"social-media-go-ddd/internal/domain/entity"
 	"social-media-go-ddd/internal/domain/valueobject"
 	"time"
+
+	"github.com/google/uuid"
 )
 
 type BaseModel struct {
@@ -52,8 +55,14 @@ func (u *User) ToEntity() (*entity.User, error) {
 
 type Post struct {
 	BaseModel
-	UserID  string `db:"user_id"`
-	Content string `db:"content"`
+	UserID     string       `db:"user_id"`
+	Content    string       `db:"content"`
+	Version    int          `db:"version"`
+	Status     string       `db:"status"`
+	PublishAt  sql.NullTime `db:"publish_at"`
+	Visibility string       `db:"visibility"`
+	// From post_quotes, NULL when the post quotes none
+	QuotedPostID uuid.NullUUID `db:"quoted_post_id"`
 }
 
 func (p *Post) ToEntity() (*entity.Post, error) {
@@ -69,16 +78,71 @@ func (p *Post) ToEntity() (*entity.Post, error) {
 		return nil, err
 	}
 	return &entity.Post{
-		BaseEntity: baseEntity,
-		UserID:     userID,
-		Content:    p.Content,
+		BaseEntity:   baseEntity,
+		UserID:       userID,
+		Content:      p.Content,
+		Version:      p.Version,
+		Status:       entity.PostStatus(p.Status),
+		PublishAt:    nullTimePtr(p.PublishAt),
+		Visibility:   entity.PostVisibility(p.Visibility),
+		QuotedPostID: nullUUIDPtr(p.QuotedPostID),
+	}, nil
+}
+
+// Nil for NULL
+func nullUUIDPtr(id uuid.NullUUID) *uuid.UUID {
+	if !id.Valid {
+		return nil
+	}
+	return &id.UUID
+}
+
+// Nil for NULL
+func nullTimePtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+type PostRevision struct {
+	ID       string    `db:"id"`
+	PostID   string    `db:"post_id"`
+	EditorID string    `db:"editor_id"`
+	Content  string    `db:"content"`
+	EditedAt time.Time `db:"edited_at"`
+}
+
+func (r *PostRevision) ToEntity() (*entity.PostRevision, error) {
+	if r == nil {
+		return nil, nil
+	}
+	id, err := entity.StringToUUID(r.ID)
+	if err != nil {
+		return nil, err
+	}
+	postID, err := entity.StringToUUID(r.PostID)
+	if err != nil {
+		return nil, err
+	}
+	editorID, err := entity.StringToUUID(r.EditorID)
+	if err != nil {
+		return nil, err
+	}
+	return &entity.PostRevision{
+		ID:       id,
+		PostID:   postID,
+		EditorID: editorID,
+		Content:  r.Content,
+		EditedAt: r.EditedAt,
 	}, nil
 }
 
 type Like struct {
 	BaseModel
-	UserID string `db:"user_id"`
-	PostID string `db:"post_id"`
+	UserID   string `db:"user_id"`
+	PostID   string `db:"post_id"`
+	Reaction string `db:"reaction"`
 }
 
 func (l *Like) ToEntity() (*entity.Like, error) {
@@ -101,13 +165,15 @@ func (l *Like) ToEntity() (*entity.Like, error) {
 		BaseEntity: baseEntity,
 		UserID:     userID,
 		PostID:     postID,
+		Reaction:   entity.Reaction(l.Reaction),
 	}, nil
 }
 
 type Favorite struct {
 	BaseModel
-	UserID string `db:"user_id"`
-	PostID string `db:"post_id"`
+	UserID       string `db:"user_id"`
+	PostID       string `db:"post_id"`
+	CollectionID string `db:"collection_id"`
 }
 
 func (f *Favorite) ToEntity() (*entity.Favorite, error) {
@@ -126,10 +192,42 @@ func (f *Favorite) ToEntity() (*entity.Favorite, error) {
 	if err != nil {
 		return nil, err
 	}
+	collectionID, err := entity.StringToUUID(f.CollectionID)
+	if err != nil {
+		return nil, err
+	}
 	return &entity.Favorite{
+		BaseEntity:   baseEntity,
+		UserID:       userID,
+		PostID:       postID,
+		CollectionID: collectionID,
+	}, nil
+}
+
+type Collection struct {
+	BaseModel
+	UserID    string `db:"user_id"`
+	Name      string `db:"name"`
+	IsDefault bool   `db:"is_default"`
+}
+
+func (c *Collection) ToEntity() (*entity.Collection, error) {
+	if c == nil {
+		return nil, nil
+	}
+	baseEntity, err := c.BaseModel.ToEntity()
+	if err != nil {
+		return nil, err
+	}
+	userID, err := entity.StringToUUID(c.UserID)
+	if err != nil {
+		return nil, err
+	}
+	return &entity.Collection{
 		BaseEntity: baseEntity,
 		UserID:     userID,
-		PostID:     postID,
+		Name:       c.Name,
+		Default:    c.IsDefault,
 	}, nil
 }
 
@@ -217,3 +315,51 @@ func (f *Follow) ToEntity() (*entity.Follow, error) {
 		FolloweeID: followeeID,
 	}, nil
 }
+
+type Poll struct {
+	BaseModel
+	PostID   string    `db:"post_id"`
+	Choice   string    `db:"choice"`
+	ClosesAt time.Time `db:"closes_at"`
+}
+
+func (p *Poll) ToEntity() (*entity.Poll, error) {
+	if p == nil {
+		return nil, nil
+	}
+	baseEntity, err := p.BaseModel.ToEntity()
+	if err != nil {
+		return nil, err
+	}
+	postID, err := entity.StringToUUID(p.PostID)
+	if err != nil {
+		return nil, err
+	}
+	return &entity.Poll{
+		BaseEntity: baseEntity,
+		PostID:     postID,
+		Choice:     entity.PollChoice(p.Choice),
+		ClosesAt:   p.ClosesAt,
+	}, nil
+}
+
+type PollOption struct {
+	ID       string `db:"id"`
+	Position int    `db:"position"`
+	Text     string `db:"text"`
+}
+
+func (o *PollOption) ToEntity() (*entity.PollOption, error) {
+	if o == nil {
+		return nil, nil
+	}
+	id, err := entity.StringToUUID(o.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &entity.PollOption{
+		ID:       id,
+		Position: o.Position,
+		Text:     o.Text,
+	}, nil
+}
@@ -0,0 +1,44 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"social-media-go-ddd/internal/domain/entity"
+
+	"github.com/google/uuid"
+)
+
+type MySQLPollVoteRepository struct {
+	baseMysqlRepository
+}
+
+func NewMySQLPollVoteRepository(db *sql.DB) *MySQLPollVoteRepository {
+	return &MySQLPollVoteRepository{
+		baseMysqlRepository: NewBaseMysqlRepository(db),
+	}
+}
+
+// A second vote of the user fails on UNIQUE (poll_id, user_id), an option of another poll with sql.ErrNoRows
+func (r *MySQLPollVoteRepository) Save(ctx context.Context, v *entity.PollVote) error {
+	return r.db.inTx(ctx, func(tx instrumentedTx) error {
+		query := `INSERT INTO poll_votes (id, poll_id, user_id) VALUES (?, ?, ?)`
+		if _, err := tx.ExecContext(ctx, query, v.ID, v.PollID, v.UserID); err != nil {
+			return err
+		}
+		for _, optionID := range v.OptionIDs {
+			query := `INSERT INTO poll_vote_options (id, vote_id, option_id) SELECT ?, ?, id FROM poll_options WHERE id = ? AND poll_id = ?`
+			res, err := tx.ExecContext(ctx, query, uuid.New(), v.ID, optionID, v.PollID)
+			if err != nil {
+				return err
+			}
+			inserted, err := res.RowsAffected()
+			if err != nil {
+				return err
+			}
+			if inserted == 0 {
+				return sql.ErrNoRows
+			}
+		}
+		return nil
+	})
+}
@@ -3,9 +3,11 @@ package mysql
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"social-media-go-ddd/internal/domain/aggregate"
 	"social-media-go-ddd/internal/domain/dto"
 	"social-media-go-ddd/internal/domain/entity"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -22,23 +24,50 @@ func NewMySQLPostRepository(db *sql.DB) *MySQLPostRepository {
 }
 
 func (r *MySQLPostRepository) Save(ctx context.Context, p *entity.Post) error {
-	query := `INSERT INTO posts (id, user_id, content) VALUES (?, ?, ?)`
-	_, err := r.db.ExecContext(ctx, query, p.ID, p.UserID, p.Content)
-	return err
+	return r.db.inTx(ctx, func(tx instrumentedTx) error {
+		query := `INSERT INTO posts (id, user_id, content, version, status, publish_at, visibility) VALUES (?, ?, ?, ?, ?, ?, ?)`
+		if _, err := tx.ExecContext(ctx, query, p.ID, p.UserID, p.Content, p.Version, string(p.Status), p.PublishAt, string(p.Visibility)); err != nil {
+			return err
+		}
+		if p.QuotedPostID != nil {
+			query := `INSERT INTO post_quotes (id, post_id, quoted_post_id) VALUES (?, ?, ?)`
+			if _, err := tx.ExecContext(ctx, query, uuid.New(), p.ID, *p.QuotedPostID); err != nil {
+				return err
+			}
+		}
+		return insertMentions(ctx, tx, p)
+	})
+}
+
+// Keep the users the content of p mentions, unknown usernames are skipped
+func insertMentions(ctx context.Context, tx instrumentedTx, p *entity.Post) error {
+	for _, username := range p.Mentions() {
+		query := `INSERT INTO post_mentions (id, post_id, user_id) SELECT ?, ?, id FROM users WHERE username = ?`
+		if _, err := tx.ExecContext(ctx, query, uuid.New(), p.ID, username); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (r *MySQLPostRepository) FindByID(ctx context.Context, id string, currentUserID *string) (*aggregate.Post, error) {
-	query := `SELECT posts.id, posts.user_id, posts.content, posts.created_at, posts.updated_at,
+	query := `SELECT posts.id, posts.user_id, posts.content, posts.created_at, posts.updated_at, posts.version, posts.status, posts.publish_at, posts.visibility,
+		post_quotes.quoted_post_id,
 		COALESCE(likes_count.count, 0) AS like_count,
 		COALESCE(favorites_count.count, 0) AS favorite_count,
 		COALESCE(reposts_count.count, 0) AS repost_count,
+		COALESCE(quotes_count.count, 0) AS quote_count,
+		COALESCE(revisions_count.count, 0) AS edit_count,
 		 users.id,
        users.username,
        users.email,
 	   -- Check if the current user has liked, favorited, or reposted the post
 	   EXISTS (SELECT 1 FROM likes l WHERE l.post_id = posts.id AND l.user_id = ?) AS liked,
 	   EXISTS (SELECT 1 FROM favorites f WHERE f.post_id = posts.id AND f.user_id = ?) AS favorited,
-	   EXISTS (SELECT 1 FROM reposts r WHERE r.post_id = posts.id AND r.user_id = ?) AS reposted
+	   EXISTS (SELECT 1 FROM reposts r WHERE r.post_id = posts.id AND r.user_id = ?) AS reposted,
+	   -- Relationship of the current user to the post, decides whether they may see it
+	   EXISTS (SELECT 1 FROM follows WHERE follows.follower_id = ? AND follows.followee_id = posts.user_id) AS follower,
+	   EXISTS (SELECT 1 FROM post_mentions m WHERE m.post_id = posts.id AND m.user_id = ?) AS mentioned
 	FROM posts
 	INNER JOIN users ON posts.user_id = users.id
 	LEFT JOIN (
@@ -50,27 +79,43 @@ func (r *MySQLPostRepository) FindByID(ctx context.Context, id string, currentUs
 	LEFT JOIN (
 		SELECT post_id, COUNT(*) AS count FROM reposts GROUP BY post_id
 	) reposts_count ON reposts_count.post_id = posts.id
+	LEFT JOIN (
+		SELECT post_quotes.quoted_post_id AS post_id, COUNT(*) AS count FROM post_quotes INNER JOIN posts quotes ON quotes.id = post_quotes.post_id WHERE quotes.status = 'published' GROUP BY post_quotes.quoted_post_id
+	) quotes_count ON quotes_count.post_id = posts.id
+	LEFT JOIN post_quotes ON post_quotes.post_id = posts.id
+	LEFT JOIN (
+		SELECT post_id, COUNT(*) AS count FROM post_revisions GROUP BY post_id
+	) revisions_count ON revisions_count.post_id = posts.id
 	WHERE posts.id = ?`
 
 	var user User
 	var post Post
-	var likeCount, favoriteCount, repostCount int
-	var liked, favorited, reposted bool
-	err := r.db.QueryRowContext(ctx, query, currentUserID, currentUserID, currentUserID, id).Scan(
+	var likeCount, favoriteCount, repostCount, quoteCount, editCount int
+	var liked, favorited, reposted, follower, mentioned bool
+	err := r.db.QueryRowContext(ctx, query, currentUserID, currentUserID, currentUserID, currentUserID, currentUserID, id).Scan(
 		&post.ID,
 		&post.UserID,
 		&post.Content,
 		&post.CreatedAt,
 		&post.UpdatedAt,
+		&post.Version,
+		&post.Status,
+		&post.PublishAt,
+		&post.Visibility,
+		&post.QuotedPostID,
 		&likeCount,
 		&favoriteCount,
 		&repostCount,
+		&quoteCount,
+		&editCount,
 		&user.ID,
 		&user.Username,
 		&user.Email,
 		&liked,
 		&favorited,
 		&reposted,
+		&follower,
+		&mentioned,
 	)
 	if err != nil {
 		return nil, err
@@ -86,97 +131,105 @@ func (r *MySQLPostRepository) FindByID(ctx context.Context, id string, currentUs
 		return nil, err
 	}
 
-	return aggregate.NewPost(*ePost, *eUser, dto.CommonPostAggregate{
+	aggregatePost := aggregate.NewPost(*ePost, *eUser, dto.CommonPostAggregate{
 		LikeCount:     likeCount,
 		FavoriteCount: favoriteCount,
 		RepostCount:   repostCount,
+		QuoteCount:    quoteCount,
+		EditCount:     editCount,
 		Liked:         liked,
 		Favorited:     favorited,
 		Reposted:      reposted,
-	}), nil
+	})
+	aggregatePost.Viewer = entity.PostViewer{
+		Author:    currentUserID != nil && *currentUserID == ePost.UserID.String(),
+		Follower:  follower,
+		Mentioned: mentioned,
+	}
+	if err := attachQuotes(ctx, r.db, []*aggregate.Post{aggregatePost}, currentUserID); err != nil {
+		return nil, err
+	}
+	if err := attachPolls(ctx, r.db, []*aggregate.Post{aggregatePost}, currentUserID); err != nil {
+		return nil, err
+	}
+	if err := attachReactions(ctx, r.db, []*aggregate.Post{aggregatePost}, currentUserID); err != nil {
+		return nil, err
+	}
+	return aggregatePost, nil
 }
 
-func (r *MySQLPostRepository) FindByUserID(ctx context.Context, userID string) ([]*aggregate.Post, error) {
+func (r *MySQLPostRepository) FindByUserID(ctx context.Context, userID string, currentUserID *string, page dto.CursorPage) ([]*aggregate.Post, *dto.Cursor, error) {
 	var user User
 	err := r.db.QueryRowContext(ctx, "SELECT id, username, email FROM users WHERE id=?", userID).
 		Scan(&user.ID, &user.Username, &user.Email)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	query := `SELECT posts.id, posts.user_id, posts.content, posts.created_at, posts.updated_at,
-		COALESCE(likes_count.count, 0) AS like_count,
-		COALESCE(favorites_count.count, 0) AS favorite_count,
-		COALESCE(reposts_count.count, 0) AS repost_count,
-		-- Check if the current user has liked, favorited, or reposted the post
-		EXISTS (SELECT 1 FROM likes l WHERE l.post_id = posts.id AND l.user_id = ?) AS liked,
-		EXISTS (SELECT 1 FROM favorites f WHERE f.post_id = posts.id AND f.user_id = ?) AS favorited,
-		EXISTS (SELECT 1 FROM reposts r WHERE r.post_id = posts.id AND r.user_id = ?) AS reposted
+	listed, listedArgs := listedTo("posts", currentUserID)
+	after, afterArgs := afterCursor("posts", page.After)
+	query := fmt.Sprintf(`SELECT %s
 	FROM posts
-	LEFT JOIN (
-		SELECT post_id, COUNT(*) AS count FROM likes GROUP BY post_id
-	) likes_count ON likes_count.post_id = posts.id
-	LEFT JOIN (
-		SELECT post_id, COUNT(*) AS count FROM favorites GROUP BY post_id
-	) favorites_count ON favorites_count.post_id = posts.id
-	LEFT JOIN (
-		SELECT post_id, COUNT(*) AS count FROM reposts GROUP BY post_id
-	) reposts_count ON reposts_count.post_id = posts.id
-	WHERE posts.user_id = ?`
-
-	rows, err := r.db.QueryContext(ctx, query, userID, userID, userID, userID)
+	%s
+	WHERE posts.user_id = ? AND %s AND %s
+		-- Pinned posts are listed first instead
+		AND NOT EXISTS (SELECT 1 FROM pinned_posts WHERE pinned_posts.user_id = posts.user_id AND pinned_posts.post_id = posts.id)
+	ORDER BY posts.created_at DESC, posts.id DESC
+	LIMIT ?`, postAggregateColumns, postCountJoins, listed, after)
+
+	args := append([]any{currentUserID, currentUserID, currentUserID, userID}, listedArgs...)
+	args = append(args, afterArgs...)
+	rows, err := r.db.QueryContext(ctx, query, append(args, page.FetchLimit())...)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	defer rows.Close()
 
 	var posts []*aggregate.Post
+	var keys []dto.Cursor
 	for rows.Next() {
 		var post Post
-		var liked, favorited, reposted bool
-		var likeCount, favoriteCount, repostCount int
-
-		if err := rows.Scan(
-			&post.ID,
-			&post.UserID,
-			&post.Content,
-			&post.CreatedAt,
-			&post.UpdatedAt,
-			&likeCount,
-			&favoriteCount,
-			&repostCount,
-			&liked,
-			&favorited,
-			&reposted,
-		); err != nil {
-			return nil, err
+		cpa, err := scanPostAggregate(rows, &post)
+		if err != nil {
+			return nil, nil, err
 		}
 
 		ePost, err := post.ToEntity()
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
 		eUser, err := user.ToEntity()
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
-		posts = append(posts, aggregate.NewPost(*ePost, *eUser, dto.CommonPostAggregate{
-			LikeCount:     likeCount,
-			FavoriteCount: favoriteCount,
-			RepostCount:   repostCount,
-			Liked:         liked,
-			Favorited:     favorited,
-			Reposted:      reposted,
-		}))
+		keys = append(keys, dto.Cursor{CreatedAt: ePost.CreatedAt, ID: ePost.ID})
+		posts = append(posts, aggregate.NewPost(*ePost, *eUser, cpa))
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	return posts, nil
+	posts, next := dto.PageOf(page, posts, keys)
+	if page.After == nil {
+		pinned, err := findPinnedPosts(ctx, r.db, user, currentUserID)
+		if err != nil {
+			return nil, nil, err
+		}
+		posts = append(pinned, posts...)
+	}
+	if err := attachQuotes(ctx, r.db, posts, currentUserID); err != nil {
+		return nil, nil, err
+	}
+	if err := attachPolls(ctx, r.db, posts, currentUserID); err != nil {
+		return nil, nil, err
+	}
+	if err := attachReactions(ctx, r.db, posts, currentUserID); err != nil {
+		return nil, nil, err
+	}
+	return posts, next, nil
 }
 
 func (r *MySQLPostRepository) Delete(ctx context.Context, id string, userID string) error {
@@ -185,20 +238,364 @@ func (r *MySQLPostRepository) Delete(ctx context.Context, id string, userID stri
 	return err
 }
 
-func (r *MySQLPostRepository) Update(ctx context.Context, p *entity.Post) error {
-	query := `UPDATE posts SET content = ?, updated_at = ? WHERE id = ? AND user_id = ?`
-	_, err := r.db.ExecContext(ctx, query, p.Content, p.UpdatedAt, p.ID, p.UserID)
-	return err
+func (r *MySQLPostRepository) Update(ctx context.Context, p *entity.Post, revision *entity.PostRevision) error {
+	return r.db.inTx(ctx, func(tx instrumentedTx) error {
+		query := `UPDATE posts SET content = ?, updated_at = ?, version = ? WHERE id = ? AND user_id = ? AND version = ?`
+		res, err := tx.ExecContext(ctx, query, p.Content, p.UpdatedAt, p.Version, p.ID, p.UserID, p.Version-1)
+		if err != nil {
+			return err
+		}
+		updated, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if updated == 0 {
+			// Nothing is updated for a post of another user, a post of p.UserID was updated concurrently
+			var exists bool
+			if err := tx.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM posts WHERE id = ? AND user_id = ?)`, p.ID, p.UserID).Scan(&exists); err != nil {
+				return err
+			}
+			if exists {
+				return entity.ErrVersionMismatch
+			}
+			return nil
+		}
+		// The new content may mention other users
+		if _, err := tx.ExecContext(ctx, `DELETE FROM post_mentions WHERE post_id = ?`, p.ID); err != nil {
+			return err
+		}
+		if err := insertMentions(ctx, tx, p); err != nil {
+			return err
+		}
+		if revision == nil {
+			return nil
+		}
+
+		query = `INSERT INTO post_revisions (id, post_id, editor_id, content, edited_at) VALUES (?, ?, ?, ?, ?)`
+		_, err = tx.ExecContext(ctx, query, revision.ID, revision.PostID, revision.EditorID, revision.Content, revision.EditedAt)
+		return err
+	})
+}
+
+func (r *MySQLPostRepository) Publish(ctx context.Context, p *entity.Post) error {
+	return r.db.inTx(ctx, func(tx instrumentedTx) error {
+		query := `UPDATE posts SET status = ?, publish_at = NULL, created_at = ?, updated_at = ?, version = ?
+		WHERE id = ? AND user_id = ? AND version = ? AND status <> ?`
+		res, err := tx.ExecContext(ctx, query, string(p.Status), p.CreatedAt, p.UpdatedAt, p.Version, p.ID, p.UserID, p.Version-1, string(entity.PostStatusPublished))
+		if err != nil {
+			return err
+		}
+		published, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if published == 0 {
+			// Nothing is published for a post of another user, a post of p.UserID was updated or published concurrently
+			var exists bool
+			if err := tx.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM posts WHERE id = ? AND user_id = ?)`, p.ID, p.UserID).Scan(&exists); err != nil {
+				return err
+			}
+			if exists {
+				return entity.ErrVersionMismatch
+			}
+		}
+		return nil
+	})
+}
+
+// Due posts are locked until the transaction ends, concurrent callers skip them rather than waiting and publishing them again
+func (r *MySQLPostRepository) PublishDue(ctx context.Context, now time.Time, limit int) ([]*entity.Post, error) {
+	var published []*entity.Post
+	err := r.db.inTx(ctx, func(tx instrumentedTx) error {
+		published = nil
+		query := `SELECT posts.id, posts.user_id, posts.content, posts.created_at, posts.updated_at, posts.version, posts.status, posts.publish_at, posts.visibility,
+		post_quotes.quoted_post_id
+		FROM posts
+		LEFT JOIN post_quotes ON post_quotes.post_id = posts.id
+		WHERE posts.status = ? AND posts.publish_at <= ?
+		ORDER BY posts.publish_at, posts.id
+		LIMIT ?
+		FOR UPDATE OF posts SKIP LOCKED`
+		rows, err := tx.QueryContext(ctx, query, string(entity.PostStatusScheduled), now, limit)
+		if err != nil {
+			return err
+		}
+		due, err := scanPosts(rows)
+		if err != nil {
+			return err
+		}
+
+		for _, p := range due {
+			if err := p.Publish(*p.PublishAt); err != nil {
+				return err
+			}
+			query := `UPDATE posts SET status = ?, publish_at = NULL, created_at = ?, updated_at = ?, version = ? WHERE id = ? AND status = ?`
+			res, err := tx.ExecContext(ctx, query, string(p.Status), p.CreatedAt, p.UpdatedAt, p.Version, p.ID, string(entity.PostStatusScheduled))
+			if err != nil {
+				return err
+			}
+			if n, err := res.RowsAffected(); err != nil {
+				return err
+			} else if n == 1 {
+				published = append(published, p)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return published, nil
+}
+
+func (r *MySQLPostRepository) FindDrafts(ctx context.Context, userID string, page dto.CursorPage) ([]*entity.Post, *dto.Cursor, error) {
+	after, afterArgs := afterCursor("posts", page.After)
+	query := fmt.Sprintf(`SELECT posts.id, posts.user_id, posts.content, posts.created_at, posts.updated_at, posts.version, posts.status, posts.publish_at, posts.visibility,
+	post_quotes.quoted_post_id
+	FROM posts
+	LEFT JOIN post_quotes ON post_quotes.post_id = posts.id
+	WHERE posts.user_id = ? AND posts.status <> ? AND %s
+	ORDER BY posts.created_at DESC, posts.id DESC
+	LIMIT ?`, after)
+	args := append([]any{userID, string(entity.PostStatusPublished)}, afterArgs...)
+	rows, err := r.db.QueryContext(ctx, query, append(args, page.FetchLimit())...)
+	if err != nil {
+		return nil, nil, err
+	}
+	drafts, err := scanPosts(rows)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	keys := make([]dto.Cursor, len(drafts))
+	for i, p := range drafts {
+		keys[i] = dto.Cursor{CreatedAt: p.CreatedAt, ID: p.ID}
+	}
+	drafts, next := dto.PageOf(page, drafts, keys)
+	return drafts, next, nil
+}
+
+// Sets the quoted post of each post the viewer may see, loaded with a single query whatever the number of posts.
+// The quotes of the quoted posts are left out
+func attachQuotes(ctx context.Context, db instrumentedDB, posts []*aggregate.Post, currentUserID *string) error {
+	var ids []any
+	for _, p := range posts {
+		if p.QuotedPostID != nil {
+			ids = append(ids, p.QuotedPostID.String())
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	listed, listedArgs := listedTo("posts", currentUserID)
+	query := fmt.Sprintf(`SELECT %s,
+		users.id, users.username, users.email
+	FROM posts
+	INNER JOIN users ON posts.user_id = users.id
+	%s
+	WHERE posts.id IN (?%s) AND %s`, postAggregateColumns, postCountJoins, strings.Repeat(", ?", len(ids)-1), listed)
+
+	args := append([]any{currentUserID, currentUserID, currentUserID}, ids...)
+	rows, err := db.QueryContext(ctx, query, append(args, listedArgs...)...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	quoted := map[uuid.UUID]*aggregate.Post{}
+	for rows.Next() {
+		var post Post
+		var user User
+		cpa, err := scanPostAggregate(rows, &post, &user.ID, &user.Username, &user.Email)
+		if err != nil {
+			return err
+		}
+
+		ePost, err := post.ToEntity()
+		if err != nil {
+			return err
+		}
+		eUser, err := user.ToEntity()
+		if err != nil {
+			return err
+		}
+		quoted[ePost.ID] = aggregate.NewPost(*ePost, *eUser, cpa)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, p := range posts {
+		if p.QuotedPostID != nil {
+			p.Quote = quoted[*p.QuotedPostID]
+		}
+	}
+	return nil
+}
+
+// Pins of owner visible to the viewer, most recently pinned first. Pins of another user's post are loaded as the repost of owner
+func findPinnedPosts(ctx context.Context, db instrumentedDB, owner User, currentUserID *string) ([]*aggregate.Post, error) {
+	listed, listedArgs := listedTo("posts", currentUserID)
+	query := fmt.Sprintf(`SELECT %s,
+		users.id, users.username, users.email,
+		COALESCE(reposts.id, ''), COALESCE(reposts.comment, ''), reposts.created_at, reposts.updated_at
+	FROM pinned_posts
+	INNER JOIN posts ON pinned_posts.post_id = posts.id
+	INNER JOIN users ON posts.user_id = users.id
+	LEFT JOIN reposts ON pinned_posts.repost_id = reposts.id
+	%s
+	WHERE pinned_posts.user_id = ? AND %s
+	ORDER BY pinned_posts.created_at DESC, pinned_posts.id DESC`, postAggregateColumns, postCountJoins, listed)
+
+	args := append([]any{currentUserID, currentUserID, currentUserID, owner.ID}, listedArgs...)
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	eOwner, err := owner.ToEntity()
+	if err != nil {
+		return nil, err
+	}
+	var posts []*aggregate.Post
+	for rows.Next() {
+		var post Post
+		var user User
+		var repost Repost
+		var repostCreatedAt, repostUpdatedAt sql.NullTime
+		cpa, err := scanPostAggregate(rows, &post, &user.ID, &user.Username, &user.Email, &repost.ID, &repost.Comment, &repostCreatedAt, &repostUpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		ePost, err := post.ToEntity()
+		if err != nil {
+			return nil, err
+		}
+		eUser, err := user.ToEntity()
+		if err != nil {
+			return nil, err
+		}
+
+		pinned := aggregate.NewPost(*ePost, *eUser, cpa)
+		if repost.ID != "" {
+			repost.UserID, repost.PostID = owner.ID, post.ID
+			repost.CreatedAt, repost.UpdatedAt = repostCreatedAt.Time, repostUpdatedAt.Time
+			eRepost, err := repost.ToEntity()
+			if err != nil {
+				return nil, err
+			}
+			pinned = aggregate.NewRepost(*ePost, eRepost, *eUser, eOwner, cpa)
+		}
+		pinned.Pinned = true
+		posts = append(posts, pinned)
+	}
+	return posts, rows.Err()
+}
+
+// Columns of a post, its counts and whether the viewer liked, favorited or reposted it, in the order scanPostAggregate
+// reads them. The viewer fills their 3 placeholders, the query joins postCountJoins
+const postAggregateColumns = `posts.id, posts.user_id, posts.content, posts.created_at, posts.updated_at, posts.version, posts.status, posts.publish_at, posts.visibility,
+		post_quotes.quoted_post_id,
+		COALESCE(likes_count.count, 0) AS like_count,
+		COALESCE(favorites_count.count, 0) AS favorite_count,
+		COALESCE(reposts_count.count, 0) AS repost_count,
+		COALESCE(quotes_count.count, 0) AS quote_count,
+		COALESCE(revisions_count.count, 0) AS edit_count,
+		-- Check if the current user has liked, favorited, or reposted the post
+		EXISTS (SELECT 1 FROM likes l WHERE l.post_id = posts.id AND l.user_id = ?) AS liked,
+		EXISTS (SELECT 1 FROM favorites f WHERE f.post_id = posts.id AND f.user_id = ?) AS favorited,
+		EXISTS (SELECT 1 FROM reposts r WHERE r.post_id = posts.id AND r.user_id = ?) AS reposted`
+
+// Joins the counts of postAggregateColumns to the posts of the query
+const postCountJoins = `LEFT JOIN (SELECT post_id, COUNT(*) AS count FROM likes GROUP BY post_id) likes_count ON likes_count.post_id = posts.id
+	LEFT JOIN (SELECT post_id, COUNT(*) AS count FROM favorites GROUP BY post_id) favorites_count ON favorites_count.post_id = posts.id
+	LEFT JOIN (SELECT post_id, COUNT(*) AS count FROM reposts GROUP BY post_id) reposts_count ON reposts_count.post_id = posts.id
+	LEFT JOIN (SELECT post_quotes.quoted_post_id AS post_id, COUNT(*) AS count FROM post_quotes INNER JOIN posts quotes ON quotes.id = post_quotes.post_id WHERE quotes.status = 'published' GROUP BY post_quotes.quoted_post_id) quotes_count ON quotes_count.post_id = posts.id
+	LEFT JOIN post_quotes ON post_quotes.post_id = posts.id
+	LEFT JOIN (SELECT post_id, COUNT(*) AS count FROM post_revisions GROUP BY post_id) revisions_count ON revisions_count.post_id = posts.id`
+
+// Scans a row starting with postAggregateColumns into post, extra receives the columns selected after them
+func scanPostAggregate(rows *sql.Rows, post *Post, extra ...any) (dto.CommonPostAggregate, error) {
+	var cpa dto.CommonPostAggregate
+	dest := []any{
+		&post.ID,
+		&post.UserID,
+		&post.Content,
+		&post.CreatedAt,
+		&post.UpdatedAt,
+		&post.Version,
+		&post.Status,
+		&post.PublishAt,
+		&post.Visibility,
+		&post.QuotedPostID,
+		&cpa.LikeCount,
+		&cpa.FavoriteCount,
+		&cpa.RepostCount,
+		&cpa.QuoteCount,
+		&cpa.EditCount,
+		&cpa.Liked,
+		&cpa.Favorited,
+		&cpa.Reposted,
+	}
+	err := rows.Scan(append(dest, extra...)...)
+	return cpa, err
+}
+
+// Posts of rows selecting the columns of the posts table, closes rows
+func scanPosts(rows *sql.Rows) ([]*entity.Post, error) {
+	defer rows.Close()
+
+	var posts []*entity.Post
+	for rows.Next() {
+		var post Post
+		if err := rows.Scan(&post.ID, &post.UserID, &post.Content, &post.CreatedAt, &post.UpdatedAt, &post.Version, &post.Status, &post.PublishAt, &post.Visibility, &post.QuotedPostID); err != nil {
+			return nil, err
+		}
+		ePost, err := post.ToEntity()
+		if err != nil {
+			return nil, err
+		}
+		posts = append(posts, ePost)
+	}
+	return posts, rows.Err()
+}
+
+func (r *MySQLPostRepository) FindRevisions(ctx context.Context, postID string) ([]*entity.PostRevision, error) {
+	query := `SELECT id, post_id, editor_id, content, edited_at FROM post_revisions
+	WHERE post_id = ?
+	ORDER BY edited_at DESC, id DESC`
+	rows, err := r.db.QueryContext(ctx, query, postID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var revisions []*entity.PostRevision
+	for rows.Next() {
+		var revision PostRevision
+		if err := rows.Scan(&revision.ID, &revision.PostID, &revision.EditorID, &revision.Content, &revision.EditedAt); err != nil {
+			return nil, err
+		}
+		eRevision, err := revision.ToEntity()
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, eRevision)
+	}
+	return revisions, rows.Err()
 }
 
 func (r *MySQLPostRepository) getFeedTotalCount(ctx context.Context, userID string) (int, error) {
-	countQuery := `
+	listed, listedArgs := listedTo("posts", userID)
+	countQuery := fmt.Sprintf(`
 		SELECT COUNT(*) FROM (
 			-- Count original posts from followed users or self
 			SELECT posts.id
 			FROM posts
-			LEFT JOIN follows ON posts.user_id = follows.followee_id
-			WHERE follows.follower_id = ? OR posts.user_id = ?
+			WHERE (posts.user_id = ? OR EXISTS (SELECT 1 FROM follows WHERE follows.follower_id = ? AND follows.followee_id = posts.user_id)) AND %[1]s
 
 			UNION ALL
 			
@@ -206,13 +603,14 @@ func (r *MySQLPostRepository) getFeedTotalCount(ctx context.Context, userID stri
 			SELECT posts.id
 			FROM reposts
 			INNER JOIN posts ON reposts.post_id = posts.id
-			LEFT JOIN follows ON reposts.user_id = follows.followee_id
-			WHERE follows.follower_id = ? OR reposts.user_id = ?
+			WHERE (reposts.user_id = ? OR EXISTS (SELECT 1 FROM follows WHERE follows.follower_id = ? AND follows.followee_id = reposts.user_id)) AND %[1]s
 		) AS feed_count
-	`
+	`, listed)
 
+	args := append([]any{userID, userID}, listedArgs...)
+	args = append(append(args, userID, userID), listedArgs...)
 	var total int
-	err := r.db.QueryRowContext(ctx, countQuery, userID, userID, userID, userID).Scan(&total)
+	err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&total)
 	if err != nil {
 		return 0, err
 	}
@@ -221,16 +619,24 @@ func (r *MySQLPostRepository) getFeedTotalCount(ctx context.Context, userID stri
 
 func (r *MySQLPostRepository) FindFeed(ctx context.Context, userID string, limit, offset int) ([]*aggregate.Post, int, error) {
 	// Must return exactly the same rows, and column type of both queries to avoid sql err
-	query := `
+	listed, listedArgs := listedTo("posts", userID)
+	query := fmt.Sprintf(`
 	SELECT 
 		posts.id,
 		posts.user_id,
 		posts.content,
 		posts.created_at,
 		posts.updated_at,
+		posts.version,
+		posts.status,
+		posts.publish_at,
+		posts.visibility,
+		post_quotes.quoted_post_id,
 		COALESCE(likes_count.count, 0) AS like_count,
 		COALESCE(favorites_count.count, 0) AS favorite_count,
 		COALESCE(reposts_count.count, 0) AS repost_count,
+		COALESCE(quotes_count.count, 0) AS quote_count,
+		COALESCE(revisions_count.count, 0) AS edit_count,
 		NULL AS repost_id,
 		NULL AS repost_user_id,
 		NULL AS repost_post_id,
@@ -239,17 +645,20 @@ func (r *MySQLPostRepository) FindFeed(ctx context.Context, userID string, limit
 		NULL AS repost_updated_at,
 		posts.created_at AS feed_time,  -- Use original post time for sorting
 		users.id, users.username, users.email, -- post owner
+		NULL AS repost_username, NULL AS repost_email, -- reposter, joined such that the feed is a single query
 		-- Check if the current user has liked, favorited, or reposted the original post
 		EXISTS (SELECT 1 FROM likes l WHERE l.post_id = posts.id AND l.user_id = ?) AS liked,
 		EXISTS (SELECT 1 FROM favorites f WHERE f.post_id = posts.id AND f.user_id = ?) AS favorited,
 		EXISTS (SELECT 1 FROM reposts r WHERE r.post_id = posts.id AND r.user_id = ?) AS reposted
 	FROM posts
 	INNER JOIN users ON posts.user_id = users.id
-	LEFT JOIN follows ON posts.user_id = follows.followee_id
 	LEFT JOIN (SELECT post_id, COUNT(*) AS count FROM likes GROUP BY post_id) likes_count ON likes_count.post_id = posts.id
 	LEFT JOIN (SELECT post_id, COUNT(*) AS count FROM favorites GROUP BY post_id) favorites_count ON favorites_count.post_id = posts.id
 	LEFT JOIN (SELECT post_id, COUNT(*) AS count FROM reposts GROUP BY post_id) reposts_count ON reposts_count.post_id = posts.id
-	WHERE follows.follower_id = ? OR posts.user_id = ?
+	LEFT JOIN (SELECT post_quotes.quoted_post_id AS post_id, COUNT(*) AS count FROM post_quotes INNER JOIN posts quotes ON quotes.id = post_quotes.post_id WHERE quotes.status = 'published' GROUP BY post_quotes.quoted_post_id) quotes_count ON quotes_count.post_id = posts.id
+	LEFT JOIN post_quotes ON post_quotes.post_id = posts.id
+	LEFT JOIN (SELECT post_id, COUNT(*) AS count FROM post_revisions GROUP BY post_id) revisions_count ON revisions_count.post_id = posts.id
+	WHERE (posts.user_id = ? OR EXISTS (SELECT 1 FROM follows WHERE follows.follower_id = ? AND follows.followee_id = posts.user_id)) AND %[1]s
 
 	UNION ALL
 
@@ -260,9 +669,16 @@ func (r *MySQLPostRepository) FindFeed(ctx context.Context, userID string, limit
 		posts.content,
 		posts.created_at,
 		posts.updated_at,
+		posts.version,
+		posts.status,
+		posts.publish_at,
+		posts.visibility,
+		post_quotes.quoted_post_id,
 		COALESCE(likes_count.count, 0) AS like_count,
 		COALESCE(favorites_count.count, 0) AS favorite_count,
 		COALESCE(reposts_count.count, 0) AS repost_count,
+		COALESCE(quotes_count.count, 0) AS quote_count,
+		COALESCE(revisions_count.count, 0) AS edit_count,
 		reposts.id AS repost_id,
 		reposts.user_id AS repost_user_id,
 		reposts.post_id AS repost_post_id,
@@ -271,6 +687,7 @@ func (r *MySQLPostRepository) FindFeed(ctx context.Context, userID string, limit
 		reposts.updated_at AS repost_updated_at,
 		reposts.created_at AS feed_time,  -- Use repost time for sorting
 		users.id, users.username, users.email, -- post owner
+		repost_users.username AS repost_username, repost_users.email AS repost_email,
 		-- Check if the current user has liked, favorited, or reposted the original post
 		EXISTS (SELECT 1 FROM likes l WHERE l.post_id = posts.id AND l.user_id = ?) AS liked,
 		EXISTS (SELECT 1 FROM favorites f WHERE f.post_id = posts.id AND f.user_id = ?) AS favorited,
@@ -278,17 +695,23 @@ func (r *MySQLPostRepository) FindFeed(ctx context.Context, userID string, limit
 	FROM reposts
 	INNER JOIN posts ON reposts.post_id = posts.id
 	INNER JOIN users ON users.id = posts.user_id
-	LEFT JOIN follows ON reposts.user_id = follows.followee_id
+	INNER JOIN users repost_users ON repost_users.id = reposts.user_id
 	LEFT JOIN (SELECT post_id, COUNT(*) AS count FROM likes GROUP BY post_id) likes_count ON likes_count.post_id = posts.id
 	LEFT JOIN (SELECT post_id, COUNT(*) AS count FROM favorites GROUP BY post_id) favorites_count ON favorites_count.post_id = posts.id
 	LEFT JOIN (SELECT post_id, COUNT(*) AS count FROM reposts GROUP BY post_id) reposts_count ON reposts_count.post_id = posts.id
-	WHERE follows.follower_id = ? OR reposts.user_id = ?
+	LEFT JOIN (SELECT post_quotes.quoted_post_id AS post_id, COUNT(*) AS count FROM post_quotes INNER JOIN posts quotes ON quotes.id = post_quotes.post_id WHERE quotes.status = 'published' GROUP BY post_quotes.quoted_post_id) quotes_count ON quotes_count.post_id = posts.id
+	LEFT JOIN post_quotes ON post_quotes.post_id = posts.id
+	LEFT JOIN (SELECT post_id, COUNT(*) AS count FROM post_revisions GROUP BY post_id) revisions_count ON revisions_count.post_id = posts.id
+	WHERE (reposts.user_id = ? OR EXISTS (SELECT 1 FROM follows WHERE follows.follower_id = ? AND follows.followee_id = reposts.user_id)) AND %[1]s
 
 	ORDER BY feed_time DESC
 	LIMIT ? OFFSET ?;
-	`
+	`, listed)
 
-	rows, err := r.db.QueryContext(ctx, query, userID, userID, userID, userID, userID, userID, userID, userID, userID, userID, limit, offset)
+	// liked, favorited, reposted and the feed owner of each query are followed by the visibility condition
+	args := append([]any{userID, userID, userID, userID, userID}, listedArgs...)
+	args = append(append(args, userID, userID, userID, userID, userID), listedArgs...)
+	rows, err := r.db.QueryContext(ctx, query, append(args, limit, offset)...)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -297,7 +720,7 @@ func (r *MySQLPostRepository) FindFeed(ctx context.Context, userID string, limit
 	var feed []*aggregate.Post
 	for rows.Next() {
 		var post Post
-		var likeCount, favoriteCount, repostCount int
+		var likeCount, favoriteCount, repostCount, quoteCount, editCount int
 		var liked, favorited, reposted bool
 		var feedTime time.Time
 		var postUser User
@@ -307,6 +730,7 @@ func (r *MySQLPostRepository) FindFeed(ctx context.Context, userID string, limit
 		var repostUserID uuid.UUID
 		var repostPostID uuid.UUID
 		var repostComment *string
+		var repostUsername, repostEmail *string
 		var repostCreatedAt, repostUpdatedAt *time.Time
 
 		err := rows.Scan(
@@ -315,9 +739,16 @@ func (r *MySQLPostRepository) FindFeed(ctx context.Context, userID string, limit
 			&post.Content,
 			&post.CreatedAt,
 			&post.UpdatedAt,
+			&post.Version,
+			&post.Status,
+			&post.PublishAt,
+			&post.Visibility,
+			&post.QuotedPostID,
 			&likeCount,
 			&favoriteCount,
 			&repostCount,
+			&quoteCount,
+			&editCount,
 			&repostID,
 			&repostUserID,
 			&repostPostID,
@@ -328,6 +759,8 @@ func (r *MySQLPostRepository) FindFeed(ctx context.Context, userID string, limit
 			&postUser.ID,
 			&postUser.Username,
 			&postUser.Email,
+			&repostUsername,
+			&repostEmail,
 			&liked,
 			&favorited,
 			&reposted,
@@ -340,6 +773,8 @@ func (r *MySQLPostRepository) FindFeed(ctx context.Context, userID string, limit
 			LikeCount:     likeCount,
 			FavoriteCount: favoriteCount,
 			RepostCount:   repostCount,
+			QuoteCount:    quoteCount,
+			EditCount:     editCount,
 			Liked:         liked,
 			Favorited:     favorited,
 			Reposted:      reposted,
@@ -371,10 +806,10 @@ func (r *MySQLPostRepository) FindFeed(ctx context.Context, userID string, limit
 				repost.Comment = *repostComment
 			}
 
-			var repostUser User
-			err = r.db.QueryRowContext(ctx, "SELECT id, username, email FROM users WHERE id = ?", repostUserID).Scan(&repostUser.ID, &repostUser.Username, &repostUser.Email)
-			if err != nil {
-				return nil, 0, err
+			repostUser := User{
+				BaseModel: BaseModel{ID: repostUserID.String()},
+				Username:  *repostUsername,
+				Email:     *repostEmail,
 			}
 
 			eRepostUser, err := repostUser.ToEntity()
@@ -393,6 +828,16 @@ func (r *MySQLPostRepository) FindFeed(ctx context.Context, userID string, limit
 		return nil, 0, err
 	}
 
+	if err := attachQuotes(ctx, r.db, feed, &userID); err != nil {
+		return nil, 0, err
+	}
+	if err := attachPolls(ctx, r.db, feed, &userID); err != nil {
+		return nil, 0, err
+	}
+	if err := attachReactions(ctx, r.db, feed, &userID); err != nil {
+		return nil, 0, err
+	}
+
 	total, err := r.getFeedTotalCount(ctx, userID)
 	if err != nil {
 		return nil, 0, err
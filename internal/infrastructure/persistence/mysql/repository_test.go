@@ -0,0 +1,88 @@
+package mysql
+
+import (
+	"database/sql"
+	"errors"
+	"os"
+	"social-media-go-ddd/internal/domain/repository/repositorytest"
+	"social-media-go-ddd/internal/infrastructure/tracing/tracingtest"
+	"testing"
+	"time"
+
+	"github.com/golang-migrate/migrate/v4"
+	_ "github.com/golang-migrate/migrate/v4/database/mysql"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// Migrated database in TEST_MYSQL_DSN, eg user:password@tcp(localhost:3306)/test?parseTime=true, skipped when unset.
+// All data in that database is deleted.
+func openTestDB(tb testing.TB) *sql.DB {
+	tb.Helper()
+
+	dsn := os.Getenv("TEST_MYSQL_DSN")
+	if dsn == "" {
+		tb.Skip("TEST_MYSQL_DSN not set")
+	}
+
+	src, err := iofs.New(Migrations, "migrations")
+	if err != nil {
+		tb.Fatalf("read migrations: %v", err)
+	}
+	m, err := migrate.NewWithSourceInstance("iofs", src, "mysql://"+dsn)
+	if err != nil {
+		tb.Fatalf("init migrations: %v", err)
+	}
+	if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		tb.Fatalf("run migrations: %v", err)
+	}
+	m.Close()
+
+	db, err := NewMySQLDB(dsn)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	tb.Cleanup(func() { db.Close() })
+	return db
+}
+
+// Repositories over an emptied database, queries are counted from the spans recorded in exp
+func newTestRepositories(tb testing.TB, db *sql.DB, exp *tracetest.InMemoryExporter) repositorytest.Repositories {
+	tb.Helper()
+
+	// Everything else cascades from users
+	if _, err := db.Exec("DELETE FROM users"); err != nil {
+		tb.Fatalf("reset database: %v", err)
+	}
+
+	return repositorytest.Repositories{
+		User:       NewMySQLUserRepository(db),
+		Session:    NewMySQLSessionRepository(db),
+		Post:       NewMySQLPostRepository(db),
+		Like:       NewMySQLLikeRepository(db),
+		Favorite:   NewMySQLFavoriteRepository(db),
+		Repost:     NewMySQLRepostRepository(db),
+		Follow:     NewMySQLFollowRepository(db),
+		Poll:       NewMySQLPollRepository(db),
+		PollVote:   NewMySQLPollVoteRepository(db),
+		Pin:        NewMySQLPinnedPostRepository(db),
+		Collection: NewMySQLCollectionRepository(db),
+		// DATETIME columns are stored with second precision
+		TimestampResolution: time.Second,
+		QueryCount:          func() int { return tracingtest.CountQueries(exp) },
+	}
+}
+
+func TestRepositories(t *testing.T) {
+	db := openTestDB(t)
+	exp := tracingtest.Record(t)
+
+	repositorytest.RunAll(t, func(t *testing.T) repositorytest.Repositories {
+		return newTestRepositories(t, db, exp)
+	})
+}
+
+func BenchmarkReads(b *testing.B) {
+	db := openTestDB(b)
+	repositorytest.BenchmarkReads(b, newTestRepositories(b, db, tracingtest.Record(b)))
+}
@@ -3,6 +3,7 @@ package mysql
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"social-media-go-ddd/internal/domain/aggregate"
 	"social-media-go-ddd/internal/domain/dto"
 	"social-media-go-ddd/internal/domain/entity"
@@ -34,38 +35,9 @@ func (r *MySQLRepostRepository) FindByID(ctx context.Context, id string) (*entit
 
 // One user can only repost one, if already exist, update comment
 func (r *MySQLRepostRepository) Save(ctx context.Context, rp *entity.Repost) error {
-	tx, err := r.db.BeginTx(ctx, nil)
-	if err != nil {
-		return err
-	}
-	defer tx.Rollback()
-
-	checkQuery := `SELECT id FROM reposts WHERE user_id = ? AND post_id = ?`
-	row := tx.QueryRowContext(ctx, checkQuery, rp.UserID, rp.PostID)
-
-	var existingID string
-	err = row.Scan(&existingID)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			// No existing repost found, insert new repost
-			insertQuery := `INSERT INTO reposts (id, user_id, post_id, comment) VALUES (?, ?, ?, ?)`
-			_, err := tx.ExecContext(ctx, insertQuery, rp.ID, rp.UserID, rp.PostID, rp.Comment)
-			if err != nil {
-				return err
-			}
-		} else {
-			return err
-		}
-	} else {
-		// Existing repost found, update comment
-		updateQuery := `UPDATE reposts SET comment = ? WHERE id = ?`
-		_, err := tx.ExecContext(ctx, updateQuery, rp.Comment, existingID)
-		if err != nil {
-			return err
-		}
-	}
-
-	return tx.Commit()
+	query := `INSERT INTO reposts (id, user_id, post_id, comment) VALUES (?, ?, ?, ?) ON DUPLICATE KEY UPDATE comment = VALUES(comment)`
+	_, err := r.db.ExecContext(ctx, query, rp.ID, rp.UserID, rp.PostID, rp.Comment)
+	return err
 }
 
 func (r *MySQLRepostRepository) Delete(ctx context.Context, userID string, postID string) error {
@@ -74,53 +46,55 @@ func (r *MySQLRepostRepository) Delete(ctx context.Context, userID string, postI
 	return err
 }
 
-func (r *MySQLRepostRepository) FindByUserID(ctx context.Context, userID string) ([]*aggregate.Post, error) {
-	var repostUser User
-	err := r.db.QueryRowContext(ctx, "SELECT id, username, email FROM users WHERE id=?", userID).
-		Scan(&repostUser.ID, &repostUser.Username, &repostUser.Email)
-	if err != nil {
-		return nil, err
-	}
-
-	eRepostUser, err := repostUser.ToEntity()
-	if err != nil {
-		return nil, err
-	}
-
-	query := `
-		SELECT p.id, p.user_id, p.content, p.created_at, p.updated_at,
+func (r *MySQLRepostRepository) FindByUserID(ctx context.Context, userID string, currentUserID *string, page dto.CursorPage) ([]*aggregate.Post, *dto.Cursor, error) {
+	listed, listedArgs := listedTo("p", currentUserID)
+	after, afterArgs := afterCursor("reposts", page.After)
+	query := fmt.Sprintf(`
+		SELECT p.id, p.user_id, p.content, p.created_at, p.updated_at, p.version, p.status, p.publish_at, p.visibility,
+			post_quotes.quoted_post_id,
 			COALESCE(likes_count.count, 0) AS like_count,
 			COALESCE(favorites_count.count, 0) AS favorite_count,
 			COALESCE(reposts_count.count, 0) AS repost_count,
+			COALESCE(quotes_count.count, 0) AS quote_count,
+			COALESCE(revisions_count.count, 0) AS edit_count,
 			reposts.id, reposts.user_id, reposts.post_id, reposts.comment, reposts.created_at, reposts.updated_at,
 			users.id, users.username, users.email,
+			repost_users.id, repost_users.username, repost_users.email,
 			-- Check if the current user has liked, favorited, or reposted the post
 			EXISTS (SELECT 1 FROM likes l WHERE l.post_id = p.id AND l.user_id = ?) AS liked,
 			EXISTS (SELECT 1 FROM favorites f WHERE f.post_id = p.id AND f.user_id = ?) AS favorited,
 			EXISTS (SELECT 1 FROM reposts r WHERE r.post_id = p.id AND r.user_id = ?) AS reposted
 		FROM reposts
-		INNER JOIN users ON reposts.user_id = users.id
 		INNER JOIN posts p ON reposts.post_id = p.id
+		INNER JOIN users ON p.user_id = users.id
+		INNER JOIN users repost_users ON reposts.user_id = repost_users.id
 		LEFT JOIN (SELECT post_id, COUNT(*) AS count FROM likes GROUP BY post_id) likes_count ON likes_count.post_id = p.id
 		LEFT JOIN (SELECT post_id, COUNT(*) AS count FROM favorites GROUP BY post_id) favorites_count ON favorites_count.post_id = p.id
 		LEFT JOIN (SELECT post_id, COUNT(*) AS count FROM reposts GROUP BY post_id) reposts_count ON reposts_count.post_id = p.id
-		WHERE reposts.user_id = ?
-		ORDER BY reposts.created_at DESC
-	`
-
-	rows, err := r.db.QueryContext(ctx, query, userID, userID, userID, userID)
+		LEFT JOIN (SELECT post_quotes.quoted_post_id AS post_id, COUNT(*) AS count FROM post_quotes INNER JOIN posts quotes ON quotes.id = post_quotes.post_id WHERE quotes.status = 'published' GROUP BY post_quotes.quoted_post_id) quotes_count ON quotes_count.post_id = p.id
+		LEFT JOIN post_quotes ON post_quotes.post_id = p.id
+		LEFT JOIN (SELECT post_id, COUNT(*) AS count FROM post_revisions GROUP BY post_id) revisions_count ON revisions_count.post_id = p.id
+		WHERE reposts.user_id = ? AND %s AND %s
+		ORDER BY reposts.created_at DESC, reposts.id DESC
+		LIMIT ?
+	`, listed, after)
+
+	args := append([]any{currentUserID, currentUserID, currentUserID, userID}, listedArgs...)
+	args = append(args, afterArgs...)
+	rows, err := r.db.QueryContext(ctx, query, append(args, page.FetchLimit())...)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	defer rows.Close()
 
 	var reposts []*aggregate.Post
+	var keys []dto.Cursor
 	for rows.Next() {
 		var post Post
-		var likeCount, favoriteCount, repostCount int
+		var likeCount, favoriteCount, repostCount, quoteCount, editCount int
 		var liked, favorited, reposted bool
 		var repost Repost
-		var user User
+		var user, repostUser User
 
 		if err := rows.Scan(
 			&post.ID,
@@ -128,9 +102,16 @@ func (r *MySQLRepostRepository) FindByUserID(ctx context.Context, userID string)
 			&post.Content,
 			&post.CreatedAt,
 			&post.UpdatedAt,
+			&post.Version,
+			&post.Status,
+			&post.PublishAt,
+			&post.Visibility,
+			&post.QuotedPostID,
 			&likeCount,
 			&favoriteCount,
 			&repostCount,
+			&quoteCount,
+			&editCount,
 			&repost.ID,
 			&repost.UserID,
 			&repost.PostID,
@@ -140,32 +121,43 @@ func (r *MySQLRepostRepository) FindByUserID(ctx context.Context, userID string)
 			&user.ID,
 			&user.Username,
 			&user.Email,
+			&repostUser.ID,
+			&repostUser.Username,
+			&repostUser.Email,
 			&liked,
 			&favorited,
 			&reposted,
 		); err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
 		ePost, err := post.ToEntity()
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
 		eRepost, err := repost.ToEntity()
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
 		eUser, err := user.ToEntity()
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
+		eRepostUser, err := repostUser.ToEntity()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		keys = append(keys, dto.Cursor{CreatedAt: eRepost.CreatedAt, ID: eRepost.ID})
 		reposts = append(reposts, aggregate.NewRepost(*ePost, eRepost, *eUser, eRepostUser, dto.CommonPostAggregate{
 			LikeCount:     likeCount,
 			FavoriteCount: favoriteCount,
 			RepostCount:   repostCount,
+			QuoteCount:    quoteCount,
+			EditCount:     editCount,
 			Liked:         liked,
 			Favorited:     favorited,
 			Reposted:      reposted,
@@ -173,8 +165,30 @@ func (r *MySQLRepostRepository) FindByUserID(ctx context.Context, userID string)
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	return reposts, nil
+	reposts, next := dto.PageOf(page, reposts, keys)
+
+	// The reposter is joined, without reposts tell an unknown user apart from one who never reposted
+	if len(reposts) == 0 {
+		var exists bool
+		if err := r.db.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM users WHERE id = ?)", userID).Scan(&exists); err != nil {
+			return nil, nil, err
+		}
+		if !exists {
+			return nil, nil, sql.ErrNoRows
+		}
+	}
+
+	if err := attachQuotes(ctx, r.db, reposts, currentUserID); err != nil {
+		return nil, nil, err
+	}
+	if err := attachPolls(ctx, r.db, reposts, currentUserID); err != nil {
+		return nil, nil, err
+	}
+	if err := attachReactions(ctx, r.db, reposts, currentUserID); err != nil {
+		return nil, nil, err
+	}
+	return reposts, next, nil
 }
@@ -0,0 +1,105 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"social-media-go-ddd/internal/infrastructure/persistence/transfer"
+	"time"
+)
+
+// MySQLTransferStore reads and writes raw table rows for cmd/transfer
+type MySQLTransferStore struct {
+	baseMysqlRepository
+}
+
+func NewMySQLTransferStore(db *sql.DB) *MySQLTransferStore {
+	return &MySQLTransferStore{baseMysqlRepository: NewBaseMysqlRepository(db)}
+}
+
+func mysqlPlaceholder(int) string {
+	return "?"
+}
+
+func (s *MySQLTransferStore) Count(ctx context.Context, t transfer.Table) (int64, error) {
+	var count int64
+	err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM "+t.Name).Scan(&count)
+	return count, err
+}
+
+func (s *MySQLTransferStore) Read(ctx context.Context, t transfer.Table, afterID string, limit int) ([]transfer.Row, error) {
+	args := []any{limit}
+	if afterID != "" {
+		args = []any{afterID, limit}
+	}
+
+	rows, err := s.db.QueryContext(ctx, t.SelectSQL(afterID, mysqlPlaceholder), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []transfer.Row
+	for rows.Next() {
+		// Requires parseTime=true in the DSN, like the repositories
+		texts := make([]sql.NullString, len(t.Columns))
+		times := make([]sql.NullTime, len(t.Columns))
+		bools := make([]sql.NullBool, len(t.Columns))
+		dest := make([]any, len(t.Columns))
+		for i, c := range t.Columns {
+			switch c.Type {
+			case transfer.ColumnTime:
+				dest[i] = &times[i]
+			case transfer.ColumnBool:
+				dest[i] = &bools[i]
+			default:
+				dest[i] = &texts[i]
+			}
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+
+		row := make(transfer.Row, len(t.Columns))
+		for i, c := range t.Columns {
+			switch c.Type {
+			case transfer.ColumnTime:
+				row[i] = transfer.Value{Time: times[i].Time, Valid: times[i].Valid}
+			case transfer.ColumnBool:
+				row[i] = transfer.Value{Bool: bools[i].Bool, Valid: bools[i].Valid}
+			default:
+				row[i] = transfer.Value{String: texts[i].String, Valid: texts[i].Valid}
+			}
+		}
+		result = append(result, row)
+	}
+
+	return result, rows.Err()
+}
+
+func (s *MySQLTransferStore) Write(ctx context.Context, t transfer.Table, rows []transfer.Row) error {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	args := make([]any, 0, len(rows)*len(t.Columns))
+	for _, row := range rows {
+		for i, v := range row {
+			switch {
+			case !v.Valid:
+				args = append(args, nil)
+			case t.Columns[i].Type == transfer.ColumnTime:
+				// DATETIME rounds the fraction away, truncate instead so the checksum matches the source
+				args = append(args, v.Time.UTC().Truncate(time.Second))
+			case t.Columns[i].Type == transfer.ColumnBool:
+				args = append(args, v.Bool)
+			default:
+				args = append(args, v.String)
+			}
+		}
+	}
+
+	// Not INSERT IGNORE, it would also turn foreign key errors into warnings
+	query := t.InsertSQL(len(rows), mysqlPlaceholder) + " ON DUPLICATE KEY UPDATE id = id"
+	_, err := s.db.ExecContext(ctx, query, args...)
+	return err
+}
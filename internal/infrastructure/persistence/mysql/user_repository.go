@@ -17,9 +17,13 @@ func NewMySQLUserRepository(db *sql.DB) *MySQLUserRepository {
 }
 
 func (r *MySQLUserRepository) Save(ctx context.Context, u *entity.User) error {
-	query := `INSERT INTO users (id, username, email, password) VALUES (?, ?, ?, ?)`
-	_, err := r.db.ExecContext(ctx, query, u.ID, u.Username, u.Email, u.Password.GetHash())
-	return err
+	return r.db.inTx(ctx, func(tx instrumentedTx) error {
+		query := `INSERT INTO users (id, username, email, password) VALUES (?, ?, ?, ?)`
+		if _, err := tx.ExecContext(ctx, query, u.ID, u.Username, u.Email, u.Password.GetHash()); err != nil {
+			return err
+		}
+		return insertCollection(ctx, tx, entity.NewDefaultCollection(u.ID))
+	})
 }
 
 func (r *MySQLUserRepository) FindByID(ctx context.Context, id string, currentUserID *string) (*aggregate.User, error) {
@@ -1,6 +1,12 @@
 package postgres
 
-import "github.com/jackc/pgx/v5/pgxpool"
+import (
+	"fmt"
+	"social-media-go-ddd/internal/domain/dto"
+	"social-media-go-ddd/internal/infrastructure/persistence/sqlfilter"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
 
 type basePgRepository struct {
 	pool *pgxpool.Pool
@@ -9,3 +15,19 @@ type basePgRepository struct {
 func NewBasePgRepository(pool *pgxpool.Pool) basePgRepository {
 	return basePgRepository{pool: pool}
 }
+
+// Condition selecting the rows that follow the cursor in the (created_at, id) desc order of table, always true on the first page.
+// Its placeholders start at $n
+func afterCursor(table string, after *dto.Cursor, n int) (string, []any) {
+	if after == nil {
+		return "TRUE", nil
+	}
+	return fmt.Sprintf("(%s.created_at, %s.id) < ($%d::timestamptz, $%d::uuid)", table, table, n, n+1), []any{after.CreatedAt, after.ID.String()}
+}
+
+// Condition selecting the published posts of table the viewer may see, see sqlfilter.PostListedTo.
+// viewer is the placeholder of the viewer id, a NULL viewer only sees public posts
+func listedTo(table, viewer string) string {
+	cond, _ := sqlfilter.PostListedTo(table, viewer)
+	return cond
+}
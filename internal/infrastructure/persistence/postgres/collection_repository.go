@@ -0,0 +1,97 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"social-media-go-ddd/internal/domain/entity"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PgCollectionRepository struct {
+	basePgRepository
+}
+
+func NewPgCollectionRepository(pool *pgxpool.Pool) *PgCollectionRepository {
+	return &PgCollectionRepository{
+		basePgRepository: NewBasePgRepository(pool),
+	}
+}
+
+const collectionColumns = `id, user_id, name, is_default, created_at, updated_at`
+
+func (r *PgCollectionRepository) Save(ctx context.Context, c *entity.Collection) error {
+	return pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		return insertCollection(ctx, tx, c)
+	})
+}
+
+// Also used by PgUserRepository.Save for the default collection
+func insertCollection(ctx context.Context, tx pgx.Tx, c *entity.Collection) error {
+	query := `INSERT INTO collections (` + collectionColumns + `) VALUES ($1, $2, $3, $4, $5, $6)`
+	_, err := tx.Exec(ctx, query, c.ID, c.UserID, c.Name, c.Default, c.CreatedAt, c.UpdatedAt)
+	return err
+}
+
+func (r *PgCollectionRepository) Update(ctx context.Context, c *entity.Collection) error {
+	query := `UPDATE collections SET name = $1, updated_at = $2 WHERE id = $3 AND user_id = $4`
+	_, err := r.pool.Exec(ctx, query, c.Name, c.UpdatedAt, c.ID, c.UserID)
+	return err
+}
+
+func (r *PgCollectionRepository) Delete(ctx context.Context, id string, userID string) error {
+	return pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		// Favorites saved into the collection meanwhile wait, rather than going away with it
+		var collectionID string
+		query := `SELECT id FROM collections WHERE id = $1 AND user_id = $2 AND NOT is_default FOR UPDATE`
+		if err := tx.QueryRow(ctx, query, id, userID).Scan(&collectionID); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil
+			}
+			return err
+		}
+
+		query = `UPDATE favorites SET collection_id = (SELECT id FROM collections WHERE user_id = $1 AND is_default) WHERE collection_id = $2`
+		if _, err := tx.Exec(ctx, query, userID, id); err != nil {
+			return err
+		}
+		_, err := tx.Exec(ctx, `DELETE FROM collections WHERE id = $1`, id)
+		return err
+	})
+}
+
+func (r *PgCollectionRepository) FindByID(ctx context.Context, id string) (*entity.Collection, error) {
+	return scanCollection(r.pool.QueryRow(ctx, `SELECT `+collectionColumns+` FROM collections WHERE id = $1`, id))
+}
+
+func (r *PgCollectionRepository) FindDefault(ctx context.Context, userID string) (*entity.Collection, error) {
+	return scanCollection(r.pool.QueryRow(ctx, `SELECT `+collectionColumns+` FROM collections WHERE user_id = $1 AND is_default`, userID))
+}
+
+func (r *PgCollectionRepository) FindByUserID(ctx context.Context, userID string) ([]*entity.Collection, error) {
+	query := `SELECT ` + collectionColumns + ` FROM collections WHERE user_id = $1 ORDER BY is_default DESC, created_at, id`
+	rows, err := r.pool.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var collections []*entity.Collection
+	for rows.Next() {
+		collection, err := scanCollection(rows)
+		if err != nil {
+			return nil, err
+		}
+		collections = append(collections, collection)
+	}
+	return collections, rows.Err()
+}
+
+func scanCollection(row pgx.Row) (*entity.Collection, error) {
+	var c Collection
+	if err := row.Scan(&c.ID, &c.UserID, &c.Name, &c.IsDefault, &c.CreatedAt, &c.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return c.ToEntity()
+}
@@ -2,6 +2,7 @@ package postgres
 
 import (
 	"context"
+	"fmt"
 	"social-media-go-ddd/internal/domain/aggregate"
 	"social-media-go-ddd/internal/domain/dto"
 	"social-media-go-ddd/internal/domain/entity"
@@ -20,32 +21,22 @@ func NewPgFavoriteRepository(pool *pgxpool.Pool) *PgFavoriteRepository {
 	}
 }
 
-// If already favorite, does nothing
+// Moves the favorite into the collection of f if the post was favorited before
 func (r *PgFavoriteRepository) Save(ctx context.Context, f *entity.Favorite) error {
-	tx, err := r.pool.Begin(ctx)
+	query := `
+		INSERT INTO favorites (id, user_id, post_id, collection_id)
+		SELECT $1, $2, $3, id FROM collections WHERE id = $4 AND user_id = $2
+		ON CONFLICT (user_id, post_id) DO UPDATE SET collection_id = excluded.collection_id, updated_at = NOW()
+	`
+	tag, err := r.pool.Exec(ctx, query, f.ID, f.UserID, f.PostID, f.CollectionID)
 	if err != nil {
 		return err
 	}
-	defer tx.Rollback(ctx)
-
-	checkQuery := `SELECT id FROM favorites WHERE user_id = $1 AND post_id = $2`
-	row := tx.QueryRow(ctx, checkQuery, f.UserID, f.PostID)
-
-	var existingID string
-	if err := row.Scan(&existingID); err != nil {
-		if err == pgx.ErrNoRows {
-			// No existing favorite found, insert new favorite
-			insertQuery := `INSERT INTO favorites (id, user_id, post_id) VALUES ($1, $2, $3)`
-			_, err := tx.Exec(ctx, insertQuery, f.ID, f.UserID, f.PostID)
-			if err != nil {
-				return err
-			}
-		} else {
-			return err
-		}
+	if tag.RowsAffected() == 0 {
+		// The collection does not exist or belongs to another user
+		return pgx.ErrNoRows
 	}
-
-	return tx.Commit(ctx)
+	return nil
 }
 
 func (r *PgFavoriteRepository) Delete(ctx context.Context, userID, postID string) error {
@@ -54,39 +45,65 @@ func (r *PgFavoriteRepository) Delete(ctx context.Context, userID, postID string
 	return err
 }
 
-func (r *PgFavoriteRepository) FindByUserID(ctx context.Context, userID string) ([]*aggregate.Post, error) {
-	query := `
-		SELECT p.id, p.user_id, p.content, p.created_at, p.updated_at,
+func (r *PgFavoriteRepository) DeleteFromCollection(ctx context.Context, collectionID, postID string) error {
+	query := `DELETE FROM favorites WHERE collection_id = $1 AND post_id = $2`
+	_, err := r.pool.Exec(ctx, query, collectionID, postID)
+	return err
+}
+
+func (r *PgFavoriteRepository) FindByUserID(ctx context.Context, userID string, currentUserID *string, page dto.CursorPage) ([]*aggregate.Post, *dto.Cursor, error) {
+	return r.find(ctx, "favorites.user_id", userID, currentUserID, page)
+}
+
+func (r *PgFavoriteRepository) FindByCollectionID(ctx context.Context, collectionID string, currentUserID *string, page dto.CursorPage) ([]*aggregate.Post, *dto.Cursor, error) {
+	return r.find(ctx, "favorites.collection_id", collectionID, currentUserID, page)
+}
+
+// Favorites whose column equals id
+func (r *PgFavoriteRepository) find(ctx context.Context, column, id string, currentUserID *string, page dto.CursorPage) ([]*aggregate.Post, *dto.Cursor, error) {
+	after, afterArgs := afterCursor("favorites", page.After, 4)
+	query := fmt.Sprintf(`
+		SELECT p.id, p.user_id, p.content, p.created_at, p.updated_at, p.version, p.status, p.publish_at, p.visibility,
+			post_quotes.quoted_post_id,
 			COALESCE(likes_count.count, 0) AS like_count,
 			COALESCE(favorites_count.count, 0) AS favorite_count,
 			COALESCE(reposts_count.count, 0) AS repost_count,
+			COALESCE(quotes_count.count, 0) AS quote_count,
+			COALESCE(revisions_count.count, 0) AS edit_count,
 			users.id, users.username, users.email,
+			favorites.id, favorites.created_at,
 			-- Check if the current user has liked, favorited, or reposted the original post
-			EXISTS (SELECT 1 FROM likes l WHERE l.post_id = p.id AND l.user_id = $1) AS liked,
-			EXISTS (SELECT 1 FROM favorites f WHERE f.post_id = p.id AND f.user_id = $1) AS favorited,
-			EXISTS (SELECT 1 FROM reposts r WHERE r.post_id = p.id AND r.user_id = $1) AS reposted
+			EXISTS (SELECT 1 FROM likes l WHERE l.post_id = p.id AND l.user_id = $2) AS liked,
+			EXISTS (SELECT 1 FROM favorites f WHERE f.post_id = p.id AND f.user_id = $2) AS favorited,
+			EXISTS (SELECT 1 FROM reposts r WHERE r.post_id = p.id AND r.user_id = $2) AS reposted
 		FROM favorites
-		INNER JOIN users ON favorites.user_id = users.id
 		INNER JOIN posts p ON favorites.post_id = p.id
+		INNER JOIN users ON p.user_id = users.id
 		LEFT JOIN (SELECT post_id, COUNT(*) AS count FROM likes GROUP BY post_id) likes_count ON likes_count.post_id = p.id
 		LEFT JOIN (SELECT post_id, COUNT(*) AS count FROM favorites GROUP BY post_id) favorites_count ON favorites_count.post_id = p.id
 		LEFT JOIN (SELECT post_id, COUNT(*) AS count FROM reposts GROUP BY post_id) reposts_count ON reposts_count.post_id = p.id
-		WHERE favorites.user_id = $1
-		ORDER BY favorites.created_at DESC
-	`
-
-	rows, err := r.pool.Query(ctx, query, userID)
+		LEFT JOIN (SELECT post_quotes.quoted_post_id AS post_id, COUNT(*) AS count FROM post_quotes INNER JOIN posts quotes ON quotes.id = post_quotes.post_id WHERE quotes.status = 'published' GROUP BY post_quotes.quoted_post_id) quotes_count ON quotes_count.post_id = p.id
+		LEFT JOIN post_quotes ON post_quotes.post_id = p.id
+		LEFT JOIN (SELECT post_id, COUNT(*) AS count FROM post_revisions GROUP BY post_id) revisions_count ON revisions_count.post_id = p.id
+		WHERE %s = $1 AND %s AND %s
+		ORDER BY favorites.created_at DESC, favorites.id DESC
+		LIMIT $3
+	`, column, listedTo("p", "$2"), after)
+
+	rows, err := r.pool.Query(ctx, query, append([]any{id, currentUserID, page.FetchLimit()}, afterArgs...)...)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	defer rows.Close()
 
 	var posts []*aggregate.Post
+	var keys []dto.Cursor
 	for rows.Next() {
 		var post Post
-		var likeCount, favoriteCount, repostCount int
+		var likeCount, favoriteCount, repostCount, quoteCount, editCount int
 		var liked, favorited, reposted bool
 		var user User
+		var favorite BaseModel
 
 		if err := rows.Scan(
 			&post.ID,
@@ -94,33 +111,50 @@ func (r *PgFavoriteRepository) FindByUserID(ctx context.Context, userID string)
 			&post.Content,
 			&post.CreatedAt,
 			&post.UpdatedAt,
+			&post.Version,
+			&post.Status,
+			&post.PublishAt,
+			&post.Visibility,
+			&post.QuotedPostID,
 			&likeCount,
 			&favoriteCount,
 			&repostCount,
+			&quoteCount,
+			&editCount,
 			&user.ID,
 			&user.Username,
 			&user.Email,
+			&favorite.ID,
+			&favorite.CreatedAt,
 			&liked,
 			&favorited,
 			&reposted,
 		); err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
 		ePost, err := post.ToEntity()
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
 		eUser, err := user.ToEntity()
 		if err != nil {
-			return nil, err
+			return nil, nil, err
+		}
+
+		key, err := favorite.ToEntity()
+		if err != nil {
+			return nil, nil, err
 		}
 
+		keys = append(keys, dto.Cursor{CreatedAt: key.CreatedAt, ID: key.ID})
 		posts = append(posts, aggregate.NewPost(*ePost, *eUser, dto.CommonPostAggregate{
 			LikeCount:     likeCount,
 			FavoriteCount: favoriteCount,
 			RepostCount:   repostCount,
+			QuoteCount:    quoteCount,
+			EditCount:     editCount,
 			Liked:         liked,
 			Favorited:     favorited,
 			Reposted:      reposted,
@@ -128,8 +162,18 @@ func (r *PgFavoriteRepository) FindByUserID(ctx context.Context, userID string)
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	return posts, nil
+	posts, next := dto.PageOf(page, posts, keys)
+	if err := attachQuotes(ctx, r.pool, posts, currentUserID); err != nil {
+		return nil, nil, err
+	}
+	if err := attachPolls(ctx, r.pool, posts, currentUserID); err != nil {
+		return nil, nil, err
+	}
+	if err := attachReactions(ctx, r.pool, posts, currentUserID); err != nil {
+		return nil, nil, err
+	}
+	return posts, next, nil
 }
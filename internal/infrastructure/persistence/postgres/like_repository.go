@@ -2,9 +2,12 @@ package postgres
 
 import (
 	"context"
+	"slices"
+	"social-media-go-ddd/internal/domain/aggregate"
 	"social-media-go-ddd/internal/domain/entity"
 
-	"github.com/jackc/pgx/v5"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -18,36 +21,82 @@ func NewPgLikeRepository(pool *pgxpool.Pool) *PgLikeRepository {
 	}
 }
 
-// If already like, does nothing
-func (r *PgLikeRepository) Save(ctx context.Context, l *entity.Like) error {
-	tx, err := r.pool.Begin(ctx)
+// Replaces the reaction if the user already reacted to the post. xmax is zero for a row the statement inserted
+func (r *PgLikeRepository) Save(ctx context.Context, l *entity.Like) (bool, error) {
+	query := `
+		INSERT INTO likes (id, user_id, post_id, reaction) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id, post_id) DO UPDATE SET reaction = excluded.reaction, updated_at = NOW()
+		RETURNING xmax = 0
+	`
+	var inserted bool
+	err := r.pool.QueryRow(ctx, query, l.ID, l.UserID, l.PostID, l.Reaction).Scan(&inserted)
+	return inserted, err
+}
+
+// Keeps the reaction if the user already reacted to the post
+func (r *PgLikeRepository) SaveIfAbsent(ctx context.Context, l *entity.Like) (bool, error) {
+	query := `
+		INSERT INTO likes (id, user_id, post_id, reaction) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id, post_id) DO NOTHING
+	`
+	tag, err := r.pool.Exec(ctx, query, l.ID, l.UserID, l.PostID, l.Reaction)
+	return tag.RowsAffected() == 1, err
+}
+
+func (r *PgLikeRepository) Delete(ctx context.Context, userID, postID string) error {
+	query := `DELETE FROM likes WHERE user_id = $1 AND post_id = $2`
+	_, err := r.pool.Exec(ctx, query, userID, postID)
+	return err
+}
+
+// Sets the reaction counts and the viewer's reaction of the posts and their quoted posts, loaded with a single query
+func attachReactions(ctx context.Context, pool *pgxpool.Pool, posts []*aggregate.Post, currentUserID *string) error {
+	// A page may hold a post next to its repost or quote, both get the reactions
+	byID := map[string][]*aggregate.Post{}
+	var ids []string
+	for _, p := range slices.Concat(posts, quotedPosts(posts)) {
+		id := p.ID.String()
+		if _, ok := byID[id]; !ok {
+			ids = append(ids, id)
+		}
+		byID[id] = append(byID[id], p)
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	query := `SELECT post_id, reaction, COUNT(*) AS count, COALESCE(BOOL_OR(user_id = $1), false) AS reacted
+	FROM likes
+	WHERE post_id = ANY($2::uuid[])
+	GROUP BY post_id, reaction`
+
+	rows, err := pool.Query(ctx, query, currentUserID, ids)
 	if err != nil {
 		return err
 	}
-	defer tx.Rollback(ctx)
-
-	checkQuery := `SELECT id FROM likes WHERE user_id = $1 AND post_id = $2`
-	row := tx.QueryRow(ctx, checkQuery, l.UserID, l.PostID)
-
-	var existingID string
-	if err := row.Scan(&existingID); err != nil {
-		if err == pgx.ErrNoRows {
-			// No existing like found, insert new like
-			insertQuery := `INSERT INTO likes (id, user_id, post_id) VALUES ($1, $2, $3)`
-			_, err := tx.Exec(ctx, insertQuery, l.ID, l.UserID, l.PostID)
-			if err != nil {
-				return err
-			}
-		} else {
+	defer rows.Close()
+
+	for rows.Next() {
+		var postID pgtype.UUID
+		var reaction string
+		var count int
+		var reacted bool
+		if err := rows.Scan(&postID, &reaction, &count, &reacted); err != nil {
 			return err
 		}
+		for _, p := range byID[uuid.UUID(postID.Bytes).String()] {
+			p.AddReactions(entity.Reaction(reaction), count, reacted)
+		}
 	}
-
-	return tx.Commit(ctx)
+	return rows.Err()
 }
 
-func (r *PgLikeRepository) Delete(ctx context.Context, userID, postID string) error {
-	query := `DELETE FROM likes WHERE user_id = $1 AND post_id = $2`
-	_, err := r.pool.Exec(ctx, query, userID, postID)
-	return err
+func quotedPosts(posts []*aggregate.Post) []*aggregate.Post {
+	var quoted []*aggregate.Post
+	for _, p := range posts {
+		if p.Quote != nil {
+			quoted = append(quoted, p.Quote)
+		}
+	}
+	return quoted
 }
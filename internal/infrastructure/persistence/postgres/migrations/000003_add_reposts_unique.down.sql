@@ -0,0 +1 @@
+ALTER TABLE reposts DROP CONSTRAINT IF EXISTS reposts_user_id_post_id_key;
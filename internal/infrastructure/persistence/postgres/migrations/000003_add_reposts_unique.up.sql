@@ -0,0 +1,8 @@
+-- Keep only the latest repost per user and post before enforcing uniqueness
+DELETE FROM reposts a
+    USING reposts b
+WHERE a.user_id = b.user_id
+  AND a.post_id = b.post_id
+  AND (a.created_at, a.id) < (b.created_at, b.id);
+
+ALTER TABLE reposts ADD CONSTRAINT reposts_user_id_post_id_key UNIQUE (user_id, post_id);
@@ -0,0 +1,9 @@
+-- Who besides the author can see a published post: public, followers or mentioned
+ALTER TABLE posts ADD COLUMN visibility VARCHAR(16) NOT NULL DEFAULT 'public';
+-- Users mentioned as @username in the content of a post, mentioned posts are visible to them
+CREATE TABLE post_mentions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    UNIQUE (post_id, user_id)
+);
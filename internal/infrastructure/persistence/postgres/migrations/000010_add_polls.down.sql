@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS poll_vote_options;
+DROP TABLE IF EXISTS poll_votes;
+DROP TABLE IF EXISTS poll_options;
+DROP TABLE IF EXISTS polls;
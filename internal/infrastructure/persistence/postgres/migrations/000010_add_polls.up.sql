@@ -0,0 +1,34 @@
+-- Poll attached to a post, choice is single or multiple
+CREATE TABLE polls (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    post_id UUID NOT NULL UNIQUE REFERENCES posts(id) ON DELETE CASCADE,
+    choice VARCHAR(16) NOT NULL DEFAULT 'single',
+    closes_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE TABLE poll_options (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    poll_id UUID NOT NULL REFERENCES polls(id) ON DELETE CASCADE,
+    position INT NOT NULL,
+    text VARCHAR(100) NOT NULL,
+    UNIQUE (poll_id, position)
+);
+
+-- One vote per user and poll, the options it chose are in poll_vote_options
+CREATE TABLE poll_votes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    poll_id UUID NOT NULL REFERENCES polls(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    UNIQUE (poll_id, user_id)
+);
+
+CREATE TABLE poll_vote_options (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    vote_id UUID NOT NULL REFERENCES poll_votes(id) ON DELETE CASCADE,
+    option_id UUID NOT NULL REFERENCES poll_options(id) ON DELETE CASCADE,
+    UNIQUE (vote_id, option_id)
+);
+CREATE INDEX IF NOT EXISTS poll_vote_options_option_id_idx ON poll_vote_options (option_id);
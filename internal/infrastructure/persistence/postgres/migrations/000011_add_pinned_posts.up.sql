@@ -0,0 +1,10 @@
+-- Posts pinned to the top of a profile, repost_id is set when the user pinned their repost of another user's post
+CREATE TABLE pinned_posts (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    repost_id UUID REFERENCES reposts(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+    UNIQUE (user_id, post_id)
+);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS favorites_collection_id_created_at_idx;
+ALTER TABLE favorites DROP COLUMN collection_id;
+DROP TABLE IF EXISTS collections;
@@ -0,0 +1,21 @@
+-- Named, private groups of favorites, every user has a default collection that cannot be deleted
+CREATE TABLE collections (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    is_default BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+    UNIQUE (user_id, name)
+);
+
+INSERT INTO collections (user_id, name, is_default) SELECT id, 'Favorites', TRUE FROM users;
+
+-- Existing favorites move into the default collection of their user
+ALTER TABLE favorites ADD COLUMN collection_id UUID REFERENCES collections(id) ON DELETE CASCADE;
+UPDATE favorites SET collection_id = collections.id
+FROM collections
+WHERE collections.user_id = favorites.user_id AND collections.is_default;
+ALTER TABLE favorites ALTER COLUMN collection_id SET NOT NULL;
+-- Collections are paginated like the favorites of a user
+CREATE INDEX IF NOT EXISTS favorites_collection_id_created_at_idx ON favorites (collection_id, created_at, id);
@@ -3,7 +3,9 @@ package postgres
 import (
 	"social-media-go-ddd/internal/domain/entity"
 	"social-media-go-ddd/internal/domain/valueobject"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
@@ -49,8 +51,14 @@ func (u *User) ToEntity() (*entity.User, error) {
 
 type Post struct {
 	BaseModel
-	UserID  pgtype.UUID `db:"user_id"`
-	Content pgtype.Text `db:"content"`
+	UserID     pgtype.UUID        `db:"user_id"`
+	Content    pgtype.Text        `db:"content"`
+	Version    int                `db:"version"`
+	Status     pgtype.Text        `db:"status"`
+	PublishAt  pgtype.Timestamptz `db:"publish_at"`
+	Visibility pgtype.Text        `db:"visibility"`
+	// From post_quotes, NULL when the post quotes none
+	QuotedPostID pgtype.UUID `db:"quoted_post_id"`
 }
 
 func (p *Post) ToEntity() (*entity.Post, error) {
@@ -62,16 +70,60 @@ func (p *Post) ToEntity() (*entity.Post, error) {
 		return nil, err
 	}
 	return &entity.Post{
-		BaseEntity: baseEntity,
-		UserID:     p.UserID.Bytes,
-		Content:    p.Content.String,
+		BaseEntity:   baseEntity,
+		UserID:       p.UserID.Bytes,
+		Content:      p.Content.String,
+		Version:      p.Version,
+		Status:       entity.PostStatus(p.Status.String),
+		PublishAt:    timestamptzPtr(p.PublishAt),
+		Visibility:   entity.PostVisibility(p.Visibility.String),
+		QuotedPostID: uuidPtr(p.QuotedPostID),
+	}, nil
+}
+
+// Nil for NULL
+func uuidPtr(id pgtype.UUID) *uuid.UUID {
+	if !id.Valid {
+		return nil
+	}
+	parsed := uuid.UUID(id.Bytes)
+	return &parsed
+}
+
+// Nil for NULL
+func timestamptzPtr(t pgtype.Timestamptz) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+type PostRevision struct {
+	ID       pgtype.UUID        `db:"id"`
+	PostID   pgtype.UUID        `db:"post_id"`
+	EditorID pgtype.UUID        `db:"editor_id"`
+	Content  pgtype.Text        `db:"content"`
+	EditedAt pgtype.Timestamptz `db:"edited_at"`
+}
+
+func (r *PostRevision) ToEntity() (*entity.PostRevision, error) {
+	if r == nil {
+		return nil, nil
+	}
+	return &entity.PostRevision{
+		ID:       r.ID.Bytes,
+		PostID:   r.PostID.Bytes,
+		EditorID: r.EditorID.Bytes,
+		Content:  r.Content.String,
+		EditedAt: r.EditedAt.Time,
 	}, nil
 }
 
 type Like struct {
 	BaseModel
-	UserID pgtype.UUID `db:"user_id"`
-	PostID pgtype.UUID `db:"post_id"`
+	UserID   pgtype.UUID `db:"user_id"`
+	PostID   pgtype.UUID `db:"post_id"`
+	Reaction pgtype.Text `db:"reaction"`
 }
 
 func (l *Like) ToEntity() (*entity.Like, error) {
@@ -86,13 +138,15 @@ func (l *Like) ToEntity() (*entity.Like, error) {
 		BaseEntity: baseEntity,
 		UserID:     l.UserID.Bytes,
 		PostID:     l.PostID.Bytes,
+		Reaction:   entity.Reaction(l.Reaction.String),
 	}, nil
 }
 
 type Favorite struct {
 	BaseModel
-	UserID pgtype.UUID `db:"user_id"`
-	PostID pgtype.UUID `db:"post_id"`
+	UserID       pgtype.UUID `db:"user_id"`
+	PostID       pgtype.UUID `db:"post_id"`
+	CollectionID pgtype.UUID `db:"collection_id"`
 }
 
 func (f *Favorite) ToEntity() (*entity.Favorite, error) {
@@ -104,9 +158,33 @@ func (f *Favorite) ToEntity() (*entity.Favorite, error) {
 		return nil, err
 	}
 	return &entity.Favorite{
+		BaseEntity:   baseEntity,
+		UserID:       f.UserID.Bytes,
+		PostID:       f.PostID.Bytes,
+		CollectionID: f.CollectionID.Bytes,
+	}, nil
+}
+
+type Collection struct {
+	BaseModel
+	UserID    pgtype.UUID `db:"user_id"`
+	Name      pgtype.Text `db:"name"`
+	IsDefault pgtype.Bool `db:"is_default"`
+}
+
+func (c *Collection) ToEntity() (*entity.Collection, error) {
+	if c == nil {
+		return nil, nil
+	}
+	baseEntity, err := c.BaseModel.ToEntity()
+	if err != nil {
+		return nil, err
+	}
+	return &entity.Collection{
 		BaseEntity: baseEntity,
-		UserID:     f.UserID.Bytes,
-		PostID:     f.PostID.Bytes,
+		UserID:     c.UserID.Bytes,
+		Name:       c.Name.String,
+		Default:    c.IsDefault.Bool,
 	}, nil
 }
 
@@ -174,3 +252,43 @@ func (f *Follow) ToEntity() (*entity.Follow, error) {
 		FolloweeID: f.FolloweeID.Bytes,
 	}, nil
 }
+
+type Poll struct {
+	BaseModel
+	PostID   pgtype.UUID        `db:"post_id"`
+	Choice   pgtype.Text        `db:"choice"`
+	ClosesAt pgtype.Timestamptz `db:"closes_at"`
+}
+
+func (p *Poll) ToEntity() (*entity.Poll, error) {
+	if p == nil {
+		return nil, nil
+	}
+	baseEntity, err := p.BaseModel.ToEntity()
+	if err != nil {
+		return nil, err
+	}
+	return &entity.Poll{
+		BaseEntity: baseEntity,
+		PostID:     p.PostID.Bytes,
+		Choice:     entity.PollChoice(p.Choice.String),
+		ClosesAt:   p.ClosesAt.Time,
+	}, nil
+}
+
+type PollOption struct {
+	ID       pgtype.UUID `db:"id"`
+	Position int         `db:"position"`
+	Text     pgtype.Text `db:"text"`
+}
+
+func (o *PollOption) ToEntity() (*entity.PollOption, error) {
+	if o == nil {
+		return nil, nil
+	}
+	return &entity.PollOption{
+		ID:       o.ID.Bytes,
+		Position: o.Position,
+		Text:     o.Text.String,
+	}, nil
+}
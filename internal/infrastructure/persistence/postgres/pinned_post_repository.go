@@ -0,0 +1,68 @@
+package postgres
+
+import (
+	"context"
+	"social-media-go-ddd/internal/domain/entity"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PgPinnedPostRepository struct {
+	basePgRepository
+}
+
+func NewPgPinnedPostRepository(pool *pgxpool.Pool) *PgPinnedPostRepository {
+	return &PgPinnedPostRepository{
+		basePgRepository: NewBasePgRepository(pool),
+	}
+}
+
+// If already pinned, does nothing. The pin of another user's post references the repost, such that it goes away with it
+func (r *PgPinnedPostRepository) Save(ctx context.Context, p *entity.PinnedPost) error {
+	return pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		// Pins of the user are counted and added one request at a time
+		var userID uuid.UUID
+		if err := tx.QueryRow(ctx, `SELECT id FROM users WHERE id = $1 FOR UPDATE`, p.UserID).Scan(&userID); err != nil {
+			return err
+		}
+
+		var pinned bool
+		query := `SELECT EXISTS (SELECT 1 FROM pinned_posts WHERE user_id = $1 AND post_id = $2)`
+		if err := tx.QueryRow(ctx, query, p.UserID, p.PostID).Scan(&pinned); err != nil || pinned {
+			return err
+		}
+
+		var authorID uuid.UUID
+		var repostID *uuid.UUID
+		query = `SELECT posts.user_id, reposts.id FROM posts LEFT JOIN reposts ON reposts.post_id = posts.id AND reposts.user_id = $1 WHERE posts.id = $2`
+		if err := tx.QueryRow(ctx, query, p.UserID, p.PostID).Scan(&authorID, &repostID); err != nil {
+			return err
+		}
+		if authorID == p.UserID {
+			// Own posts stay pinned whether or not they are reposted
+			repostID = nil
+		} else if repostID == nil {
+			return entity.ErrPinNotOwnOrRepost
+		}
+
+		var count int
+		if err := tx.QueryRow(ctx, `SELECT COUNT(*) FROM pinned_posts WHERE user_id = $1`, p.UserID).Scan(&count); err != nil {
+			return err
+		}
+		if count >= entity.MaxPinnedPosts {
+			return entity.ErrPinLimitReached
+		}
+
+		query = `INSERT INTO pinned_posts (id, user_id, post_id, repost_id) VALUES ($1, $2, $3, $4)`
+		_, err := tx.Exec(ctx, query, p.ID, p.UserID, p.PostID, repostID)
+		return err
+	})
+}
+
+func (r *PgPinnedPostRepository) Delete(ctx context.Context, userID, postID string) error {
+	query := `DELETE FROM pinned_posts WHERE user_id = $1 AND post_id = $2`
+	_, err := r.pool.Exec(ctx, query, userID, postID)
+	return err
+}
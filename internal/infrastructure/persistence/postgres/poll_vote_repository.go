@@ -0,0 +1,40 @@
+package postgres
+
+import (
+	"context"
+	"social-media-go-ddd/internal/domain/entity"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PgPollVoteRepository struct {
+	basePgRepository
+}
+
+func NewPgPollVoteRepository(pool *pgxpool.Pool) *PgPollVoteRepository {
+	return &PgPollVoteRepository{
+		basePgRepository: NewBasePgRepository(pool),
+	}
+}
+
+// A second vote of the user fails on UNIQUE (poll_id, user_id), an option of another poll with pgx.ErrNoRows
+func (r *PgPollVoteRepository) Save(ctx context.Context, v *entity.PollVote) error {
+	return pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		query := `INSERT INTO poll_votes (id, poll_id, user_id) VALUES ($1, $2, $3)`
+		if _, err := tx.Exec(ctx, query, v.ID, v.PollID, v.UserID); err != nil {
+			return err
+		}
+		for _, optionID := range v.OptionIDs {
+			query := `INSERT INTO poll_vote_options (vote_id, option_id) SELECT $1, id FROM poll_options WHERE id = $2 AND poll_id = $3`
+			tag, err := tx.Exec(ctx, query, v.ID, optionID, v.PollID)
+			if err != nil {
+				return err
+			}
+			if tag.RowsAffected() == 0 {
+				return pgx.ErrNoRows
+			}
+		}
+		return nil
+	})
+}
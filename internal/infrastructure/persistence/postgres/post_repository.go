@@ -2,12 +2,15 @@ package postgres
 
 import (
 	"context"
+	"fmt"
 	"social-media-go-ddd/internal/domain/aggregate"
 	"social-media-go-ddd/internal/domain/dto"
 	"social-media-go-ddd/internal/domain/entity"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -20,10 +23,29 @@ func NewPgPostRepository(pool *pgxpool.Pool) *PgPostRepository {
 }
 
 func (r *PgPostRepository) Save(ctx context.Context, p *entity.Post) error {
-	query := `INSERT INTO posts (id, user_id, content) VALUES ($1, $2, $3)`
+	return pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		query := `INSERT INTO posts (id, user_id, content, version, status, publish_at, visibility) VALUES ($1, $2, $3, $4, $5, $6, $7)`
+		if _, err := tx.Exec(ctx, query, p.ID, p.UserID, p.Content, p.Version, string(p.Status), p.PublishAt, string(p.Visibility)); err != nil {
+			return err
+		}
+		if p.QuotedPostID != nil {
+			if _, err := tx.Exec(ctx, `INSERT INTO post_quotes (post_id, quoted_post_id) VALUES ($1, $2)`, p.ID, *p.QuotedPostID); err != nil {
+				return err
+			}
+		}
+		return insertMentions(ctx, tx, p)
+	})
+}
 
-	_, err := r.pool.Exec(ctx, query, p.ID, p.UserID, p.Content)
-	return err
+// Keep the users the content of p mentions, unknown usernames are skipped
+func insertMentions(ctx context.Context, tx pgx.Tx, p *entity.Post) error {
+	for _, username := range p.Mentions() {
+		query := `INSERT INTO post_mentions (post_id, user_id) SELECT $1, id FROM users WHERE username = $2`
+		if _, err := tx.Exec(ctx, query, p.ID, username); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (r *PgPostRepository) FindByID(ctx context.Context, id string, currentUserID *string) (*aggregate.Post, error) {
@@ -32,16 +54,26 @@ func (r *PgPostRepository) FindByID(ctx context.Context, id string, currentUserI
        posts.content, 
        posts.created_at, 
        posts.updated_at,
+       posts.version,
+       posts.status,
+       posts.publish_at,
+       posts.visibility,
+       post_quotes.quoted_post_id,
        COALESCE(likes_count.count, 0) AS like_count,
        COALESCE(favorites_count.count, 0) AS favorite_count,
        COALESCE(reposts_count.count, 0) AS repost_count,
+       COALESCE(quotes_count.count, 0) AS quote_count,
+       COALESCE(revisions_count.count, 0) AS edit_count,
        users.id,
        users.username,
        users.email,
 	   -- Check if the current user has liked, favorited, or reposted the original post
 	   EXISTS (SELECT 1 FROM likes l WHERE l.post_id = posts.id AND l.user_id = $2) AS liked,
 	   EXISTS (SELECT 1 FROM favorites f WHERE f.post_id = posts.id AND f.user_id = $2) AS favorited,
-	   EXISTS (SELECT 1 FROM reposts r WHERE r.post_id = posts.id AND r.user_id = $2) AS reposted
+	   EXISTS (SELECT 1 FROM reposts r WHERE r.post_id = posts.id AND r.user_id = $2) AS reposted,
+	   -- Relationship of the current user to the post, decides whether they may see it
+	   EXISTS (SELECT 1 FROM follows WHERE follows.follower_id = $2 AND follows.followee_id = posts.user_id) AS follower,
+	   EXISTS (SELECT 1 FROM post_mentions m WHERE m.post_id = posts.id AND m.user_id = $2) AS mentioned
 	FROM posts
 	    INNER JOIN users ON posts.user_id = users.id
 		LEFT JOIN (
@@ -62,27 +94,43 @@ func (r *PgPostRepository) FindByID(ctx context.Context, id string, currentUserI
 			FROM reposts 
 			GROUP BY post_id
 		) reposts_count ON reposts_count.post_id = posts.id
+		LEFT JOIN (
+			SELECT post_quotes.quoted_post_id AS post_id, COUNT(*) AS count FROM post_quotes INNER JOIN posts quotes ON quotes.id = post_quotes.post_id WHERE quotes.status = 'published' GROUP BY post_quotes.quoted_post_id
+		) quotes_count ON quotes_count.post_id = posts.id
+		LEFT JOIN post_quotes ON post_quotes.post_id = posts.id
+		LEFT JOIN (
+			SELECT post_id, COUNT(*) AS count FROM post_revisions GROUP BY post_id
+		) revisions_count ON revisions_count.post_id = posts.id
 	WHERE posts.id = $1`
 
 	var post Post
-	var likeCount, favoriteCount, repostCount int
+	var likeCount, favoriteCount, repostCount, quoteCount, editCount int
 	var user User
-	var liked, favorited, reposted bool
+	var liked, favorited, reposted, follower, mentioned bool
 	err := r.pool.QueryRow(ctx, query, id, currentUserID).Scan(
 		&post.ID,
 		&post.UserID,
 		&post.Content,
 		&post.CreatedAt,
 		&post.UpdatedAt,
+		&post.Version,
+		&post.Status,
+		&post.PublishAt,
+		&post.Visibility,
+		&post.QuotedPostID,
 		&likeCount,
 		&favoriteCount,
 		&repostCount,
+		&quoteCount,
+		&editCount,
 		&user.ID,
 		&user.Username,
 		&user.Email,
 		&liked,
 		&favorited,
 		&reposted,
+		&follower,
+		&mentioned,
 	)
 	if err != nil {
 		return nil, err
@@ -98,102 +146,96 @@ func (r *PgPostRepository) FindByID(ctx context.Context, id string, currentUserI
 		return nil, err
 	}
 
-	return aggregate.NewPost(*ePost, *eUser, dto.CommonPostAggregate{
+	aggregatePost := aggregate.NewPost(*ePost, *eUser, dto.CommonPostAggregate{
 		LikeCount:     likeCount,
 		FavoriteCount: favoriteCount,
 		RepostCount:   repostCount,
+		QuoteCount:    quoteCount,
+		EditCount:     editCount,
 		Liked:         liked,
 		Favorited:     favorited,
 		Reposted:      reposted,
-	}), nil
+	})
+	aggregatePost.Viewer = entity.PostViewer{
+		Author:    currentUserID != nil && *currentUserID == ePost.UserID.String(),
+		Follower:  follower,
+		Mentioned: mentioned,
+	}
+	if err := attachQuotes(ctx, r.pool, []*aggregate.Post{aggregatePost}, currentUserID); err != nil {
+		return nil, err
+	}
+	if err := attachPolls(ctx, r.pool, []*aggregate.Post{aggregatePost}, currentUserID); err != nil {
+		return nil, err
+	}
+	if err := attachReactions(ctx, r.pool, []*aggregate.Post{aggregatePost}, currentUserID); err != nil {
+		return nil, err
+	}
+	return aggregatePost, nil
 }
 
-func (r *PgPostRepository) FindByUserID(ctx context.Context, userID string) ([]*aggregate.Post, error) {
+func (r *PgPostRepository) FindByUserID(ctx context.Context, userID string, currentUserID *string, page dto.CursorPage) ([]*aggregate.Post, *dto.Cursor, error) {
 	var user entity.User
 	err := r.pool.QueryRow(ctx, "SELECT id, username, email FROM users WHERE id=$1", userID).
 		Scan(&user.ID, &user.Username, &user.Email)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	query := `SELECT 
-				posts.id, 
-				posts.user_id, 
-				posts.content, 
-				posts.created_at, 
-				posts.updated_at, 
-				COALESCE(likes_count.count, 0) AS like_count, 
-				COALESCE(favorites_count.count, 0) AS favorite_count, 
-				COALESCE(reposts_count.count, 0) AS repost_count,
-				-- Check if the current user has liked, favorited, or reposted the original post
-				EXISTS (SELECT 1 FROM likes l WHERE l.post_id = posts.id AND l.user_id = $1) AS liked,
-				EXISTS (SELECT 1 FROM favorites f WHERE f.post_id = posts.id AND f.user_id = $1) AS favorited,
-				EXISTS (SELECT 1 FROM reposts r WHERE r.post_id = posts.id AND r.user_id = $1) AS reposted
+	after, afterArgs := afterCursor("posts", page.After, 4)
+	query := fmt.Sprintf(`SELECT %s
 			FROM posts
-			LEFT JOIN (
-				SELECT post_id, COUNT(*) AS count 
-				FROM likes 
-				GROUP BY post_id
-			) likes_count ON likes_count.post_id = posts.id
-			LEFT JOIN (
-				SELECT post_id, COUNT(*) AS count 
-				FROM favorites 
-				GROUP BY post_id
-			) favorites_count ON favorites_count.post_id = posts.id
-			LEFT JOIN (
-				SELECT post_id, COUNT(*) AS count 
-				FROM reposts 
-				GROUP BY post_id
-			) reposts_count ON reposts_count.post_id = posts.id
-			WHERE posts.user_id = $1`
-	rows, err := r.pool.Query(ctx, query, userID)
+			%s
+			WHERE posts.user_id = $1 AND %s AND %s
+				-- Pinned posts are listed first instead
+				AND NOT EXISTS (SELECT 1 FROM pinned_posts WHERE pinned_posts.user_id = posts.user_id AND pinned_posts.post_id = posts.id)
+			ORDER BY posts.created_at DESC, posts.id DESC
+			LIMIT $3`, postAggregateColumns("$2"), postCountJoins, listedTo("posts", "$2"), after)
+	rows, err := r.pool.Query(ctx, query, append([]any{userID, currentUserID, page.FetchLimit()}, afterArgs...)...)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	defer rows.Close()
 
 	var posts []*aggregate.Post
+	var keys []dto.Cursor
 	for rows.Next() {
 		var post Post
-		var likeCount, favoriteCount, repostCount int
-		var liked, favorited, reposted bool
-
-		if err := rows.Scan(
-			&post.ID,
-			&post.UserID,
-			&post.Content,
-			&post.CreatedAt,
-			&post.UpdatedAt,
-			&likeCount,
-			&favoriteCount,
-			&repostCount,
-			&liked,
-			&favorited,
-			&reposted,
-		); err != nil {
-			return nil, err
+		cpa, err := scanPostAggregate(rows, &post)
+		if err != nil {
+			return nil, nil, err
 		}
 
 		ePost, err := post.ToEntity()
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
-		posts = append(posts, aggregate.NewPost(*ePost, user, dto.CommonPostAggregate{
-			LikeCount:     likeCount,
-			FavoriteCount: favoriteCount,
-			RepostCount:   repostCount,
-			Liked:         liked,
-			Favorited:     favorited,
-			Reposted:      reposted,
-		}))
+		keys = append(keys, dto.Cursor{CreatedAt: ePost.CreatedAt, ID: ePost.ID})
+		posts = append(posts, aggregate.NewPost(*ePost, user, cpa))
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	return posts, nil
+	posts, next := dto.PageOf(page, posts, keys)
+	if page.After == nil {
+		pinned, err := findPinnedPosts(ctx, r.pool, user, currentUserID)
+		if err != nil {
+			return nil, nil, err
+		}
+		posts = append(pinned, posts...)
+	}
+	if err := attachQuotes(ctx, r.pool, posts, currentUserID); err != nil {
+		return nil, nil, err
+	}
+	if err := attachPolls(ctx, r.pool, posts, currentUserID); err != nil {
+		return nil, nil, err
+	}
+	if err := attachReactions(ctx, r.pool, posts, currentUserID); err != nil {
+		return nil, nil, err
+	}
+	return posts, next, nil
 }
 
 func (r *PgPostRepository) Delete(ctx context.Context, id string, userId string) error {
@@ -202,20 +244,344 @@ func (r *PgPostRepository) Delete(ctx context.Context, id string, userId string)
 	return err
 }
 
-func (r *PgPostRepository) Update(ctx context.Context, p *entity.Post) error {
-	query := `UPDATE posts SET content = $1, updated_at = $2 WHERE id = $3 AND user_id = $4`
-	_, err := r.pool.Exec(ctx, query, p.Content, p.UpdatedAt, p.ID, p.UserID)
-	return err
+func (r *PgPostRepository) Update(ctx context.Context, p *entity.Post, revision *entity.PostRevision) error {
+	return pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		query := `UPDATE posts SET content = $1, updated_at = $2, version = $3 WHERE id = $4 AND user_id = $5 AND version = $6`
+		tag, err := tx.Exec(ctx, query, p.Content, p.UpdatedAt, p.Version, p.ID, p.UserID, p.Version-1)
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			// Nothing is updated for a post of another user, a post of p.UserID was updated concurrently
+			var exists bool
+			if err := tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM posts WHERE id = $1 AND user_id = $2)`, p.ID, p.UserID).Scan(&exists); err != nil {
+				return err
+			}
+			if exists {
+				return entity.ErrVersionMismatch
+			}
+			return nil
+		}
+		// The new content may mention other users
+		if _, err := tx.Exec(ctx, `DELETE FROM post_mentions WHERE post_id = $1`, p.ID); err != nil {
+			return err
+		}
+		if err := insertMentions(ctx, tx, p); err != nil {
+			return err
+		}
+		if revision == nil {
+			return nil
+		}
+
+		query = `INSERT INTO post_revisions (id, post_id, editor_id, content, edited_at) VALUES ($1, $2, $3, $4, $5)`
+		_, err = tx.Exec(ctx, query, revision.ID, revision.PostID, revision.EditorID, revision.Content, revision.EditedAt)
+		return err
+	})
+}
+
+func (r *PgPostRepository) Publish(ctx context.Context, p *entity.Post) error {
+	return pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		query := `UPDATE posts SET status = $1, publish_at = NULL, created_at = $2, updated_at = $3, version = $4
+		WHERE id = $5 AND user_id = $6 AND version = $7 AND status <> $8`
+		tag, err := tx.Exec(ctx, query, string(p.Status), p.CreatedAt, p.UpdatedAt, p.Version, p.ID, p.UserID, p.Version-1, string(entity.PostStatusPublished))
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			// Nothing is published for a post of another user, a post of p.UserID was updated or published concurrently
+			var exists bool
+			if err := tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM posts WHERE id = $1 AND user_id = $2)`, p.ID, p.UserID).Scan(&exists); err != nil {
+				return err
+			}
+			if exists {
+				return entity.ErrVersionMismatch
+			}
+		}
+		return nil
+	})
+}
+
+// Due posts are locked until the transaction ends, concurrent callers skip them rather than waiting and publishing them again
+func (r *PgPostRepository) PublishDue(ctx context.Context, now time.Time, limit int) ([]*entity.Post, error) {
+	var published []*entity.Post
+	err := pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		published = nil
+		query := `SELECT posts.id, posts.user_id, posts.content, posts.created_at, posts.updated_at, posts.version, posts.status, posts.publish_at, posts.visibility,
+		post_quotes.quoted_post_id
+		FROM posts
+		LEFT JOIN post_quotes ON post_quotes.post_id = posts.id
+		WHERE posts.status = $1 AND posts.publish_at <= $2
+		ORDER BY posts.publish_at, posts.id
+		LIMIT $3
+		FOR UPDATE OF posts SKIP LOCKED`
+		rows, err := tx.Query(ctx, query, string(entity.PostStatusScheduled), now, limit)
+		if err != nil {
+			return err
+		}
+		due, err := scanPosts(rows)
+		if err != nil {
+			return err
+		}
+
+		for _, p := range due {
+			if err := p.Publish(*p.PublishAt); err != nil {
+				return err
+			}
+			query := `UPDATE posts SET status = $1, publish_at = NULL, created_at = $2, updated_at = $3, version = $4 WHERE id = $5 AND status = $6`
+			tag, err := tx.Exec(ctx, query, string(p.Status), p.CreatedAt, p.UpdatedAt, p.Version, p.ID, string(entity.PostStatusScheduled))
+			if err != nil {
+				return err
+			}
+			if tag.RowsAffected() == 1 {
+				published = append(published, p)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return published, nil
+}
+
+func (r *PgPostRepository) FindDrafts(ctx context.Context, userID string, page dto.CursorPage) ([]*entity.Post, *dto.Cursor, error) {
+	after, afterArgs := afterCursor("posts", page.After, 4)
+	query := fmt.Sprintf(`SELECT posts.id, posts.user_id, posts.content, posts.created_at, posts.updated_at, posts.version, posts.status, posts.publish_at, posts.visibility,
+	post_quotes.quoted_post_id
+	FROM posts
+	LEFT JOIN post_quotes ON post_quotes.post_id = posts.id
+	WHERE posts.user_id = $1 AND posts.status <> $2 AND %s
+	ORDER BY posts.created_at DESC, posts.id DESC
+	LIMIT $3`, after)
+	rows, err := r.pool.Query(ctx, query, append([]any{userID, string(entity.PostStatusPublished), page.FetchLimit()}, afterArgs...)...)
+	if err != nil {
+		return nil, nil, err
+	}
+	drafts, err := scanPosts(rows)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	keys := make([]dto.Cursor, len(drafts))
+	for i, p := range drafts {
+		keys[i] = dto.Cursor{CreatedAt: p.CreatedAt, ID: p.ID}
+	}
+	drafts, next := dto.PageOf(page, drafts, keys)
+	return drafts, next, nil
+}
+
+// Sets the quoted post of each post the viewer may see, loaded with a single query whatever the number of posts.
+// The quotes of the quoted posts are left out
+func attachQuotes(ctx context.Context, pool *pgxpool.Pool, posts []*aggregate.Post, currentUserID *string) error {
+	var ids []string
+	for _, p := range posts {
+		if p.QuotedPostID != nil {
+			ids = append(ids, p.QuotedPostID.String())
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	query := fmt.Sprintf(`SELECT %s,
+		users.id, users.username, users.email
+	FROM posts
+	INNER JOIN users ON posts.user_id = users.id
+	%s
+	WHERE posts.id = ANY($2::uuid[]) AND %s`, postAggregateColumns("$1"), postCountJoins, listedTo("posts", "$1"))
+
+	rows, err := pool.Query(ctx, query, currentUserID, ids)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	quoted := map[uuid.UUID]*aggregate.Post{}
+	for rows.Next() {
+		var post Post
+		var user User
+		cpa, err := scanPostAggregate(rows, &post, &user.ID, &user.Username, &user.Email)
+		if err != nil {
+			return err
+		}
+
+		ePost, err := post.ToEntity()
+		if err != nil {
+			return err
+		}
+		eUser, err := user.ToEntity()
+		if err != nil {
+			return err
+		}
+		quoted[ePost.ID] = aggregate.NewPost(*ePost, *eUser, cpa)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, p := range posts {
+		if p.QuotedPostID != nil {
+			p.Quote = quoted[*p.QuotedPostID]
+		}
+	}
+	return nil
+}
+
+// Pins of owner visible to the viewer, most recently pinned first. Pins of another user's post are loaded as the repost of owner
+func findPinnedPosts(ctx context.Context, pool *pgxpool.Pool, owner entity.User, currentUserID *string) ([]*aggregate.Post, error) {
+	query := fmt.Sprintf(`SELECT %s,
+		users.id, users.username, users.email,
+		reposts.id, reposts.comment, reposts.created_at, reposts.updated_at
+	FROM pinned_posts
+	INNER JOIN posts ON pinned_posts.post_id = posts.id
+	INNER JOIN users ON posts.user_id = users.id
+	LEFT JOIN reposts ON pinned_posts.repost_id = reposts.id
+	%s
+	WHERE pinned_posts.user_id = $2 AND %s
+	ORDER BY pinned_posts.created_at DESC, pinned_posts.id DESC`, postAggregateColumns("$1"), postCountJoins, listedTo("posts", "$1"))
+
+	rows, err := pool.Query(ctx, query, currentUserID, owner.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var posts []*aggregate.Post
+	for rows.Next() {
+		var post Post
+		var user User
+		var repost Repost
+		cpa, err := scanPostAggregate(rows, &post, &user.ID, &user.Username, &user.Email, &repost.ID, &repost.Comment, &repost.CreatedAt, &repost.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		ePost, err := post.ToEntity()
+		if err != nil {
+			return nil, err
+		}
+		eUser, err := user.ToEntity()
+		if err != nil {
+			return nil, err
+		}
+
+		pinned := aggregate.NewPost(*ePost, *eUser, cpa)
+		if repost.ID.Valid {
+			repost.UserID, repost.PostID = pgtype.UUID{Bytes: owner.ID, Valid: true}, post.ID
+			eRepost, err := repost.ToEntity()
+			if err != nil {
+				return nil, err
+			}
+			pinned = aggregate.NewRepost(*ePost, eRepost, *eUser, &owner, cpa)
+		}
+		pinned.Pinned = true
+		posts = append(posts, pinned)
+	}
+	return posts, rows.Err()
+}
+
+// Columns of a post, its counts and whether viewer liked, favorited or reposted it, in the order scanPostAggregate
+// reads them. The query joins postCountJoins
+func postAggregateColumns(viewer string) string {
+	return fmt.Sprintf(`posts.id, posts.user_id, posts.content, posts.created_at, posts.updated_at, posts.version, posts.status, posts.publish_at, posts.visibility,
+		post_quotes.quoted_post_id,
+		COALESCE(likes_count.count, 0) AS like_count,
+		COALESCE(favorites_count.count, 0) AS favorite_count,
+		COALESCE(reposts_count.count, 0) AS repost_count,
+		COALESCE(quotes_count.count, 0) AS quote_count,
+		COALESCE(revisions_count.count, 0) AS edit_count,
+		-- Check if the current user has liked, favorited, or reposted the post
+		EXISTS (SELECT 1 FROM likes l WHERE l.post_id = posts.id AND l.user_id = %[1]s) AS liked,
+		EXISTS (SELECT 1 FROM favorites f WHERE f.post_id = posts.id AND f.user_id = %[1]s) AS favorited,
+		EXISTS (SELECT 1 FROM reposts r WHERE r.post_id = posts.id AND r.user_id = %[1]s) AS reposted`, viewer)
+}
+
+// Joins the counts of postAggregateColumns to the posts of the query
+const postCountJoins = `LEFT JOIN (SELECT post_id, COUNT(*) AS count FROM likes GROUP BY post_id) likes_count ON likes_count.post_id = posts.id
+	LEFT JOIN (SELECT post_id, COUNT(*) AS count FROM favorites GROUP BY post_id) favorites_count ON favorites_count.post_id = posts.id
+	LEFT JOIN (SELECT post_id, COUNT(*) AS count FROM reposts GROUP BY post_id) reposts_count ON reposts_count.post_id = posts.id
+	LEFT JOIN (SELECT post_quotes.quoted_post_id AS post_id, COUNT(*) AS count FROM post_quotes INNER JOIN posts quotes ON quotes.id = post_quotes.post_id WHERE quotes.status = 'published' GROUP BY post_quotes.quoted_post_id) quotes_count ON quotes_count.post_id = posts.id
+	LEFT JOIN post_quotes ON post_quotes.post_id = posts.id
+	LEFT JOIN (SELECT post_id, COUNT(*) AS count FROM post_revisions GROUP BY post_id) revisions_count ON revisions_count.post_id = posts.id`
+
+// Scans a row starting with postAggregateColumns into post, extra receives the columns selected after them
+func scanPostAggregate(rows pgx.Rows, post *Post, extra ...any) (dto.CommonPostAggregate, error) {
+	var cpa dto.CommonPostAggregate
+	dest := []any{
+		&post.ID,
+		&post.UserID,
+		&post.Content,
+		&post.CreatedAt,
+		&post.UpdatedAt,
+		&post.Version,
+		&post.Status,
+		&post.PublishAt,
+		&post.Visibility,
+		&post.QuotedPostID,
+		&cpa.LikeCount,
+		&cpa.FavoriteCount,
+		&cpa.RepostCount,
+		&cpa.QuoteCount,
+		&cpa.EditCount,
+		&cpa.Liked,
+		&cpa.Favorited,
+		&cpa.Reposted,
+	}
+	err := rows.Scan(append(dest, extra...)...)
+	return cpa, err
+}
+
+// Posts of rows selecting the columns of the posts table, closes rows
+func scanPosts(rows pgx.Rows) ([]*entity.Post, error) {
+	defer rows.Close()
+
+	var posts []*entity.Post
+	for rows.Next() {
+		var post Post
+		if err := rows.Scan(&post.ID, &post.UserID, &post.Content, &post.CreatedAt, &post.UpdatedAt, &post.Version, &post.Status, &post.PublishAt, &post.Visibility, &post.QuotedPostID); err != nil {
+			return nil, err
+		}
+		ePost, err := post.ToEntity()
+		if err != nil {
+			return nil, err
+		}
+		posts = append(posts, ePost)
+	}
+	return posts, rows.Err()
+}
+
+func (r *PgPostRepository) FindRevisions(ctx context.Context, postID string) ([]*entity.PostRevision, error) {
+	query := `SELECT id, post_id, editor_id, content, edited_at FROM post_revisions
+	WHERE post_id = $1
+	ORDER BY edited_at DESC, id DESC`
+	rows, err := r.pool.Query(ctx, query, postID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var revisions []*entity.PostRevision
+	for rows.Next() {
+		var revision PostRevision
+		if err := rows.Scan(&revision.ID, &revision.PostID, &revision.EditorID, &revision.Content, &revision.EditedAt); err != nil {
+			return nil, err
+		}
+		eRevision, err := revision.ToEntity()
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, eRevision)
+	}
+	return revisions, rows.Err()
 }
 
 func (r *PgPostRepository) getFeedTotalCount(ctx context.Context, userID string) (int, error) {
-	countQuery := `
+	countQuery := fmt.Sprintf(`
 		SELECT COUNT(*) FROM (
 			-- Count original posts from followed users or self
 			SELECT posts.id
 			FROM posts
-			LEFT JOIN follows ON posts.user_id = follows.followee_id
-			WHERE follows.follower_id = $1 OR posts.user_id = $1
+			WHERE (posts.user_id = $1 OR EXISTS (SELECT 1 FROM follows WHERE follows.follower_id = $1 AND follows.followee_id = posts.user_id)) AND %[1]s
 			
 			UNION ALL
 			
@@ -223,10 +589,9 @@ func (r *PgPostRepository) getFeedTotalCount(ctx context.Context, userID string)
 			SELECT posts.id
 			FROM reposts
 			INNER JOIN posts ON reposts.post_id = posts.id
-			LEFT JOIN follows ON reposts.user_id = follows.followee_id
-			WHERE follows.follower_id = $1 OR reposts.user_id = $1
+			WHERE (reposts.user_id = $1 OR EXISTS (SELECT 1 FROM follows WHERE follows.follower_id = $1 AND follows.followee_id = reposts.user_id)) AND %[1]s
 		) AS feed_count
-	`
+	`, listedTo("posts", "$1"))
 
 	var total int
 	err := r.pool.QueryRow(ctx, countQuery, userID).Scan(&total)
@@ -238,16 +603,23 @@ func (r *PgPostRepository) getFeedTotalCount(ctx context.Context, userID string)
 
 func (r *PgPostRepository) FindFeed(ctx context.Context, userID string, limit, offset int) ([]*aggregate.Post, int, error) {
 	// Must return exactly the same rows, and column type of both queries to avoid sql err
-	query := `
+	query := fmt.Sprintf(`
 	SELECT 
 		posts.id,
 		posts.user_id,
 		posts.content,
 		posts.created_at,
 		posts.updated_at,
+		posts.version,
+		posts.status,
+		posts.publish_at,
+		posts.visibility,
+		post_quotes.quoted_post_id,
 		COALESCE(likes_count.count, 0) AS like_count,
 		COALESCE(favorites_count.count, 0) AS favorite_count,
 		COALESCE(reposts_count.count, 0) AS repost_count,
+		COALESCE(quotes_count.count, 0) AS quote_count,
+		COALESCE(revisions_count.count, 0) AS edit_count,
 		NULL::uuid AS repost_id,
 		NULL::uuid AS repost_user_id,
 		NULL::uuid AS repost_post_id,
@@ -256,17 +628,20 @@ func (r *PgPostRepository) FindFeed(ctx context.Context, userID string, limit, o
 		NULL::timestamptz AS repost_updated_at,
 		posts.created_at AS feed_time,  -- Use original post time for sorting
 		users.id, users.username, users.email, -- post owner
+		NULL::text AS repost_username, NULL::text AS repost_email, -- reposter, joined such that the feed is a single query
 		-- Check if the current user has liked, favorited, or reposted the original post
 		EXISTS (SELECT 1 FROM likes l WHERE l.post_id = posts.id AND l.user_id = $1) AS liked,
 		EXISTS (SELECT 1 FROM favorites f WHERE f.post_id = posts.id AND f.user_id = $1) AS favorited,
 		EXISTS (SELECT 1 FROM reposts r WHERE r.post_id = posts.id AND r.user_id = $1) AS reposted
 	FROM posts
 	INNER JOIN users ON posts.user_id = users.id
-	LEFT JOIN follows ON posts.user_id = follows.followee_id
 	LEFT JOIN (SELECT post_id, COUNT(*) AS count FROM likes GROUP BY post_id) likes_count ON likes_count.post_id = posts.id
 	LEFT JOIN (SELECT post_id, COUNT(*) AS count FROM favorites GROUP BY post_id) favorites_count ON favorites_count.post_id = posts.id
 	LEFT JOIN (SELECT post_id, COUNT(*) AS count FROM reposts GROUP BY post_id) reposts_count ON reposts_count.post_id = posts.id
-	WHERE follows.follower_id = $1 OR posts.user_id = $1
+	LEFT JOIN (SELECT post_quotes.quoted_post_id AS post_id, COUNT(*) AS count FROM post_quotes INNER JOIN posts quotes ON quotes.id = post_quotes.post_id WHERE quotes.status = 'published' GROUP BY post_quotes.quoted_post_id) quotes_count ON quotes_count.post_id = posts.id
+	LEFT JOIN post_quotes ON post_quotes.post_id = posts.id
+	LEFT JOIN (SELECT post_id, COUNT(*) AS count FROM post_revisions GROUP BY post_id) revisions_count ON revisions_count.post_id = posts.id
+	WHERE (posts.user_id = $1 OR EXISTS (SELECT 1 FROM follows WHERE follows.follower_id = $1 AND follows.followee_id = posts.user_id)) AND %[1]s
 
 	UNION ALL
 
@@ -277,9 +652,16 @@ func (r *PgPostRepository) FindFeed(ctx context.Context, userID string, limit, o
 		posts.content,
 		posts.created_at,
 		posts.updated_at,
+		posts.version,
+		posts.status,
+		posts.publish_at,
+		posts.visibility,
+		post_quotes.quoted_post_id,
 		COALESCE(likes_count.count, 0) AS like_count,
 		COALESCE(favorites_count.count, 0) AS favorite_count,
 		COALESCE(reposts_count.count, 0) AS repost_count,
+		COALESCE(quotes_count.count, 0) AS quote_count,
+		COALESCE(revisions_count.count, 0) AS edit_count,
 		reposts.id AS repost_id,
 		reposts.user_id AS repost_user_id,
 		reposts.post_id AS repost_post_id,
@@ -288,6 +670,7 @@ func (r *PgPostRepository) FindFeed(ctx context.Context, userID string, limit, o
 		reposts.updated_at AS repost_updated_at,
 		reposts.created_at AS feed_time,  -- Use repost time for sorting
 		users.id, users.username, users.email, -- post owner
+		repost_users.username AS repost_username, repost_users.email AS repost_email,
 		-- Check if the current user has liked, favorited, or reposted the original post
 		EXISTS (SELECT 1 FROM likes l WHERE l.post_id = posts.id AND l.user_id = $1) AS liked,
 		EXISTS (SELECT 1 FROM favorites f WHERE f.post_id = posts.id AND f.user_id = $1) AS favorited,
@@ -295,15 +678,18 @@ func (r *PgPostRepository) FindFeed(ctx context.Context, userID string, limit, o
 	FROM reposts
 	INNER JOIN posts ON reposts.post_id = posts.id
 	INNER JOIN users ON users.id = posts.user_id
-	LEFT JOIN follows ON reposts.user_id = follows.followee_id
+	INNER JOIN users repost_users ON repost_users.id = reposts.user_id
 	LEFT JOIN (SELECT post_id, COUNT(*) AS count FROM likes GROUP BY post_id) likes_count ON likes_count.post_id = posts.id
 	LEFT JOIN (SELECT post_id, COUNT(*) AS count FROM favorites GROUP BY post_id) favorites_count ON favorites_count.post_id = posts.id
 	LEFT JOIN (SELECT post_id, COUNT(*) AS count FROM reposts GROUP BY post_id) reposts_count ON reposts_count.post_id = posts.id
-	WHERE follows.follower_id = $1 OR reposts.user_id = $1
+	LEFT JOIN (SELECT post_quotes.quoted_post_id AS post_id, COUNT(*) AS count FROM post_quotes INNER JOIN posts quotes ON quotes.id = post_quotes.post_id WHERE quotes.status = 'published' GROUP BY post_quotes.quoted_post_id) quotes_count ON quotes_count.post_id = posts.id
+	LEFT JOIN post_quotes ON post_quotes.post_id = posts.id
+	LEFT JOIN (SELECT post_id, COUNT(*) AS count FROM post_revisions GROUP BY post_id) revisions_count ON revisions_count.post_id = posts.id
+	WHERE (reposts.user_id = $1 OR EXISTS (SELECT 1 FROM follows WHERE follows.follower_id = $1 AND follows.followee_id = reposts.user_id)) AND %[1]s
 
 	ORDER BY feed_time DESC
 	LIMIT $2 OFFSET $3;
-	`
+	`, listedTo("posts", "$1"))
 
 	rows, err := r.pool.Query(ctx, query, userID, limit, offset)
 	if err != nil {
@@ -314,7 +700,7 @@ func (r *PgPostRepository) FindFeed(ctx context.Context, userID string, limit, o
 	var feed []*aggregate.Post
 	for rows.Next() {
 		var post Post
-		var likeCount, favoriteCount, repostCount int
+		var likeCount, favoriteCount, repostCount, quoteCount, editCount int
 		var liked, favorited, reposted bool
 		var feedTime time.Time
 		var postUser User
@@ -325,6 +711,7 @@ func (r *PgPostRepository) FindFeed(ctx context.Context, userID string, limit, o
 		var repostPostID uuid.UUID
 		// use pointer to avoid "can't scan into dest[11] (col: repost_comment): cannot scan NULL into *string"
 		var repostComment *string
+		var repostUsername, repostEmail *string
 		var repostCreatedAt, repostUpdatedAt *time.Time
 
 		err := rows.Scan(
@@ -333,9 +720,16 @@ func (r *PgPostRepository) FindFeed(ctx context.Context, userID string, limit, o
 			&post.Content,
 			&post.CreatedAt,
 			&post.UpdatedAt,
+			&post.Version,
+			&post.Status,
+			&post.PublishAt,
+			&post.Visibility,
+			&post.QuotedPostID,
 			&likeCount,
 			&favoriteCount,
 			&repostCount,
+			&quoteCount,
+			&editCount,
 			&repostID,
 			&repostUserID,
 			&repostPostID,
@@ -346,6 +740,8 @@ func (r *PgPostRepository) FindFeed(ctx context.Context, userID string, limit, o
 			&postUser.ID,
 			&postUser.Username,
 			&postUser.Email,
+			&repostUsername,
+			&repostEmail,
 			&liked,
 			&favorited,
 			&reposted,
@@ -358,6 +754,8 @@ func (r *PgPostRepository) FindFeed(ctx context.Context, userID string, limit, o
 			LikeCount:     likeCount,
 			FavoriteCount: favoriteCount,
 			RepostCount:   repostCount,
+			QuoteCount:    quoteCount,
+			EditCount:     editCount,
 			Liked:         liked,
 			Favorited:     favorited,
 			Reposted:      reposted,
@@ -389,10 +787,10 @@ func (r *PgPostRepository) FindFeed(ctx context.Context, userID string, limit, o
 				repost.Comment = *repostComment
 			}
 
-			var repostUser User
-			err = r.pool.QueryRow(ctx, "SELECT id, username, email FROM users WHERE id = $1", repostUserID).Scan(&repostUser.ID, &repostUser.Username, &repostUser.Email)
-			if err != nil {
-				return nil, 0, err
+			repostUser := User{
+				BaseModel: BaseModel{ID: pgtype.UUID{Bytes: repostUserID, Valid: true}},
+				Username:  pgtype.Text{String: *repostUsername, Valid: true},
+				Email:     pgtype.Text{String: *repostEmail, Valid: true},
 			}
 
 			eRepostUser, err := repostUser.ToEntity()
@@ -411,6 +809,16 @@ func (r *PgPostRepository) FindFeed(ctx context.Context, userID string, limit, o
 		return nil, 0, err
 	}
 
+	if err := attachQuotes(ctx, r.pool, feed, &userID); err != nil {
+		return nil, 0, err
+	}
+	if err := attachPolls(ctx, r.pool, feed, &userID); err != nil {
+		return nil, 0, err
+	}
+	if err := attachReactions(ctx, r.pool, feed, &userID); err != nil {
+		return nil, 0, err
+	}
+
 	total, err := r.getFeedTotalCount(ctx, userID)
 	if err != nil {
 		return nil, 0, err
@@ -3,9 +3,13 @@ package postgres
 import (
 	"context"
 	"fmt"
-	"log"
+	"social-media-go-ddd/internal/infrastructure/logging"
+	"social-media-go-ddd/internal/infrastructure/tracing"
+	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"go.opentelemetry.io/otel/trace"
 )
 
 func NewPgPool(ctx context.Context, dsn string) (*pgxpool.Pool, error) {
@@ -15,6 +19,7 @@ func NewPgPool(ctx context.Context, dsn string) (*pgxpool.Pool, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse Postgres DSN: %w", err)
 	}
+	poolConfig.ConnConfig.Tracer = queryTracer{}
 
 	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
 	if err != nil {
@@ -22,8 +27,33 @@ func NewPgPool(ctx context.Context, dsn string) (*pgxpool.Pool, error) {
 	}
 
 	if err = pool.Ping(ctx); err != nil {
-		log.Fatalf("Failed to ping Postgres: %v", err)
+		pool.Close()
+		return nil, fmt.Errorf("failed to ping Postgres: %w", err)
 	}
 
 	return pool, nil
 }
+
+type queryStartKey struct{}
+
+type queryStart struct {
+	sql  string
+	time time.Time
+	span trace.Span
+}
+
+// Logs every query with the logger of the context and traces it as a child of the span of the context
+type queryTracer struct{}
+
+func (queryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	ctx, span := tracing.StartQuery(ctx, "postgresql", data.SQL)
+	return context.WithValue(ctx, queryStartKey{}, queryStart{sql: data.SQL, time: time.Now(), span: span})
+}
+
+// Called once the rows are closed, the span covers reading them
+func (queryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	if start, ok := ctx.Value(queryStartKey{}).(queryStart); ok {
+		logging.LogQuery(ctx, start.sql, start.time, data.Err)
+		tracing.End(start.span, data.Err)
+	}
+}
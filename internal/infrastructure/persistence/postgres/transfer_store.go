@@ -0,0 +1,105 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"social-media-go-ddd/internal/infrastructure/persistence/transfer"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PgTransferStore reads and writes raw table rows for cmd/transfer
+type PgTransferStore struct {
+	basePgRepository
+}
+
+func NewPgTransferStore(pool *pgxpool.Pool) *PgTransferStore {
+	return &PgTransferStore{basePgRepository: NewBasePgRepository(pool)}
+}
+
+func pgPlaceholder(n int) string {
+	return fmt.Sprintf("$%d", n)
+}
+
+func (s *PgTransferStore) Count(ctx context.Context, t transfer.Table) (int64, error) {
+	var count int64
+	err := s.pool.QueryRow(ctx, "SELECT COUNT(*) FROM "+t.Name).Scan(&count)
+	return count, err
+}
+
+func (s *PgTransferStore) Read(ctx context.Context, t transfer.Table, afterID string, limit int) ([]transfer.Row, error) {
+	args := []any{limit}
+	if afterID != "" {
+		args = []any{afterID, limit}
+	}
+
+	rows, err := s.pool.Query(ctx, t.SelectSQL(afterID, pgPlaceholder), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []transfer.Row
+	for rows.Next() {
+		// Same column types as model.go, uuid columns scan into text
+		texts := make([]pgtype.Text, len(t.Columns))
+		times := make([]pgtype.Timestamptz, len(t.Columns))
+		bools := make([]pgtype.Bool, len(t.Columns))
+		dest := make([]any, len(t.Columns))
+		for i, c := range t.Columns {
+			switch c.Type {
+			case transfer.ColumnTime:
+				dest[i] = &times[i]
+			case transfer.ColumnBool:
+				dest[i] = &bools[i]
+			default:
+				dest[i] = &texts[i]
+			}
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+
+		row := make(transfer.Row, len(t.Columns))
+		for i, c := range t.Columns {
+			switch c.Type {
+			case transfer.ColumnTime:
+				row[i] = transfer.Value{Time: times[i].Time, Valid: times[i].Valid}
+			case transfer.ColumnBool:
+				row[i] = transfer.Value{Bool: bools[i].Bool, Valid: bools[i].Valid}
+			default:
+				row[i] = transfer.Value{String: texts[i].String, Valid: texts[i].Valid}
+			}
+		}
+		result = append(result, row)
+	}
+
+	return result, rows.Err()
+}
+
+func (s *PgTransferStore) Write(ctx context.Context, t transfer.Table, rows []transfer.Row) error {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	args := make([]any, 0, len(rows)*len(t.Columns))
+	for _, row := range rows {
+		for i, v := range row {
+			switch {
+			case !v.Valid:
+				args = append(args, nil)
+			case t.Columns[i].Type == transfer.ColumnTime:
+				args = append(args, v.Time)
+			case t.Columns[i].Type == transfer.ColumnBool:
+				args = append(args, v.Bool)
+			default:
+				args = append(args, v.String)
+			}
+		}
+	}
+
+	query := t.InsertSQL(len(rows), pgPlaceholder) + " ON CONFLICT (id) DO NOTHING"
+	_, err := s.pool.Exec(ctx, query, args...)
+	return err
+}
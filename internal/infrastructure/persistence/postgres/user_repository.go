@@ -20,11 +20,15 @@ func NewPgUserRepository(pool *pgxpool.Pool) *PgUserRepository {
 	}
 }
 
+// The default collection of the user is created along with them
 func (r *PgUserRepository) Save(ctx context.Context, u *entity.User) error {
-	query := `INSERT INTO users (id, username, email, password) VALUES ($1, $2, $3, $4)`
-
-	_, err := r.pool.Exec(ctx, query, u.ID, u.Username, u.Email, u.Password.GetHash())
-	return err
+	return pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		query := `INSERT INTO users (id, username, email, password) VALUES ($1, $2, $3, $4)`
+		if _, err := tx.Exec(ctx, query, u.ID, u.Username, u.Email, u.Password.GetHash()); err != nil {
+			return err
+		}
+		return insertCollection(ctx, tx, entity.NewDefaultCollection(u.ID))
+	})
 }
 
 type UserWithFollow struct {
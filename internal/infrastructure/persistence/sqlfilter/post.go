@@ -0,0 +1,33 @@
+// Package sqlfilter builds the sql conditions the sql backends share from the rules of the domain, such that the
+// backends only differ in their placeholders
+package sqlfilter
+
+import (
+	"fmt"
+	"social-media-go-ddd/internal/domain/entity"
+	"strings"
+)
+
+// Condition selecting the published posts of table the viewer may see, the sql form of entity.Post.ListedTo built
+// from entity.PostVisibilityRules. viewer is the sql of the viewer id, eg a placeholder, a NULL viewer only sees
+// public posts. It occurs n times in the condition, backends with positional placeholders pass the viewer n times
+func PostListedTo(table, viewer string) (cond string, n int) {
+	audiences := []string{fmt.Sprintf("%s.user_id = %s", table, viewer)}
+	n = 1
+	for _, rule := range entity.PostVisibilityRules {
+		visibility := fmt.Sprintf("%s.visibility = '%s'", table, rule.Visibility)
+		switch rule.Access {
+		case entity.PostAccessAnyone:
+			audiences = append(audiences, visibility)
+		case entity.PostAccessFollower:
+			audiences = append(audiences, fmt.Sprintf("(%s AND EXISTS (SELECT 1 FROM follows WHERE follows.follower_id = %s AND follows.followee_id = %s.user_id))", visibility, viewer, table))
+			n++
+		case entity.PostAccessMentioned:
+			audiences = append(audiences, fmt.Sprintf("(%s AND EXISTS (SELECT 1 FROM post_mentions WHERE post_mentions.post_id = %s.id AND post_mentions.user_id = %s))", visibility, table, viewer))
+			n++
+		default:
+			panic(fmt.Sprintf("sqlfilter: no sql for post access %d", rule.Access))
+		}
+	}
+	return fmt.Sprintf("(%s.status = '%s' AND (%s))", table, entity.PostStatusPublished, strings.Join(audiences, "\n\t\tOR ")), n
+}
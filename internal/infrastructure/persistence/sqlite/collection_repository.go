@@ -0,0 +1,87 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"social-media-go-ddd/internal/domain/entity"
+)
+
+type SQLiteCollectionRepository struct {
+	baseSQLiteRepository
+}
+
+func NewSQLiteCollectionRepository(db *sql.DB) *SQLiteCollectionRepository {
+	return &SQLiteCollectionRepository{
+		baseSQLiteRepository: NewBaseSQLiteRepository(db),
+	}
+}
+
+const collectionColumns = `id, user_id, name, is_default, created_at, updated_at`
+
+func (r *SQLiteCollectionRepository) Save(ctx context.Context, c *entity.Collection) error {
+	return r.db.inTx(ctx, func(tx instrumentedTx) error {
+		return insertCollection(ctx, tx, c)
+	})
+}
+
+// Also used by SQLiteUserRepository.Save for the default collection
+func insertCollection(ctx context.Context, tx instrumentedTx, c *entity.Collection) error {
+	query := `INSERT INTO collections (` + collectionColumns + `) VALUES (?, ?, ?, ?, ?, ?)`
+	_, err := tx.ExecContext(ctx, query, c.ID, c.UserID, c.Name, c.Default, timeValue(c.CreatedAt), timeValue(c.UpdatedAt))
+	return err
+}
+
+func (r *SQLiteCollectionRepository) Update(ctx context.Context, c *entity.Collection) error {
+	query := `UPDATE collections SET name = ?, updated_at = ? WHERE id = ? AND user_id = ?`
+	_, err := r.db.ExecContext(ctx, query, c.Name, timeValue(c.UpdatedAt), c.ID, c.UserID)
+	return err
+}
+
+func (r *SQLiteCollectionRepository) Delete(ctx context.Context, id string, userID string) error {
+	return r.db.inTx(ctx, func(tx instrumentedTx) error {
+		query := `
+			UPDATE favorites SET collection_id = (SELECT id FROM collections WHERE user_id = ? AND is_default)
+			WHERE collection_id = (SELECT id FROM collections WHERE id = ? AND user_id = ? AND NOT is_default)
+		`
+		if _, err := tx.ExecContext(ctx, query, userID, id, userID); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, `DELETE FROM collections WHERE id = ? AND user_id = ? AND NOT is_default`, id, userID)
+		return err
+	})
+}
+
+func (r *SQLiteCollectionRepository) FindByID(ctx context.Context, id string) (*entity.Collection, error) {
+	return scanCollection(r.db.QueryRowContext(ctx, `SELECT `+collectionColumns+` FROM collections WHERE id = ?`, id))
+}
+
+func (r *SQLiteCollectionRepository) FindDefault(ctx context.Context, userID string) (*entity.Collection, error) {
+	return scanCollection(r.db.QueryRowContext(ctx, `SELECT `+collectionColumns+` FROM collections WHERE user_id = ? AND is_default`, userID))
+}
+
+func (r *SQLiteCollectionRepository) FindByUserID(ctx context.Context, userID string) ([]*entity.Collection, error) {
+	query := `SELECT ` + collectionColumns + ` FROM collections WHERE user_id = ? ORDER BY is_default DESC, created_at, id`
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var collections []*entity.Collection
+	for rows.Next() {
+		collection, err := scanCollection(rows)
+		if err != nil {
+			return nil, err
+		}
+		collections = append(collections, collection)
+	}
+	return collections, rows.Err()
+}
+
+func scanCollection(row interface{ Scan(dest ...any) error }) (*entity.Collection, error) {
+	var c Collection
+	if err := row.Scan(&c.ID, &c.UserID, &c.Name, &c.IsDefault, &c.CreatedAt, &c.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return c.ToEntity()
+}
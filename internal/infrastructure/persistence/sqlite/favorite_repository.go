@@ -0,0 +1,184 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"social-media-go-ddd/internal/domain/aggregate"
+	"social-media-go-ddd/internal/domain/dto"
+	"social-media-go-ddd/internal/domain/entity"
+)
+
+type SQLiteFavoriteRepository struct {
+	baseSQLiteRepository
+}
+
+func NewSQLiteFavoriteRepository(db *sql.DB) *SQLiteFavoriteRepository {
+	return &SQLiteFavoriteRepository{
+		baseSQLiteRepository: NewBaseSQLiteRepository(db),
+	}
+}
+
+// Moves the favorite into the collection of f if the post was favorited before
+func (r *SQLiteFavoriteRepository) Save(ctx context.Context, f *entity.Favorite) error {
+	query := `
+		INSERT INTO favorites (id, user_id, post_id, collection_id)
+		SELECT ?, ?, ?, id FROM collections WHERE id = ? AND user_id = ?
+		ON CONFLICT (user_id, post_id) DO UPDATE SET collection_id = excluded.collection_id, updated_at = excluded.updated_at
+	`
+	res, err := r.db.ExecContext(ctx, query, f.ID, f.UserID, f.PostID, f.CollectionID, f.UserID)
+	if err != nil {
+		return err
+	}
+	saved, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if saved == 0 {
+		// The collection does not exist or belongs to another user
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *SQLiteFavoriteRepository) Delete(ctx context.Context, userID, postID string) error {
+	query := `DELETE FROM favorites WHERE user_id = ? AND post_id = ?`
+	_, err := r.db.ExecContext(ctx, query, userID, postID)
+	return err
+}
+
+func (r *SQLiteFavoriteRepository) DeleteFromCollection(ctx context.Context, collectionID, postID string) error {
+	query := `DELETE FROM favorites WHERE collection_id = ? AND post_id = ?`
+	_, err := r.db.ExecContext(ctx, query, collectionID, postID)
+	return err
+}
+
+func (r *SQLiteFavoriteRepository) FindByUserID(ctx context.Context, userID string, currentUserID *string, page dto.CursorPage) ([]*aggregate.Post, *dto.Cursor, error) {
+	return r.find(ctx, "favorites.user_id", userID, currentUserID, page)
+}
+
+func (r *SQLiteFavoriteRepository) FindByCollectionID(ctx context.Context, collectionID string, currentUserID *string, page dto.CursorPage) ([]*aggregate.Post, *dto.Cursor, error) {
+	return r.find(ctx, "favorites.collection_id", collectionID, currentUserID, page)
+}
+
+// Favorites whose column equals id
+func (r *SQLiteFavoriteRepository) find(ctx context.Context, column, id string, currentUserID *string, page dto.CursorPage) ([]*aggregate.Post, *dto.Cursor, error) {
+	listed, listedArgs := listedTo("p", currentUserID)
+	after, afterArgs := afterCursor("favorites", page.After)
+	query := fmt.Sprintf(`
+		SELECT p.id, p.user_id, p.content, p.created_at, p.updated_at, p.version, p.status, p.publish_at, p.visibility,
+			post_quotes.quoted_post_id,
+			COALESCE(likes_count.count, 0) AS like_count,
+			COALESCE(favorites_count.count, 0) AS favorite_count,
+			COALESCE(reposts_count.count, 0) AS repost_count,
+			COALESCE(quotes_count.count, 0) AS quote_count,
+			COALESCE(revisions_count.count, 0) AS edit_count,
+			users.id, users.username, users.email,
+			favorites.id, favorites.created_at,
+			-- Check if the current user has liked, favorited, or reposted the post
+			EXISTS (SELECT 1 FROM likes l WHERE l.post_id = p.id AND l.user_id = ?) AS liked,
+			EXISTS (SELECT 1 FROM favorites f WHERE f.post_id = p.id AND f.user_id = ?) AS favorited,
+			EXISTS (SELECT 1 FROM reposts r WHERE r.post_id = p.id AND r.user_id = ?) AS reposted
+		FROM favorites
+		INNER JOIN posts p ON favorites.post_id = p.id
+		INNER JOIN users ON p.user_id = users.id
+		LEFT JOIN (SELECT post_id, COUNT(*) AS count FROM likes GROUP BY post_id) likes_count ON likes_count.post_id = p.id
+		LEFT JOIN (SELECT post_id, COUNT(*) AS count FROM favorites GROUP BY post_id) favorites_count ON favorites_count.post_id = p.id
+		LEFT JOIN (SELECT post_id, COUNT(*) AS count FROM reposts GROUP BY post_id) reposts_count ON reposts_count.post_id = p.id
+		LEFT JOIN (SELECT post_quotes.quoted_post_id AS post_id, COUNT(*) AS count FROM post_quotes INNER JOIN posts quotes ON quotes.id = post_quotes.post_id WHERE quotes.status = 'published' GROUP BY post_quotes.quoted_post_id) quotes_count ON quotes_count.post_id = p.id
+		LEFT JOIN post_quotes ON post_quotes.post_id = p.id
+		LEFT JOIN (SELECT post_id, COUNT(*) AS count FROM post_revisions GROUP BY post_id) revisions_count ON revisions_count.post_id = p.id
+		WHERE %s = ? AND %s AND %s
+		ORDER BY favorites.created_at DESC, favorites.id DESC
+		LIMIT ?
+	`, column, listed, after)
+
+	args := append([]any{currentUserID, currentUserID, currentUserID, id}, listedArgs...)
+	args = append(args, afterArgs...)
+	rows, err := r.db.QueryContext(ctx, query, append(args, page.FetchLimit())...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	var posts []*aggregate.Post
+	var keys []dto.Cursor
+	for rows.Next() {
+		var post Post
+		var likeCount, favoriteCount, repostCount, quoteCount, editCount int
+		var liked, favorited, reposted bool
+		var user User
+		var favorite BaseModel
+
+		if err := rows.Scan(
+			&post.ID,
+			&post.UserID,
+			&post.Content,
+			&post.CreatedAt,
+			&post.UpdatedAt,
+			&post.Version,
+			&post.Status,
+			&post.PublishAt,
+			&post.Visibility,
+			&post.QuotedPostID,
+			&likeCount,
+			&favoriteCount,
+			&repostCount,
+			&quoteCount,
+			&editCount,
+			&user.ID,
+			&user.Username,
+			&user.Email,
+			&favorite.ID,
+			&favorite.CreatedAt,
+			&liked,
+			&favorited,
+			&reposted,
+		); err != nil {
+			return nil, nil, err
+		}
+
+		ePost, err := post.ToEntity()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		eUser, err := user.ToEntity()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		key, err := favorite.ToEntity()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		keys = append(keys, dto.Cursor{CreatedAt: key.CreatedAt, ID: key.ID})
+		posts = append(posts, aggregate.NewPost(*ePost, *eUser, dto.CommonPostAggregate{
+			LikeCount:     likeCount,
+			FavoriteCount: favoriteCount,
+			RepostCount:   repostCount,
+			QuoteCount:    quoteCount,
+			EditCount:     editCount,
+			Liked:         liked,
+			Favorited:     favorited,
+			Reposted:      reposted,
+		}))
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	posts, next := dto.PageOf(page, posts, keys)
+	if err := attachQuotes(ctx, r.db, posts, currentUserID); err != nil {
+		return nil, nil, err
+	}
+	if err := attachPolls(ctx, r.db, posts, currentUserID); err != nil {
+		return nil, nil, err
+	}
+	if err := attachReactions(ctx, r.db, posts, currentUserID); err != nil {
+		return nil, nil, err
+	}
+	return posts, next, nil
+}
@@ -0,0 +1,31 @@
+package sqlite
+
+import (
+	"context"
+	"social-media-go-ddd/internal/domain/entity"
+
+	"database/sql"
+)
+
+type SQLiteFollowRepository struct {
+	baseSQLiteRepository
+}
+
+func NewSQLiteFollowRepository(db *sql.DB) *SQLiteFollowRepository {
+	return &SQLiteFollowRepository{
+		baseSQLiteRepository: NewBaseSQLiteRepository(db),
+	}
+}
+
+// If already follow, does nothing
+func (r *SQLiteFollowRepository) Save(ctx context.Context, f *entity.Follow) error {
+	query := `INSERT INTO follows (id, follower_id, followee_id) VALUES (?, ?, ?) ON CONFLICT (follower_id, followee_id) DO NOTHING`
+	_, err := r.db.ExecContext(ctx, query, f.ID, f.FollowerID, f.FolloweeID)
+	return err
+}
+
+func (r *SQLiteFollowRepository) Delete(ctx context.Context, followerID, followeeID string) error {
+	query := `DELETE FROM follows WHERE follower_id = ? AND followee_id = ?`
+	_, err := r.db.ExecContext(ctx, query, followerID, followeeID)
+	return err
+}
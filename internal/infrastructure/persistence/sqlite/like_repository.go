@@ -0,0 +1,113 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"social-media-go-ddd/internal/domain/aggregate"
+	"social-media-go-ddd/internal/domain/entity"
+	"strings"
+)
+
+type SQLiteLikeRepository struct {
+	baseSQLiteRepository
+}
+
+func NewSQLiteLikeRepository(db *sql.DB) *SQLiteLikeRepository {
+	return &SQLiteLikeRepository{
+		baseSQLiteRepository: NewBaseSQLiteRepository(db),
+	}
+}
+
+// Inserts the like unless the user already reacted to the post
+const insertLikeQuery = `
+	INSERT INTO likes (id, user_id, post_id, reaction) VALUES (?, ?, ?, ?)
+	ON CONFLICT (user_id, post_id) DO NOTHING
+`
+
+// Replaces the reaction if the user already reacted to the post. An upsert reports an update as a changed row too, the
+// insert and the update are separate statements to tell them apart
+func (r *SQLiteLikeRepository) Save(ctx context.Context, l *entity.Like) (inserted bool, err error) {
+	err = r.db.inTx(ctx, func(tx instrumentedTx) error {
+		inserted, err = insertedRow(tx.ExecContext(ctx, insertLikeQuery, l.ID, l.UserID, l.PostID, l.Reaction))
+		if err != nil || inserted {
+			return err
+		}
+		query := `UPDATE likes SET reaction = ?, updated_at = ? WHERE user_id = ? AND post_id = ?`
+		_, err = tx.ExecContext(ctx, query, l.Reaction, timeValue(l.UpdatedAt), l.UserID, l.PostID)
+		return err
+	})
+	return inserted, err
+}
+
+// Keeps the reaction if the user already reacted to the post
+func (r *SQLiteLikeRepository) SaveIfAbsent(ctx context.Context, l *entity.Like) (bool, error) {
+	return insertedRow(r.db.ExecContext(ctx, insertLikeQuery, l.ID, l.UserID, l.PostID, l.Reaction))
+}
+
+// Whether the statement inserted a row, for inserts doing nothing on conflict
+func insertedRow(res sql.Result, err error) (bool, error) {
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n == 1, err
+}
+
+func (r *SQLiteLikeRepository) Delete(ctx context.Context, userID, postID string) error {
+	query := `DELETE FROM likes WHERE user_id = ? AND post_id = ?`
+	_, err := r.db.ExecContext(ctx, query, userID, postID)
+	return err
+}
+
+// Sets the reaction counts and the viewer's reaction of the posts and their quoted posts, loaded with a single query
+func attachReactions(ctx context.Context, db instrumentedDB, posts []*aggregate.Post, currentUserID *string) error {
+	// A page may hold a post next to its repost or quote, both get the reactions
+	byID := map[string][]*aggregate.Post{}
+	var ids []any
+	for _, p := range slices.Concat(posts, quotedPosts(posts)) {
+		id := p.ID.String()
+		if _, ok := byID[id]; !ok {
+			ids = append(ids, id)
+		}
+		byID[id] = append(byID[id], p)
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	query := fmt.Sprintf(`SELECT post_id, reaction, COUNT(*) AS count, COALESCE(MAX(user_id = ?), 0) AS reacted
+	FROM likes
+	WHERE post_id IN (?%s)
+	GROUP BY post_id, reaction`, strings.Repeat(", ?", len(ids)-1))
+
+	rows, err := db.QueryContext(ctx, query, append([]any{currentUserID}, ids...)...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var postID, reaction string
+		var count int
+		var reacted bool
+		if err := rows.Scan(&postID, &reaction, &count, &reacted); err != nil {
+			return err
+		}
+		for _, p := range byID[postID] {
+			p.AddReactions(entity.Reaction(reaction), count, reacted)
+		}
+	}
+	return rows.Err()
+}
+
+func quotedPosts(posts []*aggregate.Post) []*aggregate.Post {
+	var quoted []*aggregate.Post
+	for _, p := range posts {
+		if p.Quote != nil {
+			quoted = append(quoted, p.Quote)
+		}
+	}
+	return quoted
+}
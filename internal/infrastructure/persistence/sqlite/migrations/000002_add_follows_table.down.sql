@@ -0,0 +1 @@
+DROP TABLE IF EXISTS follows;
\ No newline at end of file
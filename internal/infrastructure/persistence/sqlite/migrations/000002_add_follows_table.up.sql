@@ -0,0 +1,7 @@
+CREATE TABLE follows (
+    id TEXT PRIMARY KEY,
+    follower_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    followee_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at DATETIME NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')),
+    UNIQUE (follower_id, followee_id)
+);
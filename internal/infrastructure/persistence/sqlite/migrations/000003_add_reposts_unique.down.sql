@@ -0,0 +1 @@
+DROP INDEX IF EXISTS reposts_user_id_post_id_key;
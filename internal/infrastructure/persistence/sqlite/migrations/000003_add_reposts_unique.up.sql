@@ -0,0 +1,10 @@
+-- Keep only the latest repost per user and post before enforcing uniqueness
+DELETE FROM reposts
+WHERE EXISTS (
+    SELECT 1 FROM reposts newer
+    WHERE newer.user_id = reposts.user_id
+      AND newer.post_id = reposts.post_id
+      AND (newer.created_at > reposts.created_at OR (newer.created_at = reposts.created_at AND newer.id > reposts.id))
+);
+
+CREATE UNIQUE INDEX reposts_user_id_post_id_key ON reposts (user_id, post_id);
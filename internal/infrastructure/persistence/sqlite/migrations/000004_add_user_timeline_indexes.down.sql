@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS reposts_user_id_created_at_idx;
+DROP INDEX IF EXISTS favorites_user_id_created_at_idx;
+DROP INDEX IF EXISTS posts_user_id_created_at_idx;
@@ -0,0 +1,4 @@
+-- Profile timelines, favorites and reposts are paginated by (created_at, id) newest first per user
+CREATE INDEX IF NOT EXISTS posts_user_id_created_at_idx ON posts (user_id, created_at, id);
+CREATE INDEX IF NOT EXISTS favorites_user_id_created_at_idx ON favorites (user_id, created_at, id);
+CREATE INDEX IF NOT EXISTS reposts_user_id_created_at_idx ON reposts (user_id, created_at, id);
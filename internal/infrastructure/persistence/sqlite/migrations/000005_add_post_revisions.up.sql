@@ -0,0 +1,9 @@
+-- Content of a post before each of its edits
+CREATE TABLE post_revisions (
+    id TEXT PRIMARY KEY,
+    post_id TEXT NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    editor_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    content TEXT NOT NULL,
+    edited_at DATETIME NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now'))
+);
+CREATE INDEX IF NOT EXISTS post_revisions_post_id_edited_at_idx ON post_revisions (post_id, edited_at, id);
@@ -0,0 +1,2 @@
+-- Incremented by every update, updates made from an older version are rejected
+ALTER TABLE posts ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
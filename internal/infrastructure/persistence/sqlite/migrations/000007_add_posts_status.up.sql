@@ -0,0 +1,6 @@
+-- Drafts and scheduled posts stay private to their author until published
+ALTER TABLE posts ADD COLUMN status TEXT NOT NULL DEFAULT 'published';
+-- When a scheduled post gets published, NULL for other posts
+ALTER TABLE posts ADD COLUMN publish_at DATETIME;
+-- The scheduler looks up due scheduled posts
+CREATE INDEX IF NOT EXISTS posts_status_publish_at_idx ON posts (status, publish_at);
@@ -0,0 +1,7 @@
+-- Post quoted by another post, a quote loses its quoted post when that one is deleted
+CREATE TABLE post_quotes (
+    id TEXT PRIMARY KEY,
+    post_id TEXT NOT NULL UNIQUE REFERENCES posts(id) ON DELETE CASCADE,
+    quoted_post_id TEXT NOT NULL REFERENCES posts(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS post_quotes_quoted_post_id_idx ON post_quotes (quoted_post_id);
@@ -0,0 +1,34 @@
+-- Poll attached to a post, choice is single or multiple
+CREATE TABLE polls (
+    id TEXT PRIMARY KEY,
+    post_id TEXT NOT NULL UNIQUE REFERENCES posts(id) ON DELETE CASCADE,
+    choice TEXT NOT NULL DEFAULT 'single',
+    closes_at DATETIME NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')),
+    updated_at DATETIME NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now'))
+);
+
+CREATE TABLE poll_options (
+    id TEXT PRIMARY KEY,
+    poll_id TEXT NOT NULL REFERENCES polls(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    text TEXT NOT NULL,
+    UNIQUE (poll_id, position)
+);
+
+-- One vote per user and poll, the options it chose are in poll_vote_options
+CREATE TABLE poll_votes (
+    id TEXT PRIMARY KEY,
+    poll_id TEXT NOT NULL REFERENCES polls(id) ON DELETE CASCADE,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at DATETIME NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')),
+    UNIQUE (poll_id, user_id)
+);
+
+CREATE TABLE poll_vote_options (
+    id TEXT PRIMARY KEY,
+    vote_id TEXT NOT NULL REFERENCES poll_votes(id) ON DELETE CASCADE,
+    option_id TEXT NOT NULL REFERENCES poll_options(id) ON DELETE CASCADE,
+    UNIQUE (vote_id, option_id)
+);
+CREATE INDEX IF NOT EXISTS poll_vote_options_option_id_idx ON poll_vote_options (option_id);
@@ -0,0 +1,14 @@
+CREATE TABLE favorites_without_collection (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    post_id TEXT NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    created_at DATETIME NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')),
+    updated_at DATETIME NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')),
+    UNIQUE (user_id, post_id)
+);
+INSERT INTO favorites_without_collection (id, user_id, post_id, created_at, updated_at)
+SELECT id, user_id, post_id, created_at, updated_at FROM favorites;
+DROP TABLE favorites;
+ALTER TABLE favorites_without_collection RENAME TO favorites;
+CREATE INDEX IF NOT EXISTS favorites_user_id_created_at_idx ON favorites (user_id, created_at, id);
+DROP TABLE IF EXISTS collections;
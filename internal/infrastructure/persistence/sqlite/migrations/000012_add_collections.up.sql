@@ -0,0 +1,37 @@
+-- Named, private groups of favorites, every user has a default collection that cannot be deleted
+CREATE TABLE collections (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    is_default INTEGER NOT NULL DEFAULT 0,
+    created_at DATETIME NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')),
+    updated_at DATETIME NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')),
+    UNIQUE (user_id, name)
+);
+
+-- Random version 4 uuids, SQLite has no function for them
+INSERT INTO collections (id, user_id, name, is_default)
+SELECT lower(hex(randomblob(4))) || '-' || lower(hex(randomblob(2))) || '-4' || substr(lower(hex(randomblob(2))), 2) || '-'
+        || substr('89ab', 1 + (abs(random()) % 4), 1) || substr(lower(hex(randomblob(2))), 2) || '-' || lower(hex(randomblob(6))),
+    id, 'Favorites', 1
+FROM users;
+
+-- SQLite cannot add a NOT NULL foreign key to an existing table, move the favorites into their default collection on a copy
+CREATE TABLE favorites_with_collection (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    post_id TEXT NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    collection_id TEXT NOT NULL REFERENCES collections(id) ON DELETE CASCADE,
+    created_at DATETIME NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')),
+    updated_at DATETIME NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')),
+    UNIQUE (user_id, post_id)
+);
+INSERT INTO favorites_with_collection (id, user_id, post_id, collection_id, created_at, updated_at)
+SELECT favorites.id, favorites.user_id, favorites.post_id, collections.id, favorites.created_at, favorites.updated_at
+FROM favorites
+INNER JOIN collections ON collections.user_id = favorites.user_id AND collections.is_default = 1;
+DROP TABLE favorites;
+ALTER TABLE favorites_with_collection RENAME TO favorites;
+CREATE INDEX IF NOT EXISTS favorites_user_id_created_at_idx ON favorites (user_id, created_at, id);
+-- Collections are paginated like the favorites of a user
+CREATE INDEX IF NOT EXISTS favorites_collection_id_created_at_idx ON favorites (collection_id, created_at, id);
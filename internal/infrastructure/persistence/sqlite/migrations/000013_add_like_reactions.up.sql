@@ -0,0 +1,2 @@
+-- Likes are reactions, one per user and post: like, love, haha, wow, sad or angry. Existing likes are the reaction like
+ALTER TABLE likes ADD COLUMN reaction TEXT NOT NULL DEFAULT 'like';
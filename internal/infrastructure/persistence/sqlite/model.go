@@ -0,0 +1,424 @@
+package sqlite
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"social-media-go-ddd/internal/domain/entity"
+	"social-media-go-ddd/internal/domain/valueobject"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Layout of every timestamp column, it matches strftime('%Y-%m-%d %H:%M:%f') used by column defaults
+// such that timestamps written by sqlite and by the app sort the same way as text
+const timeLayout = "2006-01-02 15:04:05.000"
+
+// Format time for a query argument, always use it instead of passing time.Time directly
+func timeValue(t time.Time) string {
+	return t.UTC().Format(timeLayout)
+}
+
+// Time scans a timestamp column. Sqlite has no timestamp type, so depending on the declared column type
+// the driver returns either time.Time or the raw text, and NULL for the nullable columns of a UNION.
+type Time struct {
+	time.Time
+	Valid bool
+}
+
+func (t *Time) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		t.Time, t.Valid = time.Time{}, false
+		return nil
+	case time.Time:
+		t.Time, t.Valid = v, true
+		return nil
+	case string:
+		return t.parse(v)
+	case []byte:
+		return t.parse(string(v))
+	default:
+		return fmt.Errorf("sqlite: cannot scan %T into Time", src)
+	}
+}
+
+func (t *Time) parse(s string) error {
+	for _, layout := range []string{timeLayout, time.RFC3339Nano, "2006-01-02 15:04:05.999999999-07:00", "2006-01-02 15:04:05"} {
+		if parsed, err := time.ParseInLocation(layout, s, time.UTC); err == nil {
+			t.Time, t.Valid = parsed, true
+			return nil
+		}
+	}
+	return fmt.Errorf("sqlite: cannot parse %q as time", s)
+}
+
+// Nil for NULL
+func (t Time) Ptr() *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+// Format an optional time for a query argument, nil is NULL
+func nullTimeValue(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+	return timeValue(*t)
+}
+
+func (t Time) Value() (driver.Value, error) {
+	if !t.Valid {
+		return nil, nil
+	}
+	return timeValue(t.Time), nil
+}
+
+type BaseModel struct {
+	ID        string `db:"id"` // UUID stored as TEXT
+	CreatedAt Time   `db:"created_at"`
+	UpdatedAt Time   `db:"updated_at"`
+}
+
+func (b *BaseModel) ToEntity() (entity.BaseEntity, error) {
+	if b == nil {
+		return entity.BaseEntity{}, nil
+	}
+	uuidID, err := entity.StringToUUID(b.ID)
+	if err != nil {
+		return entity.BaseEntity{}, err
+	}
+	return entity.BaseEntity{
+		ID:        uuidID,
+		CreatedAt: b.CreatedAt.Time,
+		UpdatedAt: b.UpdatedAt.Time,
+	}, nil
+}
+
+type User struct {
+	BaseModel
+	Username string `db:"username"`
+	Password string `db:"password"`
+	Email    string `db:"email"`
+}
+
+func (u *User) ToEntity() (*entity.User, error) {
+	if u == nil {
+		return nil, nil
+	}
+	baseEntity, err := u.BaseModel.ToEntity()
+	if err != nil {
+		return nil, err
+	}
+	return &entity.User{
+		BaseEntity: baseEntity,
+		Username:   u.Username,
+		Password:   valueobject.PasswordFromHash(u.Password),
+		Email:      u.Email,
+	}, nil
+}
+
+type Post struct {
+	BaseModel
+	UserID     string `db:"user_id"`
+	Content    string `db:"content"`
+	Version    int    `db:"version"`
+	Status     string `db:"status"`
+	PublishAt  Time   `db:"publish_at"`
+	Visibility string `db:"visibility"`
+	// From post_quotes, NULL when the post quotes none
+	QuotedPostID uuid.NullUUID `db:"quoted_post_id"`
+}
+
+func (p *Post) ToEntity() (*entity.Post, error) {
+	if p == nil {
+		return nil, nil
+	}
+	baseEntity, err := p.BaseModel.ToEntity()
+	if err != nil {
+		return nil, err
+	}
+	userID, err := entity.StringToUUID(p.UserID)
+	if err != nil {
+		return nil, err
+	}
+	return &entity.Post{
+		BaseEntity:   baseEntity,
+		UserID:       userID,
+		Content:      p.Content,
+		Version:      p.Version,
+		Status:       entity.PostStatus(p.Status),
+		PublishAt:    p.PublishAt.Ptr(),
+		Visibility:   entity.PostVisibility(p.Visibility),
+		QuotedPostID: nullUUIDPtr(p.QuotedPostID),
+	}, nil
+}
+
+// Nil for NULL
+func nullUUIDPtr(id uuid.NullUUID) *uuid.UUID {
+	if !id.Valid {
+		return nil
+	}
+	return &id.UUID
+}
+
+type PostRevision struct {
+	ID       string `db:"id"`
+	PostID   string `db:"post_id"`
+	EditorID string `db:"editor_id"`
+	Content  string `db:"content"`
+	EditedAt Time   `db:"edited_at"`
+}
+
+func (r *PostRevision) ToEntity() (*entity.PostRevision, error) {
+	if r == nil {
+		return nil, nil
+	}
+	id, err := entity.StringToUUID(r.ID)
+	if err != nil {
+		return nil, err
+	}
+	postID, err := entity.StringToUUID(r.PostID)
+	if err != nil {
+		return nil, err
+	}
+	editorID, err := entity.StringToUUID(r.EditorID)
+	if err != nil {
+		return nil, err
+	}
+	return &entity.PostRevision{
+		ID:       id,
+		PostID:   postID,
+		EditorID: editorID,
+		Content:  r.Content,
+		EditedAt: r.EditedAt.Time,
+	}, nil
+}
+
+type Like struct {
+	BaseModel
+	UserID   string `db:"user_id"`
+	PostID   string `db:"post_id"`
+	Reaction string `db:"reaction"`
+}
+
+func (l *Like) ToEntity() (*entity.Like, error) {
+	if l == nil {
+		return nil, nil
+	}
+	baseEntity, err := l.BaseModel.ToEntity()
+	if err != nil {
+		return nil, err
+	}
+	userID, err := entity.StringToUUID(l.UserID)
+	if err != nil {
+		return nil, err
+	}
+	postID, err := entity.StringToUUID(l.PostID)
+	if err != nil {
+		return nil, err
+	}
+	return &entity.Like{
+		BaseEntity: baseEntity,
+		UserID:     userID,
+		PostID:     postID,
+		Reaction:   entity.Reaction(l.Reaction),
+	}, nil
+}
+
+type Favorite struct {
+	BaseModel
+	UserID       string `db:"user_id"`
+	PostID       string `db:"post_id"`
+	CollectionID string `db:"collection_id"`
+}
+
+func (f *Favorite) ToEntity() (*entity.Favorite, error) {
+	if f == nil {
+		return nil, nil
+	}
+	baseEntity, err := f.BaseModel.ToEntity()
+	if err != nil {
+		return nil, err
+	}
+	userID, err := entity.StringToUUID(f.UserID)
+	if err != nil {
+		return nil, err
+	}
+	postID, err := entity.StringToUUID(f.PostID)
+	if err != nil {
+		return nil, err
+	}
+	collectionID, err := entity.StringToUUID(f.CollectionID)
+	if err != nil {
+		return nil, err
+	}
+	return &entity.Favorite{
+		BaseEntity:   baseEntity,
+		UserID:       userID,
+		PostID:       postID,
+		CollectionID: collectionID,
+	}, nil
+}
+
+type Collection struct {
+	BaseModel
+	UserID    string `db:"user_id"`
+	Name      string `db:"name"`
+	IsDefault bool   `db:"is_default"`
+}
+
+func (c *Collection) ToEntity() (*entity.Collection, error) {
+	if c == nil {
+		return nil, nil
+	}
+	baseEntity, err := c.BaseModel.ToEntity()
+	if err != nil {
+		return nil, err
+	}
+	userID, err := entity.StringToUUID(c.UserID)
+	if err != nil {
+		return nil, err
+	}
+	return &entity.Collection{
+		BaseEntity: baseEntity,
+		UserID:     userID,
+		Name:       c.Name,
+		Default:    c.IsDefault,
+	}, nil
+}
+
+type Repost struct {
+	BaseModel
+	UserID  string `db:"user_id"`
+	PostID  string `db:"post_id"`
+	Comment string `db:"comment"`
+}
+
+func (r *Repost) ToEntity() (*entity.Repost, error) {
+	if r == nil {
+		return nil, nil
+	}
+	baseEntity, err := r.BaseModel.ToEntity()
+	if err != nil {
+		return nil, err
+	}
+	userID, err := entity.StringToUUID(r.UserID)
+	if err != nil {
+		return nil, err
+	}
+	postID, err := entity.StringToUUID(r.PostID)
+	if err != nil {
+		return nil, err
+	}
+	return &entity.Repost{
+		BaseEntity: baseEntity,
+		UserID:     userID,
+		PostID:     postID,
+		Comment:    r.Comment,
+	}, nil
+}
+
+type Session struct {
+	BaseModel
+	UserID   string `db:"user_id"`
+	ExpireAt Time   `db:"expire_at"`
+}
+
+func (s *Session) ToEntity() (*entity.Session, error) {
+	if s == nil {
+		return nil, nil
+	}
+	userID, err := entity.StringToUUID(s.UserID)
+	if err != nil {
+		return nil, err
+	}
+	baseEntity, err := s.BaseModel.ToEntity()
+	if err != nil {
+		return nil, err
+	}
+	return &entity.Session{
+		BaseEntity: baseEntity,
+		UserID:     userID,
+		ExpireAt:   s.ExpireAt.Time,
+	}, nil
+}
+
+type Follow struct {
+	BaseModel
+	FollowerID string `db:"follower_id"`
+	FolloweeID string `db:"followee_id"`
+}
+
+func (f *Follow) ToEntity() (*entity.Follow, error) {
+	if f == nil {
+		return nil, nil
+	}
+	baseEntity, err := f.BaseModel.ToEntity()
+	if err != nil {
+		return nil, err
+	}
+	followerID, err := entity.StringToUUID(f.FollowerID)
+	if err != nil {
+		return nil, err
+	}
+	followeeID, err := entity.StringToUUID(f.FolloweeID)
+	if err != nil {
+		return nil, err
+	}
+	return &entity.Follow{
+		BaseEntity: baseEntity,
+		FollowerID: followerID,
+		FolloweeID: followeeID,
+	}, nil
+}
+
+type Poll struct {
+	BaseModel
+	PostID   string `db:"post_id"`
+	Choice   string `db:"choice"`
+	ClosesAt Time   `db:"closes_at"`
+}
+
+func (p *Poll) ToEntity() (*entity.Poll, error) {
+	if p == nil {
+		return nil, nil
+	}
+	baseEntity, err := p.BaseModel.ToEntity()
+	if err != nil {
+		return nil, err
+	}
+	postID, err := entity.StringToUUID(p.PostID)
+	if err != nil {
+		return nil, err
+	}
+	return &entity.Poll{
+		BaseEntity: baseEntity,
+		PostID:     postID,
+		Choice:     entity.PollChoice(p.Choice),
+		ClosesAt:   p.ClosesAt.Time,
+	}, nil
+}
+
+type PollOption struct {
+	ID       string `db:"id"`
+	Position int    `db:"position"`
+	Text     string `db:"text"`
+}
+
+func (o *PollOption) ToEntity() (*entity.PollOption, error) {
+	if o == nil {
+		return nil, nil
+	}
+	id, err := entity.StringToUUID(o.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &entity.PollOption{
+		ID:       id,
+		Position: o.Position,
+		Text:     o.Text,
+	}, nil
+}
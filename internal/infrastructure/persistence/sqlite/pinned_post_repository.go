@@ -0,0 +1,59 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"social-media-go-ddd/internal/domain/entity"
+)
+
+type SQLitePinnedPostRepository struct {
+	baseSQLiteRepository
+}
+
+func NewSQLitePinnedPostRepository(db *sql.DB) *SQLitePinnedPostRepository {
+	return &SQLitePinnedPostRepository{
+		baseSQLiteRepository: NewBaseSQLiteRepository(db),
+	}
+}
+
+// If already pinned, does nothing. The pin of another user's post references the repost, such that it goes away with it
+func (r *SQLitePinnedPostRepository) Save(ctx context.Context, p *entity.PinnedPost) error {
+	return r.db.inTx(ctx, func(tx instrumentedTx) error {
+		var pinned bool
+		query := `SELECT EXISTS (SELECT 1 FROM pinned_posts WHERE user_id = ? AND post_id = ?)`
+		if err := tx.QueryRowContext(ctx, query, p.UserID, p.PostID).Scan(&pinned); err != nil || pinned {
+			return err
+		}
+
+		var authorID string
+		var repostID sql.NullString
+		query = `SELECT posts.user_id, reposts.id FROM posts LEFT JOIN reposts ON reposts.post_id = posts.id AND reposts.user_id = ? WHERE posts.id = ?`
+		if err := tx.QueryRowContext(ctx, query, p.UserID, p.PostID).Scan(&authorID, &repostID); err != nil {
+			return err
+		}
+		if authorID == p.UserID.String() {
+			// Own posts stay pinned whether or not they are reposted
+			repostID = sql.NullString{}
+		} else if !repostID.Valid {
+			return entity.ErrPinNotOwnOrRepost
+		}
+
+		var count int
+		if err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM pinned_posts WHERE user_id = ?`, p.UserID).Scan(&count); err != nil {
+			return err
+		}
+		if count >= entity.MaxPinnedPosts {
+			return entity.ErrPinLimitReached
+		}
+
+		query = `INSERT INTO pinned_posts (id, user_id, post_id, repost_id) VALUES (?, ?, ?, ?)`
+		_, err := tx.ExecContext(ctx, query, p.ID, p.UserID, p.PostID, repostID)
+		return err
+	})
+}
+
+func (r *SQLitePinnedPostRepository) Delete(ctx context.Context, userID, postID string) error {
+	query := `DELETE FROM pinned_posts WHERE user_id = ? AND post_id = ?`
+	_, err := r.db.ExecContext(ctx, query, userID, postID)
+	return err
+}
@@ -0,0 +1,152 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"social-media-go-ddd/internal/domain/aggregate"
+	"social-media-go-ddd/internal/domain/entity"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type SQLitePollRepository struct {
+	baseSQLiteRepository
+}
+
+func NewSQLitePollRepository(db *sql.DB) *SQLitePollRepository {
+	return &SQLitePollRepository{
+		baseSQLiteRepository: NewBaseSQLiteRepository(db),
+	}
+}
+
+func (r *SQLitePollRepository) Save(ctx context.Context, p *entity.Poll) error {
+	return r.db.inTx(ctx, func(tx instrumentedTx) error {
+		query := `INSERT INTO polls (id, post_id, choice, closes_at) VALUES (?, ?, ?, ?)`
+		if _, err := tx.ExecContext(ctx, query, p.ID, p.PostID, string(p.Choice), timeValue(p.ClosesAt)); err != nil {
+			return err
+		}
+		for _, o := range p.Options {
+			query := `INSERT INTO poll_options (id, poll_id, position, text) VALUES (?, ?, ?, ?)`
+			if _, err := tx.ExecContext(ctx, query, o.ID, p.ID, o.Position, o.Text); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *SQLitePollRepository) FindByPostID(ctx context.Context, postID string, currentUserID *string) (*aggregate.Poll, error) {
+	polls, err := findPolls(ctx, r.db, []any{postID}, currentUserID)
+	if err != nil {
+		return nil, err
+	}
+	poll, ok := polls[postID]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return poll, nil
+}
+
+// Sets the poll of each post that has one, loaded with a single query whatever the number of posts
+func attachPolls(ctx context.Context, db instrumentedDB, posts []*aggregate.Post, currentUserID *string) error {
+	if len(posts) == 0 {
+		return nil
+	}
+	ids := make([]any, len(posts))
+	for i, p := range posts {
+		ids[i] = p.ID.String()
+	}
+
+	polls, err := findPolls(ctx, db, ids, currentUserID)
+	if err != nil {
+		return err
+	}
+	for _, p := range posts {
+		p.Poll = polls[p.ID.String()]
+	}
+	return nil
+}
+
+// Polls of the posts by post id, with one row per option holding its votes and whether the viewer chose it
+func findPolls(ctx context.Context, db instrumentedDB, postIDs []any, currentUserID *string) (map[string]*aggregate.Poll, error) {
+	query := fmt.Sprintf(`SELECT polls.id, polls.post_id, polls.choice, polls.closes_at, polls.created_at, polls.updated_at,
+		COALESCE(voters_count.count, 0) AS voter_count,
+		poll_options.id, poll_options.position, poll_options.text,
+		COALESCE(votes_count.count, 0) AS votes,
+		EXISTS (SELECT 1 FROM poll_vote_options INNER JOIN poll_votes ON poll_votes.id = poll_vote_options.vote_id
+			WHERE poll_vote_options.option_id = poll_options.id AND poll_votes.user_id = ?) AS chosen
+	FROM polls
+	INNER JOIN poll_options ON poll_options.poll_id = polls.id
+	LEFT JOIN (SELECT poll_id, COUNT(*) AS count FROM poll_votes GROUP BY poll_id) voters_count ON voters_count.poll_id = polls.id
+	LEFT JOIN (SELECT option_id, COUNT(*) AS count FROM poll_vote_options GROUP BY option_id) votes_count ON votes_count.option_id = poll_options.id
+	WHERE polls.post_id IN (?%s)
+	ORDER BY polls.post_id, poll_options.position`, strings.Repeat(", ?", len(postIDs)-1))
+
+	rows, err := db.QueryContext(ctx, query, append([]any{currentUserID}, postIDs...)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	type result struct {
+		poll    *entity.Poll
+		voters  int
+		tallies []int
+		choice  []uuid.UUID
+	}
+	var results []*result
+	for rows.Next() {
+		var poll Poll
+		var option PollOption
+		var voters, votes int
+		var chosen bool
+		if err := rows.Scan(
+			&poll.ID,
+			&poll.PostID,
+			&poll.Choice,
+			&poll.ClosesAt,
+			&poll.CreatedAt,
+			&poll.UpdatedAt,
+			&voters,
+			&option.ID,
+			&option.Position,
+			&option.Text,
+			&votes,
+			&chosen,
+		); err != nil {
+			return nil, err
+		}
+
+		// Rows of a poll follow each other
+		if len(results) == 0 || results[len(results)-1].poll.ID.String() != poll.ID {
+			ePoll, err := poll.ToEntity()
+			if err != nil {
+				return nil, err
+			}
+			results = append(results, &result{poll: ePoll, voters: voters})
+		}
+		r := results[len(results)-1]
+		eOption, err := option.ToEntity()
+		if err != nil {
+			return nil, err
+		}
+		r.poll.Options = append(r.poll.Options, *eOption)
+		r.tallies = append(r.tallies, votes)
+		if chosen {
+			r.choice = append(r.choice, eOption.ID)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	polls := make(map[string]*aggregate.Poll, len(results))
+	for _, r := range results {
+		polls[r.poll.PostID.String()] = aggregate.NewPoll(*r.poll, r.tallies, r.voters, r.choice, now)
+	}
+	return polls, nil
+}
@@ -0,0 +1,105 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"social-media-go-ddd/internal/domain/repository/repositorytest"
+	"social-media-go-ddd/internal/infrastructure/tracing/tracingtest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-migrate/migrate/v4"
+	_ "github.com/golang-migrate/migrate/v4/database/sqlite"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestRepositories(t *testing.T) {
+	exp := tracingtest.Record(t)
+	repositorytest.RunAll(t, func(t *testing.T) repositorytest.Repositories {
+		return newTestRepositories(t, exp)
+	})
+}
+
+func BenchmarkReads(b *testing.B) {
+	repositorytest.BenchmarkReads(b, newTestRepositories(b, tracingtest.Record(b)))
+}
+
+// Repositories over a new database, queries are counted from the spans recorded in exp
+func newTestRepositories(tb testing.TB, exp *tracetest.InMemoryExporter) repositorytest.Repositories {
+	tb.Helper()
+
+	db := newTestDB(tb)
+	return repositorytest.Repositories{
+		User:                NewSQLiteUserRepository(db),
+		Session:             NewSQLiteSessionRepository(db),
+		Post:                NewSQLitePostRepository(db),
+		Like:                NewSQLiteLikeRepository(db),
+		Favorite:            NewSQLiteFavoriteRepository(db),
+		Repost:              NewSQLiteRepostRepository(db),
+		Follow:              NewSQLiteFollowRepository(db),
+		Poll:                NewSQLitePollRepository(db),
+		PollVote:            NewSQLitePollVoteRepository(db),
+		Pin:                 NewSQLitePinnedPostRepository(db),
+		Collection:          NewSQLiteCollectionRepository(db),
+		TimestampResolution: time.Millisecond,
+		QueryCount:          func() int { return tracingtest.CountQueries(exp) },
+	}
+}
+
+// Migrated database in a temp dir, closed once the test ends
+func newTestDB(t testing.TB) *sql.DB {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "test.db")
+	dsn := path + "?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)"
+
+	src, err := iofs.New(Migrations, "migrations")
+	if err != nil {
+		t.Fatalf("read migrations: %v", err)
+	}
+	m, err := migrate.NewWithSourceInstance("iofs", src, "sqlite://"+dsn)
+	if err != nil {
+		t.Fatalf("init migrations: %v", err)
+	}
+	if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		t.Fatalf("run migrations: %v", err)
+	}
+	m.Close()
+
+	db, err := NewSQLiteDB(dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestQuerySpans(t *testing.T) {
+	exp := tracingtest.Record(t)
+
+	// The user does not exist, only the query matters
+	_, _ = NewSQLiteUserRepository(newTestDB(t)).FindByName(context.Background(), "alice", nil)
+
+	spans := exp.GetSpans()
+	if len(spans) == 0 {
+		t.Fatal("no query span")
+	}
+	for _, s := range spans {
+		attrs := attribute.NewSet(s.Attributes...)
+		if s.Name != "db.query" {
+			t.Fatalf("span %q", s.Name)
+		}
+		if system, _ := attrs.Value("db.system"); system.AsString() != "sqlite" {
+			t.Fatalf("db.system %q", system.AsString())
+		}
+		text, _ := attrs.Value("db.query.text")
+		if !strings.Contains(text.AsString(), "WHERE users.username = ?") || strings.Contains(text.AsString(), "\n") {
+			t.Fatalf("db.query.text %q", text.AsString())
+		}
+	}
+}
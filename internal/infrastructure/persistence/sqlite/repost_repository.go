@@ -0,0 +1,194 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"social-media-go-ddd/internal/domain/aggregate"
+	"social-media-go-ddd/internal/domain/dto"
+	"social-media-go-ddd/internal/domain/entity"
+)
+
+type SQLiteRepostRepository struct {
+	baseSQLiteRepository
+}
+
+func NewSQLiteRepostRepository(db *sql.DB) *SQLiteRepostRepository {
+	return &SQLiteRepostRepository{
+		baseSQLiteRepository: NewBaseSQLiteRepository(db),
+	}
+}
+
+func (r *SQLiteRepostRepository) FindByID(ctx context.Context, id string) (*entity.Repost, error) {
+	query := `SELECT id, user_id, post_id, comment, created_at, updated_at FROM reposts WHERE id = ?`
+
+	row := r.db.QueryRowContext(ctx, query, id)
+
+	var rp Repost
+	err := row.Scan(&rp.ID, &rp.UserID, &rp.PostID, &rp.Comment, &rp.CreatedAt, &rp.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return rp.ToEntity()
+}
+
+// One user can only repost one, if already exist, update comment
+func (r *SQLiteRepostRepository) Save(ctx context.Context, rp *entity.Repost) error {
+	query := `INSERT INTO reposts (id, user_id, post_id, comment) VALUES (?, ?, ?, ?) ON CONFLICT (user_id, post_id) DO UPDATE SET comment = excluded.comment, updated_at = excluded.updated_at`
+	_, err := r.db.ExecContext(ctx, query, rp.ID, rp.UserID, rp.PostID, rp.Comment)
+	return err
+}
+
+func (r *SQLiteRepostRepository) Delete(ctx context.Context, userID string, postID string) error {
+	query := `DELETE FROM reposts WHERE user_id = ? AND post_id = ?`
+	_, err := r.db.ExecContext(ctx, query, userID, postID)
+	return err
+}
+
+func (r *SQLiteRepostRepository) FindByUserID(ctx context.Context, userID string, currentUserID *string, page dto.CursorPage) ([]*aggregate.Post, *dto.Cursor, error) {
+	listed, listedArgs := listedTo("p", currentUserID)
+	after, afterArgs := afterCursor("reposts", page.After)
+	query := fmt.Sprintf(`
+		SELECT p.id, p.user_id, p.content, p.created_at, p.updated_at, p.version, p.status, p.publish_at, p.visibility,
+			post_quotes.quoted_post_id,
+			COALESCE(likes_count.count, 0) AS like_count,
+			COALESCE(favorites_count.count, 0) AS favorite_count,
+			COALESCE(reposts_count.count, 0) AS repost_count,
+			COALESCE(quotes_count.count, 0) AS quote_count,
+			COALESCE(revisions_count.count, 0) AS edit_count,
+			reposts.id, reposts.user_id, reposts.post_id, reposts.comment, reposts.created_at, reposts.updated_at,
+			users.id, users.username, users.email,
+			repost_users.id, repost_users.username, repost_users.email,
+			-- Check if the current user has liked, favorited, or reposted the post
+			EXISTS (SELECT 1 FROM likes l WHERE l.post_id = p.id AND l.user_id = ?) AS liked,
+			EXISTS (SELECT 1 FROM favorites f WHERE f.post_id = p.id AND f.user_id = ?) AS favorited,
+			EXISTS (SELECT 1 FROM reposts r WHERE r.post_id = p.id AND r.user_id = ?) AS reposted
+		FROM reposts
+		INNER JOIN posts p ON reposts.post_id = p.id
+		INNER JOIN users ON p.user_id = users.id
+		INNER JOIN users repost_users ON reposts.user_id = repost_users.id
+		LEFT JOIN (SELECT post_id, COUNT(*) AS count FROM likes GROUP BY post_id) likes_count ON likes_count.post_id = p.id
+		LEFT JOIN (SELECT post_id, COUNT(*) AS count FROM favorites GROUP BY post_id) favorites_count ON favorites_count.post_id = p.id
+		LEFT JOIN (SELECT post_id, COUNT(*) AS count FROM reposts GROUP BY post_id) reposts_count ON reposts_count.post_id = p.id
+		LEFT JOIN (SELECT post_quotes.quoted_post_id AS post_id, COUNT(*) AS count FROM post_quotes INNER JOIN posts quotes ON quotes.id = post_quotes.post_id WHERE quotes.status = 'published' GROUP BY post_quotes.quoted_post_id) quotes_count ON quotes_count.post_id = p.id
+		LEFT JOIN post_quotes ON post_quotes.post_id = p.id
+		LEFT JOIN (SELECT post_id, COUNT(*) AS count FROM post_revisions GROUP BY post_id) revisions_count ON revisions_count.post_id = p.id
+		WHERE reposts.user_id = ? AND %s AND %s
+		ORDER BY reposts.created_at DESC, reposts.id DESC
+		LIMIT ?
+	`, listed, after)
+
+	args := append([]any{currentUserID, currentUserID, currentUserID, userID}, listedArgs...)
+	args = append(args, afterArgs...)
+	rows, err := r.db.QueryContext(ctx, query, append(args, page.FetchLimit())...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	var reposts []*aggregate.Post
+	var keys []dto.Cursor
+	for rows.Next() {
+		var post Post
+		var likeCount, favoriteCount, repostCount, quoteCount, editCount int
+		var liked, favorited, reposted bool
+		var repost Repost
+		var user, repostUser User
+
+		if err := rows.Scan(
+			&post.ID,
+			&post.UserID,
+			&post.Content,
+			&post.CreatedAt,
+			&post.UpdatedAt,
+			&post.Version,
+			&post.Status,
+			&post.PublishAt,
+			&post.Visibility,
+			&post.QuotedPostID,
+			&likeCount,
+			&favoriteCount,
+			&repostCount,
+			&quoteCount,
+			&editCount,
+			&repost.ID,
+			&repost.UserID,
+			&repost.PostID,
+			&repost.Comment,
+			&repost.CreatedAt,
+			&repost.UpdatedAt,
+			&user.ID,
+			&user.Username,
+			&user.Email,
+			&repostUser.ID,
+			&repostUser.Username,
+			&repostUser.Email,
+			&liked,
+			&favorited,
+			&reposted,
+		); err != nil {
+			return nil, nil, err
+		}
+
+		ePost, err := post.ToEntity()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		eRepost, err := repost.ToEntity()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		eUser, err := user.ToEntity()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		eRepostUser, err := repostUser.ToEntity()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		keys = append(keys, dto.Cursor{CreatedAt: eRepost.CreatedAt, ID: eRepost.ID})
+		reposts = append(reposts, aggregate.NewRepost(*ePost, eRepost, *eUser, eRepostUser, dto.CommonPostAggregate{
+			LikeCount:     likeCount,
+			FavoriteCount: favoriteCount,
+			RepostCount:   repostCount,
+			QuoteCount:    quoteCount,
+			EditCount:     editCount,
+			Liked:         liked,
+			Favorited:     favorited,
+			Reposted:      reposted,
+		}))
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	reposts, next := dto.PageOf(page, reposts, keys)
+
+	// The reposter is joined, without reposts tell an unknown user apart from one who never reposted
+	if len(reposts) == 0 {
+		var exists bool
+		if err := r.db.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM users WHERE id = ?)", userID).Scan(&exists); err != nil {
+			return nil, nil, err
+		}
+		if !exists {
+			return nil, nil, sql.ErrNoRows
+		}
+	}
+
+	if err := attachQuotes(ctx, r.db, reposts, currentUserID); err != nil {
+		return nil, nil, err
+	}
+	if err := attachPolls(ctx, r.db, reposts, currentUserID); err != nil {
+		return nil, nil, err
+	}
+	if err := attachReactions(ctx, r.db, reposts, currentUserID); err != nil {
+		return nil, nil, err
+	}
+	return reposts, next, nil
+}
@@ -0,0 +1,49 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"social-media-go-ddd/internal/domain/entity"
+)
+
+type SQLiteSessionRepository struct {
+	baseSQLiteRepository
+}
+
+func NewSQLiteSessionRepository(db *sql.DB) *SQLiteSessionRepository {
+	return &SQLiteSessionRepository{
+		baseSQLiteRepository: NewBaseSQLiteRepository(db),
+	}
+}
+
+func (r *SQLiteSessionRepository) Save(ctx context.Context, s *entity.Session) error {
+	query := `INSERT INTO sessions (id, user_id, expire_at) VALUES (?, ?, ?)`
+	_, err := r.db.ExecContext(ctx, query, s.ID, s.UserID, timeValue(s.ExpireAt))
+	return err
+}
+
+func (r *SQLiteSessionRepository) FindByID(ctx context.Context, id string) (*entity.Session, error) {
+	query := `SELECT id, user_id, expire_at, created_at, updated_at FROM sessions WHERE id = ?`
+
+	row := r.db.QueryRowContext(ctx, query, id)
+
+	var sess Session
+	err := row.Scan(&sess.ID, &sess.UserID, &sess.ExpireAt, &sess.CreatedAt, &sess.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return sess.ToEntity()
+}
+
+func (r *SQLiteSessionRepository) Delete(ctx context.Context, id string) error {
+	query := `DELETE FROM sessions WHERE id = ?`
+	_, err := r.db.ExecContext(ctx, query, id)
+	return err
+}
+
+func (r *SQLiteSessionRepository) UpdateExpireAt(ctx context.Context, s *entity.Session) error {
+	query := `UPDATE sessions SET expire_at = ?, updated_at = ? WHERE id = ?`
+	_, err := r.db.ExecContext(ctx, query, timeValue(s.ExpireAt), timeValue(s.UpdatedAt), s.ID)
+	return err
+}
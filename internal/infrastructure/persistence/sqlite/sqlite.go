@@ -0,0 +1,23 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+
+	_ "modernc.org/sqlite"
+)
+
+// dsn is a path to the database file, optionally followed by query params such as "?_pragma=foreign_keys(1)".
+// In-memory databases are not supported because every pooled connection would get its own empty database.
+func NewSQLiteDB(dsn string) (*sql.DB, error) {
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open SQLite database: %w", err)
+	}
+
+	if err := db.Ping(); err != nil {
+		return nil, fmt.Errorf("failed to connect to SQLite: %w", err)
+	}
+
+	return db, nil
+}
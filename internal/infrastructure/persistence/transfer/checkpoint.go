@@ -0,0 +1,79 @@
+package transfer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+type TableProgress struct {
+	// Id of the last row written to the target
+	LastID string `json:"lastId"`
+	Rows   int64  `json:"rows"`
+	Done   bool   `json:"done"`
+}
+
+// Checkpoint is the progress of a transfer saved in a json file, such that an interrupted transfer can be resumed
+type Checkpoint struct {
+	path string
+	// Fingerprints of the databases the progress belongs to, without passwords
+	Source string                   `json:"source"`
+	Target string                   `json:"target"`
+	Tables map[string]TableProgress `json:"tables"`
+}
+
+var ErrCheckpointMismatch = errors.New("checkpoint belongs to other databases")
+
+// Load the checkpoint at path, or start a new one if the file does not exist or reset is set.
+// A checkpoint saved for another source or target is refused with ErrCheckpointMismatch
+func LoadCheckpoint(path, source, target string, reset bool) (*Checkpoint, error) {
+	c := &Checkpoint{path: path, Source: source, Target: target, Tables: map[string]TableProgress{}}
+	if reset {
+		return c, nil
+	}
+
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var saved Checkpoint
+	if err := json.Unmarshal(b, &saved); err != nil {
+		return nil, err
+	}
+	if saved.Source != source || saved.Target != target {
+		return nil, fmt.Errorf("%w: saved for %s to %s", ErrCheckpointMismatch, saved.Source, saved.Target)
+	}
+	if saved.Tables != nil {
+		c.Tables = saved.Tables
+	}
+	return c, nil
+}
+
+// Written to a temporary file then renamed, such that a crash never leaves a truncated checkpoint
+func (c *Checkpoint) Save() error {
+	b, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := c.path + ".tmp"
+	if err := os.WriteFile(tmp, b, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, c.path)
+}
+
+func (c *Checkpoint) Remove() error {
+	err := os.Remove(c.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+func (c *Checkpoint) Path() string {
+	return c.path
+}
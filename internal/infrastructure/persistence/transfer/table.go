@@ -0,0 +1,168 @@
+// Package transfer copies the data of one database to another, eg from Postgres to MySQL.
+// Each persistence package implements Store, this package only deals with batching, checkpoints and verification.
+package transfer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type ColumnType int
+
+const (
+	// UUIDs and integers are transferred as strings as well
+	ColumnString ColumnType = iota
+	ColumnTime
+	// Booleans are stored differently by each backend, eg TINYINT in MySQL
+	ColumnBool
+)
+
+type Column struct {
+	Name     string
+	Type     ColumnType
+	Nullable bool
+}
+
+type Table struct {
+	Name string
+	// The first column is the primary key "id", rows are read in id order
+	Columns []Column
+}
+
+// Value of a column in a backend agnostic form
+type Value struct {
+	String string
+	Time   time.Time
+	Bool   bool
+	// False for NULL
+	Valid bool
+}
+
+type Row []Value
+
+// Ordered such that foreign keys always reference rows that are already transferred.
+// A migration adding a table or column must add it here as well, TestTablesCoverSchema checks it
+var Tables = []Table{
+	{Name: "users", Columns: []Column{
+		{Name: "id"}, {Name: "username"}, {Name: "email"}, {Name: "password"},
+		{Name: "created_at", Type: ColumnTime, Nullable: true}, {Name: "updated_at", Type: ColumnTime, Nullable: true},
+	}},
+	{Name: "posts", Columns: []Column{
+		{Name: "id"}, {Name: "user_id"}, {Name: "content"},
+		{Name: "created_at", Type: ColumnTime, Nullable: true}, {Name: "updated_at", Type: ColumnTime, Nullable: true},
+		{Name: "version"}, {Name: "status"}, {Name: "publish_at", Type: ColumnTime, Nullable: true}, {Name: "visibility"},
+	}},
+	// post_mentions has no timestamps
+	{Name: "post_mentions", Columns: []Column{
+		{Name: "id"}, {Name: "post_id"}, {Name: "user_id"},
+	}},
+	// Quotes are kept out of posts, a column referencing posts could point to a post not transferred yet
+	{Name: "post_quotes", Columns: []Column{
+		{Name: "id"}, {Name: "post_id"}, {Name: "quoted_post_id"},
+	}},
+	{Name: "polls", Columns: []Column{
+		{Name: "id"}, {Name: "post_id"}, {Name: "choice"}, {Name: "closes_at", Type: ColumnTime},
+		{Name: "created_at", Type: ColumnTime, Nullable: true}, {Name: "updated_at", Type: ColumnTime, Nullable: true},
+	}},
+	{Name: "poll_options", Columns: []Column{
+		{Name: "id"}, {Name: "poll_id"}, {Name: "position"}, {Name: "text"},
+	}},
+	// poll_votes has no updated_at, a vote is never changed
+	{Name: "poll_votes", Columns: []Column{
+		{Name: "id"}, {Name: "poll_id"}, {Name: "user_id"},
+		{Name: "created_at", Type: ColumnTime, Nullable: true},
+	}},
+	{Name: "poll_vote_options", Columns: []Column{
+		{Name: "id"}, {Name: "vote_id"}, {Name: "option_id"},
+	}},
+	{Name: "likes", Columns: []Column{
+		{Name: "id"}, {Name: "user_id"}, {Name: "post_id"}, {Name: "reaction"},
+		{Name: "created_at", Type: ColumnTime, Nullable: true}, {Name: "updated_at", Type: ColumnTime, Nullable: true},
+	}},
+	{Name: "collections", Columns: []Column{
+		{Name: "id"}, {Name: "user_id"}, {Name: "name"}, {Name: "is_default", Type: ColumnBool},
+		{Name: "created_at", Type: ColumnTime, Nullable: true}, {Name: "updated_at", Type: ColumnTime, Nullable: true},
+	}},
+	{Name: "favorites", Columns: []Column{
+		{Name: "id"}, {Name: "user_id"}, {Name: "post_id"}, {Name: "collection_id"},
+		{Name: "created_at", Type: ColumnTime, Nullable: true}, {Name: "updated_at", Type: ColumnTime, Nullable: true},
+	}},
+	{Name: "reposts", Columns: []Column{
+		{Name: "id"}, {Name: "user_id"}, {Name: "post_id"}, {Name: "comment", Nullable: true},
+		{Name: "created_at", Type: ColumnTime, Nullable: true}, {Name: "updated_at", Type: ColumnTime, Nullable: true},
+	}},
+	// After reposts, the pin of another user's post references the repost
+	{Name: "pinned_posts", Columns: []Column{
+		{Name: "id"}, {Name: "user_id"}, {Name: "post_id"}, {Name: "repost_id", Nullable: true},
+		{Name: "created_at", Type: ColumnTime, Nullable: true}, {Name: "updated_at", Type: ColumnTime, Nullable: true},
+	}},
+	// post_revisions are never updated, edited_at is their only timestamp
+	{Name: "post_revisions", Columns: []Column{
+		{Name: "id"}, {Name: "post_id"}, {Name: "editor_id"}, {Name: "content"},
+		{Name: "edited_at", Type: ColumnTime},
+	}},
+	// follows has no updated_at
+	{Name: "follows", Columns: []Column{
+		{Name: "id"}, {Name: "follower_id"}, {Name: "followee_id"},
+		{Name: "created_at", Type: ColumnTime, Nullable: true},
+	}},
+	{Name: "sessions", Columns: []Column{
+		{Name: "id"}, {Name: "user_id"}, {Name: "expire_at", Type: ColumnTime},
+		{Name: "created_at", Type: ColumnTime, Nullable: true}, {Name: "updated_at", Type: ColumnTime, Nullable: true},
+	}},
+}
+
+func (t Table) ColumnNames() []string {
+	names := make([]string, len(t.Columns))
+	for i, c := range t.Columns {
+		names[i] = c.Name
+	}
+	return names
+}
+
+type Store interface {
+	Count(ctx context.Context, t Table) (int64, error)
+	// Up to limit rows ordered by id, starting after afterID or from the first row if afterID is empty
+	Read(ctx context.Context, t Table, afterID string, limit int) ([]Row, error)
+	// Insert rows keeping their ids and timestamps in one transaction.
+	// Rows whose id already exists are skipped, such that a batch interrupted halfway can be written again.
+	Write(ctx context.Context, t Table, rows []Row) error
+}
+
+// Bind parameter of the n-th argument starting at 1, eg "$1" for Postgres or "?" for MySQL
+type Placeholder func(n int) string
+
+// Query for Store.Read, the arguments are afterID when not empty, then the limit
+func (t Table) SelectSQL(afterID string, ph Placeholder) string {
+	query := fmt.Sprintf("SELECT %s FROM %s", strings.Join(t.ColumnNames(), ", "), t.Name)
+	n := 1
+	if afterID != "" {
+		query += fmt.Sprintf(" WHERE id > %s", ph(n))
+		n++
+	}
+	return query + fmt.Sprintf(" ORDER BY id LIMIT %s", ph(n))
+}
+
+// Insert statement for count rows without the conflict clause, the arguments are the row values one row after another
+func (t Table) InsertSQL(count int, ph Placeholder) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "INSERT INTO %s (%s) VALUES ", t.Name, strings.Join(t.ColumnNames(), ", "))
+	n := 1
+	for i := range count {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString("(")
+		for j := range t.Columns {
+			if j > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteString(ph(n))
+			n++
+		}
+		b.WriteString(")")
+	}
+	return b.String()
+}
@@ -0,0 +1,169 @@
+package transfer
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"time"
+)
+
+const DefaultBatchSize = 500
+
+type Transfer struct {
+	Source Store
+	Target Store
+	// Rows per read and per write transaction
+	BatchSize int
+	// Progress is saved after every batch, nil disables resuming
+	Checkpoint *Checkpoint
+	// Progress messages, io.Discard by default
+	Log io.Writer
+}
+
+type TableReport struct {
+	Table          string
+	SourceCount    int64
+	TargetCount    int64
+	SourceChecksum string
+	TargetChecksum string
+}
+
+func (r TableReport) OK() bool {
+	return r.SourceCount == r.TargetCount && r.SourceChecksum == r.TargetChecksum
+}
+
+func (tr *Transfer) logf(format string, args ...any) {
+	if tr.Log != nil {
+		fmt.Fprintf(tr.Log, format+"\n", args...)
+	}
+}
+
+func (tr *Transfer) batchSize() int {
+	if tr.BatchSize <= 0 {
+		return DefaultBatchSize
+	}
+	return tr.BatchSize
+}
+
+// Copy every table, continuing from the checkpoint if there is one
+func (tr *Transfer) Run(ctx context.Context) error {
+	for _, t := range Tables {
+		if err := tr.copyTable(ctx, t); err != nil {
+			return fmt.Errorf("transfer %s: %w", t.Name, err)
+		}
+	}
+	return nil
+}
+
+func (tr *Transfer) copyTable(ctx context.Context, t Table) error {
+	var progress TableProgress
+	if tr.Checkpoint != nil {
+		progress = tr.Checkpoint.Tables[t.Name]
+	}
+	if progress.Done {
+		tr.logf("%s: already transferred, skipping", t.Name)
+		return nil
+	}
+	if progress.LastID != "" {
+		tr.logf("%s: resuming after id %s", t.Name, progress.LastID)
+	}
+
+	for {
+		rows, err := tr.Source.Read(ctx, t, progress.LastID, tr.batchSize())
+		if err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			break
+		}
+		if err := tr.Target.Write(ctx, t, rows); err != nil {
+			return err
+		}
+
+		progress.LastID = rows[len(rows)-1][0].String
+		progress.Rows += int64(len(rows))
+		if err := tr.save(t, progress); err != nil {
+			return err
+		}
+		tr.logf("%s: %d rows", t.Name, progress.Rows)
+
+		if len(rows) < tr.batchSize() {
+			break
+		}
+	}
+
+	progress.Done = true
+	return tr.save(t, progress)
+}
+
+func (tr *Transfer) save(t Table, progress TableProgress) error {
+	if tr.Checkpoint == nil {
+		return nil
+	}
+	tr.Checkpoint.Tables[t.Name] = progress
+	return tr.Checkpoint.Save()
+}
+
+// Compare row counts and checksums of every table in source and target
+func (tr *Transfer) Verify(ctx context.Context) ([]TableReport, error) {
+	var reports []TableReport
+	for _, t := range Tables {
+		report := TableReport{Table: t.Name}
+		var err error
+
+		if report.SourceCount, err = tr.Source.Count(ctx, t); err != nil {
+			return nil, fmt.Errorf("count source %s: %w", t.Name, err)
+		}
+		if report.TargetCount, err = tr.Target.Count(ctx, t); err != nil {
+			return nil, fmt.Errorf("count target %s: %w", t.Name, err)
+		}
+		if report.SourceChecksum, err = Checksum(ctx, tr.Source, t, tr.batchSize()); err != nil {
+			return nil, fmt.Errorf("checksum source %s: %w", t.Name, err)
+		}
+		if report.TargetChecksum, err = Checksum(ctx, tr.Target, t, tr.batchSize()); err != nil {
+			return nil, fmt.Errorf("checksum target %s: %w", t.Name, err)
+		}
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
+
+// SHA-256 of every row in id order.
+// Times are compared at second precision because MySQL DATETIME columns drop the fraction.
+func Checksum(ctx context.Context, s Store, t Table, batchSize int) (string, error) {
+	h := sha256.New()
+	afterID := ""
+	for {
+		rows, err := s.Read(ctx, t, afterID, batchSize)
+		if err != nil {
+			return "", err
+		}
+		for _, row := range rows {
+			writeRow(h, t, row)
+		}
+		if len(rows) < batchSize {
+			break
+		}
+		afterID = rows[len(rows)-1][0].String
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func writeRow(h hash.Hash, t Table, row Row) {
+	for i, v := range row {
+		switch {
+		case !v.Valid:
+			h.Write([]byte{0})
+		case t.Columns[i].Type == ColumnTime:
+			fmt.Fprintf(h, "%s\x1f", v.Time.UTC().Truncate(time.Second).Format(time.RFC3339))
+		case t.Columns[i].Type == ColumnBool:
+			fmt.Fprintf(h, "%t\x1f", v.Bool)
+		default:
+			fmt.Fprintf(h, "%q\x1f", v.String)
+		}
+	}
+	h.Write([]byte{'\n'})
+}
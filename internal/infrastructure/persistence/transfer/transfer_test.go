@@ -0,0 +1,343 @@
+package transfer_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"social-media-go-ddd/internal/application/config"
+	"social-media-go-ddd/internal/domain/dto"
+	"social-media-go-ddd/internal/domain/entity"
+	"social-media-go-ddd/internal/domain/valueobject"
+	"social-media-go-ddd/internal/infrastructure/persistence/migration"
+	"social-media-go-ddd/internal/infrastructure/persistence/sqlite"
+	"social-media-go-ddd/internal/infrastructure/persistence/transfer"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func newSQLite(t *testing.T) *sql.DB {
+	t.Helper()
+
+	cfg := config.DBConfig{Driver: config.DB_DRIVER_SQLITE, Name: filepath.Join(t.TempDir(), "test.db")}
+	m, err := migration.New(cfg.Driver, cfg.BuildMigrateDSN())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Up(); err != nil {
+		t.Fatal(err)
+	}
+	m.Close()
+
+	db, err := sqlite.NewSQLiteDB(cfg.BuildDSN())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func seed(t *testing.T, db *sql.DB) {
+	t.Helper()
+	ctx := context.Background()
+
+	userRepo := sqlite.NewSQLiteUserRepository(db)
+	postRepo := sqlite.NewSQLitePostRepository(db)
+	likeRepo := sqlite.NewSQLiteLikeRepository(db)
+	repostRepo := sqlite.NewSQLiteRepostRepository(db)
+	followRepo := sqlite.NewSQLiteFollowRepository(db)
+	pollRepo := sqlite.NewSQLitePollRepository(db)
+	pollVoteRepo := sqlite.NewSQLitePollVoteRepository(db)
+	pinRepo := sqlite.NewSQLitePinnedPostRepository(db)
+	collectionRepo := sqlite.NewSQLiteCollectionRepository(db)
+	favoriteRepo := sqlite.NewSQLiteFavoriteRepository(db)
+
+	var users []*entity.User
+	for i := range 5 {
+		u := &entity.User{
+			BaseEntity: entity.NewBaseEntity(),
+			Username:   fmt.Sprintf("user%d", i),
+			Email:      fmt.Sprintf("user%d@example.com", i),
+			Password:   valueobject.PasswordFromHash("hash"),
+		}
+		if err := userRepo.Save(ctx, u); err != nil {
+			t.Fatal(err)
+		}
+		users = append(users, u)
+	}
+	var posts []*entity.Post
+	for i, u := range users {
+		post, _ := entity.NewPost(dto.NewPost{UserID: u.ID, Content: fmt.Sprintf("post %d", i)})
+		if err := postRepo.Save(ctx, post); err != nil {
+			t.Fatal(err)
+		}
+		posts = append(posts, post)
+		edited, _ := entity.NewPostForUpdate(post, dto.UpdatePost{Content: fmt.Sprintf("post %d edited", i)})
+		revision, _ := entity.NewPostRevision(post, edited, u.ID)
+		if err := postRepo.Update(ctx, edited, revision); err != nil {
+			t.Fatal(err)
+		}
+		for j, other := range users {
+			// Every kind of reaction is copied
+			reaction := entity.Reactions[(i+j)%len(entity.Reactions)]
+			like, _ := entity.NewLike(dto.NewLike{UserID: other.ID, PostID: post.ID, Reaction: string(reaction)})
+			if _, err := likeRepo.Save(ctx, like); err != nil {
+				t.Fatal(err)
+			}
+			if other.ID != u.ID {
+				follow, _ := entity.NewFollow(dto.NewFollow{FollowerID: other.ID, FolloweeID: u.ID})
+				if err := followRepo.Save(ctx, follow); err != nil {
+					t.Fatal(err)
+				}
+			}
+		}
+		// NULL comment
+		repost, _ := entity.NewRepost(dto.NewRepost{UserID: u.ID, PostID: post.ID})
+		if err := repostRepo.Save(ctx, repost); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// publish_at is only set for scheduled posts
+	publishAt := time.Now().Add(time.Hour)
+	scheduled, _ := entity.NewPost(dto.NewPost{UserID: users[0].ID, Content: "scheduled", PublishAt: &publishAt})
+	if err := postRepo.Save(ctx, scheduled); err != nil {
+		t.Fatal(err)
+	}
+	mentioned, _ := entity.NewPost(dto.NewPost{UserID: users[0].ID, Content: "for @user1 only", Visibility: string(entity.PostVisibilityMentioned)})
+	if err := postRepo.Save(ctx, mentioned); err != nil {
+		t.Fatal(err)
+	}
+	quote, _ := entity.NewPost(dto.NewPost{UserID: users[1].ID, Content: "quoting user0", QuotedPostID: &mentioned.ID})
+	if err := postRepo.Save(ctx, quote); err != nil {
+		t.Fatal(err)
+	}
+	// Multiple choice such that a vote has several options
+	poll, _ := entity.NewPoll(quote, dto.NewPoll{Options: []string{"yes", "no", "maybe"}, Choice: string(entity.PollChoiceMultiple), ClosesAt: publishAt})
+	if err := pollRepo.Save(ctx, poll); err != nil {
+		t.Fatal(err)
+	}
+	for _, u := range users[:2] {
+		vote, _ := entity.NewPollVote(poll, dto.NewPollVote{UserID: u.ID, OptionIDs: []uuid.UUID{poll.Options[0].ID, poll.Options[2].ID}})
+		if err := pollVoteRepo.Save(ctx, vote); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// An own post with a NULL repost_id and a repost of another user's post
+	otherRepost, _ := entity.NewRepost(dto.NewRepost{UserID: users[1].ID, PostID: posts[0].ID, Comment: "pinned"})
+	if err := repostRepo.Save(ctx, otherRepost); err != nil {
+		t.Fatal(err)
+	}
+	for _, np := range []dto.NewPinnedPost{{UserID: users[0].ID, PostID: posts[0].ID}, {UserID: users[1].ID, PostID: posts[0].ID}} {
+		pin, _ := entity.NewPinnedPost(np)
+		if err := pinRepo.Save(ctx, pin); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Every user has a default collection already, a favorite goes into each kind
+	later, _ := entity.NewCollection(dto.NewCollection{UserID: users[0].ID, Name: "later"})
+	if err := collectionRepo.Save(ctx, later); err != nil {
+		t.Fatal(err)
+	}
+	defaultCollection, err := collectionRepo.FindDefault(ctx, users[0].ID.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, collection := range []*entity.Collection{later, defaultCollection} {
+		favorite, _ := entity.NewFavorite(dto.NewFavorite{UserID: users[0].ID, PostID: posts[i+1].ID, CollectionID: collection.ID})
+		if err := favoriteRepo.Save(ctx, favorite); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := db.Exec("UPDATE reposts SET comment = NULL WHERE rowid % 2 = 0"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Fails every write after the first n, like a crash in the middle of a transfer
+type failingStore struct {
+	transfer.Store
+	writes int
+}
+
+var errCrash = errors.New("crash")
+
+func (s *failingStore) Write(ctx context.Context, tbl transfer.Table, rows []transfer.Row) error {
+	if s.writes == 0 {
+		return errCrash
+	}
+	s.writes--
+	return s.Store.Write(ctx, tbl, rows)
+}
+
+func TestTransferResumesAndVerifies(t *testing.T) {
+	ctx := context.Background()
+	sourceDB := newSQLite(t)
+	seed(t, sourceDB)
+	source := sqlite.NewSQLiteTransferStore(sourceDB)
+	target := sqlite.NewSQLiteTransferStore(newSQLite(t))
+
+	checkpoint, err := transfer.LoadCheckpoint(filepath.Join(t.TempDir(), "checkpoint.json"), "sqlite://source.db", "sqlite://target.db", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	crashing := &transfer.Transfer{Source: source, Target: &failingStore{Store: target, writes: 3}, BatchSize: 3, Checkpoint: checkpoint}
+	if err := crashing.Run(ctx); !errors.Is(err, errCrash) {
+		t.Fatalf("got %v, want crash", err)
+	}
+	if !checkpoint.Tables["users"].Done || checkpoint.Tables["posts"].Done {
+		t.Fatalf("expected users done and posts in progress, got %+v", checkpoint.Tables)
+	}
+
+	// Resume from the saved checkpoint
+	checkpoint, err = transfer.LoadCheckpoint(checkpoint.Path(), "sqlite://source.db", "sqlite://target.db", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := &transfer.Transfer{Source: source, Target: target, BatchSize: 3, Checkpoint: checkpoint}
+	if err := tr.Run(ctx); err != nil {
+		t.Fatalf("resume: %v", err)
+	}
+
+	reports, err := tr.Verify(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reports) != len(transfer.Tables) {
+		t.Fatalf("got %d reports, want %d", len(reports), len(transfer.Tables))
+	}
+	for _, r := range reports {
+		if !r.OK() {
+			t.Fatalf("%s differs: %+v", r.Table, r)
+		}
+	}
+	counts := map[string]int64{}
+	for _, r := range reports {
+		counts[r.Table] = r.SourceCount
+	}
+	if counts["users"] != 5 || counts["post_mentions"] != 1 || counts["post_quotes"] != 1 ||
+		counts["poll_options"] != 3 || counts["poll_votes"] != 2 || counts["poll_vote_options"] != 4 || counts["pinned_posts"] != 2 || counts["likes"] != 25 ||
+		counts["collections"] != 6 || counts["favorites"] != 2 {
+		t.Fatalf("unexpected counts %+v", reports)
+	}
+}
+
+func TestCheckpointBelongsToDatabases(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoint.json")
+	checkpoint, err := transfer.LoadCheckpoint(path, "postgres://social@db:5432/social_media", "sqlite://target.db", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkpoint.Tables["users"] = transfer.TableProgress{LastID: "3", Rows: 3}
+	if err := checkpoint.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := transfer.LoadCheckpoint(path, "postgres://social@db:5432/social_media", "sqlite://other.db", false); !errors.Is(err, transfer.ErrCheckpointMismatch) {
+		t.Fatalf("got %v, want ErrCheckpointMismatch", err)
+	}
+
+	checkpoint, err = transfer.LoadCheckpoint(path, "postgres://social@db:5432/social_media", "sqlite://other.db", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(checkpoint.Tables) != 0 || checkpoint.Target != "sqlite://other.db" {
+		t.Fatalf("reset kept %+v", checkpoint)
+	}
+
+	resumed, err := transfer.LoadCheckpoint(path, "postgres://social@db:5432/social_media", "sqlite://target.db", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resumed.Tables["users"].LastID != "3" {
+		t.Fatalf("got %+v", resumed.Tables)
+	}
+}
+
+func TestVerifyDetectsChanges(t *testing.T) {
+	ctx := context.Background()
+	sourceDB := newSQLite(t)
+	seed(t, sourceDB)
+	targetDB := newSQLite(t)
+
+	tr := &transfer.Transfer{Source: sqlite.NewSQLiteTransferStore(sourceDB), Target: sqlite.NewSQLiteTransferStore(targetDB)}
+	if err := tr.Run(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := targetDB.Exec("UPDATE posts SET content = 'changed' WHERE rowid = 1"); err != nil {
+		t.Fatal(err)
+	}
+
+	reports, err := tr.Verify(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range reports {
+		want := r.Table != "posts"
+		if r.OK() != want {
+			t.Fatalf("%s: got ok=%v, want %v", r.Table, r.OK(), want)
+		}
+		if r.SourceCount != r.TargetCount {
+			t.Fatalf("%s: counts differ", r.Table)
+		}
+	}
+}
+
+// Tables is maintained by hand, every table and column the migrations create must be listed there to be transferred
+func TestTablesCoverSchema(t *testing.T) {
+	db := newSQLite(t)
+
+	listed := map[string]map[string]bool{}
+	for _, tbl := range transfer.Tables {
+		listed[tbl.Name] = map[string]bool{}
+		for _, c := range tbl.Columns {
+			listed[tbl.Name][c.Name] = true
+		}
+	}
+
+	rows, err := db.Query("SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%' AND name != 'schema_migrations'")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tables []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			t.Fatal(err)
+		}
+		tables = append(tables, name)
+	}
+	rows.Close()
+	if len(tables) != len(listed) {
+		t.Fatalf("migrations create %d tables %v, transfer.Tables lists %d", len(tables), tables, len(listed))
+	}
+
+	for _, table := range tables {
+		columns, ok := listed[table]
+		if !ok {
+			t.Fatalf("table %s is missing from transfer.Tables", table)
+		}
+		rows, err := db.Query("SELECT name FROM pragma_table_info(?)", table)
+		if err != nil {
+			t.Fatal(err)
+		}
+		count := 0
+		for rows.Next() {
+			var column string
+			if err := rows.Scan(&column); err != nil {
+				t.Fatal(err)
+			}
+			if !columns[column] {
+				t.Fatalf("column %s.%s is missing from transfer.Tables", table, column)
+			}
+			count++
+		}
+		rows.Close()
+		if count != len(columns) {
+			t.Fatalf("transfer.Tables lists %d columns of %s, the table has %d", len(columns), table, count)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+// Package tracing sets up OpenTelemetry and starts the spans of the services, repositories and cache.
+// Spans are started from the global tracer provider, such that a provider installed later or by a test is picked up.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+const instrumentationName = "social-media-go-ddd"
+
+const (
+	ExporterNone   = "none"
+	ExporterStdout = "stdout"
+	ExporterOTLP   = "otlp"
+)
+
+// Installs the global tracer provider and the W3C trace context propagator.
+// exporter is none, stdout (spans written to w) or otlp (sent to endpoint), the returned func flushes and stops it
+func Setup(ctx context.Context, exporter, endpoint, serviceName string, w io.Writer) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var exp sdktrace.SpanExporter
+	switch strings.ToLower(exporter) {
+	case ExporterNone, "":
+		otel.SetTracerProvider(noop.NewTracerProvider())
+		return func(context.Context) error { return nil }, nil
+	case ExporterStdout:
+		var err error
+		exp, err = stdouttrace.New(stdouttrace.WithWriter(w))
+		if err != nil {
+			return nil, err
+		}
+	case ExporterOTLP:
+		var err error
+		exp, err = NewOTLPExporter(ctx, endpoint)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("invalid trace exporter %q, want %s, %s or %s", exporter, ExporterNone, ExporterStdout, ExporterOTLP)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(semconv.ServiceName(serviceName)))
+	if err != nil {
+		return nil, err
+	}
+	tp := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exp), sdktrace.WithResource(res))
+	otel.SetTracerProvider(tp)
+	return tp.Shutdown, nil
+}
+
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, opts...)
+}
+
+// Records err on the span and ends it
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+const QuerySpanName = "db.query"
+
+// Client span of a database query, the sql is sanitized such that no values end up in the trace
+func StartQuery(ctx context.Context, system, sql string) (context.Context, trace.Span) {
+	return Start(ctx, QuerySpanName, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(
+		semconv.DBSystemKey.String(system),
+		semconv.DBQueryText(SanitizeSQL(sql)),
+	))
+}
+
+// Client span of a cache operation, keyPrefix is the key without ids or tokens, eg "session"
+func StartCache(ctx context.Context, operation, keyPrefix string) (context.Context, trace.Span) {
+	return Start(ctx, "cache."+operation, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(
+		attribute.String("cache.operation", operation),
+		attribute.String("cache.key_prefix", keyPrefix),
+	))
+}
+
+var (
+	// Strings are matched before comments such that -- inside a string is kept, postgres placeholders such that their number is
+	sqlLiteralRe    = regexp.MustCompile(`'(?:[^']|'')*'|--[^\n]*|\$\d+|\b\d+(?:\.\d+)?\b`)
+	sqlWhitespaceRe = regexp.MustCompile(`\s+`)
+)
+
+// Literals are replaced by ?, comments dropped and whitespace collapsed to a single line
+func SanitizeSQL(sql string) string {
+	sql = sqlLiteralRe.ReplaceAllStringFunc(sql, func(lit string) string {
+		switch {
+		case strings.HasPrefix(lit, "$"):
+			return lit
+		case strings.HasPrefix(lit, "--"):
+			return " "
+		}
+		return "?"
+	})
+	return strings.TrimSpace(sqlWhitespaceRe.ReplaceAllString(sql, " "))
+}
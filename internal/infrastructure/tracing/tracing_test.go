@@ -0,0 +1,89 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestSanitizeSQL(t *testing.T) {
+	tests := []struct {
+		sql  string
+		want string
+	}{
+		{"SELECT * FROM posts WHERE id = $1", "SELECT * FROM posts WHERE id = $1"},
+		{"SELECT * FROM users WHERE name = 'alice' AND age > 30", "SELECT * FROM users WHERE name = ? AND age > ?"},
+		{"SELECT 'it''s', 1.5 FROM t1", "SELECT ?, ? FROM t1"},
+		{"SELECT id -- by id\n  FROM posts\n\tLIMIT 10", "SELECT id FROM posts LIMIT ?"},
+		{"SELECT '-- not a comment' FROM posts", "SELECT ? FROM posts"},
+	}
+	for _, tt := range tests {
+		if got := SanitizeSQL(tt.sql); got != tt.want {
+			t.Errorf("SanitizeSQL(%q) = %q, want %q", tt.sql, got, tt.want)
+		}
+	}
+}
+
+func TestOTLPExporter(t *testing.T) {
+	var req coltracepb.ExportTraceServiceRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/traces" {
+			t.Errorf("path %s", r.URL.Path)
+		}
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("read: %v", err)
+		}
+		if err := proto.Unmarshal(body, &req); err != nil {
+			t.Errorf("decode: %v", err)
+		}
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	exp, err := NewOTLPExporter(ctx, srv.URL+"/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exp))
+	ctx, parent := tp.Tracer("test").Start(ctx, "parent")
+	_, child := tp.Tracer("test").Start(ctx, "child", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(
+		attribute.Int("rows", 3),
+		attribute.StringSlice("tags", []string{"a", "b"}),
+	))
+	End(child, errors.New("boom"))
+
+	span := req.GetResourceSpans()[0].GetScopeSpans()[0].GetSpans()[0]
+	parentID := parent.SpanContext().SpanID()
+	if span.GetName() != "child" || !bytes.Equal(span.GetParentSpanId(), parentID[:]) {
+		t.Fatalf("span %v", span)
+	}
+	if span.GetKind() != tracepb.Span_SPAN_KIND_CLIENT || span.GetStatus().GetCode() != tracepb.Status_STATUS_CODE_ERROR || span.GetStatus().GetMessage() != "boom" {
+		t.Fatalf("kind %v status %v", span.GetKind(), span.GetStatus())
+	}
+	if len(span.GetEvents()) != 1 || span.GetEvents()[0].GetName() != "exception" {
+		t.Fatalf("events %v", span.GetEvents())
+	}
+	attrs := map[string]*commonpb.AnyValue{}
+	for _, kv := range span.GetAttributes() {
+		attrs[kv.GetKey()] = kv.GetValue()
+	}
+	if attrs["rows"].GetIntValue() != 3 {
+		t.Fatalf("rows %v", attrs["rows"])
+	}
+	if v := attrs["tags"].GetArrayValue().GetValues(); len(v) != 2 || v[1].GetStringValue() != "b" {
+		t.Fatalf("tags %v", attrs["tags"])
+	}
+}
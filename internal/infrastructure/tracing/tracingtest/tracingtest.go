@@ -0,0 +1,39 @@
+// Package tracingtest records spans in memory for tests and counts the database queries they trace.
+package tracingtest
+
+import (
+	"social-media-go-ddd/internal/infrastructure/tracing"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// Installs a provider recording every span in the returned exporter, the previous provider and propagator are restored
+// once the test ends. Tests using it must not run in parallel since the provider is global
+func Record(tb testing.TB) *tracetest.InMemoryExporter {
+	tb.Helper()
+
+	exp := tracetest.NewInMemoryExporter()
+	prevProvider, prevPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exp)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	tb.Cleanup(func() {
+		otel.SetTracerProvider(prevProvider)
+		otel.SetTextMapPropagator(prevPropagator)
+	})
+	return exp
+}
+
+// Queries run since exp was last reset
+func CountQueries(exp *tracetest.InMemoryExporter) int {
+	n := 0
+	for _, s := range exp.GetSpans() {
+		if s.Name == tracing.QuerySpanName {
+			n++
+		}
+	}
+	return n
+}